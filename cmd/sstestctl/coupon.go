@@ -0,0 +1,402 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sstest/model/coupon"
+	"sstest/repository"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//couponCommands are the commands of the coupon resource
+var couponCommands = map[string]command{
+	"create":      createCoupon,
+	"set":         setCoupon,
+	"activate":    couponStatusCommand("coupon activate", coupon.StatusActive),
+	"deactivate":  couponStatusCommand("coupon deactivate", coupon.StatusInactive),
+	"suspend":     couponStatusCommand("coupon suspend", coupon.StatusSuspended),
+	"show":        showCoupon,
+	"list":        listCoupons,
+	"redemptions": listCouponRedemptions,
+	"delete":      deleteCoupon,
+}
+
+//couponFlags are the flags setting a coupon's values
+type couponFlags struct {
+	*flag.FlagSet
+	kind        *string
+	value       *string
+	currency    *string
+	stock       *int64
+	startDate   *string
+	endDate     *string
+	minSubtotal *string
+	minQuantity *int
+	maxDiscount *string
+	products    *string
+	excluded    *string
+	userLimit   *int
+	limit       *int
+	stackable   *bool
+	buy         *int
+	free        *int
+	freeIDs     *string
+	tiers       *string
+	automatic   *bool
+	priority    *int
+	excludes    *bool
+}
+
+//newCouponFlags declares the flags setting a coupon's values
+func newCouponFlags(name string) couponFlags {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	return couponFlags{
+		flags,
+		flags.String("kind", "", "coupon kind (V for value, P for percentage, B for buy X get Y, F for free shipping, T for tiered, N for bundle)"),
+		flags.String("value", "", "coupon value (decimal, the bundle price of a bundle coupon)"),
+		flags.String("currency", "", "coupon value currency (ISO 4217 code, USD by default)"),
+		flags.Int64("stock", 0, "coupon stock"),
+		flags.String("start", "", "coupon start date"),
+		flags.String("end", "", "coupon end date"),
+		flags.String("min-subtotal", "", "minimum order subtotal (decimal, in the coupon currency, 0 for none)"),
+		flags.Int("min-quantity", 0, "minimum order item count (0 for none)"),
+		flags.String("max-discount", "", "maximum discount of a percentage coupon (decimal, in the coupon currency, 0 for no cap)"),
+		flags.String("products", "", "comma separated ids of the discounted products (empty for every product)"),
+		flags.String("exclude", "", "comma separated ids of the products never discounted"),
+		flags.Int("user-limit", 0, "maximum redemptions by a user (0 for no limit)"),
+		flags.Int("limit", 0, "maximum redemptions by every user (0 for no limit)"),
+		flags.Bool("stackable", false, "combinable with other stackable coupons (-stackable=false for an exclusive coupon)"),
+		flags.Int("buy", 0, "count of items bought to get free items of a buy X get Y coupon"),
+		flags.Int("free", 0, "count of free items got for every bought items of a buy X get Y coupon"),
+		flags.String("free-products", "", "comma separated ids of the products given free by a buy X get Y coupon (empty for the bought products)"),
+		flags.String("tiers", "", "comma separated spend tiers of a tiered coupon as minimum spend:percentage pairs (e.g. 50:5,100:10)"),
+		flags.Bool("automatic", false, "automatic promotion applied without its code on eligible orders (-automatic=false for an entered coupon)"),
+		flags.Int("priority", 0, "rank of an automatic promotion (the highest applied first)"),
+		flags.Bool("excludes-coupons", false, "automatic promotion skipped on orders having coupons"),
+	}
+}
+
+//apply sets the values of a coupon given by flags, validated through the coupon's setters
+func (flags couponFlags) apply(c *coupon.Coupon) *errors.Error {
+	if isSet(flags.FlagSet, "stock") {
+		if *flags.stock < 0 {
+			return errors.Wrap(fmt.Errorf("Can't set negative value %d for stock", *flags.stock), 0)
+		}
+		c.SetStock(*flags.stock)
+	}
+	kind := c.Kind()
+	if isSet(flags.FlagSet, "kind") {
+		kind = *flags.kind
+	}
+	if isSet(flags.FlagSet, "value") {
+		value, err := decimal.NewFromString(*flags.value)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read value %v: %v", *flags.value, err), 0)
+		}
+		//note: value is set under the value kind (accepting any positive value), the final kind then validates it
+		c.SetKind(coupon.KindValue)
+		if _, err := c.SetValue(value); err != nil {
+			return err
+		}
+	}
+	if _, err := c.SetKind(kind); err != nil {
+		return err
+	}
+	if isSet(flags.FlagSet, "currency") {
+		if _, err := c.SetCurrency(*flags.currency); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "min-subtotal") {
+		minSubtotal, err := decimal.NewFromString(*flags.minSubtotal)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read minimum subtotal %v: %v", *flags.minSubtotal, err), 0)
+		}
+		if _, err := c.SetMinSubtotal(minSubtotal); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "min-quantity") {
+		if _, err := c.SetMinQuantity(*flags.minQuantity); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "max-discount") {
+		maxDiscount, err := decimal.NewFromString(*flags.maxDiscount)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read maximum discount %v: %v", *flags.maxDiscount, err), 0)
+		}
+		if _, err := c.SetMaxDiscount(maxDiscount); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "products") {
+		c.SetProducts(splitIDs(*flags.products)...)
+	}
+	if isSet(flags.FlagSet, "exclude") {
+		c.SetExcludedProducts(splitIDs(*flags.excluded)...)
+	}
+	if isSet(flags.FlagSet, "user-limit") {
+		if _, err := c.SetUserLimit(*flags.userLimit); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "limit") {
+		if _, err := c.SetRedemptionLimit(*flags.limit); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "stackable") {
+		c.SetStackable(*flags.stackable)
+	}
+	if isSet(flags.FlagSet, "buy") {
+		if _, err := c.SetBuyQuantity(*flags.buy); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "free") {
+		if _, err := c.SetFreeQuantity(*flags.free); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "free-products") {
+		c.SetFreeProducts(splitIDs(*flags.freeIDs)...)
+	}
+	if isSet(flags.FlagSet, "tiers") {
+		tiers, err := parseTiers(*flags.tiers)
+		if err != nil {
+			return err
+		}
+		if _, err := c.SetTiers(tiers...); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "automatic") {
+		c.SetAutomatic(*flags.automatic)
+	}
+	if isSet(flags.FlagSet, "priority") {
+		c.SetPriority(*flags.priority)
+	}
+	if isSet(flags.FlagSet, "excludes-coupons") {
+		c.SetExcludesCoupons(*flags.excludes)
+	}
+
+	startDate, endDate := c.StartDate(), c.EndDate()
+	var err *errors.Error
+	if isSet(flags.FlagSet, "start") {
+		if startDate, err = parseDate(*flags.startDate); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "end") {
+		if endDate, err = parseDate(*flags.endDate); err != nil {
+			return err
+		}
+	}
+	//note: dates are set in the order keeping start date before end date at every step
+	if startDate.After(c.EndDate()) {
+		if _, err := c.SetEndDate(endDate); err != nil {
+			return err
+		}
+		_, err = c.SetStartDate(startDate)
+		return err
+	}
+	if _, err := c.SetStartDate(startDate); err != nil {
+		return err
+	}
+	_, err = c.SetEndDate(endDate)
+	return err
+}
+
+//createCoupon creates a new inactive coupon
+func createCoupon(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := newCouponFlags("coupon create")
+	args, err := parse(flags.FlagSet, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	c := coupon.New(args[0])
+	if err := flags.apply(c); err != nil {
+		return err
+	}
+	if err := store.Coupons.Create(c); err != nil {
+		return err
+	}
+	printCoupons(out, c)
+	return nil
+}
+
+//setCoupon sets the values of a coupon given by flags
+func setCoupon(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := newCouponFlags("coupon set")
+	args, err := parse(flags.FlagSet, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	c, err := store.Coupons.FindByCode(args[0])
+	if err != nil {
+		return err
+	}
+	if err := flags.apply(c); err != nil {
+		return err
+	}
+	//the stored stock is left unchanged (orders may have used the coupon since it was loaded) unless a stock is given
+	save := store.Coupons.Update
+	if isSet(flags.FlagSet, "stock") {
+		save = store.Coupons.Save
+	}
+	if err := save(c); err != nil {
+		return err
+	}
+	printCoupons(out, c)
+	return nil
+}
+
+//couponStatusCommand returns the command setting a coupon's status
+func couponStatusCommand(name, status string) command {
+	return func(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+		args, err := parse(flag.NewFlagSet(name, flag.ContinueOnError), args, 1, 1)
+		if err != nil {
+			return err
+		}
+		c, err := store.Coupons.FindByCode(args[0])
+		if err != nil {
+			return err
+		}
+		if _, err := c.SetStatus(status); err != nil {
+			return err
+		}
+		if err := store.Coupons.Update(c); err != nil {
+			return err
+		}
+		printCoupons(out, c)
+		return nil
+	}
+}
+
+//showCoupon prints a coupon
+func showCoupon(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("coupon show", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	c, err := store.Coupons.FindByCode(args[0])
+	if err != nil {
+		return err
+	}
+	printCoupons(out, c)
+	return nil
+}
+
+//listCoupons prints all coupons
+func listCoupons(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	if _, err := parse(flag.NewFlagSet("coupon list", flag.ContinueOnError), args, 0, 0); err != nil {
+		return err
+	}
+	coupons, err := store.Coupons.FindAll()
+	if err != nil {
+		return err
+	}
+	printCoupons(out, coupons...)
+	return nil
+}
+
+//listCouponRedemptions prints a coupon's redemptions, the reversed ones included
+func listCouponRedemptions(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("coupon redemptions", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	c, err := store.Coupons.FindByCode(args[0])
+	if err != nil {
+		return err
+	}
+	redemptions, err := store.Coupons.FindRedemptions(c.ID())
+	if err != nil {
+		return err
+	}
+	printRedemptions(out, redemptions...)
+	return nil
+}
+
+//deleteCoupon deletes a coupon
+func deleteCoupon(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("coupon delete", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	c, err := store.Coupons.FindByCode(args[0])
+	if err != nil {
+		return err
+	}
+	return store.Coupons.Delete(c.ID())
+}
+
+//splitIDs splits a comma separated list of ids (none when empty)
+func splitIDs(list string) []string {
+	ids := make([]string, 0)
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); "" != id {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+//parseTiers parses a comma separated list of minimum spend:percentage spend tiers (none when empty)
+func parseTiers(list string) ([]*coupon.Tier, *errors.Error) {
+	tiers := make([]*coupon.Tier, 0)
+	for _, pair := range splitIDs(list) {
+		parts := strings.SplitN(pair, ":", 2)
+		if 2 != len(parts) {
+			return nil, errors.Wrap(fmt.Errorf("Can't read tier %v, expected minimum spend:percentage", pair), 0)
+		}
+		minSpend, err := decimal.NewFromString(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read tier %v minimum spend: %v", pair, err), 0)
+		}
+		percentage, err := decimal.NewFromString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read tier %v percentage: %v", pair, err), 0)
+		}
+		tiers = append(tiers, coupon.NewTier(minSpend, percentage))
+	}
+	return tiers, nil
+}
+
+//formatTiers formats spend tiers as comma separated minimum spend:percentage pairs
+func formatTiers(tiers []*coupon.Tier) string {
+	pairs := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		pairs = append(pairs, fmt.Sprintf("%v:%v", tier.MinSpend(), tier.Percentage()))
+	}
+	return strings.Join(pairs, ",")
+}
+
+//printCoupons prints coupons as a table
+func printCoupons(out io.Writer, coupons ...*coupon.Coupon) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CODE\tSTATUS\tSTOCK\tKIND\tVALUE\tCURRENCY\tSTART\tEND\tMIN SUBTOTAL\tMIN QUANTITY\tMAX DISCOUNT\tPRODUCTS\tEXCLUDED\tUSER LIMIT\tLIMIT\tSTACKABLE\tBUY\tFREE\tFREE PRODUCTS\tTIERS\tAUTOMATIC\tPRIORITY\tEXCLUDES COUPONS")
+	for _, c := range coupons {
+		fmt.Fprintf(w, "%v\t%v\t%d\t%v\t%v\t%v\t%v\t%v\t%v\t%d\t%v\t%v\t%v\t%d\t%d\t%v\t%d\t%d\t%v\t%v\t%v\t%d\t%v\n", c.ID(), c.Status(), c.Stock(), c.Kind(), c.Value(), c.Currency(),
+			formatDate(c.StartDate()), formatDate(c.EndDate()), c.MinSubtotal(), c.MinQuantity(), c.MaxDiscount(),
+			strings.Join(c.Products(), ","), strings.Join(c.ExcludedProducts(), ","), c.UserLimit(), c.RedemptionLimit(), c.Stackable(),
+			c.BuyQuantity(), c.FreeQuantity(), strings.Join(c.FreeProducts(), ","), formatTiers(c.Tiers()), c.Automatic(), c.Priority(), c.ExcludesCoupons())
+	}
+	w.Flush()
+}
+
+//printRedemptions prints coupon redemptions as a table (the reversed date is empty until the order is canceled)
+func printRedemptions(out io.Writer, redemptions ...*coupon.Redemption) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ORDER\tUSER\tAMOUNT\tCURRENCY\tREDEEMED\tREVERSED")
+	for _, r := range redemptions {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", r.OrderID(), r.UserID(), r.Amount(), r.Currency(), formatDate(r.RedeemedDate()), formatDate(r.ReversedDate()))
+	}
+	w.Flush()
+}
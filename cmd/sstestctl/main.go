@@ -0,0 +1,267 @@
+//Command sstestctl operates the business domain models stored in an SQLite repository file
+//
+//Usage:
+//
+//	sstestctl [-db file] [-shipping rate] [-shipping-currency code] [-taxes file] [-rates file] [-rounding mode] [-locale tag] [-stacking order] <resource> <command> [flags] [arguments]
+//
+//The repository file defaults to $SSTEST_DB, or sstest.db when it is not set.
+//The shipping rate of submitted orders defaults to $SSTEST_SHIPPING, or free shipping when it is not set.
+//The currency of its costs and threshold defaults to $SSTEST_SHIPPING_CURRENCY, or USD when it is not set.
+//The tax table file of submitted orders defaults to $SSTEST_TAXES, orders are not taxed when it is not set.
+//The exchange rate file converting the orders' products priced in another currency defaults to $SSTEST_RATES, products are not converted when it is not set.
+//The converted prices are rounded by $SSTEST_ROUNDING, or half-up when it is not set.
+//The locale of the formatted amounts (e.g. receipts) defaults to $SSTEST_LOCALE, or en-US when it is not set.
+//The order stacked coupons are applied in defaults to $SSTEST_STACKING, or percentage-first when it is not set. Run "sstestctl help" for the commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/repository"
+	"sstest/repository/sqlite"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//command is a resource command, run on the store with its arguments (after the resource and command names)
+type command func(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error
+
+//commands maps the resource and command names to their command
+var commands = map[string]map[string]command{
+	"product": productCommands,
+	"coupon":  couponCommands,
+	"user":    userCommands,
+	"order":   orderCommands,
+}
+
+//usage is the help text of the tool
+const usage = `usage: sstestctl [-db file] [-shipping rate] [-shipping-currency code] [-taxes file] [-rates file] [-rounding mode] [-locale tag] [-stacking order] <resource> <command> [flags] [arguments]
+
+product create [-price p] [-currency c] [-stock n [-warehouse w]] [-status s] [-weight kg] [-dimensions LxWxH] [-tax-category c] <id> <name>
+product set    [-name n] [-price p] [-currency c] [-stock n [-warehouse w]] [-status s] [-weight kg] [-dimensions LxWxH] [-tax-category c] <id>
+product show   <id>
+product list
+product delete <id>
+product sweep  [-every interval]  (releases the expired stock holds of draft orders, every interval (e.g. 1m) until interrupted)
+
+coupon create      [-kind k] [-value v] [-currency c] [-stock n] [-start date] [-end date] [conditions] [limits] [promotion] [-stackable] [automatic] <code>
+coupon set         [-kind k] [-value v] [-currency c] [-stock n] [-start date] [-end date] [conditions] [limits] [promotion] [-stackable] [automatic] <code>
+coupon activate    <code>
+coupon deactivate  <code>
+coupon suspend     <code>
+coupon show        <code>
+coupon list
+coupon redemptions <code>    (prints a coupon's redemptions by submitted orders, reversed when canceled)
+coupon delete      <code>
+
+user create     [-address a] [-password p] <id> <name>
+user set        [-name n] [-address a] <id>
+user password   <id> <password>    ("-" reads the password from standard input)
+user activate   <id>
+user deactivate <id>
+user suspend    <id>
+user show       <id>
+user list
+user orders     <id>
+user delete     <id>
+
+order create  [-user id] [-currency c] [id]
+order add     <id> <productId> <quantity>
+order edit    <id> <productId> <quantity>    (quantity is added to the item's quantity)
+order remove  <id> <productId>
+order submit  [-name n] [-address a] [-region r] [-coupon code]... <id>
+order quote   [-coupon code]... [-region r] <id>    (prints a draft order's price breakdown as submitted)
+order receipt <id>    (prints a submitted order's receipt, amounts formatted in the locale)
+order process <id>
+order cancel  <id>
+order ship    [-carrier c] [-item productId=quantity]... <id> <trackingId>    (ships every unshipped quantity without item)
+order deliver <id> <shipmentId>    (finishes the order once every shipment is delivered)
+order track   [-date d] [-location l] [-description d] <id> <shipmentId> <type>    (type is PU, IT, OD, DL, FA or RS)
+order finish  <id>    (delivers every shipment of a fully shipped order)
+order fire    <id> <event>    (fires another event of the order state machine)
+order diagram [-format dot|mermaid]
+order show    <id>
+order list    [-status s]
+order delete  <id>
+
+dates are formatted as 2006-01-02 or RFC 3339 (2006-01-02T15:04:05Z07:00)
+shipping rates are flat:COST, weight:BASE:PERKG[:DIVISOR] (volumetric weight with a divisor, e.g. 5000)
+or free-over:THRESHOLD:RATE (e.g. free-over:50:flat:4.99), shipping is free when no rate is given
+the shipping costs and threshold are in -shipping-currency (USD by default), converted with -rates into an order's other currency
+tax table files are JSON: {"regions": [{"code": "FR", "inclusive": true, "exempt": ["food"], "rules": [{"name": "VAT", "category": "standard", "rate": "0.2"}]}]}
+an order is taxed by the region matching its shipping region (-region), a rule without category applies to every product tax category
+currencies are ISO 4217 codes (AUD, CAD, CHF, EUR, GBP, IDR, JPY or USD, the default), an order is in its first product's currency and its products and value coupon must be in it
+rate files are JSON: {"base": "USD", "date": "2017-09-15T00:00:00Z", "rates": {"EUR": "0.84", "JPY": "110.9"}} (read on every quote, dated by modification when no date)
+with -rates, an order keeps its currency (-currency) and converts the products priced in another one, rounding with -rounding half-up, half-even, down or up
+coupon conditions are [-min-subtotal s] [-min-quantity n] [-max-discount d] [-products id,...] [-exclude id,...],
+a coupon only applies on an order meeting its minimum subtotal and item count, and only discounts its products (every one when none) but the excluded ones
+coupon limits are [-user-limit n] [-limit n], the maximum redemptions by a user and by every user (0 for no limit), checked on order submission
+coupon kinds are V (value), P (percentage), B (buy X get Y), F (free shipping), T (tiered percentage by spend) and N (bundle, -value is its price),
+coupon promotion is [-buy n] [-free n] [-free-products id,...] [-tiers spend:percentage,...]: a buy X get Y coupon gives -free items
+of the same product (or of its free products) for every -buy items of its products, a bundle sells one item of each of its products at its value
+coupons are exclusive unless -stackable, several coupons of an order must all be stackable and are applied in the -stacking order
+percentage-first (the default) or value-first (after the buy X get Y and bundle coupons, before the free shipping ones),
+each one discounting the amount left by the previous ones (never to zero or less)
+coupon automatic is [-automatic] [-priority n] [-excludes-coupons]: an active automatic promotion is applied without its code on every
+quoted or submitted order it's eligible for within its dates (before the coupons, highest -priority first, ignoring its stock and limits),
+an exclusive one is applied alone and an -excludes-coupons one is skipped on orders quoted or submitted with coupons
+amounts are formatted in the -locale de-DE, en-GB, en-US (the default), fr-FR, id-ID or ja-JP
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "sstestctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+//run runs the tool with its arguments (without the program name)
+func run(args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("sstestctl", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("db", defaultPath(), "repository file")
+	shipping := flags.String("shipping", os.Getenv("SSTEST_SHIPPING"), "shipping rate of submitted orders")
+	shippingCurrency := flags.String("shipping-currency", os.Getenv("SSTEST_SHIPPING_CURRENCY"), "currency of the shipping rate")
+	taxes := flags.String("taxes", os.Getenv("SSTEST_TAXES"), "tax table file of submitted orders")
+	rates := flags.String("rates", os.Getenv("SSTEST_RATES"), "exchange rate file of the orders' converted products")
+	rounding := flags.String("rounding", os.Getenv("SSTEST_ROUNDING"), "rounding mode of the converted prices")
+	locale := flags.String("locale", os.Getenv("SSTEST_LOCALE"), "locale of the formatted amounts")
+	stacking := flags.String("stacking", os.Getenv("SSTEST_STACKING"), "order stacked coupons are applied in")
+	if err := flags.Parse(args); err != nil {
+		return errors.Wrap(fmt.Errorf("%v\n%v", err, usage), 0)
+	}
+	rate, err := parseShippingRate(*shipping, *shippingCurrency)
+	if err != nil {
+		return err
+	}
+	order.SetDefaultShippingRate(rate)
+	table, err := loadTaxTable(*taxes)
+	if err != nil {
+		return err
+	}
+	order.SetDefaultTaxTable(table)
+	exchangeRates, err := loadExchangeRates(*rates)
+	if err != nil {
+		return err
+	}
+	order.SetDefaultExchangeRates(exchangeRates)
+	if err := money.SetDefaultRounding(*rounding); err != nil {
+		return err
+	}
+	if err := money.SetDefaultLocale(*locale); err != nil {
+		return err
+	}
+	if err := order.SetDefaultStacking(*stacking); err != nil {
+		return err
+	}
+	args = flags.Args()
+	if 1 == len(args) && "help" == args[0] {
+		fmt.Fprint(out, usage)
+		return nil
+	}
+	if len(args) < 2 {
+		return errors.Wrap(fmt.Errorf("missing resource or command\n%v", usage), 0)
+	}
+	resourceCommands, ok := commands[args[0]]
+	if false == ok {
+		return errors.Wrap(fmt.Errorf("unknown resource %v, expected one of: %v", args[0], strings.Join(resourceNames(), ", ")), 0)
+	}
+	cmd, ok := resourceCommands[args[1]]
+	if false == ok {
+		return errors.Wrap(fmt.Errorf("unknown %v command %v, expected one of: %v", args[0], args[1], strings.Join(commandNames(resourceCommands), ", ")), 0)
+	}
+
+	db, err := sqlite.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return cmd(sqlite.NewStore(db), args[2:], in, out)
+}
+
+//defaultPath returns the default repository file path
+func defaultPath() string {
+	if path := os.Getenv("SSTEST_DB"); "" != path {
+		return path
+	}
+	return "sstest.db"
+}
+
+//resourceNames returns the sorted names of the resources
+func resourceNames() []string {
+	keys := make([]string, 0, len(commands))
+	for key := range commands {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//commandNames returns the sorted names of a resource's commands
+func commandNames(resourceCommands map[string]command) []string {
+	keys := make([]string, 0, len(resourceCommands))
+	for key := range resourceCommands {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//parse parses a command's flags (before, between or after the arguments) and returns its arguments
+func parse(flags *flag.FlagSet, args []string, min, max int) ([]string, *errors.Error) {
+	flags.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("%v: %v", flags.Name(), err), 0)
+		}
+		args = flags.Args()
+		if 0 == len(args) {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) < min || len(positional) > max {
+		return nil, errors.Wrap(fmt.Errorf("%v: wrong number of arguments %d, see sstestctl help", flags.Name(), len(positional)), 0)
+	}
+	return positional, nil
+}
+
+//isSet returns whether a flag has been given on the command line
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if name == f.Name {
+			set = true
+		}
+	})
+	return set
+}
+
+//parseDate parses a date formatted as 2006-01-02 (beginning of day, local time) or as RFC 3339
+func parseDate(value string) (time.Time, *errors.Error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); nil == err {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Wrap(fmt.Errorf("Can't read date %v, expected 2006-01-02 or RFC 3339", value), 0)
+	}
+	return date, nil
+}
+
+//formatDate formats a date for output (an empty string for a date not set yet)
+func formatDate(date time.Time) string {
+	if date.IsZero() || 0 == date.UnixNano() {
+		return ""
+	}
+	return date.Format(time.RFC3339)
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"sstest/repository/sqlite"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sstest.db")
+	var out bytes.Buffer
+	sstestctl := func(args ...string) *errors.Error {
+		out.Reset()
+		return run(append([]string{"-db", path}, args...), strings.NewReader("newSecret\n"), &out)
+	}
+
+	var steps = []struct {
+		testCase string
+		args     []string
+	}{
+		{"Create Product", []string{"product", "create", "-price", "100", "-stock", "10", "-status", product.StatusAvailable, "prod1", "Product One"}},
+		{"Set Product Stock", []string{"product", "set", "prod1", "-stock", "20"}},
+		{"Set Product Main Warehouse Stock", []string{"product", "set", "prod1", "-stock", "15", "-warehouse", product.DefaultWarehouse}},
+		{"Set Product East Warehouse Stock", []string{"product", "set", "prod1", "-stock", "5", "-warehouse", "east"}},
+		{"Create Coupon", []string{"coupon", "create", "-kind", "V", "-value", "150", "-stock", "5", "-start", "2000-01-01", "-end", "2100-01-01", "save150"}},
+		{"Switch Coupon Kind", []string{"coupon", "set", "-kind", "P", "-value", "10", "SAVE150"}},
+		{"Activate Coupon", []string{"coupon", "activate", "save150"}},
+		{"Create User", []string{"user", "create", "-address", "Address One", "user1", "User One"}},
+		{"Set User Password", []string{"user", "password", "user1", "-"}},
+		{"Activate User", []string{"user", "activate", "user1"}},
+		{"Create Order", []string{"order", "create", "-user", "user1", "order1"}},
+		{"Add Product", []string{"order", "add", "order1", "prod1", "2"}},
+		{"Edit Product", []string{"order", "edit", "order1", "prod1", "1"}},
+		{"Submit Order", []string{"order", "submit", "-name", "ship name", "-address", "ship address", "-coupon", "save150", "order1"}},
+		{"Process Order", []string{"order", "process", "order1"}},
+		{"Ship Order Partially", []string{"order", "ship", "-carrier", "dummyCarrier", "-item", "prod1=1", "order1", "dummyTrackingNo"}},
+		{"Ship Order Remainder", []string{"order", "ship", "order1", "secondTrackingNo"}},
+		{"Finish Order", []string{"order", "finish", "order1"}},
+	}
+	for _, step := range steps {
+		t.Run(fmt.Sprintf("%s", step.testCase), func(t *testing.T) {
+			if err := sstestctl(step.args...); err != nil {
+				t.Fatalf("expected no error but got %v\n", err)
+			}
+		})
+	}
+	showErr := sstestctl("order", "show", "order1")
+	shown := out.String()
+	userOrdersErr := sstestctl("user", "orders", "user1")
+	userOrders := out.String()
+	dotErr := sstestctl("order", "diagram")
+	dot := out.String()
+	mermaidErr := sstestctl("order", "diagram", "-format", "mermaid")
+	mermaid := out.String()
+	sstestctl("order", "create", "order2")
+	holdErr := sstestctl("order", "add", "order2", "prod1", "4")
+	sstestctl("product", "show", "prod1")
+	held := out.String()
+	deleteErr := sstestctl("order", "delete", "order2")
+	sstestctl("product", "show", "prod1")
+	released := out.String()
+	sweepErr := sstestctl("product", "sweep")
+	swept := out.String()
+	//SIGWINCH is ignored by default, it can be sent until the periodic sweep is listening to it
+	defaultStopSignals := stopSignals
+	t.Cleanup(func() { stopSignals = defaultStopSignals })
+	stopSignals = []os.Signal{syscall.SIGWINCH}
+	periodicSweep := make(chan *errors.Error, 1)
+	go func() { periodicSweep <- sstestctl("product", "sweep", "-every", "5ms") }()
+	var periodicSweepErr *errors.Error
+	for stopped := false; false == stopped; {
+		select {
+		case periodicSweepErr = <-periodicSweep:
+			stopped = true
+		case <-time.After(20 * time.Millisecond):
+			syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+		}
+	}
+	periodicallySwept := out.String()
+	sstestctl("order", "create", "order3")
+	sstestctl("order", "add", "order3", "prod1", "1")
+	sstestctl("order", "submit", "order3")
+	sstestctl("order", "process", "order3")
+	sstestctl("order", "ship", "-carrier", "acme", "order3", "acmeTrackingNo")
+	var shipmentID string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); 2 < len(fields) && "acme" == fields[1] {
+			shipmentID = fields[0]
+		}
+	}
+	trackErr := sstestctl("order", "track", "-location", "Hub", "-date", "2100-01-01", "order3", shipmentID, order.TrackingInTransit)
+	tracked := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "track", "-date", "2100-01-02", "order3", shipmentID, order.TrackingReturned)
+	returnedCancelErr := sstestctl("order", "cancel", "order3")
+	sizeErr := sstestctl("product", "set", "-weight", "1.5", "-dimensions", "30x20x10", "prod1")
+	sized := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "create", "order4")
+	sstestctl("order", "add", "order4", "prod1", "1")
+	quoteErr := sstestctl("-shipping", "free-over:500:weight:5:2", "order", "quote", "order4")
+	quoted := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("-shipping", "free-over:500:weight:5:2", "order", "submit", "order4")
+	shippedSubmitted := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "cancel", "order4")
+	taxes := filepath.Join(t.TempDir(), "taxes.json")
+	os.WriteFile(taxes, []byte(`{"regions": [{"code": "CA-QC", "exempt": ["food"],
+		"rules": [{"name": "GST", "rate": "0.05"}, {"name": "QST", "rate": "0.09975"}]}]}`), 0600)
+	taxCategoryErr := sstestctl("product", "set", "-tax-category", "reduced", "prod1")
+	taxCategorized := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "create", "order5")
+	sstestctl("order", "add", "order5", "prod1", "1")
+	taxQuoteErr := sstestctl("-taxes", taxes, "order", "quote", "-region", "CA-QC", "order5")
+	taxQuoted := strings.Join(strings.Fields(out.String()), " ")
+	taxSubmitErr := sstestctl("-taxes", taxes, "order", "submit", "-region", "CA-QC", "order5")
+	taxSubmitted := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "cancel", "order5")
+	sstestctl("product", "set", "-name", "Renamed Product", "-price", "120", "prod1")
+	snapshotErr := sstestctl("order", "show", "order1")
+	snapshot := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("product", "create", "-price", "1234.5", "-currency", "EUR", "-stock", "5", "-status", product.StatusAvailable, "prod2", "Product Two")
+	euroProduct := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "create", "order6")
+	sstestctl("order", "add", "order6", "prod2", "1")
+	mixedErr := sstestctl("order", "add", "order6", "prod1", "1")
+	sstestctl("order", "submit", "-name", "ship name", "-address", "ship address", "order6")
+	euroSubmitted := strings.Join(strings.Fields(out.String()), " ")
+	receiptErr := sstestctl("-locale", "de-DE", "order", "receipt", "order6")
+	receipt := strings.Join(strings.Fields(out.String()), " ")
+	rates := filepath.Join(t.TempDir(), "rates.json")
+	os.WriteFile(rates, []byte(`{"base": "USD", "date": "2017-09-15T00:00:00Z", "rates": {"EUR": "0.8"}}`), 0600)
+	sstestctl("order", "create", "-currency", "USD", "order7")
+	convertErr := sstestctl("-rates", rates, "order", "add", "order7", "prod2", "1")
+	sstestctl("-rates", rates, "-rounding", "down", "order", "submit", "order7")
+	converted := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("product", "create", "-price", "50", "-stock", "5", "-status", product.StatusAvailable, "prod3", "Product Three")
+	conditionErr := sstestctl("coupon", "create", "-value", "50", "-stock", "5", "-start", "2000-01-01", "-end", "2100-01-01",
+		"-min-quantity", "2", "-max-discount", "20", "-products", "prod3", "half")
+	conditioned := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("coupon", "activate", "half")
+	sstestctl("order", "create", "order8")
+	sstestctl("order", "add", "order8", "prod1", "1")
+	ineligibleErr := sstestctl("order", "quote", "-coupon", "half", "order8")
+	sstestctl("order", "add", "order8", "prod3", "1")
+	eligibleErr := sstestctl("order", "quote", "-coupon", "half", "order8")
+	eligibleQuoted := strings.Join(strings.Fields(out.String()), " ")
+	limitErr := sstestctl("coupon", "create", "-kind", "V", "-value", "10", "-stock", "5", "-start", "2000-01-01", "-end", "2100-01-01",
+		"-user-limit", "1", "-limit", "3", "once")
+	limited := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("coupon", "activate", "once")
+	sstestctl("order", "create", "-user", "user1", "order9")
+	sstestctl("order", "add", "order9", "prod3", "1")
+	redeemErr := sstestctl("order", "submit", "-coupon", "once", "order9")
+	sstestctl("order", "create", "-user", "user1", "order10")
+	sstestctl("order", "add", "order10", "prod3", "1")
+	overLimitErr := sstestctl("order", "submit", "-coupon", "once", "order10")
+	sstestctl("order", "cancel", "order9")
+	reversedRedeemErr := sstestctl("order", "submit", "-coupon", "once", "order10")
+	redemptionsErr := sstestctl("coupon", "redemptions", "once")
+	redemptions := strings.Join(strings.Fields(out.String()), " ")
+	stackableErr := sstestctl("coupon", "create", "-value", "10", "-stock", "5", "-start", "2000-01-01", "-end", "2100-01-01", "-stackable", "tenoff")
+	stackable := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("coupon", "create", "-kind", "V", "-value", "5", "-stock", "5", "-start", "2000-01-01", "-end", "2100-01-01", "-stackable", "five")
+	sstestctl("coupon", "activate", "tenoff")
+	sstestctl("coupon", "activate", "five")
+	sstestctl("order", "create", "order11")
+	sstestctl("order", "add", "order11", "prod3", "2")
+	valueFirstErr := sstestctl("-stacking", "value-first", "order", "quote", "-coupon", "tenoff", "-coupon", "five", "order11")
+	valueFirstQuoted := strings.Join(strings.Fields(out.String()), " ")
+	exclusiveErr := sstestctl("order", "submit", "-coupon", "five", "-coupon", "once", "order11")
+	stackedErr := sstestctl("order", "submit", "-coupon", "five", "-coupon", "tenoff", "order11")
+	stacked := strings.Join(strings.Fields(out.String()), " ")
+	buyXGetYErr := sstestctl("coupon", "create", "-kind", "B", "-buy", "1", "-free", "1", "-products", "prod3", "-stock", "5",
+		"-start", "2000-01-01", "-end", "2100-01-01", "pair")
+	buyXGetY := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("coupon", "activate", "pair")
+	sstestctl("product", "set", "-stock", "10", "prod3")
+	sstestctl("order", "create", "order12")
+	sstestctl("order", "add", "order12", "prod3", "2")
+	freeItemErr := sstestctl("order", "quote", "-coupon", "pair", "order12")
+	freeItemQuoted := strings.Join(strings.Fields(out.String()), " ")
+	tieredErr := sstestctl("coupon", "create", "-kind", "T", "-tiers", "100:10, 50:5", "spend")
+	tiered := strings.Join(strings.Fields(out.String()), " ")
+	invalidTiersErr := sstestctl("coupon", "create", "-kind", "T", "-tiers", "100", "badtiers")
+	automaticErr := sstestctl("coupon", "create", "-kind", "V", "-value", "5", "-start", "2000-01-01", "-end", "2100-01-01", "-stackable",
+		"-automatic", "-priority", "1", "auto")
+	automatic := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("coupon", "create", "-kind", "V", "-value", "10", "-start", "2000-01-01", "-end", "2100-01-01", "-stackable",
+		"-automatic", "-priority", "2", "-excludes-coupons", "nocoupon")
+	sstestctl("coupon", "activate", "auto")
+	sstestctl("coupon", "activate", "nocoupon")
+	sstestctl("order", "create", "order13")
+	sstestctl("order", "add", "order13", "prod3", "1")
+	promotionsErr := sstestctl("order", "quote", "order13")
+	promotionsQuoted := strings.Join(strings.Fields(out.String()), " ")
+	sstestctl("order", "quote", "-coupon", "five", "order13")
+	couponPromotionsQuoted := strings.Join(strings.Fields(out.String()), " ")
+	promotionsSubmitErr := sstestctl("order", "submit", "order13")
+	promotionsSubmitted := strings.Join(strings.Fields(out.String()), " ")
+
+	db, _ := sqlite.Open(path)
+	defer db.Close()
+	store := sqlite.NewStore(db)
+	storedProduct, _ := store.Products.FindByID("prod1")
+	storedCoupon, _ := store.Coupons.FindByCode("SAVE150")
+	storedUser, _ := store.Users.FindByID("user1")
+	storedOrder, _ := store.Orders.FindByID("order1")
+	storedShippedOrder, _ := store.Orders.FindByID("order4")
+	storedTaxedOrder, _ := store.Orders.FindByID("order5")
+	passwordOk, _ := storedUser.ValidatePassword("newSecret")
+
+	var lifecycleTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Product Stock", int64(17), storedProduct.Stock()},
+		{"Coupon Kind", "P", storedCoupon.Kind()},
+		{"Coupon Stock", int64(4), storedCoupon.Stock()},
+		{"User Status", user.StatusActive, storedUser.Status()},
+		{"User Password", true, passwordOk},
+		{"Order Status", order.StatusDelivered, storedOrder.Status()},
+		{"Order Amount", "270", storedOrder.Amount().String()},
+		{"Order Subtotal", "300", storedOrder.Subtotal().String()},
+		{"Order Discount", "30", storedOrder.Discount().String()},
+		{"Show Prints Breakdown", true, strings.Contains(strings.Join(strings.Fields(shown), " "), "SUBTOTAL: 300 DISCOUNT: 30 AMOUNT: 270 ")},
+		{"Order Shipping Name", "ship name", storedOrder.ShippingName()},
+		{"Order User", "user1", storedOrder.User().ID()},
+		{"Show Without Error", true, nil == showErr},
+		{"Show Prints Tracking ID", true, strings.Contains(shown, "secondTrackingNo")},
+		{"Show Prints Item Allocation", true, strings.Contains(shown, " main=3\n")},
+		{"Show Prints Shipment Carrier", true, strings.Contains(shown, "dummyCarrier")},
+		{"Show Prints Shipment Items", true, strings.Contains(shown, " prod1=2\n")},
+		{"Order Shipments", 2, len(storedOrder.Shipments())},
+		{"Product Warehouse Stocks", "east=5 main=12", fmt.Sprintf("east=%d main=%d", storedProduct.WarehouseStock("east"), storedProduct.WarehouseStock(product.DefaultWarehouse))},
+		{"User Orders Without Error", true, nil == userOrdersErr},
+		{"User Orders Prints Order", true, strings.Contains(userOrders, "order1")},
+		{"Diagram Without Error", true, nil == dotErr},
+		{"Diagram Prints Graphviz", true, strings.Contains(dot, `"P" -> "DL" [label="finish"];`)},
+		{"Mermaid Diagram Without Error", true, nil == mermaidErr},
+		{"Diagram Prints Mermaid", true, strings.Contains(mermaid, "s_P --> s_DL : finish")},
+		{"Hold Without Error", true, nil == holdErr},
+		{"Show Prints Held Available Stock", true, strings.HasSuffix(strings.Join(strings.Fields(held), " "), " 17 13 east=5 main=12")},
+		{"Delete Without Error", true, nil == deleteErr},
+		{"Deleted Order Hold Must Be Released", true, strings.HasSuffix(strings.Join(strings.Fields(released), " "), " 17 17 east=5 main=12")},
+		{"Sweep Without Error", true, nil == sweepErr},
+		{"Sweep Prints Released Holds", "released 0 expired holds\n", swept},
+		{"Periodic Sweep Without Error", true, nil == periodicSweepErr},
+		{"Periodic Sweep Prints Its Interval And Stop", "sweeping expired holds every 5ms\nstopped sweeping\n", periodicallySwept},
+		{"Show Prints Finish Tracking Events", true, strings.Contains(shown, " "+order.TrackingDelivered+" ")},
+		{"Track Without Error", true, nil == trackErr},
+		{"Track Prints Shipping Status", true, strings.Contains(tracked, "SHIPPING STATUS: "+order.ShipStatusInTransit+" ")},
+		{"Track Prints Tracking Event", true, strings.Contains(tracked, " "+order.TrackingInTransit+" Hub")},
+		{"Returned Order Cancel Without Error", true, nil == returnedCancelErr},
+		{"Set Shipping Size Without Error", true, nil == sizeErr},
+		{"Set Shipping Size Prints Size", true, strings.Contains(sized, " 100 1.5 30x20x10 ")},
+		{"Quote Without Error", true, nil == quoteErr},
+		{"Quote Prints Breakdown", "SUBTOTAL: 100 COUPONS: PROMOTIONS: DISCOUNT: 0 SHIPPING COST: 8 TAX: 0 AMOUNT: 108", strings.Split(quoted, " PRODUCT ")[0]},
+		{"Quote Prints Breakdown Lines", true, strings.HasSuffix(quoted, "PRODUCT UNIT PRICE QUANTITY SUBTOTAL DISCOUNT TAX prod1 100 1 100 0 0")},
+		{"Submit Prints Shipping Cost", true, strings.Contains(shippedSubmitted, "AMOUNT: 108 SHIPPING COST: 8 ")},
+		{"Submitted Order Amount Must Be Quoted Amount", "108", storedShippedOrder.Amount().String()},
+		{"Submitted Order Shipping Cost", "8", storedShippedOrder.ShippingCost().String()},
+		{"Set Tax Category Without Error", true, nil == taxCategoryErr},
+		{"Set Tax Category Prints Category", true, strings.Contains(taxCategorized, " 30x20x10 reduced ")},
+		{"Tax Quote Without Error", true, nil == taxQuoteErr},
+		{"Tax Quote Prints Taxed Amount", true, strings.Contains(taxQuoted, "TAX: 14.98 AMOUNT: 114.98 ")},
+		{"Tax Submit Without Error", true, nil == taxSubmitErr},
+		{"Tax Submit Prints Tax", true, strings.Contains(taxSubmitted, "TAX: 14.98 SHIPPING REGION: CA-QC ")},
+		{"Tax Submit Prints Taxes", true, strings.Contains(taxSubmitted, "TAX RATE AMOUNT GST 0.05 5 QST 0.09975 9.98")},
+		{"Taxed Order Amount", "114.98", storedTaxedOrder.Amount().String()},
+		{"Taxed Order Item Taxes", 2, len(storedTaxedOrder.Items()["prod1"].Taxes())},
+		{"Show Captured Item Without Error", true, nil == snapshotErr},
+		{"Show Prints Captured Item", true, strings.Contains(snapshot, " prod1 Product One A 100 3 300 ")},
+		{"Captured Item Unit Price", "100", storedOrder.Items()["prod1"].UnitPrice().String()},
+		{"Show Prints Product Currency", true, strings.Contains(euroProduct, " prod2 Product Two A EUR 1234.5 ")},
+		{"Mixed Currencies Must Fail", true, errors.Is(mixedErr, order.ErrCurrencyMismatch)},
+		{"Submit Prints Order Currency", true, strings.Contains(euroSubmitted, "CURRENCY: EUR ")},
+		{"Receipt Without Error", true, nil == receiptErr},
+		{"Receipt Prints Localized Line", true, strings.Contains(receipt, " Product Two 1 1.234,50 € 1.234,50 € ")},
+		{"Receipt Prints Localized Total", true, strings.HasSuffix(receipt, " TOTAL 1.234,50 €")},
+		{"Add Converted Product Without Error", true, nil == convertErr},
+		{"Submit Prints Converted Item", true, strings.Contains(converted, " prod2 Product Two A 1543.12 1 1543.12 0 0 1.25 2017-09-15T00:00:00Z ")},
+		{"Submit Prints Converted Amount", true, strings.Contains(converted, "CURRENCY: USD SUBTOTAL: 1543.12 ")},
+		{"Create Coupon With Conditions Without Error", true, nil == conditionErr},
+		{"Create Coupon Prints Conditions", true, strings.HasSuffix(conditioned, " 0 2 20 prod3 0 0 false 0 0 false 0 false")},
+		{"Ineligible Order Quote Must Fail", true, errors.Is(ineligibleErr, coupon.ErrNotEligible)},
+		{"Eligible Order Quote Without Error", true, nil == eligibleErr},
+		{"Quote Prints Capped Discount On Eligible Items", "SUBTOTAL: 170 COUPONS: half=20 PROMOTIONS: DISCOUNT: 20 SHIPPING COST: 0 TAX: 0 AMOUNT: 150", strings.Split(eligibleQuoted, " PRODUCT ")[0]},
+		{"Create Coupon With Limits Without Error", true, nil == limitErr},
+		{"Create Coupon Prints Limits", true, strings.HasSuffix(limited, " 0 0 0 1 3 false 0 0 false 0 false")},
+		{"Redeem Coupon Without Error", true, nil == redeemErr},
+		{"Redeem Coupon Over User Limit Must Fail", true, errors.Is(overLimitErr, coupon.ErrRedemptionLimit)},
+		{"Redeem Coupon After Cancellation Without Error", true, nil == reversedRedeemErr},
+		{"Redemptions Without Error", true, nil == redemptionsErr},
+		{"Redemptions Prints Reversed Redemption", true, strings.HasPrefix(redemptions, "ORDER USER AMOUNT CURRENCY REDEEMED REVERSED order9 user1 10 USD ")},
+		{"Redemptions Prints Redemption", true, strings.Contains(redemptions, " order10 user1 10 USD ")},
+		{"Redemptions Count", 2, strings.Count(redemptions, " user1 ")},
+		{"Create Stackable Coupon Without Error", true, nil == stackableErr},
+		{"Create Coupon Prints Stackable", true, strings.HasSuffix(stackable, " 0 0 0 0 0 true 0 0 false 0 false")},
+		{"Value First Quote Without Error", true, nil == valueFirstErr},
+		{"Value First Quote Prints Coupons", "SUBTOTAL: 100 COUPONS: five=5 tenoff=9.5 PROMOTIONS: DISCOUNT: 14.5 SHIPPING COST: 0 TAX: 0 AMOUNT: 85.5", strings.Split(valueFirstQuoted, " PRODUCT ")[0]},
+		{"Submit With Exclusive Coupon Must Fail", true, errors.Is(exclusiveErr, coupon.ErrNotCombinable)},
+		{"Submit With Stacked Coupons Without Error", true, nil == stackedErr},
+		{"Submit Prints Coupons In Application Order", true, strings.Contains(stacked, "COUPONS: tenoff=10 five=5 PROMOTIONS: CURRENCY: USD SUBTOTAL: 100 DISCOUNT: 15 AMOUNT: 85 ")},
+		{"Create Buy X Get Y Coupon Without Error", true, nil == buyXGetYErr},
+		{"Create Coupon Prints Buy X Get Y", true, strings.HasSuffix(buyXGetY, " prod3 0 0 false 1 1 false 0 false")},
+		{"Buy X Get Y Quote Without Error", true, nil == freeItemErr},
+		{"Buy X Get Y Quote Prints Free Item", "SUBTOTAL: 100 COUPONS: pair=50 PROMOTIONS: DISCOUNT: 50 SHIPPING COST: 0 TAX: 0 AMOUNT: 50", strings.Split(freeItemQuoted, " PRODUCT ")[0]},
+		{"Create Tiered Coupon Without Error", true, nil == tieredErr},
+		{"Create Coupon Prints Tiers", true, strings.HasSuffix(tiered, " false 0 0 50:5,100:10 false 0 false")},
+		{"Create Coupon With Invalid Tiers Must Fail", true, nil != invalidTiersErr},
+		{"Create Automatic Promotion Without Error", true, nil == automaticErr},
+		{"Create Coupon Prints Automatic", true, strings.HasSuffix(automatic, " true 0 0 true 1 false")},
+		{"Promotions Quote Without Error", true, nil == promotionsErr},
+		{"Promotions Quote Prints Promotions", "SUBTOTAL: 50 COUPONS: PROMOTIONS: nocoupon=10 auto=5 DISCOUNT: 15 SHIPPING COST: 0 TAX: 0 AMOUNT: 35", strings.Split(promotionsQuoted, " PRODUCT ")[0]},
+		{"Coupon Quote Skips Excluding Promotion", "SUBTOTAL: 50 COUPONS: five=5 PROMOTIONS: auto=5 DISCOUNT: 10 SHIPPING COST: 0 TAX: 0 AMOUNT: 40", strings.Split(couponPromotionsQuoted, " PRODUCT ")[0]},
+		{"Promotions Submit Without Error", true, nil == promotionsSubmitErr},
+		{"Submit Prints Promotions", true, strings.Contains(promotionsSubmitted, "PROMOTIONS: nocoupon=10 auto=5 CURRENCY: USD SUBTOTAL: 50 DISCOUNT: 15 AMOUNT: 35 ")},
+	}
+
+	for _, test := range lifecycleTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sstest.db")
+	sstestctl := func(args ...string) *errors.Error {
+		var out bytes.Buffer
+		return run(append([]string{"-db", path}, args...), strings.NewReader(""), &out)
+	}
+	sstestctl("product", "create", "-stock", "1", "-status", product.StatusAvailable, "prod1", "Product One")
+	sstestctl("order", "create", "order1")
+
+	var commandErrorTests = []struct {
+		testCase   string
+		err        *errors.Error
+		isNotFound bool
+	}{
+		{"Unknown Resource", sstestctl("invoice", "list"), false},
+		{"Unknown Command", sstestctl("product", "explode"), false},
+		{"Missing Arguments", sstestctl("product", "create", "prod2"), false},
+		{"Unknown Flag", sstestctl("product", "set", "-colour", "red", "prod1"), false},
+		{"Invalid Price", sstestctl("product", "set", "-price", "-1", "prod1"), false},
+		{"Invalid Status", sstestctl("product", "set", "-status", "X", "prod1"), false},
+		{"Warehouse Without Stock", sstestctl("product", "set", "-warehouse", "east", "prod1"), false},
+		{"Duplicate Product", sstestctl("product", "create", "prod1", "Product One"), false},
+		{"Negative Weight", sstestctl("product", "set", "-weight", "-1", "prod1"), false},
+		{"Invalid Dimensions", sstestctl("product", "set", "-dimensions", "30x20", "prod1"), false},
+		{"Negative Sweep Interval", sstestctl("product", "sweep", "-every", "-1s"), false},
+		{"Invalid Shipping Rate", sstestctl("-shipping", "free-over:50", "order", "show", "order1"), false},
+		{"Unknown Shipping Currency", sstestctl("-shipping", "flat:5", "-shipping-currency", "XYZ", "order", "show", "order1"), false},
+		{"Quote Without Item", sstestctl("order", "quote", "order1"), false},
+		{"Missing Tax Table", sstestctl("-taxes", filepath.Join(filepath.Dir(path), "missing.json"), "order", "show", "order1"), false},
+		{"Empty Tax Category", sstestctl("product", "set", "-tax-category", "", "prod1"), false},
+		{"Unknown Currency", sstestctl("product", "set", "-currency", "XYZ", "prod1"), false},
+		{"Unknown Locale", sstestctl("-locale", "xx-XX", "order", "show", "order1"), false},
+		{"Receipt Of Draft Order", sstestctl("order", "receipt", "order1"), false},
+		{"Missing Rates File", sstestctl("-rates", filepath.Join(filepath.Dir(path), "missing.json"), "order", "show", "order1"), false},
+		{"Unknown Rounding", sstestctl("-rounding", "sideways", "order", "show", "order1"), false},
+		{"Unknown Stacking", sstestctl("-stacking", "sideways", "order", "show", "order1"), false},
+		{"Unknown Order Currency", sstestctl("order", "create", "-currency", "XYZ", "order2"), false},
+		{"Percentage Over 100", sstestctl("coupon", "create", "-kind", "P", "-value", "100", "BIG"), false},
+		{"Invalid Date", sstestctl("coupon", "create", "-start", "yesterday", "BADDATE"), false},
+		{"Negative Minimum Quantity", sstestctl("coupon", "create", "-min-quantity", "-1", "NEGATIVE"), false},
+		{"Invalid Maximum Discount", sstestctl("coupon", "create", "-max-discount", "lots", "NOCAP"), false},
+		{"Negative User Limit", sstestctl("coupon", "create", "-user-limit", "-1", "NOLIMIT"), false},
+		{"Insufficient Stock", sstestctl("order", "add", "order1", "prod1", "2"), false},
+		{"Invalid Quantity", sstestctl("order", "add", "order1", "prod1", "two"), false},
+		{"Submit Without Item", sstestctl("order", "submit", "order1"), false},
+		{"Invalid Ship Item", sstestctl("order", "ship", "-item", "prod1", "order1", "dummyTrackingNo"), false},
+		{"Ship Draft Order", sstestctl("order", "ship", "order1", "dummyTrackingNo"), false},
+		{"Deliver Missing Shipment", sstestctl("order", "deliver", "order1", "shipment1"), false},
+		{"Track Missing Shipment", sstestctl("order", "track", "order1", "shipment1", order.TrackingInTransit), false},
+		{"Invalid Tracking Date", sstestctl("order", "track", "-date", "tomorrow", "order1", "shipment1", order.TrackingInTransit), false},
+		{"Fire Unknown Event", sstestctl("order", "fire", "order1", "unknown"), false},
+		{"Unknown Diagram Format", sstestctl("order", "diagram", "-format", "png"), false},
+		{"Missing Product", sstestctl("product", "show", "prod2"), true},
+		{"Missing Order", sstestctl("order", "process", "order2"), true},
+		{"Redemptions Of Missing Coupon", sstestctl("coupon", "redemptions", "NOLIMIT"), true},
+		{"Order Of Missing User", sstestctl("order", "create", "-user", "user1", "order2"), true},
+	}
+
+	for _, test := range commandErrorTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if nil == test.err {
+				t.Fatal("expected error but got none\n")
+			}
+			if test.isNotFound != errors.Is(test.err, repository.ErrNotFound) {
+				t.Errorf("want not found %v, got error %v", test.isNotFound, test.err)
+			}
+		})
+	}
+}
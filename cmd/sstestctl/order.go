@@ -0,0 +1,716 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/repository"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+//orderCommands are the commands of the order resource
+var orderCommands = map[string]command{
+	"create":  createOrder,
+	"add":     addOrderProduct,
+	"edit":    editOrderProduct,
+	"remove":  removeOrderProduct,
+	"submit":  submitOrder,
+	"quote":   quoteOrder,
+	"receipt": receiptOrder,
+	"process": orderActionCommand("order process", (*order.Order).Process),
+	"cancel":  cancelOrder,
+	"ship":    shipOrder,
+	"deliver": deliverShipment,
+	"track":   trackShipment,
+	"finish":  orderActionCommand("order finish", (*order.Order).FinishOrder),
+	"fire":    fireOrderEvent,
+	"diagram": orderDiagram,
+	"show":    showOrder,
+	"list":    listOrders,
+	"delete":  deleteOrder,
+}
+
+//createOrder creates a new draft order (with a generated id when none is given), optionally of a user and in a currency
+func createOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order create", flag.ContinueOnError)
+	userID := flags.String("user", "", "user id")
+	currency := flags.String("currency", "", "order currency (ISO 4217 code, the first product's currency without exchange rates)")
+	args, err := parse(flags, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	id := uuid.New().String()
+	if 1 == len(args) {
+		id = args[0]
+	}
+	if _, err := store.Orders.FindByID(id); nil == err {
+		return repository.Duplicate("order", id)
+	} else if false == errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	o := order.New(id)
+	if "" != *userID {
+		u, err := store.Users.FindByID(*userID)
+		if err != nil {
+			return err
+		}
+		o.SetUser(u)
+	}
+	if "" != *currency {
+		if _, err := o.ChangeCurrency(*currency); err != nil {
+			return err
+		}
+	}
+	if err := store.Orders.Save(o); err != nil {
+		return err
+	}
+	printOrder(out, o)
+	return nil
+}
+
+//parseQuantity parses an item quantity argument
+func parseQuantity(value string) (int, *errors.Error) {
+	quantity, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("Can't read quantity %v: %v", value, err), 0)
+	}
+	return quantity, nil
+}
+
+//addOrderProduct adds a quantity of a product to a draft order
+func addOrderProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order add", flag.ContinueOnError), args, 3, 3)
+	if err != nil {
+		return err
+	}
+	quantity, err := parseQuantity(args[2])
+	if err != nil {
+		return err
+	}
+	p, err := store.Products.FindByID(args[1])
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		_, err := o.SetInventory(store.Products).AddProduct(p, quantity)
+		return err
+	})
+}
+
+//editOrderProduct adds a quantity to a product of a draft order
+func editOrderProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order edit", flag.ContinueOnError), args, 3, 3)
+	if err != nil {
+		return err
+	}
+	quantity, err := parseQuantity(args[2])
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		item, ok := o.Items()[args[1]]
+		if false == ok {
+			return errors.WrapPrefix(order.ErrItemNotFound, fmt.Sprintf("Can't edit, order %v has no product with id: %v", o.ID(), args[1]), 0)
+		}
+		_, err := o.SetInventory(store.Products).EditProduct(item.Product(), quantity)
+		return err
+	})
+}
+
+//removeOrderProduct deletes a product from a draft order
+func removeOrderProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order remove", flag.ContinueOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		item, ok := o.Items()[args[1]]
+		if false == ok {
+			return errors.WrapPrefix(order.ErrItemNotFound, fmt.Sprintf("Can't delete, order %v has no product with id: %v", o.ID(), args[1]), 0)
+		}
+		_, err := o.SetInventory(store.Products).DeleteProduct(item.Product())
+		return err
+	})
+}
+
+//submitOrder submits a draft order, decrementing product stocks in the product repository
+func submitOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order submit", flag.ContinueOnError)
+	name := flags.String("name", "", "shipping name")
+	address := flags.String("address", "", "shipping address")
+	region := flags.String("region", "", "shipping tax region")
+	var codes codesFlag
+	flags.Var(&codes, "coupon", "coupon code (repeatable, several coupons must all be stackable)")
+	args, err := parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	coupons, err := findCoupons(store, codes)
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		if "" != *region {
+			o.SetShippingRegion(*region)
+		}
+		_, err := o.SetInventory(store.Products).SetLedger(store.Coupons).SetPromotions(store.Coupons).SetSubmitter(store.Orders).Submit(*name, *address, coupons...)
+		return err
+	})
+}
+
+//quoteOrder prints a draft order's price breakdown as submitted with the running automatic promotions, optional coupons and shipping tax region
+func quoteOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order quote", flag.ContinueOnError)
+	var codes codesFlag
+	flags.Var(&codes, "coupon", "coupon code (repeatable, several coupons must all be stackable)")
+	region := flags.String("region", "", "shipping tax region (the order's one by default)")
+	args, err := parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	o, err := store.Orders.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	coupons, err := findCoupons(store, codes)
+	if err != nil {
+		return err
+	}
+	b, err := o.SetPromotions(store.Coupons).Quote(*region, coupons...)
+	if err != nil {
+		return err
+	}
+	printBreakdown(out, b)
+	return nil
+}
+
+//printBreakdown prints a price breakdown's totals followed by its lines
+func printBreakdown(out io.Writer, b *order.Breakdown) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "SUBTOTAL:\t%v\n", b.Subtotal())
+	fmt.Fprintf(w, "COUPONS:\t%v\n", formatApplied(b.AppliedCoupons()))
+	fmt.Fprintf(w, "PROMOTIONS:\t%v\n", formatApplied(b.AppliedPromotions()))
+	fmt.Fprintf(w, "DISCOUNT:\t%v\n", b.Discount())
+	fmt.Fprintf(w, "SHIPPING COST:\t%v\n", b.ShippingCost())
+	if b.TaxIncluded() {
+		fmt.Fprintf(w, "TAX:\t%v (included)\n", b.Tax())
+	} else {
+		fmt.Fprintf(w, "TAX:\t%v\n", b.Tax())
+	}
+	fmt.Fprintf(w, "AMOUNT:\t%v\n", b.Total())
+	w.Flush()
+
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PRODUCT\tUNIT PRICE\tQUANTITY\tSUBTOTAL\tDISCOUNT\tTAX")
+	for _, l := range b.Lines() {
+		fmt.Fprintf(w, "%v\t%v\t%d\t%v\t%v\t%v\n", l.ProductID(), l.UnitPrice(), l.Quantity(), l.Subtotal(), l.Discount(), l.Tax())
+	}
+	w.Flush()
+}
+
+//formatApplied formats applied coupons (or automatic promotions) as code=discount pairs in their application order
+func formatApplied(applied []*order.AppliedCoupon) string {
+	pairs := make([]string, 0, len(applied))
+	for _, a := range applied {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", a.Coupon().ID(), a.Discount()))
+	}
+	return strings.Join(pairs, " ")
+}
+
+//receiptOrder prints the receipt of a submitted order, its amounts formatted in the default locale (see -locale)
+func receiptOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order receipt", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	o, err := store.Orders.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	if order.StatusDraft == o.Status() {
+		return errors.WrapPrefix(order.ErrInvalidStatus, fmt.Sprintf("Can't print receipt of order %v, it is not submitted", o.ID()), 0)
+	}
+	format := func(amount decimal.Decimal) string {
+		return money.Format(amount, o.Currency())
+	}
+	b := o.Breakdown()
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ORDER:\t%v\n", o.ID())
+	fmt.Fprintf(w, "SUBMITTED:\t%v\n", formatDate(o.SubmittedDate()))
+	fmt.Fprintf(w, "SHIP TO:\t%v, %v\n", o.ShippingName(), o.ShippingAddress())
+	w.Flush()
+
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PRODUCT\tQUANTITY\tUNIT PRICE\tAMOUNT\t")
+	for _, l := range b.Lines() {
+		fmt.Fprintf(w, "%v\t%d\t%v\t%v\t\n", o.Items()[l.ProductID()].ProductName(), l.Quantity(), format(l.UnitPrice()), format(l.Subtotal()))
+	}
+	fmt.Fprintf(w, "SUBTOTAL\t\t\t%v\t\n", format(b.Subtotal()))
+	fmt.Fprintf(w, "DISCOUNT\t\t\t%v\t\n", format(b.Discount().Neg()))
+	fmt.Fprintf(w, "SHIPPING\t\t\t%v\t\n", format(b.ShippingCost()))
+	if b.TaxIncluded() {
+		fmt.Fprintf(w, "TAX (included)\t\t\t%v\t\n", format(b.Tax()))
+	} else {
+		fmt.Fprintf(w, "TAX\t\t\t%v\t\n", format(b.Tax()))
+	}
+	fmt.Fprintf(w, "TOTAL\t\t\t%v\t\n", format(b.Total()))
+	w.Flush()
+	return nil
+}
+
+//parseShippingRate parses a shipping rate specification of costs in a currency, free shipping when empty:
+//
+//	flat:COST                       the same cost for every order
+//	weight:BASE:PERKG[:DIVISOR]     a base cost plus a cost per billable kilogram
+//	free-over:THRESHOLD:SPEC        free shipping from a subtotal, rated by another specification otherwise
+func parseShippingRate(spec, currency string) (order.ShippingRateProvider, *errors.Error) {
+	if "" == currency {
+		currency = money.DefaultCurrency
+	}
+	if false == money.IsCurrency(currency) {
+		return nil, errors.Wrap(fmt.Errorf("Can't read shipping rate currency %v, expected one of: %v", currency, strings.Join(money.Currencies(), ", ")), 0)
+	}
+	if "" == spec {
+		return nil, nil
+	}
+	parts := strings.SplitN(spec, ":", 2)
+	params := ""
+	if 2 == len(parts) {
+		params = parts[1]
+	}
+	switch parts[0] {
+	case "flat":
+		if values, err := parseDecimals(spec, params); err != nil {
+			return nil, err
+		} else if 1 == len(values) {
+			return order.NewFlatRate(values[0], currency), nil
+		}
+	case "weight":
+		if values, err := parseDecimals(spec, params); err != nil {
+			return nil, err
+		} else if 2 == len(values) {
+			return order.NewWeightRate(values[0], values[1], decimal.New(0, 0), currency), nil
+		} else if 3 == len(values) {
+			return order.NewWeightRate(values[0], values[1], values[2], currency), nil
+		}
+	case "free-over":
+		thresholdSpec := strings.SplitN(params, ":", 2)
+		if 2 != len(thresholdSpec) {
+			break
+		}
+		values, err := parseDecimals(spec, thresholdSpec[0])
+		if err != nil {
+			return nil, err
+		}
+		otherwise, err := parseShippingRate(thresholdSpec[1], currency)
+		if err != nil {
+			return nil, err
+		}
+		if nil != otherwise {
+			return order.NewFreeOverThreshold(values[0], currency, otherwise), nil
+		}
+	}
+	return nil, errors.Wrap(fmt.Errorf("Can't read shipping rate %v, expected flat:COST, weight:BASE:PERKG[:DIVISOR] or free-over:THRESHOLD:SPEC", spec), 0)
+}
+
+//parseDecimals parses the colon separated decimal values of a shipping rate specification
+func parseDecimals(spec, params string) ([]decimal.Decimal, *errors.Error) {
+	values := make([]decimal.Decimal, 0)
+	for _, param := range strings.Split(params, ":") {
+		value, err := decimal.NewFromString(param)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read shipping rate %v value %v: %v", spec, param, err), 0)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+//taxTableFile is the JSON representation of a tax table file
+type taxTableFile struct {
+	Regions []struct {
+		Code      string   `json:"code"`
+		Inclusive bool     `json:"inclusive"`
+		Exempt    []string `json:"exempt"`
+		Rules     []struct {
+			Name     string          `json:"name"`
+			Category string          `json:"category"`
+			Rate     decimal.Decimal `json:"rate"`
+			Compound bool            `json:"compound"`
+		} `json:"rules"`
+	} `json:"regions"`
+}
+
+//loadTaxTable reads a tax table from a JSON file (no file is an empty tax table, orders are not taxed)
+func loadTaxTable(path string) (*order.TaxTable, *errors.Error) {
+	if "" == path {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read tax table %v: %v", path, err), 0)
+	}
+	var file taxTableFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read tax table %v: %v", path, err), 0)
+	}
+	regions := make([]*order.TaxRegion, 0, len(file.Regions))
+	for _, r := range file.Regions {
+		if "" == r.Code {
+			return nil, errors.Wrap(fmt.Errorf("Can't read tax table %v: region without code", path), 0)
+		}
+		rules := make([]*order.TaxRule, 0, len(r.Rules))
+		for _, rule := range r.Rules {
+			if "" == rule.Name || rule.Rate.IsNegative() {
+				return nil, errors.Wrap(fmt.Errorf("Can't read tax table %v: region %v rule needs a name and a non negative rate", path, r.Code), 0)
+			}
+			rules = append(rules, order.NewTaxRule(rule.Name, rule.Category, rule.Rate, rule.Compound))
+		}
+		regions = append(regions, order.NewTaxRegion(r.Code, r.Inclusive, rules...).Exempt(r.Exempt...))
+	}
+	return order.NewTaxTable(regions...), nil
+}
+
+//loadExchangeRates checks a rate table file and returns the exchange rate provider reading it on every quote
+func loadExchangeRates(path string) (money.ExchangeRateProvider, *errors.Error) {
+	if "" == path {
+		return nil, nil
+	}
+	if _, err := money.LoadRateTable(path); err != nil {
+		return nil, err
+	}
+	return money.NewFileRates(path), nil
+}
+
+//cancelOrder cancels a submitted or processed order, returning its product stocks to the product repository and its coupons' uses
+func cancelOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order cancel", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		_, err := o.SetCanceler(store.Orders).Cancel()
+		return err
+	})
+}
+
+//quantitiesFlag is a repeatable flag of product quantities given as productId=quantity
+type quantitiesFlag map[string]int
+
+//String returns the quantities of the flag as productId=quantity pairs (sorted by product id)
+func (f quantitiesFlag) String() string {
+	productIDs := make([]string, 0, len(f))
+	for productID := range f {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+	pairs := make([]string, 0, len(f))
+	for _, productID := range productIDs {
+		pairs = append(pairs, fmt.Sprintf("%v=%d", productID, f[productID]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+//Set adds a productId=quantity pair to the flag
+func (f quantitiesFlag) Set(value string) error {
+	pair := strings.SplitN(value, "=", 2)
+	if 2 != len(pair) || "" == pair[0] {
+		return fmt.Errorf("expected productId=quantity, got %v", value)
+	}
+	quantity, err := strconv.Atoi(pair[1])
+	if err != nil {
+		return fmt.Errorf("can't read quantity %v of product %v", pair[1], pair[0])
+	}
+	f[pair[0]] = quantity
+	return nil
+}
+
+//codesFlag is a repeatable flag of coupon codes
+type codesFlag []string
+
+//String returns the coupon codes of the flag separated by spaces
+func (f *codesFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+//Set adds a coupon code to the flag
+func (f *codesFlag) Set(value string) error {
+	if "" == value {
+		return fmt.Errorf("expected a coupon code")
+	}
+	*f = append(*f, value)
+	return nil
+}
+
+//findCoupons finds the coupons having the given codes (in their given order)
+func findCoupons(store *repository.Store, codes []string) ([]*coupon.Coupon, *errors.Error) {
+	coupons := make([]*coupon.Coupon, 0, len(codes))
+	for _, code := range codes {
+		c, err := store.Coupons.FindByCode(code)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, c)
+	}
+	return coupons, nil
+}
+
+//shipOrder ships quantities of a processed order in a new shipment (every unshipped quantity when no item is given)
+func shipOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order ship", flag.ContinueOnError)
+	carrier := flags.String("carrier", "", "shipment carrier")
+	items := make(quantitiesFlag)
+	flags.Var(items, "item", "shipped quantity of a product as productId=quantity (repeatable)")
+	args, err := parse(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		_, err := o.Ship(*carrier, args[1], items)
+		return err
+	})
+}
+
+//deliverShipment marks a shipment of an order as delivered (finishing the order once every shipment is delivered)
+func deliverShipment(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order deliver", flag.ContinueOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		_, err := o.DeliverShipment(args[1])
+		return err
+	})
+}
+
+//trackShipment records a carrier tracking event of a shipment of an order (dated now unless a date is given)
+func trackShipment(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order track", flag.ContinueOnError)
+	date := flags.String("date", "", "event date (2006-01-02 or RFC 3339, default now)")
+	location := flags.String("location", "", "event location")
+	description := flags.String("description", "", "event description")
+	args, err := parse(flags, args, 3, 3)
+	if err != nil {
+		return err
+	}
+	eventDate := time.Now()
+	if "" != *date {
+		if eventDate, err = parseDate(*date); err != nil {
+			return err
+		}
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		_, err := o.TrackShipment(args[1], order.NewTrackingEvent(args[2], eventDate, *location, *description))
+		return err
+	})
+}
+
+//orderActionCommand returns the command applying a status changing action on an order
+func orderActionCommand(name string, action func(o *order.Order) (bool, *errors.Error)) command {
+	return func(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+		args, err := parse(flag.NewFlagSet(name, flag.ContinueOnError), args, 1, 1)
+		if err != nil {
+			return err
+		}
+		return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+			_, err := action(o)
+			return err
+		})
+	}
+}
+
+//fireOrderEvent fires another event of the order state machine on an order (e.g. a custom status change)
+func fireOrderEvent(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order fire", flag.ContinueOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	return updateOrder(store, out, args[0], func(o *order.Order) *errors.Error {
+		_, err := o.Fire(args[1])
+		return err
+	})
+}
+
+//orderDiagram prints the order state machine as a Graphviz (dot) or Mermaid diagram
+func orderDiagram(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order diagram", flag.ContinueOnError)
+	format := flags.String("format", "dot", "diagram format (dot or mermaid)")
+	if _, err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	switch *format {
+	case "dot":
+		fmt.Fprint(out, order.DefaultStateMachine().Graphviz())
+	case "mermaid":
+		fmt.Fprint(out, order.DefaultStateMachine().Mermaid())
+	default:
+		return errors.Wrap(fmt.Errorf("order diagram: unknown format %v, expected dot or mermaid", *format), 0)
+	}
+	return nil
+}
+
+//showOrder prints an order
+func showOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order show", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	o, err := store.Orders.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	printOrder(out, o)
+	return nil
+}
+
+//listOrders prints the orders having a status
+func listOrders(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("order list", flag.ContinueOnError)
+	status := flags.String("status", order.StatusDraft, "order status")
+	if _, err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	orders, err := store.Orders.FindByStatus(*status)
+	if err != nil {
+		return err
+	}
+	printOrders(out, orders)
+	return nil
+}
+
+//printOrders prints a list of orders
+func printOrders(out io.Writer, orders []*order.Order) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tCREATED\tITEMS\tAMOUNT")
+	for _, o := range orders {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%d\t%v\n", o.ID(), o.Status(), formatDate(o.CreatedDate()), len(o.Items()), o.Amount())
+	}
+	w.Flush()
+}
+
+//deleteOrder deletes an order, releasing its stock holds
+func deleteOrder(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("order delete", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if err := store.Orders.Delete(args[0]); err != nil {
+		return err
+	}
+	return store.Products.Release(args[0], "")
+}
+
+//updateOrder loads an order, applies a change on it, saves it and prints it
+func updateOrder(store *repository.Store, out io.Writer, id string, change func(o *order.Order) *errors.Error) *errors.Error {
+	o, err := store.Orders.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := change(o); err != nil {
+		return err
+	}
+	if err := store.Orders.Save(o); err != nil {
+		return err
+	}
+	printOrder(out, o)
+	return nil
+}
+
+//printOrder prints an order's details followed by its items, taxes, shipments and their tracking events
+func printOrder(out io.Writer, o *order.Order) {
+	var userID string
+	if o.User() != nil {
+		userID = o.User().ID()
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%v\n", o.ID())
+	fmt.Fprintf(w, "STATUS:\t%v\n", o.Status())
+	fmt.Fprintf(w, "EVENTS:\t%v\n", strings.Join(o.AllowedEvents(), ", "))
+	fmt.Fprintf(w, "USER:\t%v\n", userID)
+	fmt.Fprintf(w, "CREATED:\t%v\n", formatDate(o.CreatedDate()))
+	fmt.Fprintf(w, "SUBMITTED:\t%v\n", formatDate(o.SubmittedDate()))
+	fmt.Fprintf(w, "PROCESSED:\t%v\n", formatDate(o.ProcessedDate()))
+	fmt.Fprintf(w, "COUPONS:\t%v\n", formatApplied(o.AppliedCoupons()))
+	fmt.Fprintf(w, "PROMOTIONS:\t%v\n", formatApplied(o.AppliedPromotions()))
+	fmt.Fprintf(w, "CURRENCY:\t%v\n", o.Currency())
+	fmt.Fprintf(w, "SUBTOTAL:\t%v\n", o.Subtotal())
+	fmt.Fprintf(w, "DISCOUNT:\t%v\n", o.Discount())
+	fmt.Fprintf(w, "AMOUNT:\t%v\n", o.Amount())
+	fmt.Fprintf(w, "SHIPPING COST:\t%v\n", o.ShippingCost())
+	if o.TaxIncluded() {
+		fmt.Fprintf(w, "TAX:\t%v (included)\n", o.Tax())
+	} else {
+		fmt.Fprintf(w, "TAX:\t%v\n", o.Tax())
+	}
+	fmt.Fprintf(w, "SHIPPING REGION:\t%v\n", o.ShippingRegion())
+	fmt.Fprintf(w, "SHIPPING STATUS:\t%v\n", o.ShippingStatus())
+	fmt.Fprintf(w, "SHIPPING NAME:\t%v\n", o.ShippingName())
+	fmt.Fprintf(w, "SHIPPING ADDRESS:\t%v\n", o.ShippingAddress())
+	fmt.Fprintf(w, "TRACKING ID:\t%v\n", o.ShippingTrackingID())
+	w.Flush()
+
+	productIDs := make([]string, 0, len(o.Items()))
+	for productID := range o.Items() {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PRODUCT\tNAME\tSTATUS\tPRICE\tQUANTITY\tSUBTOTAL\tDISCOUNT\tTAX\tEXCHANGE RATE\tRATE DATE\tALLOCATION")
+	for _, productID := range productIDs {
+		item := o.Items()[productID]
+		allocation := make([]string, 0, len(item.Allocation()))
+		for _, warehouse := range item.Allocation().Warehouses() {
+			allocation = append(allocation, fmt.Sprintf("%v=%d", warehouse, item.Allocation()[warehouse]))
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%d\t%v\t%v\t%v\t%v\t%v\t%v\n", productID, item.ProductName(), item.ProductStatus(), item.UnitPrice(), item.Quantity(),
+			item.Subtotal(), item.Discount(), item.Tax(), item.ExchangeRate(), formatDate(item.RateDate()), strings.Join(allocation, " "))
+	}
+	w.Flush()
+
+	if 0 != len(o.Taxes()) {
+		w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "TAX\tRATE\tAMOUNT")
+		for _, t := range o.Taxes() {
+			fmt.Fprintf(w, "%v\t%v\t%v\n", t.Name(), t.Rate(), t.Amount())
+		}
+		w.Flush()
+	}
+
+	if 0 == len(o.Shipments()) {
+		return
+	}
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SHIPMENT\tCARRIER\tTRACKING ID\tSTATUS\tSHIPPED\tDELIVERED\tITEMS")
+	for _, s := range o.Shipments() {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", s.ID(), s.Carrier(), s.TrackingID(), s.Status(),
+			formatDate(s.ShippedDate()), formatDate(s.DeliveredDate()), quantitiesFlag(s.Items()))
+	}
+	w.Flush()
+
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	tracked := false
+	for _, s := range o.Shipments() {
+		for _, e := range s.Events() {
+			if false == tracked {
+				fmt.Fprintln(w, "SHIPMENT\tDATE\tEVENT\tLOCATION\tDESCRIPTION")
+				tracked = true
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", s.ID(), formatDate(e.Date()), e.Kind(), e.Location(), e.Description())
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sstest/model/product"
+	"sstest/repository"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//productCommands are the commands of the product resource
+var productCommands = map[string]command{
+	"create": createProduct,
+	"set":    setProduct,
+	"show":   showProduct,
+	"list":   listProducts,
+	"delete": deleteProduct,
+	"sweep":  sweepHolds,
+}
+
+//productFlags are the flags setting a product's values
+type productFlags struct {
+	*flag.FlagSet
+	name       *string
+	price      *string
+	currency   *string
+	stock      *int64
+	warehouse  *string
+	status     *string
+	weight     *string
+	dimensions *string
+	tax        *string
+}
+
+//newProductFlags declares the flags setting a product's values
+func newProductFlags(name string) productFlags {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	return productFlags{
+		flags,
+		flags.String("name", "", "product name"),
+		flags.String("price", "", "product price (decimal)"),
+		flags.String("currency", "", "product price currency (ISO 4217 code, USD by default)"),
+		flags.Int64("stock", 0, "product stock"),
+		flags.String("warehouse", "", "warehouse of the stock (the stock of every warehouse is replaced when omitted)"),
+		flags.String("status", "", "product status (P for prototype, A for available, D for discontinued)"),
+		flags.String("weight", "", "product shipping weight in kilograms (decimal)"),
+		flags.String("dimensions", "", "product shipping dimensions in centimeters (LxWxH, decimals)"),
+		flags.String("tax-category", "", "product tax category (standard by default)"),
+	}
+}
+
+//apply sets the values of a product given by flags, validated through the product's setters
+func (flags productFlags) apply(p *product.Product) *errors.Error {
+	if isSet(flags.FlagSet, "name") {
+		p.SetName(*flags.name)
+	}
+	if isSet(flags.FlagSet, "price") {
+		price, err := decimal.NewFromString(*flags.price)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read price %v: %v", *flags.price, err), 0)
+		}
+		if _, err := p.SetPrice(price); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "currency") {
+		if _, err := p.SetCurrency(*flags.currency); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "stock") {
+		if *flags.stock < 0 {
+			return errors.Wrap(fmt.Errorf("Can't set negative value %d for stock", *flags.stock), 0)
+		}
+		if isSet(flags.FlagSet, "warehouse") {
+			p.SetWarehouseStock(*flags.warehouse, *flags.stock)
+		} else {
+			p.SetStock(*flags.stock)
+		}
+	} else if isSet(flags.FlagSet, "warehouse") {
+		return errors.Wrap(fmt.Errorf("Can't set warehouse %v without stock", *flags.warehouse), 0)
+	}
+	if isSet(flags.FlagSet, "status") {
+		if _, err := p.SetStatus(*flags.status); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "weight") {
+		weight, err := decimal.NewFromString(*flags.weight)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read weight %v: %v", *flags.weight, err), 0)
+		}
+		if _, err := p.SetWeight(weight); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "dimensions") {
+		sizes := strings.Split(*flags.dimensions, "x")
+		if 3 != len(sizes) {
+			return errors.Wrap(fmt.Errorf("Can't read dimensions %v, expected LxWxH", *flags.dimensions), 0)
+		}
+		dimensions := make([]decimal.Decimal, 3)
+		for i, size := range sizes {
+			var err error
+			if dimensions[i], err = decimal.NewFromString(size); err != nil {
+				return errors.Wrap(fmt.Errorf("Can't read dimensions %v: %v", *flags.dimensions, err), 0)
+			}
+		}
+		if _, err := p.SetDimensions(dimensions[0], dimensions[1], dimensions[2]); err != nil {
+			return err
+		}
+	}
+	if isSet(flags.FlagSet, "tax-category") {
+		if _, err := p.SetTaxCategory(*flags.tax); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//createProduct creates a new product
+func createProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := newProductFlags("product create")
+	args, err := parse(flags.FlagSet, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if _, err := store.Products.FindByID(args[0]); nil == err {
+		return repository.Duplicate("product", args[0])
+	} else if false == errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	p := product.New(args[0], args[1])
+	if err := flags.apply(p); err != nil {
+		return err
+	}
+	if err := store.Products.Save(p); err != nil {
+		return err
+	}
+	return printProducts(store, out, p)
+}
+
+//setProduct sets the values of a product given by flags
+func setProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := newProductFlags("product set")
+	args, err := parse(flags.FlagSet, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := store.Products.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := flags.apply(p); err != nil {
+		return err
+	}
+	//the stored stocks are left unchanged (orders may have been submitted since the product was loaded) unless a stock is given
+	save := store.Products.Update
+	if isSet(flags.FlagSet, "stock") {
+		save = store.Products.Save
+	}
+	if err := save(p); err != nil {
+		return err
+	}
+	return printProducts(store, out, p)
+}
+
+//showProduct prints a product
+func showProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("product show", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := store.Products.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	return printProducts(store, out, p)
+}
+
+//listProducts prints all products
+func listProducts(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	if _, err := parse(flag.NewFlagSet("product list", flag.ContinueOnError), args, 0, 0); err != nil {
+		return err
+	}
+	products, err := store.Products.FindAll()
+	if err != nil {
+		return err
+	}
+	return printProducts(store, out, products...)
+}
+
+//deleteProduct deletes a product
+func deleteProduct(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("product delete", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	return store.Products.Delete(args[0])
+}
+
+//stopSignals are the signals stopping a periodic sweep
+var stopSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+//sweepHolds releases the expired holds of draft orders on product stock, once or with -every at every interval until interrupted
+func sweepHolds(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("product sweep", flag.ContinueOnError)
+	every := flags.Duration("every", 0, "interval of the sweeps")
+	if _, err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *every < 0 {
+		return errors.Wrap(fmt.Errorf("product sweep: invalid interval %v", *every), 0)
+	}
+	if 0 == *every {
+		released, err := store.Products.ReleaseExpired(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "released %d expired holds\n", released)
+		return nil
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, stopSignals...)
+	defer signal.Stop(stop)
+	sweeper := repository.NewSweeper(store.Products, *every).SetErrorHandler(func(err *errors.Error) {
+		fmt.Fprintf(out, "sweep failed: %v\n", err)
+	})
+	sweeper.Start()
+	fmt.Fprintf(out, "sweeping expired holds every %v\n", *every)
+	<-stop
+	sweeper.Stop()
+	fmt.Fprintln(out, "stopped sweeping")
+	return nil
+}
+
+//printProducts prints products as a table with their available-to-sell stock and their stock in every warehouse
+func printProducts(store *repository.Store, out io.Writer, products ...*product.Product) *errors.Error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCURRENCY\tPRICE\tWEIGHT\tDIMENSIONS\tTAX CATEGORY\tSTOCK\tAVAILABLE\tWAREHOUSES")
+	for _, p := range products {
+		available, err := store.Products.Available(p.ID())
+		if err != nil {
+			return err
+		}
+		warehouses := make([]string, 0, len(p.Warehouses()))
+		for _, warehouse := range p.Warehouses() {
+			warehouses = append(warehouses, fmt.Sprintf("%v=%d", warehouse, p.WarehouseStock(warehouse)))
+		}
+		length, width, height := p.Dimensions()
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%vx%vx%v\t%v\t%d\t%d\t%v\n", p.ID(), p.Name(), p.Status(), p.Currency(), p.Price(), p.Weight(), length, width, height,
+			p.TaxCategory(), p.Stock(), available, strings.Join(warehouses, " "))
+	}
+	w.Flush()
+	return nil
+}
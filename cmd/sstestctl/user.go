@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"sstest/model/user"
+	"sstest/repository"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-errors/errors"
+)
+
+//userCommands are the commands of the user resource
+var userCommands = map[string]command{
+	"create":     createUser,
+	"set":        setUser,
+	"password":   setUserPassword,
+	"activate":   userStatusCommand("user activate", (*user.User).Activate),
+	"deactivate": userStatusCommand("user deactivate", (*user.User).Deactivate),
+	"suspend":    userStatusCommand("user suspend", (*user.User).Suspend),
+	"show":       showUser,
+	"list":       listUsers,
+	"orders":     listUserOrders,
+	"delete":     deleteUser,
+}
+
+//createUser creates a new inactive user (with the model's default password when none is given)
+func createUser(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	address := flags.String("address", "", "user address")
+	password := flags.String("password", "", "user password")
+	args, err := parse(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	u, err := user.New(args[0], args[1], *address)
+	if err != nil {
+		return err
+	}
+	if isSet(flags, "password") {
+		if _, err := u.SetPassword(*password); err != nil {
+			return err
+		}
+	}
+	if err := store.Users.Create(u); err != nil {
+		return err
+	}
+	printUsers(out, u)
+	return nil
+}
+
+//setUser sets the name or address of a user given by flags
+func setUser(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	flags := flag.NewFlagSet("user set", flag.ContinueOnError)
+	name := flags.String("name", "", "user name")
+	address := flags.String("address", "", "user address")
+	args, err := parse(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	u, err := store.Users.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	if isSet(flags, "name") {
+		u.SetName(*name)
+	}
+	if isSet(flags, "address") {
+		u.SetAddress(*address)
+	}
+	if err := store.Users.Save(u); err != nil {
+		return err
+	}
+	printUsers(out, u)
+	return nil
+}
+
+//setUserPassword sets a user's password (read from the first line of standard input when given as "-")
+func setUserPassword(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("user password", flag.ContinueOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	password := args[1]
+	if "-" == password {
+		line, readErr := bufio.NewReader(in).ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return errors.Wrap(fmt.Errorf("Can't read password: %v", readErr), 0)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	u, err := store.Users.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := u.SetPassword(password); err != nil {
+		return err
+	}
+	if err := store.Users.Save(u); err != nil {
+		return err
+	}
+	printUsers(out, u)
+	return nil
+}
+
+//userStatusCommand returns the command changing a user's status through one of the user's status methods
+func userStatusCommand(name string, change func(u *user.User)) command {
+	return func(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+		args, err := parse(flag.NewFlagSet(name, flag.ContinueOnError), args, 1, 1)
+		if err != nil {
+			return err
+		}
+		u, err := store.Users.FindByID(args[0])
+		if err != nil {
+			return err
+		}
+		change(u)
+		if err := store.Users.Save(u); err != nil {
+			return err
+		}
+		printUsers(out, u)
+		return nil
+	}
+}
+
+//showUser prints a user
+func showUser(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("user show", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	u, err := store.Users.FindByID(args[0])
+	if err != nil {
+		return err
+	}
+	printUsers(out, u)
+	return nil
+}
+
+//listUsers prints all users
+func listUsers(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	if _, err := parse(flag.NewFlagSet("user list", flag.ContinueOnError), args, 0, 0); err != nil {
+		return err
+	}
+	users, err := store.Users.FindAll()
+	if err != nil {
+		return err
+	}
+	printUsers(out, users...)
+	return nil
+}
+
+//listUserOrders prints the orders of a user
+func listUserOrders(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("user orders", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if _, err := store.Users.FindByID(args[0]); err != nil {
+		return err
+	}
+	orders, err := store.Orders.FindByUser(args[0])
+	if err != nil {
+		return err
+	}
+	printOrders(out, orders)
+	return nil
+}
+
+func deleteUser(store *repository.Store, args []string, in io.Reader, out io.Writer) *errors.Error {
+	args, err := parse(flag.NewFlagSet("user delete", flag.ContinueOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	return store.Users.Delete(args[0])
+}
+
+//printUsers prints users as a table (without their password hash)
+func printUsers(out io.Writer, users ...*user.User) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tADDRESS\tSTATUS")
+	for _, u := range users {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", u.ID(), u.Name(), u.Address(), u.Status())
+	}
+	w.Flush()
+}
@@ -0,0 +1,49 @@
+package coupon
+
+import (
+	"time"
+)
+
+//Automatic is a getter function for returning whether a coupon is an automatic promotion, applied without its code being entered
+func (c *Coupon) Automatic() bool {
+	return c.automatic
+}
+
+//Priority is a getter function for returning the rank of an automatic promotion among the others, the highest applied first
+func (c *Coupon) Priority() int {
+	return c.priority
+}
+
+//ExcludesCoupons is a getter function for returning whether an automatic promotion is skipped on the orders having entered coupons
+func (c *Coupon) ExcludesCoupons() bool {
+	return c.excludesCoupons
+}
+
+//SetAutomatic is a setter function for setting whether a coupon is an automatic promotion
+func (c *Coupon) SetAutomatic(automatic bool) *Coupon {
+	c.automatic = automatic
+	return c
+}
+
+//SetPriority is a setter function for setting the rank of an automatic promotion among the others
+func (c *Coupon) SetPriority(priority int) *Coupon {
+	c.priority = priority
+	return c
+}
+
+//SetExcludesCoupons is a setter function for setting whether an automatic promotion is skipped on the orders having entered coupons
+func (c *Coupon) SetExcludesCoupons(excludesCoupons bool) *Coupon {
+	c.excludesCoupons = excludesCoupons
+	return c
+}
+
+//IsRunning is a function for inquiring whether an automatic promotion is active and runs at a given date
+func (c *Coupon) IsRunning(date time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if false == c.automatic || StatusActive != c.status {
+		return false
+	}
+	return false == date.Before(c.startDate) && false == date.After(c.endDate)
+}
@@ -0,0 +1,47 @@
+package coupon_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"testing"
+	"time"
+)
+
+func TestCouponAutomatic(t *testing.T) {
+	now := time.Now()
+	promotion := coupon.New("AUTO").SetAutomatic(true).SetPriority(3).SetExcludesCoupons(true)
+	promotion.SetStatus(coupon.StatusActive)
+	inactivePromotion := coupon.New("INACTIVE").SetAutomatic(true)
+	enteredCoupon := coupon.New("ENTERED")
+	enteredCoupon.SetStatus(coupon.StatusActive)
+	expiredPromotion := coupon.New("EXPIRED").SetAutomatic(true)
+	expiredPromotion.SetStatus(coupon.StatusActive)
+	expiredPromotion.SetStartDate(now.AddDate(0, 0, -10))
+	expiredPromotion.SetEndDate(now.AddDate(0, 0, -1))
+
+	var automaticTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon Is Entered By Default", false, coupon.New("DEFAULT").Automatic()},
+		{"Automatic Promotion", true, promotion.Automatic()},
+		{"Promotion Priority", 3, promotion.Priority()},
+		{"Promotion Excludes Coupons", true, promotion.ExcludesCoupons()},
+		{"Active Promotion Is Running", true, promotion.IsRunning(now)},
+		{"Promotion Is Not Running Before Start Date", false, promotion.IsRunning(promotion.StartDate().Add(-time.Second))},
+		{"Promotion Is Not Running After End Date", false, promotion.IsRunning(promotion.EndDate().Add(time.Second))},
+		{"Inactive Promotion Is Not Running", false, inactivePromotion.IsRunning(now)},
+		{"Entered Coupon Is Not Running", false, enteredCoupon.IsRunning(now)},
+		{"Expired Promotion Is Not Running", false, expiredPromotion.IsRunning(now)},
+		{"Expired Promotion Status Is Left Unchanged", coupon.StatusActive, expiredPromotion.Status()},
+	}
+
+	for _, test := range automaticTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
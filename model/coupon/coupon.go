@@ -3,6 +3,7 @@ package coupon
 
 import (
 	"fmt"
+	"sstest/model/money"
 	"sync"
 	"time"
 
@@ -36,12 +37,37 @@ const KindValue string = "V"
 //KindPercentage is const for 'percentage' coupon kind/type
 const KindPercentage string = "P"
 
+//KindBuyXGetY is const for 'buy X get Y free' coupon kind/type (see GetLineDiscounts)
+const KindBuyXGetY string = "B"
+
+//KindFreeShipping is const for 'free shipping' coupon kind/type (waiving an order's shipping cost)
+const KindFreeShipping string = "F"
+
+//KindTiered is const for 'tiered percentage by spend' coupon kind/type (see SetTiers)
+const KindTiered string = "T"
+
+//KindBundle is const for 'fixed price bundle' coupon kind/type (the coupon's products sold together at its value)
+const KindBundle string = "N"
+
 //kindMap is a map of known kind/type code and its label pairs
 var kindMap = map[string]string{
-	KindValue:      "Value",
-	KindPercentage: "Percentage",
+	KindValue:        "Value",
+	KindPercentage:   "Percentage",
+	KindBuyXGetY:     "Buy X Get Y",
+	KindFreeShipping: "Free Shipping",
+	KindTiered:       "Tiered",
+	KindBundle:       "Bundle",
 }
 
+//ErrNotApplicable is the error returned (wrapped) when a coupon is not active or used outside its start and end date
+var ErrNotApplicable = fmt.Errorf("coupon not applicable")
+
+//ErrNoStock is the error returned (wrapped) when a coupon has no stock left
+var ErrNoStock = fmt.Errorf("coupon has no stock")
+
+//ErrNotEligible is the error returned (wrapped) when an order does not meet a coupon's conditions
+var ErrNotEligible = fmt.Errorf("order not eligible for coupon")
+
 //Coupon is business domain model definition of product
 type Coupon struct {
 	id        string
@@ -49,9 +75,30 @@ type Coupon struct {
 	stock     int64
 	kind      string
 	value     decimal.Decimal
+	currency  string //ISO 4217 code of a value coupon's value currency (a percentage coupon applies in every currency)
 	startDate time.Time
 	endDate   time.Time
-	mu        sync.Mutex
+	//conditions of the orders the coupon applies on (zero or empty for none)
+	minSubtotal decimal.Decimal //minimum subtotal of an order, in the coupon's currency
+	minQuantity int             //minimum count of items (quantities summed) of an order
+	maxDiscount decimal.Decimal //maximum discount amount of a percentage coupon, in the coupon's currency
+	products    []string        //ids of the products discounted by the coupon (every product when empty)
+	excluded    []string        //ids of the products never discounted by the coupon
+	//limits of the coupon's redemptions (zero for none), see CanBeRedeemed
+	userLimit       int
+	redemptionLimit int
+	redemptions     []*Redemption //redemptions recorded on the coupon itself (by an order without ledger)
+	stackable       bool          //whether the coupon can be combined with other stackable coupons (an exclusive coupon is applied alone)
+	//promotion terms of the coupon's kind (zero or empty for none), see GetLineDiscounts
+	buyQuantity  int      //count of items bought to get free items, of a buy X get Y coupon
+	freeQuantity int      //count of free items got for every bought items, of a buy X get Y coupon
+	freeProducts []string //ids of the products given free by a buy X get Y coupon (the bought products when empty)
+	tiers        []*Tier  //spend tiers of a tiered coupon, ordered by minimum spend
+	//automatic promotion terms, see IsRunning
+	automatic       bool //whether the coupon is applied on every order it's eligible for, without its code being entered
+	priority        int  //the rank of an automatic promotion among the others (highest first)
+	excludesCoupons bool //whether an automatic promotion is skipped on orders having entered coupons
+	mu              sync.Mutex
 }
 
 //New creates a new coupon model struct, initializes it's properties and returns a reference to it
@@ -68,8 +115,25 @@ func New(id string) *Coupon {
 		0,
 		KindPercentage,     //default kind/type is percentage
 		decimal.New(10, 0), //default value is 10 (10 percent)
+		money.DefaultCurrency,
 		couponStartDate,
 		couponEndDate,
+		decimal.New(0, 0),
+		0,
+		decimal.New(0, 0),
+		nil,
+		nil,
+		0,
+		0,
+		make([]*Redemption, 0),
+		false, //default is exclusive
+		0,
+		0,
+		nil,
+		nil,
+		false, //default is entered by its code
+		0,
+		false,
 		*new(sync.Mutex),
 	}
 }
@@ -99,6 +163,11 @@ func (c *Coupon) Value() decimal.Decimal {
 	return c.value
 }
 
+//Currency is a getter function for returning the ISO 4217 code of a value coupon's value currency
+func (c *Coupon) Currency() string {
+	return c.currency
+}
+
 //StartDate is a getter function for returning a coupon's start date
 func (c *Coupon) StartDate() time.Time {
 	return c.startDate
@@ -109,6 +178,31 @@ func (c *Coupon) EndDate() time.Time {
 	return c.endDate
 }
 
+//MinSubtotal is a getter function for returning the minimum subtotal of the orders a coupon applies on (zero for none)
+func (c *Coupon) MinSubtotal() decimal.Decimal {
+	return c.minSubtotal
+}
+
+//MinQuantity is a getter function for returning the minimum count of items of the orders a coupon applies on (zero for none)
+func (c *Coupon) MinQuantity() int {
+	return c.minQuantity
+}
+
+//MaxDiscount is a getter function for returning the maximum discount amount of a percentage coupon (zero for no cap)
+func (c *Coupon) MaxDiscount() decimal.Decimal {
+	return c.maxDiscount
+}
+
+//Products is a getter function for returning the ids of the products discounted by a coupon (every product when empty)
+func (c *Coupon) Products() []string {
+	return c.products
+}
+
+//ExcludedProducts is a getter function for returning the ids of the products never discounted by a coupon
+func (c *Coupon) ExcludedProducts() []string {
+	return c.excluded
+}
+
 //SetID is a setter function for setting a coupon's id
 func (c *Coupon) SetID(id string) *Coupon {
 	c.id = id
@@ -159,6 +253,16 @@ func (c *Coupon) SetValue(value decimal.Decimal) (*Coupon, *errors.Error) {
 	return c, nil
 }
 
+//SetCurrency is a setter function for setting the ISO 4217 code of a value coupon's value currency
+func (c *Coupon) SetCurrency(currency string) (*Coupon, *errors.Error) {
+	if false == money.IsCurrency(currency) {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set unknown currency: %v", currency), 0)
+	}
+	c.currency = currency
+	return c, nil
+}
+
 //SetStartDate is a setter function for setting a coupon's start date
 func (c *Coupon) SetStartDate(startDate time.Time) (*Coupon, *errors.Error) {
 	if startIsAfterEnd := c.endDate.Before(startDate); startIsAfterEnd {
@@ -179,6 +283,48 @@ func (c *Coupon) SetEndDate(endDate time.Time) (*Coupon, *errors.Error) {
 	return c, nil
 }
 
+//SetMinSubtotal is a setter function for setting the minimum subtotal of the orders a coupon applies on (zero for none)
+func (c *Coupon) SetMinSubtotal(minSubtotal decimal.Decimal) (*Coupon, *errors.Error) {
+	if minSubtotal.IsNegative() {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set minimum subtotal to negative amount %v", minSubtotal.String()), 0)
+	}
+	c.minSubtotal = minSubtotal
+	return c, nil
+}
+
+//SetMinQuantity is a setter function for setting the minimum count of items of the orders a coupon applies on (zero for none)
+func (c *Coupon) SetMinQuantity(minQuantity int) (*Coupon, *errors.Error) {
+	if minQuantity < 0 {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set minimum quantity to negative quantity %d", minQuantity), 0)
+	}
+	c.minQuantity = minQuantity
+	return c, nil
+}
+
+//SetMaxDiscount is a setter function for setting the maximum discount amount of a percentage coupon (zero for no cap)
+func (c *Coupon) SetMaxDiscount(maxDiscount decimal.Decimal) (*Coupon, *errors.Error) {
+	if maxDiscount.IsNegative() {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set maximum discount to negative amount %v", maxDiscount.String()), 0)
+	}
+	c.maxDiscount = maxDiscount
+	return c, nil
+}
+
+//SetProducts is a setter function for setting the ids of the products discounted by a coupon (none for every product)
+func (c *Coupon) SetProducts(productIDs ...string) *Coupon {
+	c.products = productIDs
+	return c
+}
+
+//SetExcludedProducts is a setter function for setting the ids of the products never discounted by a coupon
+func (c *Coupon) SetExcludedProducts(productIDs ...string) *Coupon {
+	c.excluded = productIDs
+	return c
+}
+
 //Business logic methods
 
 //IsEarly is a function for inquiring whether the coupon start date is in the future (indicating coupon is too early to be applied)
@@ -208,27 +354,109 @@ func (c *Coupon) CanBeApplied() (bool, *errors.Error) {
 	defer c.mu.Unlock()
 
 	if c.status != StatusActive {
-		return false, errors.Wrap(fmt.Errorf("Coupon status is %v (not active)", statusMap[c.status]), 0)
+		return false, errors.WrapPrefix(ErrNotApplicable, fmt.Sprintf("Coupon status is %v (not active)", statusMap[c.status]), 0)
 	}
 	if c.IsEarly() {
-		return false, errors.Wrap(fmt.Errorf("coupon can't be applied before %v", c.startDate.Format("2000-12-31 23:59:59")), 0)
+		return false, errors.WrapPrefix(ErrNotApplicable, fmt.Sprintf("coupon can't be applied before %v", c.startDate.Format("2000-12-31 23:59:59")), 0)
 	}
 	if c.IsExpired() {
-		return false, errors.Wrap(fmt.Errorf("coupon can't be applied after %v", c.endDate.Format("2000-12-31 23:59:59")), 0)
+		return false, errors.WrapPrefix(ErrNotApplicable, fmt.Sprintf("coupon can't be applied after %v", c.endDate.Format("2000-12-31 23:59:59")), 0)
 	}
 	if c.stock <= 0 {
-		return false, errors.Wrap(fmt.Errorf("coupon can't be applied, stock is %d (no stock)", c.stock), 0)
+		return false, errors.WrapPrefix(ErrNoStock, fmt.Sprintf("coupon can't be applied, stock is %d (no stock)", c.stock), 0)
 	}
 
 	return true, nil
 }
 
+//AppliesIn is a function for inquiring whether a coupon's discount can be applied on an amount of a currency
+func (c *Coupon) AppliesIn(currency string) bool {
+	if currency == c.currency {
+		return true
+	}
+	if KindValue == c.kind || KindTiered == c.kind || KindBundle == c.kind {
+		return false
+	}
+	return c.minSubtotal.IsZero() && c.maxDiscount.IsZero()
+}
+
+//AppliesTo is a function for inquiring whether a coupon discounts the items of a product
+func (c *Coupon) AppliesTo(productID string) bool {
+	for _, excluded := range c.excluded {
+		if excluded == productID {
+			return false
+		}
+	}
+	if 0 == len(c.products) {
+		return true
+	}
+	for _, applicable := range c.products {
+		if applicable == productID {
+			return true
+		}
+	}
+	return false
+}
+
+//IsEligible is a function for inquiring whether an order of a given subtotal, count of items and items' product ids meets a coupon's conditions
+//Returns true if the order is eligible or false and an error describing the unmet condition
+func (c *Coupon) IsEligible(subtotal decimal.Decimal, quantity int, productIDs []string) (bool, *errors.Error) {
+	if subtotal.LessThan(c.minSubtotal) {
+		return false, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v requires a minimum subtotal of %v, order subtotal is %v", c.id, c.minSubtotal.String(), subtotal.String()), 0)
+	}
+	if quantity < c.minQuantity {
+		return false, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v requires a minimum of %d items, order has %d", c.id, c.minQuantity, quantity), 0)
+	}
+	for _, productID := range productIDs {
+		if c.AppliesTo(productID) {
+			return true, nil
+		}
+	}
+	return false, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v discounts none of the order's products", c.id), 0)
+}
+
+//DecrementStock is a function for atomically decrementing a coupon's stock by one use
+//returns true if stock is decremented or false and an error describing the failure
+func (c *Coupon) DecrementStock() (bool, *errors.Error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stock <= 0 {
+		return false, errors.WrapPrefix(ErrNoStock, fmt.Sprintf("Can't decrement coupon (id: %v) stock %d (no stock)", c.id, c.stock), 0)
+	}
+	c.stock--
+	return true, nil
+}
+
+//IncrementStock is a function for atomically incrementing a coupon's stock by one use (e.g. returning the use of a canceled order)
+func (c *Coupon) IncrementStock() *Coupon {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stock++
+	return c
+}
+
 //GetDiscountAmount returns discount amount from a certain given amount when applied by this coupon
 func (c *Coupon) GetDiscountAmount(amount decimal.Decimal) decimal.Decimal {
 	var retAmount decimal.Decimal
-	if KindPercentage == c.kind {
-		//coupon kind/type is KindPercentage
-		retAmount = amount.Mul(c.value).Div(decimal.New(100, 0))
+	if c.IsPromotion() {
+		return decimal.New(0, 0)
+	}
+	if KindPercentage == c.kind || KindTiered == c.kind {
+		//coupon kind/type is KindPercentage or KindTiered
+		percentage := c.value
+		if KindTiered == c.kind {
+			tier := c.tier(amount)
+			if nil == tier {
+				return decimal.New(0, 0)
+			}
+			percentage = tier.percentage
+		}
+		retAmount = amount.Mul(percentage).Div(decimal.New(100, 0))
+		if false == c.maxDiscount.IsZero() && retAmount.GreaterThan(c.maxDiscount) {
+			retAmount = c.maxDiscount
+		}
 		return retAmount
 	}
 	//coupon kind/type is KindValue
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"sstest/model/coupon"
+	"sstest/model/money"
 	"testing"
 	"time"
 
+	"github.com/go-errors/errors"
 	"github.com/shopspring/decimal"
 )
 
@@ -250,6 +252,36 @@ func TestGetDiscountAmount(t *testing.T) {
 	})
 }
 
+func TestCouponCurrency(t *testing.T) {
+	valueCoupon := coupon.New("valueCoupon")
+	valueCoupon.SetKind(coupon.KindValue)
+	valueCoupon.SetValue(decimal.New(5, 0))
+	percentageCoupon := coupon.New("percentageCoupon")
+	_, errUnknown := valueCoupon.SetCurrency("XYZ")
+	_, errEuro := valueCoupon.SetCurrency("EUR")
+
+	var couponCurrencyTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Currency Must Be Default Currency", money.DefaultCurrency, percentageCoupon.Currency()},
+		{"Set Unknown Currency", false, nil == errUnknown},
+		{"Set Currency", true, nil == errEuro},
+		{"Value Coupon Applies In Its Currency", true, valueCoupon.AppliesIn("EUR")},
+		{"Value Coupon Does Not Apply In Other Currency", false, valueCoupon.AppliesIn("USD")},
+		{"Percentage Coupon Applies In Every Currency", true, percentageCoupon.AppliesIn("JPY")},
+	}
+
+	for _, test := range couponCurrencyTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
 func TestCanBeApplied(t *testing.T) {
 	validCouponApplication, _ := activeCoupon.CanBeApplied()
 	invalidCouponApplication, _ := inactiveCoupon.CanBeApplied()
@@ -279,3 +311,86 @@ func TestCanBeApplied(t *testing.T) {
 		})
 	}
 }
+
+func TestCouponStock(t *testing.T) {
+	lastUseCoupon := coupon.New("lastUseCoupon")
+	lastUseCoupon.SetStock(1)
+
+	lastUseDecrement, _ := lastUseCoupon.DecrementStock()
+	usedUpDecrement, errUsedUp := lastUseCoupon.DecrementStock()
+	usedUpStock := lastUseCoupon.Stock()
+	lastUseCoupon.IncrementStock()
+
+	var couponStockTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Last Use Decrement", true, lastUseDecrement},
+		{"Used Up Decrement", false, usedUpDecrement},
+		{"Used Up Decrement Failure Reason", true, nil != errUsedUp && errors.Is(errUsedUp, coupon.ErrNoStock)},
+		{"Used Up Stock", int64(0), usedUpStock},
+		{"Incremented Stock", int64(1), lastUseCoupon.Stock()},
+	}
+
+	for _, test := range couponStockTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponConditions(t *testing.T) {
+	cappedCoupon := coupon.New("cappedCoupon")
+	cappedCoupon.SetValue(decimal.New(50, 0))
+	cappedCoupon.SetMaxDiscount(decimal.New(20, 0))
+	cappedCoupon.SetMinSubtotal(decimal.New(30, 0))
+	cappedCoupon.SetMinQuantity(2)
+	cappedCoupon.SetProducts("shirt", "pants")
+	cappedCoupon.SetExcludedProducts("pants")
+	_, errNegativeSubtotal := cappedCoupon.SetMinSubtotal(decimal.New(-1, 0))
+	_, errNegativeQuantity := cappedCoupon.SetMinQuantity(-1)
+	_, errNegativeDiscount := cappedCoupon.SetMaxDiscount(decimal.New(-1, 0))
+
+	eligible, _ := cappedCoupon.IsEligible(decimal.New(30, 0), 2, []string{"shirt", "socks"})
+	lowSubtotal, errLowSubtotal := cappedCoupon.IsEligible(decimal.New(2999, -2), 2, []string{"shirt"})
+	lowQuantity, errLowQuantity := cappedCoupon.IsEligible(decimal.New(30, 0), 1, []string{"shirt"})
+	noProduct, errNoProduct := cappedCoupon.IsEligible(decimal.New(30, 0), 2, []string{"pants", "socks"})
+
+	var couponConditionsTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Negative Minimum Subtotal", false, nil == errNegativeSubtotal},
+		{"Negative Minimum Quantity", false, nil == errNegativeQuantity},
+		{"Negative Maximum Discount", false, nil == errNegativeDiscount},
+		{"Minimum Subtotal Unchanged On Failure", "30", cappedCoupon.MinSubtotal().String()},
+		{"Minimum Quantity Unchanged On Failure", 2, cappedCoupon.MinQuantity()},
+		{"Maximum Discount Unchanged On Failure", "20", cappedCoupon.MaxDiscount().String()},
+		{"Applies To Applicable Product", true, cappedCoupon.AppliesTo("shirt")},
+		{"Does Not Apply To Excluded Product", false, cappedCoupon.AppliesTo("pants")},
+		{"Does Not Apply To Other Product", false, cappedCoupon.AppliesTo("socks")},
+		{"Applies To Every Product Without Applicable Products", true, coupon.New("anyProduct").AppliesTo("socks")},
+		{"Eligible Order", true, eligible},
+		{"Subtotal Below Minimum", false, lowSubtotal},
+		{"Subtotal Below Minimum Failure Reason", true, nil != errLowSubtotal && errors.Is(errLowSubtotal, coupon.ErrNotEligible)},
+		{"Quantity Below Minimum", false, lowQuantity},
+		{"Quantity Below Minimum Failure Reason", true, nil != errLowQuantity && errors.Is(errLowQuantity, coupon.ErrNotEligible)},
+		{"No Discounted Product", false, noProduct},
+		{"No Discounted Product Failure Reason", true, nil != errNoProduct && errors.Is(errNoProduct, coupon.ErrNotEligible)},
+		{"Discount Below Cap", "15", cappedCoupon.GetDiscountAmount(decimal.New(30, 0)).String()},
+		{"Discount Capped", "20", cappedCoupon.GetDiscountAmount(decimal.New(100, 0)).String()},
+		{"Capped Percentage Coupon Only Applies In Its Currency", false, cappedCoupon.AppliesIn("EUR")},
+	}
+
+	for _, test := range couponConditionsTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
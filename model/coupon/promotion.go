@@ -0,0 +1,295 @@
+package coupon
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//discountPlaces is the count of decimal places every line discount is rounded to (half away from zero)
+const discountPlaces int32 = 2
+
+//Tier is a spend tier of a tiered coupon: the percentage discounted once the spend reaches its minimum
+type Tier struct {
+	minSpend   decimal.Decimal //in the coupon's currency
+	percentage decimal.Decimal
+}
+
+//NewTier creates a new spend tier of a minimum spend and a percentage and returns a reference to it
+func NewTier(minSpend, percentage decimal.Decimal) *Tier {
+	return &Tier{minSpend, percentage}
+}
+
+//MinSpend is a getter function for returning the minimum spend (in the coupon's currency) reaching a spend tier
+func (t *Tier) MinSpend() decimal.Decimal {
+	return t.minSpend
+}
+
+//Percentage is a getter function for returning the percentage discounted by a spend tier
+func (t *Tier) Percentage() decimal.Decimal {
+	return t.percentage
+}
+
+//Line is an order line discounted by a coupon: its product, unit price, quantity and amount left to discount
+type Line struct {
+	productID string
+	unitPrice decimal.Decimal
+	quantity  int
+	amount    decimal.Decimal
+}
+
+//NewLine creates a new order line discounted by a coupon and returns a reference to it
+func NewLine(productID string, unitPrice decimal.Decimal, quantity int, amount decimal.Decimal) *Line {
+	return &Line{productID, unitPrice, quantity, amount}
+}
+
+//ProductID is a getter function for returning the product id of an order line
+func (l *Line) ProductID() string {
+	return l.productID
+}
+
+//UnitPrice is a getter function for returning the unit price of an order line
+func (l *Line) UnitPrice() decimal.Decimal {
+	return l.unitPrice
+}
+
+//Quantity is a getter function for returning the quantity of an order line
+func (l *Line) Quantity() int {
+	return l.quantity
+}
+
+//Amount is a getter function for returning the amount left to discount of an order line
+func (l *Line) Amount() decimal.Decimal {
+	return l.amount
+}
+
+//BuyQuantity is a getter function for returning the count of items bought to get free items of a buy X get Y coupon
+func (c *Coupon) BuyQuantity() int {
+	return c.buyQuantity
+}
+
+//FreeQuantity is a getter function for returning the count of free items got for every bought items of a buy X get Y coupon
+func (c *Coupon) FreeQuantity() int {
+	return c.freeQuantity
+}
+
+//FreeProducts is a getter function for returning the ids of the products given free by a buy X get Y coupon (the bought products when empty)
+func (c *Coupon) FreeProducts() []string {
+	return c.freeProducts
+}
+
+//Tiers is a getter function for returning the spend tiers of a tiered coupon, ordered by minimum spend
+func (c *Coupon) Tiers() []*Tier {
+	return c.tiers
+}
+
+//SetBuyQuantity is a setter function for setting the count of items bought to get free items of a buy X get Y coupon
+func (c *Coupon) SetBuyQuantity(buyQuantity int) (*Coupon, *errors.Error) {
+	if buyQuantity < 0 {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set buy quantity to negative quantity %d", buyQuantity), 0)
+	}
+	c.buyQuantity = buyQuantity
+	return c, nil
+}
+
+//SetFreeQuantity is a setter function for setting the count of free items got for every bought items of a buy X get Y coupon
+func (c *Coupon) SetFreeQuantity(freeQuantity int) (*Coupon, *errors.Error) {
+	if freeQuantity < 0 {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set free quantity to negative quantity %d", freeQuantity), 0)
+	}
+	c.freeQuantity = freeQuantity
+	return c, nil
+}
+
+//SetFreeProducts is a setter function for setting the ids of the products given free by a buy X get Y coupon (none for the bought products)
+func (c *Coupon) SetFreeProducts(productIDs ...string) *Coupon {
+	c.freeProducts = productIDs
+	return c
+}
+
+//SetTiers is a setter function for setting the spend tiers of a tiered coupon (in any order, each of a distinct minimum spend)
+func (c *Coupon) SetTiers(tiers ...*Tier) (*Coupon, *errors.Error) {
+	sorted := append([]*Tier{}, tiers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].minSpend.LessThan(sorted[j].minSpend)
+	})
+	for i, tier := range sorted {
+		if tier.minSpend.IsNegative() {
+			//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+			return nil, errors.Wrap(fmt.Errorf("Can't set tier minimum spend to negative amount %v", tier.minSpend.String()), 0)
+		}
+		if tier.percentage.LessThanOrEqual(decimal.New(0, 0)) || tier.percentage.GreaterThanOrEqual(decimal.New(100, 0)) {
+			return nil, errors.Wrap(fmt.Errorf("Can't set tier percentage to %v (expected more than 0 and less than 100)", tier.percentage.String()), 0)
+		}
+		if 0 < i && tier.minSpend.Equal(sorted[i-1].minSpend) {
+			return nil, errors.Wrap(fmt.Errorf("Can't set two tiers of minimum spend %v", tier.minSpend.String()), 0)
+		}
+	}
+	c.tiers = sorted
+	return c, nil
+}
+
+//IsPromotion is a function for inquiring whether a coupon's discounts are computed on an order's lines rather than off an amount
+func (c *Coupon) IsPromotion() bool {
+	return KindBuyXGetY == c.kind || KindBundle == c.kind || KindFreeShipping == c.kind
+}
+
+//IsFreeShipping is a function for inquiring whether a coupon waives its order's shipping cost rather than discounting its lines
+func (c *Coupon) IsFreeShipping() bool {
+	return KindFreeShipping == c.kind
+}
+
+//GetLineDiscounts returns the discount of every order line when applied by this coupon, in the lines' order
+//Returns the lines' discounts or an error describing why the lines don't meet the coupon's promotion
+func (c *Coupon) GetLineDiscounts(lines []*Line) ([]decimal.Decimal, *errors.Error) {
+	discounts := make([]decimal.Decimal, len(lines))
+	for i := range discounts {
+		discounts[i] = decimal.New(0, 0)
+	}
+	switch c.kind {
+	case KindFreeShipping:
+		return discounts, nil
+	case KindBuyXGetY:
+		return c.freeItemDiscounts(lines, discounts)
+	case KindBundle:
+		return c.bundleDiscounts(lines, discounts)
+	}
+	weights, eligible := make([]decimal.Decimal, len(lines)), decimal.New(0, 0)
+	for i, line := range lines {
+		weights[i] = decimal.New(0, 0)
+		if c.AppliesTo(line.productID) {
+			weights[i], eligible = line.amount, eligible.Add(line.amount)
+		}
+	}
+	if KindTiered == c.kind && nil == c.tier(eligible) {
+		if 0 == len(c.tiers) {
+			return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v has no spend tier", c.id), 0)
+		}
+		return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v requires a minimum spend of %v, order spend is %v", c.id, c.tiers[0].minSpend.String(), eligible.String()), 0)
+	}
+	discount := c.GetDiscountAmount(eligible)
+	return allocate(discount, weights, discounts), nil
+}
+
+//tier returns the highest spend tier of a tiered coupon reached by a spend (nil when none is)
+func (c *Coupon) tier(spend decimal.Decimal) *Tier {
+	var reached *Tier
+	for _, tier := range c.tiers {
+		if spend.LessThan(tier.minSpend) {
+			break
+		}
+		reached = tier
+	}
+	return reached
+}
+
+//freeItemDiscounts returns the discounts of the free items of a buy X get Y coupon on order lines (see GetLineDiscounts)
+func (c *Coupon) freeItemDiscounts(lines []*Line, discounts []decimal.Decimal) ([]decimal.Decimal, *errors.Error) {
+	if c.buyQuantity <= 0 || c.freeQuantity <= 0 {
+		return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v has no buy or free quantity", c.id), 0)
+	}
+	freeItems := 0
+	if 0 == len(c.freeProducts) {
+		for i, line := range lines {
+			if c.AppliesTo(line.productID) {
+				free := line.quantity / (c.buyQuantity + c.freeQuantity) * c.freeQuantity
+				discounts[i], freeItems = freeItemsDiscount(line, free), freeItems+free
+			}
+		}
+	} else {
+		bought := 0
+		for _, line := range lines {
+			if c.AppliesTo(line.productID) && false == contains(c.freeProducts, line.productID) {
+				bought += line.quantity
+			}
+		}
+		free := bought / c.buyQuantity * c.freeQuantity
+		for i, line := range lines {
+			if 0 < free && contains(c.freeProducts, line.productID) {
+				lineFree := free
+				if line.quantity < lineFree {
+					lineFree = line.quantity
+				}
+				discounts[i], free, freeItems = freeItemsDiscount(line, lineFree), free-lineFree, freeItems+lineFree
+			}
+		}
+	}
+	if 0 == freeItems {
+		return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v requires buying %d items to get %d free, order gets no free item", c.id, c.buyQuantity, c.freeQuantity), 0)
+	}
+	return discounts, nil
+}
+
+//freeItemsDiscount returns the discount of a count of free items of an order line (up to the line's amount left)
+func freeItemsDiscount(line *Line, free int) decimal.Decimal {
+	discount := line.unitPrice.Mul(decimal.New(int64(free), 0))
+	if discount.GreaterThan(line.amount) {
+		return line.amount
+	}
+	return discount
+}
+
+//bundleDiscounts returns the discounts of the complete bundles of a bundle coupon on order lines (see GetLineDiscounts)
+func (c *Coupon) bundleDiscounts(lines []*Line, discounts []decimal.Decimal) ([]decimal.Decimal, *errors.Error) {
+	if 0 == len(c.products) {
+		return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v bundles no product", c.id), 0)
+	}
+	weights, price, bundles := make([]decimal.Decimal, len(lines)), decimal.New(0, 0), -1
+	for i := range weights {
+		weights[i] = decimal.New(0, 0)
+	}
+	for _, productID := range c.products {
+		quantity := 0
+		for i, line := range lines {
+			if productID == line.productID {
+				weights[i], price, quantity = line.unitPrice, price.Add(line.unitPrice), line.quantity
+			}
+		}
+		if bundles < 0 || quantity < bundles {
+			bundles = quantity
+		}
+	}
+	if 0 == bundles {
+		return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v requires one item of each of products %v", c.id, c.products), 0)
+	}
+	if price.LessThanOrEqual(c.value) {
+		return nil, errors.WrapPrefix(ErrNotEligible, fmt.Sprintf("coupon %v bundle price %v is not lower than its products' price %v", c.id, c.value.String(), price.String()), 0)
+	}
+	return allocate(price.Sub(c.value).Mul(decimal.New(int64(bundles), 0)), weights, discounts), nil
+}
+
+//allocate adds a discount to the discounts of the lines of non-zero weights, in proportion to their weights
+func allocate(discount decimal.Decimal, weights, discounts []decimal.Decimal) []decimal.Decimal {
+	last, total := -1, decimal.New(0, 0)
+	for i, weight := range weights {
+		if false == weight.IsZero() {
+			last, total = i, total.Add(weight)
+		}
+	}
+	allocated := decimal.New(0, 0)
+	for i, weight := range weights {
+		if weight.IsZero() {
+			continue
+		}
+		share := discount.Sub(allocated)
+		if i < last {
+			share = discount.Mul(weight).Div(total).Round(discountPlaces)
+		}
+		discounts[i], allocated = discounts[i].Add(share), allocated.Add(share)
+	}
+	return discounts
+}
+
+//contains returns whether a product id is one of product ids
+func contains(productIDs []string, productID string) bool {
+	for _, id := range productIDs {
+		if id == productID {
+			return true
+		}
+	}
+	return false
+}
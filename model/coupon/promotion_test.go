@@ -0,0 +1,110 @@
+package coupon_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//formatDiscounts formats line discounts as space separated amounts
+func formatDiscounts(discounts []decimal.Decimal) string {
+	formatted := make([]string, 0, len(discounts))
+	for _, discount := range discounts {
+		formatted = append(formatted, discount.String())
+	}
+	return strings.Join(formatted, " ")
+}
+
+func TestCouponPromotions(t *testing.T) {
+	lines := []*coupon.Line{
+		coupon.NewLine("hat", decimal.New(15, 0), 1, decimal.New(15, 0)),
+		coupon.NewLine("shirt", decimal.New(20, 0), 3, decimal.New(60, 0)),
+		coupon.NewLine("sock", decimal.New(5, 0), 4, decimal.New(20, 0)),
+	}
+	newPromotion := func(id, kind string) *coupon.Coupon {
+		c := coupon.New(id)
+		c.SetKind(kind)
+		return c
+	}
+
+	sameProductCoupon := newPromotion("BUY2GET1", coupon.KindBuyXGetY).SetProducts("shirt")
+	sameProductCoupon.SetBuyQuantity(2)
+	sameProductCoupon.SetFreeQuantity(1)
+	sameProductDiscounts, _ := sameProductCoupon.GetLineDiscounts(lines)
+	otherProductCoupon := newPromotion("SHIRTSOCK", coupon.KindBuyXGetY).SetProducts("shirt").SetFreeProducts("sock")
+	otherProductCoupon.SetBuyQuantity(1)
+	otherProductCoupon.SetFreeQuantity(1)
+	otherProductDiscounts, _ := otherProductCoupon.GetLineDiscounts(lines)
+	noFreeItemCoupon := newPromotion("HATS", coupon.KindBuyXGetY).SetProducts("hat")
+	noFreeItemCoupon.SetBuyQuantity(1)
+	noFreeItemCoupon.SetFreeQuantity(1)
+	_, errNoFreeItem := noFreeItemCoupon.GetLineDiscounts(lines)
+	_, errNoQuantity := newPromotion("NOQUANTITY", coupon.KindBuyXGetY).GetLineDiscounts(lines)
+	_, errNegativeQuantity := sameProductCoupon.SetBuyQuantity(-1)
+
+	bundleCoupon := newPromotion("OUTFIT", coupon.KindBundle).SetProducts("shirt", "hat")
+	bundleCoupon.SetValue(decimal.New(30, 0))
+	bundleDiscounts, _ := bundleCoupon.GetLineDiscounts(lines)
+	expensiveBundleCoupon := newPromotion("EXPENSIVE", coupon.KindBundle).SetProducts("shirt", "hat")
+	expensiveBundleCoupon.SetValue(decimal.New(40, 0))
+	_, errExpensiveBundle := expensiveBundleCoupon.GetLineDiscounts(lines)
+	incompleteBundleCoupon := newPromotion("INCOMPLETE", coupon.KindBundle).SetProducts("shirt", "cap")
+	_, errIncompleteBundle := incompleteBundleCoupon.GetLineDiscounts(lines)
+
+	tieredCoupon := newPromotion("SPEND", coupon.KindTiered)
+	tieredCoupon.SetTiers(coupon.NewTier(decimal.New(90, 0), decimal.New(10, 0)), coupon.NewTier(decimal.New(50, 0), decimal.New(5, 0)))
+	tieredDiscounts, _ := tieredCoupon.GetLineDiscounts(lines)
+	lowSpendCoupon := newPromotion("LOWSPEND", coupon.KindTiered).SetProducts("hat", "sock")
+	lowSpendCoupon.SetTiers(tieredCoupon.Tiers()...)
+	_, errLowSpend := lowSpendCoupon.GetLineDiscounts(lines)
+	_, errNegativeSpend := newPromotion("NEGATIVE", coupon.KindTiered).SetTiers(coupon.NewTier(decimal.New(-1, 0), decimal.New(5, 0)))
+	_, errFullPercentage := newPromotion("FULL", coupon.KindTiered).SetTiers(coupon.NewTier(decimal.New(50, 0), decimal.New(100, 0)))
+	_, errSameSpend := newPromotion("SAME", coupon.KindTiered).SetTiers(coupon.NewTier(decimal.New(50, 0), decimal.New(5, 0)),
+		coupon.NewTier(decimal.New(50, 0), decimal.New(10, 0)))
+
+	freeShippingCoupon := newPromotion("FREESHIP", coupon.KindFreeShipping)
+	freeShippingDiscounts, _ := freeShippingCoupon.GetLineDiscounts(lines)
+
+	var promotionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Buy X Get Y Of Same Product", "0 20 0", formatDiscounts(sameProductDiscounts)},
+		{"Buy X Get Y Of Free Product", "0 0 15", formatDiscounts(otherProductDiscounts)},
+		{"Buy X Get Y Without Free Item Must Fail", true, nil != errNoFreeItem && errors.Is(errNoFreeItem, coupon.ErrNotEligible)},
+		{"Buy X Get Y Without Quantities Must Fail", true, nil != errNoQuantity && errors.Is(errNoQuantity, coupon.ErrNotEligible)},
+		{"Negative Buy Quantity Must Fail", true, nil != errNegativeQuantity},
+		{"Buy X Get Y Is A Promotion", true, sameProductCoupon.IsPromotion()},
+		{"Promotion Discounts Nothing Off An Amount", "0", sameProductCoupon.GetDiscountAmount(decimal.New(100, 0)).String()},
+		{"Buy X Get Y Applies In Every Currency", true, sameProductCoupon.AppliesIn("EUR")},
+		{"Bundle Discount Allocated By Unit Price", "2.14 2.86 0", formatDiscounts(bundleDiscounts)},
+		{"Bundle Not Lower Than Its Products Must Fail", true, nil != errExpensiveBundle && errors.Is(errExpensiveBundle, coupon.ErrNotEligible)},
+		{"Incomplete Bundle Must Fail", true, nil != errIncompleteBundle && errors.Is(errIncompleteBundle, coupon.ErrNotEligible)},
+		{"Bundle Only Applies In Its Currency", false, bundleCoupon.AppliesIn("EUR")},
+		{"Tiers Ordered By Minimum Spend", "50", tieredCoupon.Tiers()[0].MinSpend().String()},
+		{"Tiered Highest Tier Reached", "1.5 6 2", formatDiscounts(tieredDiscounts)},
+		{"Tiered Discount Amount", "4", tieredCoupon.GetDiscountAmount(decimal.New(80, 0)).String()},
+		{"Tiered Discount Amount Below Tiers", "0", tieredCoupon.GetDiscountAmount(decimal.New(40, 0)).String()},
+		{"Tiered Below Tiers Must Fail", true, nil != errLowSpend && errors.Is(errLowSpend, coupon.ErrNotEligible)},
+		{"Tier Negative Minimum Spend Must Fail", true, nil != errNegativeSpend},
+		{"Tier Percentage Of 100 Must Fail", true, nil != errFullPercentage},
+		{"Tiers Of Same Minimum Spend Must Fail", true, nil != errSameSpend},
+		{"Tiered Only Applies In Its Currency", false, tieredCoupon.AppliesIn("EUR")},
+		{"Free Shipping", true, freeShippingCoupon.IsFreeShipping()},
+		{"Free Shipping Discounts No Line", "0 0 0", formatDiscounts(freeShippingDiscounts)},
+		{"Percentage Is Not A Promotion", false, coupon.New("PERCENT").IsPromotion()},
+	}
+
+	for _, test := range promotionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
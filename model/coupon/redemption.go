@@ -0,0 +1,195 @@
+package coupon
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//ErrRedemptionLimit is the error returned (wrapped) when a coupon was redeemed as many times as its limits allow (overall or by a user)
+var ErrRedemptionLimit = fmt.Errorf("coupon redemption limit reached")
+
+//Redemption is business domain model definition of the use of a coupon by an order
+type Redemption struct {
+	couponID     string
+	userID       string //the user of the order (empty for an order without user)
+	orderID      string
+	amount       decimal.Decimal //the discount of the order
+	currency     string          //ISO 4217 code of the discount's currency (the order's currency)
+	redeemedDate time.Time
+	reversedDate time.Time //zero until the redemption is reversed
+}
+
+//NewRedemption creates a new redemption of a coupon by an order and returns a reference to it
+func NewRedemption(couponID, userID, orderID string, amount decimal.Decimal, currency string, redeemedDate time.Time) *Redemption {
+	return &Redemption{
+		couponID,
+		userID,
+		orderID,
+		amount,
+		currency,
+		redeemedDate,
+		time.Time{},
+	}
+}
+
+//CouponID is a getter function for returning the id of a redemption's coupon
+func (r *Redemption) CouponID() string {
+	return r.couponID
+}
+
+//UserID is a getter function for returning the id of a redemption's user (empty for an order without user)
+func (r *Redemption) UserID() string {
+	return r.userID
+}
+
+//OrderID is a getter function for returning the id of the order redeeming a coupon
+func (r *Redemption) OrderID() string {
+	return r.orderID
+}
+
+//Amount is a getter function for returning the discount of a redemption's order
+func (r *Redemption) Amount() decimal.Decimal {
+	return r.amount
+}
+
+//Currency is a getter function for returning the ISO 4217 code of a redemption's discount currency
+func (r *Redemption) Currency() string {
+	return r.currency
+}
+
+//RedeemedDate is a getter function for returning the time a coupon was redeemed at
+func (r *Redemption) RedeemedDate() time.Time {
+	return r.redeemedDate
+}
+
+//ReversedDate is a getter function for returning the time a redemption was reversed at (zero when it is not reversed)
+func (r *Redemption) ReversedDate() time.Time {
+	return r.reversedDate
+}
+
+//SetReversedDate is a setter function for setting the time a redemption was reversed at (zero when it is not reversed)
+func (r *Redemption) SetReversedDate(reversedDate time.Time) *Redemption {
+	r.reversedDate = reversedDate
+	return r
+}
+
+//IsReversed is a function for inquiring whether a redemption is reversed (its order was canceled)
+func (r *Redemption) IsReversed() bool {
+	return false == r.reversedDate.IsZero()
+}
+
+//UserLimit is a getter function for returning the maximum count of a coupon's redemptions by a user (zero for no limit)
+func (c *Coupon) UserLimit() int {
+	return c.userLimit
+}
+
+//RedemptionLimit is a getter function for returning the maximum count of a coupon's redemptions by every user (zero for no limit)
+func (c *Coupon) RedemptionLimit() int {
+	return c.redemptionLimit
+}
+
+//Redemptions is a getter function for returning the redemptions recorded on a coupon itself (see Redeem)
+func (c *Coupon) Redemptions() []*Redemption {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*Redemption{}, c.redemptions...)
+}
+
+//SetUserLimit is a setter function for setting the maximum count of a coupon's redemptions by a user (zero for no limit)
+func (c *Coupon) SetUserLimit(limit int) (*Coupon, *errors.Error) {
+	if limit < 0 {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set user limit to negative count %d", limit), 0)
+	}
+	c.userLimit = limit
+	return c, nil
+}
+
+//SetRedemptionLimit is a setter function for setting the maximum count of a coupon's redemptions by every user (zero for no limit)
+func (c *Coupon) SetRedemptionLimit(limit int) (*Coupon, *errors.Error) {
+	if limit < 0 {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set redemption limit to negative count %d", limit), 0)
+	}
+	c.redemptionLimit = limit
+	return c, nil
+}
+
+//CanBeRedeemed is a function for inquiring whether a coupon can be redeemed once more by a user, given the coupon's redemptions
+//Returns true if the coupon can be redeemed or false and an error describing the reached limit
+func (c *Coupon) CanBeRedeemed(userID string, redemptions []*Redemption) (bool, *errors.Error) {
+	redeemed, redeemedByUser := 0, 0
+	for _, r := range redemptions {
+		if r.couponID != c.id || r.IsReversed() {
+			continue
+		}
+		redeemed++
+		if "" != userID && r.userID == userID {
+			redeemedByUser++
+		}
+	}
+	if 0 < c.redemptionLimit && c.redemptionLimit <= redeemed {
+		return false, errors.WrapPrefix(ErrRedemptionLimit, fmt.Sprintf("coupon %v is redeemed %d times (limit is %d)", c.id, redeemed, c.redemptionLimit), 0)
+	}
+	if "" != userID && 0 < c.userLimit && c.userLimit <= redeemedByUser {
+		return false, errors.WrapPrefix(ErrRedemptionLimit, fmt.Sprintf("coupon %v is redeemed %d times by user %v (limit is %d)", c.id, redeemedByUser, userID, c.userLimit), 0)
+	}
+	return true, nil
+}
+
+//Redeem is a function for atomically recording a redemption on a coupon itself, only if the coupon's limits are not reached
+//Returns true if the redemption is recorded or false and an error describing the failure
+func (c *Coupon) Redeem(r *Redemption) (bool, *errors.Error) {
+	return RedeemAll(map[*Coupon]*Redemption{c: r})
+}
+
+//RedeemAll is a function for atomically recording redemptions on their coupons themselves
+//Returns true if the redemptions are recorded or false and an error describing the failure
+func RedeemAll(redemptions map[*Coupon]*Redemption) (bool, *errors.Error) {
+	coupons := make([]*Coupon, 0, len(redemptions))
+	for c := range redemptions {
+		coupons = append(coupons, c)
+	}
+	//note: the coupons are locked in the order of their ids, so concurrent redemptions of the same coupons can't deadlock
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].id < coupons[j].id
+	})
+	for _, c := range coupons {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+
+	for _, c := range coupons {
+		r := redemptions[c]
+		if r.couponID != c.id {
+			return false, errors.Wrap(fmt.Errorf("Can't redeem coupon %v with a redemption of coupon %v", c.id, r.couponID), 0)
+		}
+		if ok, err := c.CanBeRedeemed(r.userID, c.redemptions); false == ok {
+			return false, err
+		}
+	}
+	for _, c := range coupons {
+		c.redemptions = append(c.redemptions, redemptions[c])
+	}
+	return true, nil
+}
+
+//Reverse is a function for atomically reversing the unreversed redemption of a coupon by an order at a date
+//Returns true if a redemption is reversed or false when the order has no unreversed redemption of the coupon
+func (c *Coupon) Reverse(orderID string, date time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, r := range c.redemptions {
+		if r.orderID == orderID && false == r.IsReversed() {
+			r.reversedDate = date
+			return true
+		}
+	}
+	return false
+}
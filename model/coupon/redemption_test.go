@@ -0,0 +1,79 @@
+package coupon_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestCouponRedemptionLimits(t *testing.T) {
+	redeemedDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	newRedemption := func(couponID, userID, orderID string) *coupon.Redemption {
+		return coupon.NewRedemption(couponID, userID, orderID, decimal.New(10, 0), "USD", redeemedDate)
+	}
+
+	limitedCoupon := coupon.New("LIMITED")
+	_, errNegativeUserLimit := limitedCoupon.SetUserLimit(-1)
+	_, errNegativeLimit := limitedCoupon.SetRedemptionLimit(-1)
+	limitedCoupon.SetUserLimit(1)
+	limitedCoupon.SetRedemptionLimit(3)
+
+	firstOk, _ := limitedCoupon.Redeem(newRedemption("LIMITED", "user1", "order1"))
+	sameUserOk, errSameUser := limitedCoupon.Redeem(newRedemption("LIMITED", "user1", "order2"))
+	otherCouponOk, errOtherCoupon := limitedCoupon.Redeem(newRedemption("OTHER", "user2", "order2"))
+	noUserOk, _ := limitedCoupon.Redeem(newRedemption("LIMITED", "", "order3"))
+	secondNoUserOk, _ := limitedCoupon.Redeem(newRedemption("LIMITED", "", "order4"))
+	overLimitOk, errOverLimit := limitedCoupon.Redeem(newRedemption("LIMITED", "user2", "order5"))
+	reversed := limitedCoupon.Reverse("order1", redeemedDate.AddDate(0, 0, 1))
+	reversedTwice := limitedCoupon.Reverse("order1", redeemedDate.AddDate(0, 0, 2))
+	afterReversalOk, _ := limitedCoupon.Redeem(newRedemption("LIMITED", "user1", "order6"))
+	redemptions := limitedCoupon.Redemptions()
+
+	unlimitedCoupon := coupon.New("UNLIMITED")
+	unlimitedOk, _ := unlimitedCoupon.CanBeRedeemed("user1", []*coupon.Redemption{newRedemption("UNLIMITED", "user1", "order1"), newRedemption("UNLIMITED", "user1", "order2")})
+	otherCouponRedemptionsOk, _ := limitedCoupon.CanBeRedeemed("user1", []*coupon.Redemption{newRedemption("OTHER", "user1", "order1")})
+
+	var redemptionLimitTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Negative User Limit Must Fail", true, nil != errNegativeUserLimit},
+		{"Negative Redemption Limit Must Fail", true, nil != errNegativeLimit},
+		{"User Limit", 1, limitedCoupon.UserLimit()},
+		{"Redemption Limit", 3, limitedCoupon.RedemptionLimit()},
+		{"First Redemption", true, firstOk},
+		{"Redemption Over User Limit Must Fail", false, sameUserOk},
+		{"Redemption Over User Limit Failure Reason", true, nil != errSameUser && errors.Is(errSameUser, coupon.ErrRedemptionLimit)},
+		{"Redemption Of Another Coupon Must Fail", false, otherCouponOk},
+		{"Redemption Of Another Coupon Failure Reason", true, nil != errOtherCoupon && false == errors.Is(errOtherCoupon, coupon.ErrRedemptionLimit)},
+		{"Redemptions Without User Ignore User Limit", true, noUserOk && secondNoUserOk},
+		{"Redemption Over Redemption Limit Must Fail", false, overLimitOk},
+		{"Redemption Over Redemption Limit Failure Reason", true, nil != errOverLimit && errors.Is(errOverLimit, coupon.ErrRedemptionLimit)},
+		{"Reverse Redemption", true, reversed},
+		{"Reverse Reversed Redemption Must Fail", false, reversedTwice},
+		{"Reversed Redemption Must Not Count", true, afterReversalOk},
+		{"Reversed Redemption Is Kept", 4, len(redemptions)},
+		{"Reversed Redemption Is Reversed", true, redemptions[0].IsReversed()},
+		{"Reversed Redemption Date", redeemedDate.AddDate(0, 0, 1), redemptions[0].ReversedDate()},
+		{"Unreversed Redemption", false, redemptions[3].IsReversed()},
+		{"Redemption User", "user1", redemptions[3].UserID()},
+		{"Redemption Order", "order6", redemptions[3].OrderID()},
+		{"Redemption Amount", "10", redemptions[3].Amount().String()},
+		{"Redemption Currency", "USD", redemptions[3].Currency()},
+		{"Unlimited Coupon Can Be Redeemed", true, unlimitedOk},
+		{"Other Coupon Redemptions Must Not Count", true, otherCouponRedemptionsOk},
+	}
+
+	for _, test := range redemptionLimitTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
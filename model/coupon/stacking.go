@@ -0,0 +1,36 @@
+package coupon
+
+import (
+	"fmt"
+
+	"github.com/go-errors/errors"
+)
+
+//ErrNotCombinable is the error returned (wrapped) when coupons can't be applied together on an order
+var ErrNotCombinable = fmt.Errorf("coupons can't be combined")
+
+//Stackable is a getter function for returning whether a coupon can be combined with other stackable coupons
+func (c *Coupon) Stackable() bool {
+	return c.stackable
+}
+
+//SetStackable is a setter function for setting whether a coupon can be combined with other stackable coupons
+func (c *Coupon) SetStackable(stackable bool) *Coupon {
+	c.stackable = stackable
+	return c
+}
+
+//CanBeCombinedWith is a function for inquiring whether a coupon can be applied on an order along with another coupon
+//Returns true if the coupons can be combined or false and an error describing the failure
+func (c *Coupon) CanBeCombinedWith(other *Coupon) (bool, *errors.Error) {
+	if c.id == other.id {
+		return false, errors.WrapPrefix(ErrNotCombinable, fmt.Sprintf("coupon %v is applied twice", c.id), 0)
+	}
+	if false == c.stackable {
+		return false, errors.WrapPrefix(ErrNotCombinable, fmt.Sprintf("coupon %v is exclusive, it can't be combined with coupon %v", c.id, other.id), 0)
+	}
+	if false == other.stackable {
+		return false, errors.WrapPrefix(ErrNotCombinable, fmt.Sprintf("coupon %v is exclusive, it can't be combined with coupon %v", other.id, c.id), 0)
+	}
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package coupon_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestCouponStacking(t *testing.T) {
+	redeemedDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	newRedemption := func(couponID, orderID string) *coupon.Redemption {
+		return coupon.NewRedemption(couponID, "user1", orderID, decimal.New(10, 0), "USD", redeemedDate)
+	}
+
+	exclusiveCoupon := coupon.New("EXCLUSIVE")
+	stackableCoupon := coupon.New("STACKABLE").SetStackable(true)
+	otherStackableCoupon := coupon.New("OTHERSTACKABLE").SetStackable(true)
+	otherStackableCoupon.SetRedemptionLimit(1)
+
+	stackableOk, _ := stackableCoupon.CanBeCombinedWith(otherStackableCoupon)
+	exclusiveOk, errExclusive := stackableCoupon.CanBeCombinedWith(exclusiveCoupon)
+	exclusiveFirstOk, errExclusiveFirst := exclusiveCoupon.CanBeCombinedWith(stackableCoupon)
+	twiceOk, errTwice := stackableCoupon.CanBeCombinedWith(coupon.New("STACKABLE").SetStackable(true))
+
+	redeemedOk, _ := coupon.RedeemAll(map[*coupon.Coupon]*coupon.Redemption{
+		stackableCoupon:      newRedemption("STACKABLE", "order1"),
+		otherStackableCoupon: newRedemption("OTHERSTACKABLE", "order1"),
+	})
+	overLimitOk, errOverLimit := coupon.RedeemAll(map[*coupon.Coupon]*coupon.Redemption{
+		stackableCoupon:      newRedemption("STACKABLE", "order2"),
+		otherStackableCoupon: newRedemption("OTHERSTACKABLE", "order2"),
+	})
+	mismatchOk, _ := coupon.RedeemAll(map[*coupon.Coupon]*coupon.Redemption{
+		stackableCoupon: newRedemption("OTHERSTACKABLE", "order3"),
+	})
+
+	var stackingTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon Is Exclusive By Default", false, exclusiveCoupon.Stackable()},
+		{"Stackable Coupon", true, stackableCoupon.Stackable()},
+		{"Stackable Coupons Can Be Combined", true, stackableOk},
+		{"Exclusive Coupon Can't Be Combined", false, exclusiveOk},
+		{"Exclusive Coupon Failure Reason", true, nil != errExclusive && errors.Is(errExclusive, coupon.ErrNotCombinable)},
+		{"Exclusive Coupon Can't Be Combined First", false, exclusiveFirstOk},
+		{"Exclusive Coupon First Failure Reason", true, nil != errExclusiveFirst && errors.Is(errExclusiveFirst, coupon.ErrNotCombinable)},
+		{"Same Coupon Can't Be Combined", false, twiceOk},
+		{"Same Coupon Failure Reason", true, nil != errTwice && errors.Is(errTwice, coupon.ErrNotCombinable)},
+		{"Redeem All", true, redeemedOk},
+		{"Redeem All Over A Limit Must Fail", false, overLimitOk},
+		{"Redeem All Over A Limit Failure Reason", true, nil != errOverLimit && errors.Is(errOverLimit, coupon.ErrRedemptionLimit)},
+		{"Redeem All Over A Limit Records None", 1, len(stackableCoupon.Redemptions())},
+		{"Redeem All Another Coupon's Redemption Must Fail", false, mismatchOk},
+		{"Redeemed Coupon", "order1", otherStackableCoupon.Redemptions()[0].OrderID()},
+	}
+
+	for _, test := range stackingTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//ErrNoRate is the error returned (wrapped) when no exchange rate converts the amounts of a currency into another
+var ErrNoRate = fmt.Errorf("exchange rate not found")
+
+//ExchangeRateProvider is interface of an exchange rate source, quoting the rate converting the amounts of a currency into another
+type ExchangeRateProvider interface {
+	//Rate returns the rate an amount in the from currency is multiplied by to be converted into the to currency,
+	//and the time the rate was quoted at
+	Rate(from, to string) (decimal.Decimal, time.Time, *errors.Error)
+}
+
+//RateTable is an ExchangeRateProvider of the rates of currencies against a base currency, all quoted at the same time
+type RateTable struct {
+	base  string
+	date  time.Time
+	rates map[string]decimal.Decimal //units of a currency bought by one unit of the base currency
+}
+
+//NewRateTable creates a new rate table of rates against a base currency quoted at a date and returns a reference to it
+func NewRateTable(base string, date time.Time, rates map[string]decimal.Decimal) *RateTable {
+	return &RateTable{base, date, rates}
+}
+
+//Base is a getter function for returning the currency the rates of a rate table are quoted against
+func (t *RateTable) Base() string {
+	return t.base
+}
+
+//Date is a getter function for returning the time the rates of a rate table were quoted at
+func (t *RateTable) Date() time.Time {
+	return t.date
+}
+
+//Currencies returns the currencies a rate table converts, sorted (its base currency included)
+func (t *RateTable) Currencies() []string {
+	codes := []string{t.base}
+	for code := range t.rates {
+		if code != t.base {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+//Rate returns the rate converting the amounts of a currency into another (one between the same currencies)
+func (t *RateTable) Rate(from, to string) (decimal.Decimal, time.Time, *errors.Error) {
+	if from == to {
+		return decimal.New(1, 0), t.date, nil
+	}
+	fromRate, fromOk := t.rate(from)
+	toRate, toOk := t.rate(to)
+	if false == fromOk || false == toOk {
+		return decimal.Zero, t.date, errors.WrapPrefix(ErrNoRate, fmt.Sprintf("Can't convert %v into %v, rates are quoted for %v", from, to, strings.Join(t.Currencies(), ", ")), 0)
+	}
+	return toRate.Div(fromRate), t.date, nil
+}
+
+//rate returns the rate of a currency against the base currency and whether it is known
+func (t *RateTable) rate(code string) (decimal.Decimal, bool) {
+	if code == t.base {
+		return decimal.New(1, 0), true
+	}
+	rate, ok := t.rates[code]
+	return rate, ok && rate.IsPositive()
+}
+
+//rateFile is the JSON representation of a rate table file
+type rateFile struct {
+	Base  string                     `json:"base"`
+	Date  time.Time                  `json:"date"`
+	Rates map[string]decimal.Decimal `json:"rates"`
+}
+
+//LoadRateTable reads a rate table file (see FileRates)
+func LoadRateTable(path string) (*RateTable, *errors.Error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read exchange rates %v: %v", path, err), 0)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read exchange rates %v: %v", path, err), 0)
+	}
+	var file rateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read exchange rates %v: %v", path, err), 0)
+	}
+	if false == IsCurrency(file.Base) {
+		return nil, errors.Wrap(fmt.Errorf("Can't read exchange rates %v: unknown base currency %v", path, file.Base), 0)
+	}
+	for code, rate := range file.Rates {
+		if false == IsCurrency(code) || false == rate.IsPositive() {
+			return nil, errors.Wrap(fmt.Errorf("Can't read exchange rates %v: invalid rate %v of currency %v", path, rate, code), 0)
+		}
+	}
+	if file.Date.IsZero() {
+		file.Date = info.ModTime()
+	}
+	return NewRateTable(file.Base, file.Date, file.Rates), nil
+}
+
+//FileRates is an ExchangeRateProvider reading its rates from a rate table file on every quote
+type FileRates struct {
+	path string
+}
+
+//NewFileRates creates a new file exchange rate provider reading a rate table file and returns a reference to it
+func NewFileRates(path string) *FileRates {
+	return &FileRates{path}
+}
+
+//Path is a getter function for returning the rate table file of a file exchange rate provider
+func (r *FileRates) Path() string {
+	return r.path
+}
+
+//Rate returns the rate converting the amounts of a currency into another, read from the rate table file
+func (r *FileRates) Rate(from, to string) (decimal.Decimal, time.Time, *errors.Error) {
+	table, err := LoadRateTable(r.path)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+	return table.Rate(from, to)
+}
+
+//RoundHalfUp is const for rounding half away from zero (e.g. 0.125 to 0.13)
+const RoundHalfUp string = "half-up"
+
+//RoundHalfEven is const for rounding half to the even minor unit, the banker's rounding (e.g. 0.125 to 0.12)
+const RoundHalfEven string = "half-even"
+
+//RoundDown is const for rounding toward zero (e.g. 0.129 to 0.12)
+const RoundDown string = "down"
+
+//RoundUp is const for rounding away from zero (e.g. 0.121 to 0.13)
+const RoundUp string = "up"
+
+//roundingMap is a map of known rounding mode and its rounding function pairs
+var roundingMap = map[string]func(amount decimal.Decimal, places int32) decimal.Decimal{
+	RoundHalfUp:   decimal.Decimal.Round,
+	RoundHalfEven: decimal.Decimal.RoundBank,
+	RoundDown:     decimal.Decimal.RoundDown,
+	RoundUp:       decimal.Decimal.RoundUp,
+}
+
+//IsRounding returns whether a rounding mode is a known rounding mode
+func IsRounding(mode string) bool {
+	_, ok := roundingMap[mode]
+	return ok
+}
+
+//Roundings returns the known rounding modes, sorted
+func Roundings() []string {
+	modes := make([]string, 0, len(roundingMap))
+	for mode := range roundingMap {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+//defaultRounding is the rounding mode of converted amounts
+var defaultRounding = RoundHalfUp
+
+//DefaultRounding returns the rounding mode of converted amounts
+func DefaultRounding() string {
+	return defaultRounding
+}
+
+//SetDefaultRounding sets the rounding mode of converted amounts, half-up when empty
+func SetDefaultRounding(mode string) *errors.Error {
+	if "" == mode {
+		mode = RoundHalfUp
+	}
+	if false == IsRounding(mode) {
+		return errors.Wrap(fmt.Errorf("Can't set unknown rounding %v, expected one of: %v", mode, strings.Join(Roundings(), ", ")), 0)
+	}
+	defaultRounding = mode
+	return nil
+}
+
+//Round rounds an amount to the minor unit of its currency with the default rounding mode (see RoundMode)
+func Round(amount decimal.Decimal, code string) decimal.Decimal {
+	return RoundMode(amount, code, defaultRounding)
+}
+
+//RoundMode rounds an amount to the minor unit of its currency with a rounding mode (half-up for an unknown one)
+func RoundMode(amount decimal.Decimal, code, mode string) decimal.Decimal {
+	round, ok := roundingMap[mode]
+	if false == ok {
+		round = decimal.Decimal.Round
+	}
+	return round(amount, MinorUnits(code))
+}
+
+//Convert converts an amount with an exchange rate into a currency, rounded to its minor unit with the default rounding mode
+func Convert(amount, rate decimal.Decimal, code string) decimal.Decimal {
+	return Round(amount.Mul(rate), code)
+}
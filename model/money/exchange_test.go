@@ -0,0 +1,96 @@
+package money_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sstest/model/money"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestRateTable(t *testing.T) {
+	date := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	table := money.NewRateTable("USD", date, map[string]decimal.Decimal{"EUR": decimal.New(8, -1), "JPY": decimal.New(110, 0)})
+	direct, directDate, _ := table.Rate("USD", "EUR")
+	inverse, _, _ := table.Rate("EUR", "USD")
+	cross, _, _ := table.Rate("EUR", "JPY")
+	same, _, _ := table.Rate("JPY", "JPY")
+	_, _, errMissing := table.Rate("EUR", "GBP")
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	os.WriteFile(path, []byte(`{"base": "EUR", "date": "2017-09-15T00:00:00Z", "rates": {"USD": "1.25"}}`), 0600)
+	file := money.NewFileRates(path)
+	fileRate, fileDate, errFile := file.Rate("EUR", "USD")
+	os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"USD": "1.2"}}`), 0600)
+	rewrittenRate, rewrittenDate, _ := file.Rate("EUR", "USD")
+	os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"XYZ": "1.2"}}`), 0600)
+	_, errUnknown := money.LoadRateTable(path)
+	_, _, errNoFile := money.NewFileRates(filepath.Join(t.TempDir(), "missing.json")).Rate("EUR", "USD")
+
+	var rateTableTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Direct Rate", "0.8", direct.String()},
+		{"Rate Date", date, directDate},
+		{"Inverse Rate", "1.25", inverse.String()},
+		{"Cross Rate", "137.5", cross.String()},
+		{"Same Currency Rate", "1", same.String()},
+		{"Missing Rate", true, errors.Is(errMissing, money.ErrNoRate)},
+		{"Currencies", "EUR, JPY, USD", fmt.Sprintf("%s, %s, %s", table.Currencies()[0], table.Currencies()[1], table.Currencies()[2])},
+		{"File Rate Without Error", true, nil == errFile},
+		{"File Rate", "1.25", fileRate.String()},
+		{"File Rate Date", date, fileDate.UTC()},
+		{"Rewritten File Rate", "1.2", rewrittenRate.String()},
+		{"File Without Date Must Be Dated", false, rewrittenDate.IsZero()},
+		{"Unknown Currency File Must Fail", false, nil == errUnknown},
+		{"Missing File Must Fail", false, nil == errNoFile},
+	}
+
+	for _, test := range rateTableTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestRounding(t *testing.T) {
+	t.Cleanup(func() { money.SetDefaultRounding("") })
+	half := decimal.New(125, -3)
+	errUnknown := money.SetDefaultRounding("sideways")
+	unknownRounding := money.DefaultRounding()
+	halfUp := money.Round(half, "USD")
+	money.SetDefaultRounding(money.RoundDown)
+	converted := money.Convert(decimal.New(100, 0), decimal.New(11111, -4), "USD")
+
+	var roundingTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Unknown Rounding Must Fail", false, nil == errUnknown},
+		{"Unknown Rounding Must Not Be Set", money.RoundHalfUp, unknownRounding},
+		{"Default Rounding Is Half Up", "0.13", halfUp.String()},
+		{"Half Even", "0.12", money.RoundMode(half, "USD", money.RoundHalfEven).String()},
+		{"Down", "0.12", money.RoundMode(decimal.New(129, -3), "EUR", money.RoundDown).String()},
+		{"Up", "0.13", money.RoundMode(decimal.New(121, -3), "EUR", money.RoundUp).String()},
+		{"Currency Without Minor Unit", "13", money.RoundMode(decimal.New(125, -1), "JPY", money.RoundHalfUp).String()},
+		{"Convert With Default Rounding", "111.11", converted.String()},
+		{"Negative Amount Rounded Up Away From Zero", "-0.13", money.RoundMode(decimal.New(-121, -3), "USD", money.RoundUp).String()},
+	}
+
+	for _, test := range roundingTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
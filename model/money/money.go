@@ -0,0 +1,156 @@
+//Package money provides the currencies of the business domain models' amounts and their locale-aware formatting
+package money
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//DefaultCurrency is the ISO 4217 code of the currency of a model without currency (e.g. stored before currencies were introduced)
+const DefaultCurrency string = "USD"
+
+//currency is the formatting definition of a currency
+type currency struct {
+	symbol     string
+	minorUnits int32 //count of decimal places of the currency's minor unit (e.g. 2 for cents)
+}
+
+//currencyMap is a map of known ISO 4217 currency code and its formatting definition pairs
+var currencyMap = map[string]currency{
+	"AUD": {"A$", 2},
+	"CAD": {"CA$", 2},
+	"CHF": {"CHF", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"IDR": {"Rp", 2},
+	"JPY": {"¥", 0},
+	"USD": {"$", 2},
+}
+
+//locale is the formatting definition of the amounts of a locale
+type locale struct {
+	thousands   string //separator of the thousands groups of the integer part
+	decimal     string //separator of the integer and fractional parts
+	symbolAfter bool   //whether the currency symbol follows the amount (separated with a space) or precedes it
+}
+
+//localeMap is a map of known BCP 47 locale tag and its formatting definition pairs
+var localeMap = map[string]locale{
+	"de-DE": {".", ",", true},
+	"en-GB": {",", ".", false},
+	"en-US": {",", ".", false},
+	"fr-FR": {" ", ",", true},
+	"id-ID": {".", ",", false},
+	"ja-JP": {",", ".", false},
+}
+
+//IsCurrency returns whether a currency code is a known ISO 4217 currency code
+func IsCurrency(code string) bool {
+	_, ok := currencyMap[code]
+	return ok
+}
+
+//Currencies returns the known currency codes, sorted
+func Currencies() []string {
+	codes := make([]string, 0, len(currencyMap))
+	for code := range currencyMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+//Symbol returns the symbol of a currency (its code for an unknown currency)
+func Symbol(code string) string {
+	if c, ok := currencyMap[code]; ok {
+		return c.symbol
+	}
+	return code
+}
+
+//MinorUnits returns the count of decimal places of a currency's minor unit (2 for an unknown currency)
+func MinorUnits(code string) int32 {
+	if c, ok := currencyMap[code]; ok {
+		return c.minorUnits
+	}
+	return 2
+}
+
+//IsLocale returns whether a locale tag is a known locale
+func IsLocale(tag string) bool {
+	_, ok := localeMap[tag]
+	return ok
+}
+
+//Locales returns the known locale tags, sorted
+func Locales() []string {
+	tags := make([]string, 0, len(localeMap))
+	for tag := range localeMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+//defaultLocale is the locale amounts are formatted in by Format
+var defaultLocale = "en-US"
+
+//DefaultLocale returns the locale amounts are formatted in by Format
+func DefaultLocale() string {
+	return defaultLocale
+}
+
+//SetDefaultLocale sets the locale amounts are formatted in by Format, en-US when empty
+func SetDefaultLocale(tag string) *errors.Error {
+	if "" == tag {
+		tag = "en-US"
+	}
+	if false == IsLocale(tag) {
+		return errors.Wrap(fmt.Errorf("Can't set unknown locale %v, expected one of: %v", tag, strings.Join(Locales(), ", ")), 0)
+	}
+	defaultLocale = tag
+	return nil
+}
+
+//Format formats an amount of a currency in the default locale (see FormatLocale)
+func Format(amount decimal.Decimal, code string) string {
+	return FormatLocale(amount, code, defaultLocale)
+}
+
+//FormatLocale formats an amount of a currency in a locale, e.g. $1,234.50 in en-US or 1.234,50 € in de-DE
+func FormatLocale(amount decimal.Decimal, code, tag string) string {
+	l, ok := localeMap[tag]
+	if false == ok {
+		l = localeMap[defaultLocale]
+	}
+	places := MinorUnits(code)
+	digits := amount.Abs().StringFixed(places)
+	integer, fraction := digits, ""
+	if 0 < places {
+		integer, fraction = digits[:len(digits)-int(places)-1], digits[len(digits)-int(places):]
+	}
+
+	var number strings.Builder
+	for i, digit := range integer {
+		if 0 < i && 0 == (len(integer)-i)%3 {
+			number.WriteString(l.thousands)
+		}
+		number.WriteRune(digit)
+	}
+	if "" != fraction {
+		number.WriteString(l.decimal + fraction)
+	}
+
+	sign := ""
+	if amount.Round(places).IsNegative() {
+		sign = "-"
+	}
+	if l.symbolAfter {
+		return sign + number.String() + " " + Symbol(code)
+	}
+	return sign + Symbol(code) + number.String()
+}
@@ -0,0 +1,70 @@
+//money_test provides unit tests for the currencies and the formatting of amounts
+package money_test
+
+import (
+	"fmt"
+	"sstest/model/money"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFormatLocale(t *testing.T) {
+	var formatTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"US Dollar In US English", "$1,234,567.50", money.FormatLocale(decimal.New(12345675, -1), "USD", "en-US")},
+		{"Euro In German", "1.234,50 €", money.FormatLocale(decimal.New(12345, -1), "EUR", "de-DE")},
+		{"Euro In French", "1 234,50 €", money.FormatLocale(decimal.New(12345, -1), "EUR", "fr-FR")},
+		{"Rupiah In Indonesian", "Rp1.000.000,00", money.FormatLocale(decimal.New(1000000, 0), "IDR", "id-ID")},
+		{"Yen Without Minor Unit", "¥1,235", money.FormatLocale(decimal.New(12345, -1), "JPY", "ja-JP")},
+		{"Rounded To Minor Unit", "$0.13", money.FormatLocale(decimal.New(125, -3), "USD", "en-US")},
+		{"Negative Amount", "-£12.00", money.FormatLocale(decimal.New(-12, 0), "GBP", "en-GB")},
+		{"Negative Amount Rounded To Zero", "$0.00", money.FormatLocale(decimal.New(-1, -3), "USD", "en-US")},
+		{"Hundreds Not Grouped", "$999.99", money.FormatLocale(decimal.New(99999, -2), "USD", "en-US")},
+		{"Unknown Currency Symbol Is Code", "XYZ5.00", money.FormatLocale(decimal.New(5, 0), "XYZ", "en-US")},
+		{"Unknown Locale Is Default Locale", "$5.00", money.FormatLocale(decimal.New(5, 0), "USD", "xx-XX")},
+		{"Default Locale", "$5.00", money.Format(decimal.New(5, 0), "USD")},
+	}
+
+	for _, test := range formatTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestDefaultLocale(t *testing.T) {
+	t.Cleanup(func() { money.SetDefaultLocale("") })
+	errUnknown := money.SetDefaultLocale("xx-XX")
+	unknownLocale := money.DefaultLocale()
+	errGerman := money.SetDefaultLocale("de-DE")
+	german := money.Format(decimal.New(12345, -1), "EUR")
+
+	var defaultLocaleTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Unknown Locale Must Fail", false, nil == errUnknown},
+		{"Unknown Locale Must Not Be Set", "en-US", unknownLocale},
+		{"Known Locale", true, nil == errGerman},
+		{"Format In Default Locale", "1.234,50 €", german},
+		{"Known Currency", true, money.IsCurrency("EUR")},
+		{"Unknown Currency", false, money.IsCurrency("XYZ")},
+		{"Currencies Sorted", "AUD", money.Currencies()[0]},
+		{"Minor Units", int32(0), money.MinorUnits("JPY")},
+	}
+
+	for _, test := range defaultLocaleTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package order
+
+import (
+	"sort"
+	"sstest/model/coupon"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//PromotionProvider is interface of the automatic promotions store, providing the promotions evaluated when pricing a draft order
+type PromotionProvider interface {
+	//Promotions returns the automatic promotions, running or not (see coupon.Coupon.IsRunning)
+	Promotions() ([]*coupon.Coupon, *errors.Error)
+}
+
+//noPromotions is the default PromotionProvider of an order, providing no automatic promotion
+type noPromotions struct{}
+
+//Promotions is a function for returning no automatic promotion
+func (noPromotions) Promotions() ([]*coupon.Coupon, *errors.Error) {
+	return nil, nil
+}
+
+//SetPromotions is a setter function for setting the provider of the automatic promotions evaluated when pricing a draft order
+func (o *Order) SetPromotions(p PromotionProvider) *Order {
+	if nil == p {
+		p = noPromotions{}
+	}
+	o.promotionRules = p
+	return o
+}
+
+//AppliedPromotions is a getter function for returning the automatic promotions applied on an order with their discounts, in their application order
+func (o *Order) AppliedPromotions() []*AppliedCoupon {
+	return append([]*AppliedCoupon{}, o.promotions...)
+}
+
+//SetAppliedPromotions is a setter function for setting the automatic promotions applied on an order with their discounts, in their application order
+func (o *Order) SetAppliedPromotions(applied ...*AppliedCoupon) *Order {
+	o.promotions = append([]*AppliedCoupon{}, applied...)
+	return o
+}
+
+//runningPromotions is a function for returning the automatic promotions of an order running at a date, ordered by their priority
+//Returns the promotions or an error describing why they can't be provided
+func (o *Order) runningPromotions(date time.Time, withCoupons bool) ([]*coupon.Coupon, *errors.Error) {
+	promotions, err := o.promotionRules.Promotions()
+	if err != nil {
+		return nil, err
+	}
+	running := make([]*coupon.Coupon, 0, len(promotions))
+	for _, p := range promotions {
+		if false == p.IsRunning(date) || (withCoupons && p.ExcludesCoupons()) {
+			continue
+		}
+		running = append(running, p)
+	}
+	sort.SliceStable(running, func(i, j int) bool {
+		if running[i].Priority() != running[j].Priority() {
+			return running[i].Priority() > running[j].Priority()
+		}
+		return running[i].ID() < running[j].ID()
+	})
+	return running, nil
+}
+
+//combinable is a function for inquiring whether an automatic promotion can be applied on an order along with the applied promotions and entered coupons
+func (o *Order) combinable(p *coupon.Coupon, applied []*AppliedCoupon, coupons []*coupon.Coupon) bool {
+	for _, prev := range applied {
+		if ok, _ := prev.coupon.CanBeCombinedWith(p); false == ok {
+			return false
+		}
+	}
+	for _, c := range coupons {
+		if c.ID() == p.ID() {
+			return false
+		}
+	}
+	return true
+}
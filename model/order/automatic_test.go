@@ -0,0 +1,98 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+//newAutomaticCoupon creates an active stackable automatic promotion of a kind, value and priority without stock
+func newAutomaticCoupon(id, kind string, value int64, priority int) *coupon.Coupon {
+	c := newConditionalCoupon(id, kind, value).SetAutomatic(true).SetPriority(priority).SetStackable(true)
+	c.SetStock(0)
+	return c
+}
+
+func TestOrderAutomaticPromotions(t *testing.T) {
+	promotions := repository.NewMemoryCouponRepository()
+	shirtProd := newTaxedProduct("shirtProd", 20, product.TaxCategoryStandard)
+	sockProd := newTaxedProduct("sockProd", 5, product.TaxCategoryStandard)
+	newOrder := func(id string) *order.Order {
+		o := order.New(id).SetShippingRate(order.NewFlatRate(decimal.New(10, 0), "USD")).SetLedger(promotions).SetPromotions(promotions)
+		o.AddProduct(shirtProd, 3)
+		o.AddProduct(sockProd, 4)
+		return o
+	}
+
+	percentagePromotion := newAutomaticCoupon("AUTO10", coupon.KindPercentage, 10, 1)
+	promotions.Create(percentagePromotion)
+	noCouponPromotion := newAutomaticCoupon("NOCOUPON", coupon.KindValue, 20, 5).SetExcludesCoupons(true)
+	promotions.Create(noCouponPromotion)
+	exclusivePromotion := newAutomaticCoupon("EXCLUSIVE", coupon.KindValue, 30, 3).SetStackable(false)
+	promotions.Create(exclusivePromotion)
+	freeShippingPromotion := newAutomaticCoupon("FREESHIP", coupon.KindFreeShipping, 0, 0)
+	freeShippingPromotion.SetMinSubtotal(decimal.New(100, 0))
+	promotions.Create(freeShippingPromotion)
+	inactivePromotion := newAutomaticCoupon("INACTIVE", coupon.KindValue, 40, 9)
+	inactivePromotion.SetStatus(coupon.StatusInactive)
+	promotions.Create(inactivePromotion)
+	enteredCoupon := newConditionalCoupon("PERCENT10", coupon.KindPercentage, 10)
+	promotions.Create(enteredCoupon)
+
+	quotedOrder := newOrder("quotedOrder")
+	quoted, errQuote := quotedOrder.Quote("")
+	couponQuoted, _ := quotedOrder.Quote("", enteredCoupon)
+	withoutProvider, _ := newOrder("withoutProvider").SetPromotions(nil).Quote("")
+
+	submittedOrder := newOrder("submittedOrder")
+	submittedOk, _ := submittedOrder.Submit("ship name", "ship address")
+	promotionRedemptions, _ := promotions.FindRedemptions("NOCOUPON")
+	canceledOk, _ := submittedOrder.Cancel()
+
+	var automaticTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Quote With Promotions", true, nil == errQuote},
+		{"Promotions Applied By Priority", "NOCOUPON AUTO10", formatPromotions(quoted.AppliedPromotions())},
+		{"Percentage Promotion Discounts Amount Left", "6", quoted.AppliedPromotions()[1].Discount().String()},
+		{"Promotions Discount", "26", quoted.Discount().String()},
+		{"Promotions Total", "64", quoted.Total().String()},
+		{"No Coupon Applied", 0, len(quoted.AppliedCoupons())},
+		{"Coupon Skips Excluding Promotion", "EXCLUSIVE", formatPromotions(couponQuoted.AppliedPromotions())},
+		{"Promotions Applied Before Coupons", "5", couponQuoted.AppliedCoupons()[0].Discount().String()},
+		{"Coupon Quote Total", "55", couponQuoted.Total().String()},
+		{"Order Without Provider Has No Promotion", 0, len(withoutProvider.AppliedPromotions())},
+		{"Submit With Promotions", true, submittedOk},
+		{"Submitted Promotions", "NOCOUPON AUTO10", formatPromotions(submittedOrder.AppliedPromotions())},
+		{"Submitted Breakdown Promotions", 2, len(submittedOrder.Breakdown().AppliedPromotions())},
+		{"Submitted Amount", "64", submittedOrder.Amount().String()},
+		{"Promotion Stock Is Left Unchanged", int64(0), noCouponPromotion.Stock()},
+		{"Promotion Redemption Is Not Recorded", 0, len(promotionRedemptions)},
+		{"Cancel With Promotions", true, canceledOk},
+	}
+
+	for _, test := range automaticTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+//formatPromotions formats the ids of applied promotions space separated in their application order
+func formatPromotions(applied []*order.AppliedCoupon) string {
+	ids := make([]string, 0, len(applied))
+	for _, a := range applied {
+		ids = append(ids, a.Coupon().ID())
+	}
+	return strings.Join(ids, " ")
+}
@@ -0,0 +1,237 @@
+package order
+
+import (
+	"sort"
+	"sstest/model/coupon"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+//Line is the price breakdown of an order item
+type Line struct {
+	productID    string
+	unitPrice    decimal.Decimal
+	quantity     int
+	subtotal     decimal.Decimal //unit price multiplied by quantity
+	discount     decimal.Decimal //the item's share of the order's coupons' discounts
+	taxes        []*Tax
+	exchangeRate decimal.Decimal //converting the product's price into the order's currency (one when priced in it)
+	rateDate     time.Time       //the time the exchange rate was quoted at
+}
+
+//ProductID is a getter function for returning the product id of a price breakdown line
+func (l *Line) ProductID() string {
+	return l.productID
+}
+
+//UnitPrice is a getter function for returning the unit price a price breakdown line is priced with
+func (l *Line) UnitPrice() decimal.Decimal {
+	return l.unitPrice
+}
+
+//Quantity is a getter function for returning the quantity of a price breakdown line
+func (l *Line) Quantity() int {
+	return l.quantity
+}
+
+//Subtotal is a getter function for returning the amount of a price breakdown line (unit price multiplied by quantity)
+func (l *Line) Subtotal() decimal.Decimal {
+	return l.subtotal
+}
+
+//Discount is a getter function for returning the share of the order's coupons' discounts allocated to a price breakdown line
+func (l *Line) Discount() decimal.Decimal {
+	return l.discount
+}
+
+//Taxes is a getter function for returning the taxes of a price breakdown line in their rules' order
+func (l *Line) Taxes() []*Tax {
+	return l.taxes
+}
+
+//ExchangeRate is a getter function for returning the exchange rate converting a price breakdown line's product price into the order's currency
+func (l *Line) ExchangeRate() decimal.Decimal {
+	return l.exchangeRate
+}
+
+//RateDate is a getter function for returning the time the exchange rate of a price breakdown line was quoted at
+func (l *Line) RateDate() time.Time {
+	return l.rateDate
+}
+
+//Tax returns the total amount of the taxes of a price breakdown line
+func (l *Line) Tax() decimal.Decimal {
+	return sumTaxes(l.taxes)
+}
+
+//Breakdown is the itemized price of an order: its lines (ordered by product id) and its totals
+type Breakdown struct {
+	lines        []*Line
+	subtotal     decimal.Decimal
+	discount     decimal.Decimal
+	coupons      []*AppliedCoupon //the coupons applied in their application order, each with its discount
+	promotions   []*AppliedCoupon //the automatic promotions applied (before the coupons) in their priority order, each with its discount
+	shippingCost decimal.Decimal
+	tax          decimal.Decimal
+	taxIncluded  bool
+	total        decimal.Decimal
+}
+
+//newBreakdown creates an empty price breakdown having room for a count of lines
+func newBreakdown(lines int) *Breakdown {
+	zero := decimal.New(0, 0)
+	return &Breakdown{make([]*Line, 0, lines), zero, zero, make([]*AppliedCoupon, 0), make([]*AppliedCoupon, 0), zero, zero, false, zero}
+}
+
+//Lines is a getter function for returning the lines of a price breakdown ordered by product id
+func (b *Breakdown) Lines() []*Line {
+	return b.lines
+}
+
+//Subtotal is a getter function for returning the sum of the lines' amounts of a price breakdown
+func (b *Breakdown) Subtotal() decimal.Decimal {
+	return b.subtotal
+}
+
+//Discount is a getter function for returning the coupons' and automatic promotions' discounts of a price breakdown
+func (b *Breakdown) Discount() decimal.Decimal {
+	return b.discount
+}
+
+//AppliedCoupons is a getter function for returning the coupons applied on a price breakdown with their discounts, in their application order
+func (b *Breakdown) AppliedCoupons() []*AppliedCoupon {
+	return b.coupons
+}
+
+//AppliedPromotions is a getter function for returning the automatic promotions applied on a price breakdown with their discounts
+func (b *Breakdown) AppliedPromotions() []*AppliedCoupon {
+	return b.promotions
+}
+
+//apply is a function for applying the line discounts of a coupon (see Order.lineDiscounts) on a price breakdown, adding them to its lines' discounts and its discount
+//Returns the applied coupon (a free shipping coupon's discount is the shipping cost, set once rated)
+func (b *Breakdown) apply(c *coupon.Coupon, discounts []decimal.Decimal) *AppliedCoupon {
+	discount := decimal.New(0, 0)
+	for i, line := range b.lines {
+		if i < len(discounts) {
+			line.discount = line.discount.Add(discounts[i])
+			discount = discount.Add(discounts[i])
+		}
+	}
+	b.discount = b.discount.Add(discount)
+	return &AppliedCoupon{c, discount}
+}
+
+//ShippingCost is a getter function for returning the shipping cost of a price breakdown
+func (b *Breakdown) ShippingCost() decimal.Decimal {
+	return b.shippingCost
+}
+
+//Tax is a getter function for returning the sum of the lines' taxes of a price breakdown
+func (b *Breakdown) Tax() decimal.Decimal {
+	return b.tax
+}
+
+//TaxIncluded is a getter function for returning whether the taxes of a price breakdown are included in its lines' prices
+func (b *Breakdown) TaxIncluded() bool {
+	return b.taxIncluded
+}
+
+//Total is a getter function for returning the grand total of a price breakdown (the order's amount)
+func (b *Breakdown) Total() decimal.Decimal {
+	return b.total
+}
+
+//Breakdown returns the price breakdown an order was submitted with (zero amounts until it is submitted, see Quote)
+func (o *Order) Breakdown() *Breakdown {
+	productIDs := make([]string, 0, len(o.items))
+	for productID := range o.items {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+	b := newBreakdown(len(productIDs))
+	for _, productID := range productIDs {
+		val := o.items[productID]
+		b.lines = append(b.lines, &Line{productID, val.unitPrice, val.quantity, val.subtotal, val.discount, val.taxes, val.exchangeRate, val.rateDate})
+		b.tax = b.tax.Add(val.Tax())
+	}
+	b.subtotal, b.discount, b.shippingCost, b.taxIncluded, b.total = o.subtotal, o.discount, o.shippingCost, o.taxIncluded, o.amount
+	b.coupons = append(b.coupons, o.coupons...)
+	b.promotions = append(b.promotions, o.promotions...)
+	return b
+}
+
+//setBreakdown stores the price breakdown of an order on the order and its items (with its applied coupons and promotions)
+func (o *Order) setBreakdown(b *Breakdown) {
+	o.subtotal, o.discount, o.shippingCost, o.taxIncluded, o.amount = b.subtotal, b.discount, b.shippingCost, b.taxIncluded, b.total
+	o.coupons = append([]*AppliedCoupon{}, b.coupons...)
+	o.promotions = append([]*AppliedCoupon{}, b.promotions...)
+	for _, line := range b.lines {
+		if val, ok := o.items[line.productID]; ok {
+			val.unitPrice, val.subtotal, val.discount, val.taxes = line.unitPrice, line.subtotal, line.discount, line.taxes
+			val.exchangeRate, val.rateDate = line.exchangeRate, line.rateDate
+		}
+	}
+}
+
+//Subtotal is a getter function for returning the items' amount of an order, before discount, shipping and taxes (calculated on submission)
+func (o *Order) Subtotal() decimal.Decimal {
+	return o.subtotal
+}
+
+//Discount is a getter function for returning the coupons' discounts of an order (calculated on submission)
+func (o *Order) Discount() decimal.Decimal {
+	return o.discount
+}
+
+//SetSubtotal is a setter function for setting the items' amount of an order
+func (o *Order) SetSubtotal(subtotal decimal.Decimal) *Order {
+	o.subtotal = subtotal
+	return o
+}
+
+//SetDiscount is a setter function for setting the coupons' discounts of an order
+func (o *Order) SetDiscount(discount decimal.Decimal) *Order {
+	o.discount = discount
+	return o
+}
+
+//UnitPrice is a getter function for returning the unit price captured on an order item's submission
+func (i *Item) UnitPrice() decimal.Decimal {
+	if false == i.Captured() {
+		if nil == i.order {
+			return i.product.Price()
+		}
+		return convertPrice(i.product, i.order.Currency(), i.exchangeRate)
+	}
+	return i.unitPrice
+}
+
+//Subtotal is a getter function for returning an order item's amount, unit price multiplied by quantity (calculated on submission)
+func (i *Item) Subtotal() decimal.Decimal {
+	return i.subtotal
+}
+
+//Discount is a getter function for returning an order item's share of its order's coupons' discounts (calculated on submission)
+func (i *Item) Discount() decimal.Decimal {
+	return i.discount
+}
+
+//SetUnitPrice is a setter function for setting the unit price an order item was priced with
+func (i *Item) SetUnitPrice(unitPrice decimal.Decimal) *Item {
+	i.unitPrice = unitPrice
+	return i
+}
+
+//SetSubtotal is a setter function for setting an order item's amount
+func (i *Item) SetSubtotal(subtotal decimal.Decimal) *Item {
+	i.subtotal = subtotal
+	return i
+}
+
+//SetDiscount is a setter function for setting an order item's share of its order's coupons' discounts
+func (i *Item) SetDiscount(discount decimal.Decimal) *Item {
+	i.discount = discount
+	return i
+}
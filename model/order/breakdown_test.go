@@ -0,0 +1,146 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestOrderBreakdown(t *testing.T) {
+	bookProd := newTaxedProduct("bookProd", 10, product.TaxCategoryStandard)
+	penProd := newTaxedProduct("penProd", 20, product.TaxCategoryStandard)
+	gadgetProd := newTaxedProduct("gadgetProd", 100, product.TaxCategoryStandard)
+	valueCoupon := coupon.New("SAVE10")
+	valueCoupon.SetStatus(coupon.StatusActive)
+	valueCoupon.SetStock(10)
+	valueCoupon.SetKind(coupon.KindValue)
+	valueCoupon.SetValue(decimal.New(10, 0))
+
+	o := order.New("brokenDownOrder").
+		SetShippingRate(order.NewFlatRate(decimal.New(5, 0), "USD")).
+		SetTaxTable(order.NewTaxTable(order.NewTaxRegion("CA", false, order.NewTaxRule("GST", "", decimal.New(5, -2), false)))).
+		SetShippingRegion("CA")
+	o.AddProduct(bookProd, 3)
+	o.AddProduct(penProd, 1)
+	o.AddProduct(gadgetProd, 1)
+	draft := o.Breakdown()
+	draftSubtotal := o.Items()["bookProd"].Subtotal().String()
+	quoted, errQuote := o.Quote("", valueCoupon)
+	submitOk, _ := o.Submit("ship name", "ship address", valueCoupon)
+	submitted := o.Breakdown()
+	lineDiscounts, lineTaxes := decimal.New(0, 0), decimal.New(0, 0)
+	for _, line := range submitted.Lines() {
+		lineDiscounts = lineDiscounts.Add(line.Discount())
+		lineTaxes = lineTaxes.Add(line.Tax())
+	}
+
+	var orderBreakdownTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Draft Breakdown Lines", 3, len(draft.Lines())},
+		{"Draft Breakdown Total", "0", draft.Total().String()},
+		{"Draft Item Subtotal", "0", draftSubtotal},
+		{"Quote Error", true, nil == errQuote},
+		{"Quoted Subtotal", "150", quoted.Subtotal().String()},
+		{"Quoted Discount", "10", quoted.Discount().String()},
+		{"Quoted Shipping Cost", "5", quoted.ShippingCost().String()},
+		{"Quoted Tax", "7", quoted.Tax().String()},
+		{"Quoted Total", "152", quoted.Total().String()},
+		{"Quoted Lines Ordered By Product Id", "bookProd", quoted.Lines()[0].ProductID()},
+		{"Submit Must Succeed", true, submitOk},
+		{"Submitted Total Must Be Amount", o.Amount().String(), submitted.Total().String()},
+		{"Submitted Total Must Be Quoted Total", quoted.Total().String(), submitted.Total().String()},
+		{"Submitted Subtotal", "150", o.Subtotal().String()},
+		{"Submitted Discount", "10", o.Discount().String()},
+		{"Line Unit Price", "10", submitted.Lines()[0].UnitPrice().String()},
+		{"Line Quantity", 3, submitted.Lines()[0].Quantity()},
+		{"Line Subtotal", "30", submitted.Lines()[0].Subtotal().String()},
+		{"Proportional Line Discount", "2", submitted.Lines()[0].Discount().String()},
+		{"Rounded Line Discount", "6.67", submitted.Lines()[1].Discount().String()},
+		{"Last Line Takes Discount Remainder", "1.33", submitted.Lines()[2].Discount().String()},
+		{"Line Discounts Must Add Up To Discount", submitted.Discount().String(), lineDiscounts.String()},
+		{"Line Taxes Must Add Up To Tax", submitted.Tax().String(), lineTaxes.String()},
+		{"Item Unit Price", "20", o.Items()["penProd"].UnitPrice().String()},
+		{"Item Subtotal", "100", o.Items()["gadgetProd"].Subtotal().String()},
+		{"Item Discount", "6.67", o.Items()["gadgetProd"].Discount().String()},
+	}
+
+	for _, test := range orderBreakdownTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+//newConditionalCoupon creates an active coupon of a kind and value
+func newConditionalCoupon(id, kind string, value int64) *coupon.Coupon {
+	c := coupon.New(id)
+	c.SetStatus(coupon.StatusActive)
+	c.SetStock(10)
+	c.SetKind(kind)
+	c.SetValue(decimal.New(value, 0))
+	return c
+}
+
+func TestCouponEligibility(t *testing.T) {
+	o := order.New("conditionalOrder").SetShippingRate(order.NewFlatRate(decimal.New(0, 0), "USD"))
+	o.AddProduct(newTaxedProduct("bookProd", 10, product.TaxCategoryStandard), 3)
+	o.AddProduct(newTaxedProduct("penProd", 20, product.TaxCategoryStandard), 1)
+	o.AddProduct(newTaxedProduct("gadgetProd", 100, product.TaxCategoryStandard), 1)
+
+	cappedCoupon := newConditionalCoupon("CAPPED", coupon.KindPercentage, 10)
+	cappedCoupon.SetExcludedProducts("gadgetProd")
+	cappedCoupon.SetMaxDiscount(decimal.New(4, 0))
+	minSubtotalCoupon := newConditionalCoupon("MINSUBTOTAL", coupon.KindPercentage, 10)
+	minSubtotalCoupon.SetMinSubtotal(decimal.New(200, 0))
+	minQuantityCoupon := newConditionalCoupon("MINQUANTITY", coupon.KindPercentage, 10)
+	minQuantityCoupon.SetMinQuantity(6)
+	otherProductCoupon := newConditionalCoupon("OTHERPRODUCT", coupon.KindPercentage, 10)
+	otherProductCoupon.SetProducts("otherProd")
+	penCoupon := newConditionalCoupon("PEN", coupon.KindValue, 20)
+	penCoupon.SetProducts("penProd")
+
+	capped, errCapped := o.Quote("", cappedCoupon)
+	_, errMinSubtotal := o.Quote("", minSubtotalCoupon)
+	_, errMinQuantity := o.Quote("", minQuantityCoupon)
+	_, errOtherProduct := o.Quote("", otherProductCoupon)
+	_, errPen := o.Quote("", penCoupon)
+	submitOk, errSubmit := o.Submit("ship name", "ship address", minQuantityCoupon)
+
+	var couponEligibilityTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Quote Error", true, nil == errCapped},
+		{"Discount Capped On Eligible Items", "4", capped.Discount().String()},
+		{"Eligible Line Discount", "2.4", capped.Lines()[0].Discount().String()},
+		{"Excluded Line Not Discounted", "0", capped.Lines()[1].Discount().String()},
+		{"Last Eligible Line Takes Discount Remainder", "1.6", capped.Lines()[2].Discount().String()},
+		{"Total", "146", capped.Total().String()},
+		{"Subtotal Below Minimum", true, nil != errMinSubtotal && errors.Is(errMinSubtotal, coupon.ErrNotEligible)},
+		{"Quantity Below Minimum", true, nil != errMinQuantity && errors.Is(errMinQuantity, coupon.ErrNotEligible)},
+		{"No Discounted Item", true, nil != errOtherProduct && errors.Is(errOtherProduct, coupon.ErrNotEligible)},
+		{"Value Exceeding Eligible Items Amount", true, nil != errPen && errors.Is(errPen, order.ErrInvalidAmount)},
+		{"Submit With Ineligible Coupon Must Fail", false, submitOk},
+		{"Submit With Ineligible Coupon Failure Reason", true, nil != errSubmit && errors.Is(errSubmit, coupon.ErrNotEligible)},
+		{"Order Must Stay Draft", order.StatusDraft, o.Status()},
+	}
+
+	for _, test := range couponEligibilityTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
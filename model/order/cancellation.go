@@ -0,0 +1,38 @@
+package order
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//Canceler is interface of the order store recording an order's cancellation
+type Canceler interface {
+	//Cancel atomically changes the stored status of an order from a status to another only if the order is still stored with the former
+	//(fails with ErrInvalidStatus otherwise), incrementing the warehouse stocks of every given product by its allocation and reversing
+	//the redemptions of the given coupons by the order at a date (see Ledger.Reverse), either all or none of the changes are stored
+	//(the given products' and coupons' stocks are updated to the incremented values)
+	Cancel(orderID, from, to string, allocations Allocations, coupons []*coupon.Coupon, date time.Time) *errors.Error
+}
+
+//cancel is a function for recording the cancellation of an order taking a transition at a date
+func (o *Order) cancel(t Transition, date time.Time) *errors.Error {
+	if o.canceler != nil {
+		if err := o.canceler.Cancel(o.id, o.status, t.To, o.allocations(), o.Coupons(), date); err != nil {
+			return errors.WrapPrefix(err, fmt.Sprintf("Can't cancel: order %v", o.id), 0)
+		}
+		o.storedStatus = t.To
+		return nil
+	}
+	for _, applied := range o.coupons {
+		if err := o.ledger.Reverse(applied.coupon, o.id, date); err != nil {
+			return errors.WrapPrefix(err, fmt.Sprintf("Can't cancel: order %v can't reverse coupon %v redemption", o.id, applied.coupon.ID()), 0)
+		}
+	}
+	if err := o.inventory.IncrementStocks(o.allocations()); err != nil {
+		return errors.WrapPrefix(err, fmt.Sprintf("Can't cancel: order %v can't return product stock", o.id), 0)
+	}
+	return nil
+}
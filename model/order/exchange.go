@@ -0,0 +1,98 @@
+package order
+
+import (
+	"fmt"
+	"sstest/model/money"
+	"sstest/model/product"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//defaultExchangeRates is the exchange rate provider of newly created orders (nil when products aren't converted)
+var defaultExchangeRates money.ExchangeRateProvider
+
+//DefaultExchangeRates returns the exchange rate provider of newly created orders
+func DefaultExchangeRates() money.ExchangeRateProvider {
+	return defaultExchangeRates
+}
+
+//SetDefaultExchangeRates sets the exchange rate provider of newly created orders, no conversion when nil
+func SetDefaultExchangeRates(r money.ExchangeRateProvider) {
+	defaultExchangeRates = r
+}
+
+//exchangeRate returns the exchange rate converting a product's price into a currency with an order's exchange rates and the time it was quoted at
+func (o *Order) exchangeRate(p *product.Product, currency string) (decimal.Decimal, time.Time, *errors.Error) {
+	if p.Currency() == currency {
+		return decimal.New(1, 0), time.Unix(0, 0), nil
+	}
+	if nil == o.exchangeRates {
+		return decimal.Zero, time.Unix(0, 0), errors.WrapPrefix(ErrCurrencyMismatch, fmt.Sprintf("Can't convert product %v priced in %v into %v without exchange rates", p.ID(), p.Currency(), currency), 0)
+	}
+	rate, date, err := o.exchangeRates.Rate(p.Currency(), currency)
+	if err != nil {
+		return decimal.Zero, time.Unix(0, 0), errors.WrapPrefix(err, fmt.Sprintf("Can't convert product %v priced in %v into %v", p.ID(), p.Currency(), currency), 0)
+	}
+	return rate, date, nil
+}
+
+//convertPrice converts a product's price with an exchange rate into a currency, rounded to its minor unit
+func convertPrice(p *product.Product, currency string, rate decimal.Decimal) decimal.Decimal {
+	if p.Currency() == currency {
+		return p.Price()
+	}
+	return money.Convert(p.Price(), rate, currency)
+}
+
+//ChangeCurrency is a function for changing the currency of a draft order, converting its items' products prices with its exchange rates
+//Returns true if the currency change is successful or false and an error describing the failure
+func (o *Order) ChangeCurrency(currency string) (bool, *errors.Error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if false == o.machine.Editable(o.status) {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't change currency of order %v, status is %v (not editable)", o.id, o.machine.Label(o.status)), 0)
+	}
+	if false == money.IsCurrency(currency) {
+		return false, errors.Wrap(fmt.Errorf("Can't change order %v to unknown currency %v, expected one of: %v", o.id, currency, strings.Join(money.Currencies(), ", ")), 0)
+	}
+	rates := make(map[string]decimal.Decimal, len(o.items))
+	rateDates := make(map[string]time.Time, len(o.items))
+	for productID, val := range o.items {
+		rate, rateDate, err := o.exchangeRate(val.product, currency)
+		if err != nil {
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't change currency of order %v", o.id), 0)
+		}
+		rates[productID], rateDates[productID] = rate, rateDate
+	}
+	for productID, val := range o.items {
+		val.exchangeRate, val.rateDate = rates[productID], rateDates[productID]
+	}
+	o.currency = currency
+	return true, nil
+}
+
+//ExchangeRate is a getter function for returning the exchange rate converting an order item's product price into its order's currency
+func (i *Item) ExchangeRate() decimal.Decimal {
+	return i.exchangeRate
+}
+
+//RateDate is a getter function for returning the time an order item's exchange rate was quoted at
+func (i *Item) RateDate() time.Time {
+	return i.rateDate
+}
+
+//SetExchangeRate is a setter function for setting the exchange rate converting an order item's product price into its order's currency
+func (i *Item) SetExchangeRate(rate decimal.Decimal) *Item {
+	i.exchangeRate = rate
+	return i
+}
+
+//SetRateDate is a setter function for setting the time an order item's exchange rate was quoted at
+func (i *Item) SetRateDate(rateDate time.Time) *Item {
+	i.rateDate = rateDate
+	return i
+}
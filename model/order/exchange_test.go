@@ -0,0 +1,88 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//newPricedProduct creates an available product having a price in a currency
+func newPricedProduct(id string, price int64, currency string) *product.Product {
+	p := product.New(id, id)
+	p.SetStatus(product.StatusAvailable)
+	p.SetStock(100)
+	p.SetPrice(decimal.New(price, 0))
+	p.SetCurrency(currency)
+	return p
+}
+
+func TestOrderExchangeRates(t *testing.T) {
+	t.Cleanup(func() { money.SetDefaultRounding("") })
+	date := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	rates := money.NewRateTable("USD", date, map[string]decimal.Decimal{"EUR": decimal.New(9, -1), "JPY": decimal.New(150, 0)})
+	euroProd, yenProd := newPricedProduct("euroProd", 100, "EUR"), newPricedProduct("yenProd", 1000, "JPY")
+	swissProd := newPricedProduct("swissProd", 100, "CHF")
+
+	o := order.New("convertedOrder").SetExchangeRates(rates)
+	euroOk, errEuro := o.AddProduct(euroProd, 1)
+	o.AddProduct(yenProd, 1)
+	_, errSwiss := o.AddProduct(swissProd, 1)
+	quoted, errQuote := o.Quote("", nil)
+	changeOk, _ := o.ChangeCurrency("JPY")
+	yenQuoted, _ := o.Quote("", nil)
+	_, errUnknownCurrency := o.ChangeCurrency("XYZ")
+	o.ChangeCurrency("USD")
+	money.SetDefaultRounding(money.RoundUp)
+	roundedUp, _ := o.Quote("", nil)
+	money.SetDefaultRounding("")
+	submitOk, _ := o.Submit("ship name", "ship address", nil)
+	o.SetExchangeRates(money.NewRateTable("USD", date, map[string]decimal.Decimal{"EUR": decimal.New(5, -1), "JPY": decimal.New(100, 0)}))
+	_, errSubmittedChange := o.ChangeCurrency("EUR")
+
+	unconverted := order.New("unconvertedOrder")
+	unconverted.AddProduct(euroProd, 1)
+	_, errUnconverted := unconverted.ChangeCurrency("USD")
+
+	var orderExchangeRateTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Product In Other Currency Must Be Added", true, euroOk},
+		{"Product In Other Currency Error", true, nil == errEuro},
+		{"Order Must Keep Its Currency", money.DefaultCurrency, o.Currency()},
+		{"Product Without Rate Must Not Be Added", true, errors.Is(errSwiss, money.ErrNoRate)},
+		{"Quote Without Error", true, nil == errQuote},
+		{"Quoted Unit Price Rounded To Cents", "111.11", quoted.Lines()[0].UnitPrice().String()},
+		{"Quoted Line Rate", "0.0066666666666667", quoted.Lines()[1].ExchangeRate().String()},
+		{"Quoted Total", "117.78", quoted.Total().String()},
+		{"Change Currency Must Succeed", true, changeOk},
+		{"Unit Price Rounded To Yen", "16667", yenQuoted.Lines()[0].UnitPrice().String()},
+		{"Product In Order Currency Must Not Be Converted", "1000", yenQuoted.Lines()[1].UnitPrice().String()},
+		{"Unknown Currency Must Not Be Changed To", false, nil == errUnknownCurrency},
+		{"Configured Rounding", "111.12", roundedUp.Lines()[0].UnitPrice().String()},
+		{"Submit Must Succeed", true, submitOk},
+		{"Submitted Amount", "117.78", o.Amount().String()},
+		{"Item Exchange Rate", "1.1111111111111111", o.Items()["euroProd"].ExchangeRate().String()},
+		{"Item Rate Date", date, o.Items()["euroProd"].RateDate()},
+		{"Captured Unit Price Must Not Follow Rates", "111.11", o.Items()["euroProd"].UnitPrice().String()},
+		{"Item In Order Currency Rate", "1", order.NewItem("item", o, newPricedProduct("dollarProd", 1, "USD")).ExchangeRate().String()},
+		{"Submitted Order Currency Must Not Change", true, errors.Is(errSubmittedChange, order.ErrInvalidStatus)},
+		{"Order Without Rates Must Not Convert", true, errors.Is(errUnconverted, order.ErrCurrencyMismatch)},
+		{"Order Without Rates Must Keep Currency", "EUR", unconverted.Currency()},
+	}
+
+	for _, test := range orderExchangeRateTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
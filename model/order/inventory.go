@@ -0,0 +1,81 @@
+package order
+
+import (
+	"sort"
+	"sstest/model/product"
+
+	"github.com/go-errors/errors"
+)
+
+//Inventory is interface of the product stock store decremented by order submission and incremented by order cancellation
+type Inventory interface {
+	//DecrementStocks atomically decrements the stock of every given product by its quantity only if every stock is enough,
+	//each quantity being taken from the warehouses picked by the allocator (nil for product.DefaultAllocator),
+	//either all or none of the stocks are decremented (the given products' stocks are updated to the decremented values)
+	//Returns the allocation of every given product
+	DecrementStocks(quantities map[*product.Product]int, allocate product.Allocator) (Allocations, *errors.Error)
+	//IncrementStocks atomically increments the warehouse stocks of every given product by its allocation (returning the stock of a canceled order),
+	//a product no longer in the inventory is skipped (the given products' stocks are updated to the incremented values)
+	IncrementStocks(allocations Allocations) *errors.Error
+}
+
+//Allocations is the allocation of the ordered quantity of every product on the warehouse stocks
+type Allocations map[*product.Product]product.Allocation
+
+//Products returns the products of allocations sorted by product id
+func (a Allocations) Products() []*product.Product {
+	products := make([]*product.Product, 0, len(a))
+	for p := range a {
+		products = append(products, p)
+	}
+	sortProducts(products)
+	return products
+}
+
+//productInventory is the default Inventory of an order, changing the stock held by the products themselves
+type productInventory struct{}
+
+//DecrementStocks is a function for decrementing the stock of the given products, rolling back on failure
+func (productInventory) DecrementStocks(quantities map[*product.Product]int, allocate product.Allocator) (Allocations, *errors.Error) {
+	products := SortedProducts(quantities)
+	allocations := make(Allocations, len(products))
+	for _, p := range products {
+		allocation, err := p.TakeStock(quantities[p], allocate)
+		if err != nil {
+			//roll back stocks that are already decremented
+			for done, doneAllocation := range allocations {
+				done.ReturnStock(doneAllocation)
+			}
+			return nil, err
+		}
+		allocations[p] = allocation
+	}
+	return allocations, nil
+}
+
+//IncrementStocks is a function for incrementing the warehouse stocks of the given products
+func (productInventory) IncrementStocks(allocations Allocations) *errors.Error {
+	for _, p := range allocations.Products() {
+		if _, err := p.ReturnStock(allocations[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//SortedProducts returns the products of a product quantity map sorted by product id
+func SortedProducts(quantities map[*product.Product]int) []*product.Product {
+	products := make([]*product.Product, 0, len(quantities))
+	for p := range quantities {
+		products = append(products, p)
+	}
+	sortProducts(products)
+	return products
+}
+
+//sortProducts sorts products by product id
+func sortProducts(products []*product.Product) {
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ID() < products[j].ID()
+	})
+}
@@ -5,22 +5,35 @@ import (
 	"fmt"
 	"sstest/model/product"
 	"sync"
+	"time"
 
 	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
 )
 
 //Item is business domain model definition of order item
 type Item struct {
-	id       string
-	order    *Order
-	product  *product.Product
-	quantity int
-	mu       sync.Mutex
+	id           string
+	order        *Order
+	product      *product.Product
+	quantity     int
+	allocation   product.Allocation //the warehouses fulfilling the item (nil until the order is submitted)
+	taxes        []*Tax             //the item's taxes (nil until the order is submitted, or when not taxed)
+	unitPrice    decimal.Decimal    //the price breakdown of the item (zero until the order is submitted)
+	subtotal     decimal.Decimal
+	discount     decimal.Decimal
+	name         string //the product name, status and tax category captured on submission (empty until then)
+	status       string
+	taxCategory  string
+	exchangeRate decimal.Decimal //converting the product's price into the order's currency (one when priced in it)
+	rateDate     time.Time       //the time the exchange rate was quoted at (recorded on addition and submission)
+	mu           sync.Mutex
 }
 
 //NewItem creates a new order item model struct, initializes it's properties and returns a reference to it
 func NewItem(id string, orderID *Order, productID *product.Product) *Item {
-	return &Item{id, orderID, productID, 0, *new(sync.Mutex)}
+	return &Item{id, orderID, productID, 0, nil, nil, decimal.New(0, 0), decimal.New(0, 0), decimal.New(0, 0), "", "", "",
+		decimal.New(1, 0), time.Unix(0, 0), *new(sync.Mutex)}
 }
 
 //ID is a getter function for returning an order item's id
@@ -43,6 +56,40 @@ func (i *Item) Quantity() int {
 	return i.quantity
 }
 
+//Allocation is a getter function for returning the quantity taken from every warehouse fulfilling an order item
+func (i *Item) Allocation() product.Allocation {
+	return i.allocation
+}
+
+//ProductName is a getter function for returning the product name captured on an order item's submission
+func (i *Item) ProductName() string {
+	if false == i.Captured() {
+		return i.product.Name()
+	}
+	return i.name
+}
+
+//ProductStatus is a getter function for returning the product status captured on an order item's submission
+func (i *Item) ProductStatus() string {
+	if false == i.Captured() {
+		return i.product.Status()
+	}
+	return i.status
+}
+
+//TaxCategory is a getter function for returning the product tax category captured on an order item's submission
+func (i *Item) TaxCategory() string {
+	if false == i.Captured() {
+		return i.product.TaxCategory()
+	}
+	return i.taxCategory
+}
+
+//Captured returns whether an order item holds the product values captured on its order's submission
+func (i *Item) Captured() bool {
+	return "" != i.status
+}
+
 //SetID is a setter function for setting an order item's id
 func (i *Item) SetID(id string) *Item {
 	i.id = id
@@ -67,8 +114,37 @@ func (i *Item) SetQuantity(quantity int) *Item {
 	return i
 }
 
+//SetAllocation is a setter function for setting the quantity taken from every warehouse fulfilling an order item
+func (i *Item) SetAllocation(allocation product.Allocation) *Item {
+	i.allocation = allocation
+	return i
+}
+
+//SetProductName is a setter function for setting the product name captured on an order item's submission
+func (i *Item) SetProductName(name string) *Item {
+	i.name = name
+	return i
+}
+
+//SetProductStatus is a setter function for setting the product status captured on an order item's submission
+func (i *Item) SetProductStatus(status string) *Item {
+	i.status = status
+	return i
+}
+
+//SetTaxCategory is a setter function for setting the product tax category captured on an order item's submission
+func (i *Item) SetTaxCategory(category string) *Item {
+	i.taxCategory = category
+	return i
+}
+
 //Business logic methods
 
+//capture is a function for capturing an order item's product name, status and tax category
+func (i *Item) capture() {
+	i.name, i.status, i.taxCategory = i.product.Name(), i.product.Status(), i.product.TaxCategory()
+}
+
 //AddQuantity is a function for adding some quantity to an order item
 /*
 func (i *Item) AddQuantity(quantity int) (*Item, error) {
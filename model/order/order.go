@@ -3,8 +3,11 @@ package order
 
 import (
 	"fmt"
+	"sort"
 	"sstest/model/coupon"
+	"sstest/model/money"
 	"sstest/model/product"
+	"sstest/model/user"
 	"sync"
 	"time"
 
@@ -40,51 +43,125 @@ var statusMap = map[string]string{
 //ShipStatusNone is const for 'none' order ship status
 const ShipStatusNone string = "N"
 
-//ShipStatusOnProcess is const for 'on process' order ship status
+//ShipStatusPartiallyShipped is const for 'partially shipped' order ship status (some ordered quantity is not shipped yet)
+const ShipStatusPartiallyShipped string = "PS"
+
+//ShipStatusOnProcess is const for 'on process' order ship status (every ordered quantity is shipped, no carrier tracking event yet)
 const ShipStatusOnProcess string = "O"
 
-//ShipStatusDelivered is const for 'on process' order ship status
+//ShipStatusInTransit is const for 'in transit' order ship status (every ordered quantity is shipped, picked up by the carrier)
+const ShipStatusInTransit string = "T"
+
+//ShipStatusOutForDelivery is const for 'out for delivery' order ship status (the latest tracking event is out for delivery)
+const ShipStatusOutForDelivery string = "OD"
+
+//ShipStatusDeliveryFailed is const for 'delivery failed' order ship status (the latest tracking event is a failed delivery attempt)
+const ShipStatusDeliveryFailed string = "F"
+
+//ShipStatusReturned is const for 'returned' order ship status (every shipment is returned to sender, nothing left shipped)
+const ShipStatusReturned string = "R"
+
+//ShipStatusDelivered is const for 'delivered' order ship status (every ordered quantity is delivered)
 const ShipStatusDelivered string = "D"
 
 //shipstatusMap is a map of known shipping status code and its label pairs
 var shipstatusMap = map[string]string{
-	ShipStatusNone:      "None",
-	ShipStatusOnProcess: "On Process",
-	ShipStatusDelivered: "Delivered",
+	ShipStatusNone:             "None",
+	ShipStatusPartiallyShipped: "Partially Shipped",
+	ShipStatusOnProcess:        "On Process",
+	ShipStatusInTransit:        "In Transit",
+	ShipStatusOutForDelivery:   "Out For Delivery",
+	ShipStatusDeliveryFailed:   "Delivery Failed",
+	ShipStatusReturned:         "Returned",
+	ShipStatusDelivered:        "Delivered",
 }
 
+//ErrInvalidStatus is the error returned (wrapped) when an operation is not allowed in the order's current status
+var ErrInvalidStatus = fmt.Errorf("invalid order status")
+
+//ErrNoItem is the error returned (wrapped) when an operation requires an order to have items
+var ErrNoItem = fmt.Errorf("order has no item")
+
+//ErrItemNotFound is the error returned (wrapped) when an order has no item of a given product
+var ErrItemNotFound = fmt.Errorf("order item not found")
+
+//ErrInvalidAmount is the error returned (wrapped) when an order's calculated amount is zero or less
+var ErrInvalidAmount = fmt.Errorf("invalid order amount")
+
+//ErrCurrencyMismatch is the error returned (wrapped) when a product or a value coupon is not in the currency of an order
+var ErrCurrencyMismatch = fmt.Errorf("currency mismatch")
+
 //Order is business domain model definition of order
 type Order struct {
-	id                 string
-	createdDate        time.Time
-	submittedDate      time.Time
-	processedDate      time.Time
-	status             string
-	items              map[string]*Item
-	coupon             *coupon.Coupon
-	amount             decimal.Decimal
-	shippingName       string
-	shippingAddress    string
-	shippingStatus     string
-	shippingTrackingID string
-	mu                 sync.Mutex
+	id              string
+	createdDate     time.Time
+	submittedDate   time.Time
+	processedDate   time.Time
+	status          string
+	storedStatus    string //the status the order was loaded or last saved with (empty for an order not stored yet)
+	items           map[string]*Item
+	coupons         []*AppliedCoupon //the coupons applied on submission, in their application order
+	promotions      []*AppliedCoupon //the automatic promotions applied on submission, in their application order
+	user            *user.User       //the customer placing the order (nil for an order without customer)
+	currency        string           //ISO 4217 code of every amount of the order (empty until set or taken from its first item)
+	amount          decimal.Decimal
+	subtotal        decimal.Decimal //the items' amount, before discount, shipping and taxes
+	discount        decimal.Decimal //the coupons' and automatic promotions' discounts
+	shippingCost    decimal.Decimal
+	taxIncluded     bool //whether the taxes are included in the items' prices (or added to the amount)
+	shippingName    string
+	shippingAddress string
+	shippingRegion  string      //the tax region the order is shipped to
+	shipments       []*Shipment //the packages fulfilling the order, in the order they were shipped
+	inventory       Inventory
+	ledger          Ledger            //recording the coupons' redemptions on submission and reversing them on cancellation
+	canceler        Canceler          //storing the cancellation atomically (nil for returning the stocks through the inventory and the ledger)
+	submitter       Submitter         //storing the submission atomically (nil for taking the stocks through the inventory)
+	promotionRules  PromotionProvider //providing the automatic promotions evaluated when pricing the draft order
+	allocator       product.Allocator
+	shippingRate    ShippingRateProvider
+	taxTable        *TaxTable
+	exchangeRates   money.ExchangeRateProvider //converting the items' prices into the order's currency (nil when they must be in it)
+	stacking        string                     //the order stacked coupons are applied in
+	machine         *StateMachine
+	mu              sync.Mutex
 }
 
 //New creates a new product model struct, initializes it's properties and returns a reference to it
 func New(id string) *Order {
+	machine := DefaultStateMachine()
 	return &Order{
 		id,
 		time.Now(),
 		time.Unix(0, 0),
 		time.Unix(0, 0),
-		StatusDraft,
+		machine.Initial(),
+		"",
 		make(map[string]*Item, 5),
+		make([]*AppliedCoupon, 0),
+		make([]*AppliedCoupon, 0),
 		nil,
+		"",
+		decimal.New(0, 0),
+		decimal.New(0, 0),
+		decimal.New(0, 0),
 		decimal.New(0, 0),
+		false,
 		"",
 		"",
-		ShipStatusNone,
 		"",
+		make([]*Shipment, 0),
+		productInventory{},
+		couponLedger{},
+		nil,
+		nil,
+		noPromotions{},
+		product.DefaultAllocator,
+		defaultShippingRate,
+		defaultTaxTable,
+		defaultExchangeRates,
+		defaultStacking,
+		machine,
 		*new(sync.Mutex),
 	}
 }
@@ -119,11 +196,39 @@ func (o *Order) Items() map[string]*Item {
 	return o.items
 }
 
-//Amount is a getter function for returning an order's amount
+//User is a getter function for returning an order's user (the customer placing the order)
+func (o *Order) User() *user.User {
+	return o.user
+}
+
+//Currency is a getter function for returning the ISO 4217 code of the currency of an order's amounts
+func (o *Order) Currency() string {
+	if "" == o.currency {
+		return money.DefaultCurrency
+	}
+	return o.currency
+}
+
+//HasCurrency returns whether the currency of an order is set
+func (o *Order) HasCurrency() bool {
+	return "" != o.currency
+}
+
+//Amount is a getter function for returning an order's amount (including its shipping cost)
 func (o *Order) Amount() decimal.Decimal {
 	return o.amount
 }
 
+//ShippingCost is a getter function for returning an order's shipping cost (rated on submission)
+func (o *Order) ShippingCost() decimal.Decimal {
+	return o.shippingCost
+}
+
+//ShippingRate is a getter function for returning the provider rating an order's shipping cost
+func (o *Order) ShippingRate() ShippingRateProvider {
+	return o.shippingRate
+}
+
 //ShippingName is a getter function for returning an order's shipping name
 func (o *Order) ShippingName() string {
 	return o.shippingName
@@ -134,14 +239,24 @@ func (o *Order) ShippingAddress() string {
 	return o.shippingAddress
 }
 
-//ShippingStatus is a getter function for returning an order's shipping status
-func (o *Order) ShippingStatus() string {
-	return o.shippingStatus
+//ShippingRegion is a getter function for returning the tax region an order is shipped to
+func (o *Order) ShippingRegion() string {
+	return o.shippingRegion
 }
 
-//ShippingTrackingID is a getter function for returning an order's shipping tracking id
-func (o *Order) ShippingTrackingID() string {
-	return o.shippingTrackingID
+//TaxIncluded is a getter function for returning whether an order's taxes are included in its items' prices (or added to its amount)
+func (o *Order) TaxIncluded() bool {
+	return o.taxIncluded
+}
+
+//TaxTable is a getter function for returning the tax table taxing an order
+func (o *Order) TaxTable() *TaxTable {
+	return o.taxTable
+}
+
+//ExchangeRates is a getter function for returning the provider converting an order's items' prices into its currency
+func (o *Order) ExchangeRates() money.ExchangeRateProvider {
+	return o.exchangeRates
 }
 
 //SetID is a setter function for setting an order's id
@@ -168,9 +283,14 @@ func (o *Order) SetProcessedDate(processedDate time.Time) *Order {
 	return o
 }
 
-//SetStatus is a setter function for setting an order's status
+//StateMachine is a getter function for returning the state machine driving an order's status changes
+func (o *Order) StateMachine() *StateMachine {
+	return o.machine
+}
+
+//SetStatus is a setter function for setting an order's status (one of its state machine's statuses)
 func (o *Order) SetStatus(status string) (*Order, *errors.Error) {
-	if _, ok := statusMap[status]; false == ok {
+	if false == o.machine.HasStatus(status) {
 		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
 		return nil, errors.Wrap(fmt.Errorf("Can't set unknown status type: %v", status), 0)
 	}
@@ -178,9 +298,26 @@ func (o *Order) SetStatus(status string) (*Order, *errors.Error) {
 	return o, nil
 }
 
-//SetCoupon is a setter function for setting an order's coupon
-func (o *Order) SetCoupon(coupon *coupon.Coupon) *Order {
-	o.coupon = coupon
+//StoredStatus is a getter function for returning the status an order was loaded or last saved with
+func (o *Order) StoredStatus() string {
+	return o.storedStatus
+}
+
+//SetStoredStatus is a setter function for setting the status an order was loaded or last saved with (set by the order repositories)
+func (o *Order) SetStoredStatus(status string) *Order {
+	o.storedStatus = status
+	return o
+}
+
+//SetUser is a setter function for setting an order's user (the customer placing the order)
+func (o *Order) SetUser(u *user.User) *Order {
+	o.user = u
+	return o
+}
+
+//SetCurrency is a setter function for setting the ISO 4217 code of the currency of an order's amounts (empty for none)
+func (o *Order) SetCurrency(currency string) *Order {
+	o.currency = currency
 	return o
 }
 
@@ -190,6 +327,12 @@ func (o *Order) SetAmount(amount decimal.Decimal) *Order {
 	return o
 }
 
+//SetShippingCost is a setter function for setting an order's shipping cost
+func (o *Order) SetShippingCost(shippingCost decimal.Decimal) *Order {
+	o.shippingCost = shippingCost
+	return o
+}
+
 //SetShippingName is a setter function for setting an order's shipping name
 func (o *Order) SetShippingName(name string) *Order {
 	o.shippingName = name
@@ -202,19 +345,90 @@ func (o *Order) SetShippingAddress(address string) *Order {
 	return o
 }
 
-//SetShippingStatus is a setter function for setting an order's shipping status
-func (o *Order) SetShippingStatus(status string) (*Order, *errors.Error) {
-	if _, ok := shipstatusMap[status]; false == ok {
-		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
-		return nil, errors.Wrap(fmt.Errorf("Can't set unknown shipping status type: %v", status), 0)
+//SetShippingRegion is a setter function for setting the tax region an order is shipped to (set before submission to tax the order)
+func (o *Order) SetShippingRegion(region string) *Order {
+	o.shippingRegion = region
+	return o
+}
+
+//SetTaxIncluded is a setter function for setting whether an order's taxes are included in its items' prices
+func (o *Order) SetTaxIncluded(included bool) *Order {
+	o.taxIncluded = included
+	return o
+}
+
+//SetInventory is a setter function for setting the inventory an order's product stocks are decremented from on submission
+func (o *Order) SetInventory(inventory Inventory) *Order {
+	if nil == inventory {
+		inventory = productInventory{}
 	}
-	o.shippingStatus = status
-	return o, nil
+	o.inventory = inventory
+	return o
+}
+
+//SetLedger is a setter function for setting the ledger an order's coupon redemptions are recorded in
+func (o *Order) SetLedger(ledger Ledger) *Order {
+	if nil == ledger {
+		ledger = couponLedger{}
+	}
+	o.ledger = ledger
+	return o
+}
+
+//SetCanceler is a setter function for setting the store an order's cancellation is recorded in
+func (o *Order) SetCanceler(canceler Canceler) *Order {
+	o.canceler = canceler
+	return o
+}
+
+//SetSubmitter is a setter function for setting the store an order's submission is recorded in (defaults to nil)
+func (o *Order) SetSubmitter(submitter Submitter) *Order {
+	o.submitter = submitter
+	return o
+}
+
+//SetAllocator is a setter function for setting the strategy picking the warehouses fulfilling every item on submission
+func (o *Order) SetAllocator(allocate product.Allocator) *Order {
+	if nil == allocate {
+		allocate = product.DefaultAllocator
+	}
+	o.allocator = allocate
+	return o
+}
+
+//SetShippingRate is a setter function for setting the provider rating an order's shipping cost on submission
+func (o *Order) SetShippingRate(r ShippingRateProvider) *Order {
+	if nil == r {
+		r = defaultShippingRate
+	}
+	o.shippingRate = r
+	return o
+}
+
+//SetTaxTable is a setter function for setting the tax table taxing an order on submission
+func (o *Order) SetTaxTable(t *TaxTable) *Order {
+	if nil == t {
+		t = defaultTaxTable
+	}
+	o.taxTable = t
+	return o
 }
 
-//SetShippingTrackingID is a setter function for setting an order's shipping tracking id
-func (o *Order) SetShippingTrackingID(trackNo string) *Order {
-	o.shippingTrackingID = trackNo
+//SetExchangeRates is a setter function for setting the provider converting an order's items' prices into its currency
+func (o *Order) SetExchangeRates(r money.ExchangeRateProvider) *Order {
+	if nil == r {
+		r = defaultExchangeRates
+	}
+	o.exchangeRates = r
+	return o
+}
+
+//SetStateMachine is a setter function for setting the state machine driving an order's status changes
+func (o *Order) SetStateMachine(m *StateMachine) *Order {
+	if nil == m {
+		m = DefaultStateMachine()
+	}
+	o.machine = m
 	return o
 }
 
@@ -223,22 +437,41 @@ func (o *Order) SetShippingTrackingID(trackNo string) *Order {
 //AddProduct is a function for adding a product to an order (as order item) with a specified quantity for the purpose of ordering
 //Returns true if product addition is successful or false and an error describing the failure
 func (o *Order) AddProduct(product *product.Product, quantity int) (bool, *errors.Error) {
-	if StatusDraft != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't add product to order %v, status is %v (not draft)", o.id, statusMap[o.status]), 0)
+	if false == o.machine.Editable(o.status) {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't add product to order %v, status is %v (not editable)", o.id, o.machine.Label(o.status)), 0)
 	}
 	if false == o.HasProduct(product) {
 		//product doesn't exist in order item
+		currency := o.currency
+		if "" == currency {
+			currency = money.DefaultCurrency
+			if nil == o.exchangeRates {
+				currency = product.Currency()
+			}
+		}
+		rate, rateDate, err := o.exchangeRate(product, currency)
+		if err != nil {
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't add product %v to order %v", product.ID(), o.id), 0)
+		}
 		if ok, err := product.CanBeOrdered(quantity); false == ok {
-			return false, errors.Wrap(fmt.Errorf("Can't add product %v to order %v with quantity %d: %v", product.ID(), o.id, quantity, err), 0)
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't add product %v to order %v with quantity %d", product.ID(), o.id, quantity), 0)
+		}
+		if err := o.hold(product, quantity); err != nil {
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't add product %v to order %v, can't hold quantity %d", product.ID(), o.id, quantity), 0)
 		}
 		newItem := NewItem(uuid.New().String(), o, product)
 		newItem.quantity = quantity
+		newItem.exchangeRate, newItem.rateDate = rate, rateDate
 		o.items[product.ID()] = newItem
+		o.currency = currency
 	} else {
 		//product exists in order item
 		existingItem := o.items[product.ID()]
 		if ok, err := product.CanBeOrdered(existingItem.quantity + quantity); false == ok {
-			return false, errors.Wrap(fmt.Errorf("Can't order product %v with quantity %d: %v", product.ID(), existingItem.quantity+quantity, err), 0)
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't order product %v with quantity %d", product.ID(), existingItem.quantity+quantity), 0)
+		}
+		if err := o.hold(product, existingItem.quantity+quantity); err != nil {
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't order product %v, can't hold quantity %d", product.ID(), existingItem.quantity+quantity), 0)
 		}
 		o.items[product.ID()].AddQuantity(quantity)
 	}
@@ -248,16 +481,19 @@ func (o *Order) AddProduct(product *product.Product, quantity int) (bool, *error
 //EditProduct is a function for editing a product in an order (as order item) to a specified quantity for the purpose of ordering
 //Returns true if product editing is successful or false and an error describing the failure
 func (o *Order) EditProduct(product *product.Product, quantity int) (bool, *errors.Error) {
-	if StatusDraft != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't edit product in order %v, status is %v (not draft)", o.id, statusMap[o.status]), 0)
+	if false == o.machine.Editable(o.status) {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't edit product in order %v, status is %v (not editable)", o.id, o.machine.Label(o.status)), 0)
 	}
 	if false == o.HasProduct(product) {
-		return false, errors.Wrap(fmt.Errorf("Can't edit, order %v has no product with id: %v", o.id, product.ID()), 0)
+		return false, errors.WrapPrefix(ErrItemNotFound, fmt.Sprintf("Can't edit, order %v has no product with id: %v", o.id, product.ID()), 0)
 	}
 	//else product exists in order
 	existingItem := o.items[product.ID()]
 	if ok, err := product.CanBeOrdered(existingItem.quantity + quantity); false == ok {
-		return false, errors.Wrap(fmt.Errorf("Can't edit, can't order product %v with quantity %d: %v", product.ID(), existingItem.quantity+quantity, err), 0)
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't edit, can't order product %v with quantity %d", product.ID(), existingItem.quantity+quantity), 0)
+	}
+	if err := o.hold(product, existingItem.quantity+quantity); err != nil {
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't edit, can't hold product %v with quantity %d", product.ID(), existingItem.quantity+quantity), 0)
 	}
 	existingItem.quantity += quantity
 	return true, nil
@@ -266,11 +502,14 @@ func (o *Order) EditProduct(product *product.Product, quantity int) (bool, *erro
 //DeleteProduct is a function for removing a product from an order (as order item) for the purpose of ordering
 //Returns true if product deletion is successful or false and an error describing the failure
 func (o *Order) DeleteProduct(product *product.Product) (bool, *errors.Error) {
-	if StatusDraft != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't delete product in order %v, status is %v (not draft)", o.id, statusMap[o.status]), 0)
+	if false == o.machine.Editable(o.status) {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't delete product in order %v, status is %v (not editable)", o.id, o.machine.Label(o.status)), 0)
 	}
 	if false == o.HasProduct(product) {
-		return false, errors.Wrap(fmt.Errorf("Can't delete, order %v has no product with id: %v", o.id, product.ID()), 0)
+		return false, errors.WrapPrefix(ErrItemNotFound, fmt.Sprintf("Can't delete, order %v has no product with id: %v", o.id, product.ID()), 0)
+	}
+	if err := o.release(product); err != nil {
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't delete, order %v can't release product %v", o.id, product.ID()), 0)
 	}
 	delete(o.items, product.ID())
 	return true, nil
@@ -283,114 +522,281 @@ func (o *Order) HasProduct(product *product.Product) bool {
 	return ok
 }
 
-//applyCoupon is a function for applying a coupon to an order
-//Returns true if coupon application is successful or false and an error describing the failure
-func (o *Order) applyCoupon(coupon *coupon.Coupon) (bool, *errors.Error) {
-	if StatusDraft != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't apply coupon in order %v, status is %v (not draft)", o.id, statusMap[o.status]), 0)
+//applyCoupons is a function for applying coupons to an order, given in their application order (see stack)
+//Returns true if coupons application is successful or false and an error describing the failure
+func (o *Order) applyCoupons(coupons []*coupon.Coupon) (bool, *errors.Error) {
+	if false == o.machine.Editable(o.status) {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't apply coupon in order %v, status is %v (not editable)", o.id, o.machine.Label(o.status)), 0)
 	}
 	if 0 == len(o.items) {
-		return false, errors.Wrap(fmt.Errorf("Can't apply coupon: order %v has no item", o.id), 0)
+		return false, errors.WrapPrefix(ErrNoItem, fmt.Sprintf("Can't apply coupon: order %v has no item", o.id), 0)
 	}
-	if _, err := coupon.CanBeApplied(); err != nil {
-		return false, errors.Wrap(fmt.Errorf("Can't apply coupon with id %v in order %v: %v", coupon.ID(), o.id, err), 0)
+	for _, c := range coupons {
+		if _, err := c.CanBeApplied(); err != nil {
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't apply coupon with id %v in order %v", c.ID(), o.id), 0)
+		}
 	}
-	if _, err := o.calculateAmount(coupon); err != nil {
-		return false, errors.Wrap(fmt.Errorf("Can't apply coupon with id %v in order %v: %v", coupon.ID(), o.id, err), 0)
+	if _, err := o.calculateAmount(coupons); err != nil {
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't apply coupons %v in order %v", couponIDs(coupons), o.id), 0)
 	}
-	o.SetCoupon(coupon)
 	return true, nil
 }
 
-//calculateAmount is a function for calculating the order's amount (subtracted with discount from a given coupon)
-//(regardless of the order's status, order item's product status, and the coupon status)
-func (o *Order) calculateAmount(coupon *coupon.Coupon) (bool, *errors.Error) {
-	amount := decimal.New(0, 0)
-	for _, val := range o.items {
-		amount = amount.Add(val.Product().Price().Mul(decimal.New(int64(val.Quantity()), 0)))
+//calculateAmount is a function for calculating the order's amount (subtracted with the discounts of given coupons)
+//(regardless of the order's status, order item's product status, and the coupons status)
+func (o *Order) calculateAmount(coupons []*coupon.Coupon) (bool, *errors.Error) {
+	b, err := o.price(o.shippingRegion, coupons)
+	if err != nil {
+		return false, err
 	}
-	if coupon != nil {
-		discountAmount := coupon.GetDiscountAmount(amount)
-		amount = amount.Sub(discountAmount)
-		if decimal.New(0, 0).GreaterThanOrEqual(amount) {
-			return false, errors.Wrap(fmt.Errorf("Zero or less calculated amount of order with id %v (applied with coupon with id %v)", o.id, o.coupon.ID()), 0)
+	o.setBreakdown(b)
+	return true, nil
+}
+
+//price is a function for pricing an order without changing it
+//Returns the price breakdown or an error describing the failure
+func (o *Order) price(shippingRegion string, coupons []*coupon.Coupon) (*Breakdown, *errors.Error) {
+	productIDs := make([]string, 0, len(o.items))
+	for productID := range o.items {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+	b := newBreakdown(len(productIDs))
+	for _, productID := range productIDs {
+		val := o.items[productID]
+		unitPrice, rate, rateDate := val.unitPrice, val.exchangeRate, val.rateDate
+		if false == val.Captured() {
+			var err *errors.Error
+			if rate, rateDate, err = o.exchangeRate(val.product, o.Currency()); err != nil {
+				return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't price order %v", o.id), 0)
+			}
+			unitPrice = convertPrice(val.product, o.Currency(), rate)
 		}
+		line := &Line{productID, unitPrice, val.quantity, unitPrice.Mul(decimal.New(int64(val.quantity), 0)), decimal.New(0, 0), nil, rate, rateDate}
+		b.lines = append(b.lines, line)
+		b.subtotal = b.subtotal.Add(line.subtotal)
 	}
-	o.amount = amount
-	return true, nil
+	quantity := 0
+	for _, line := range b.lines {
+		quantity += line.quantity
+	}
+	shippingCoupons := make([]*AppliedCoupon, 0)
+	promotions, err := o.runningPromotions(time.Now(), 0 < len(coupons))
+	if err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't price order %v", o.id), 0)
+	}
+	for _, p := range promotions {
+		if false == o.combinable(p, b.promotions, coupons) {
+			continue
+		}
+		discounts, err := o.lineDiscounts(b, p, quantity, productIDs)
+		if err != nil {
+			//the order isn't eligible for the promotion, it's skipped
+			continue
+		}
+		applied := b.apply(p, discounts)
+		b.promotions = append(b.promotions, applied)
+		if p.IsFreeShipping() {
+			shippingCoupons = append(shippingCoupons, applied)
+		}
+	}
+	for _, c := range coupons {
+		discounts, err := o.lineDiscounts(b, c, quantity, productIDs)
+		if err != nil {
+			return nil, err
+		}
+		applied := b.apply(c, discounts)
+		b.coupons = append(b.coupons, applied)
+		if c.IsFreeShipping() {
+			shippingCoupons = append(shippingCoupons, applied)
+		}
+	}
+	if region, ok := o.taxTable.Region(shippingRegion); ok {
+		b.taxIncluded = region.Inclusive()
+		for _, line := range b.lines {
+			line.taxes = region.Taxes(o.items[line.productID].TaxCategory(), o.Currency(), line.subtotal.Sub(line.discount))
+			b.tax = b.tax.Add(line.Tax())
+		}
+	}
+	discounted := b.subtotal.Sub(b.discount)
+	shippingCost, err := o.shippingRate.Rate(o.quantities(), discounted, o.Currency(), o.exchangeRates)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't rate shipping of order %v", o.id), 0)
+	}
+	if 0 < len(shippingCoupons) {
+		//only the first free shipping coupon waives the shipping cost, there's nothing left to waive for the others
+		shippingCoupons[0].discount, b.discount = shippingCost, b.discount.Add(shippingCost)
+	}
+	b.shippingCost, b.total = shippingCost, b.subtotal.Sub(b.discount).Add(shippingCost)
+	if false == b.taxIncluded {
+		b.total = b.total.Add(b.tax)
+	}
+	return b, nil
 }
 
-//Submit is a function for submitting order
-func (o *Order) Submit(shippingName, shippingAddress string, coupon *coupon.Coupon) (bool, *errors.Error) {
-	if StatusDraft != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't submit: order %v status is %v (not draft)", o.id, o.status), 0)
+//lineDiscounts is a function for computing the line discounts of a coupon on a price breakdown
+//Returns the discount of every line or an error describing why the coupon can't be applied
+func (o *Order) lineDiscounts(b *Breakdown, c *coupon.Coupon, quantity int, productIDs []string) ([]decimal.Decimal, *errors.Error) {
+	if false == c.AppliesIn(o.Currency()) {
+		return nil, errors.WrapPrefix(ErrCurrencyMismatch, fmt.Sprintf("Can't apply coupon %v in %v on order %v in %v", c.ID(), c.Currency(), o.id, o.Currency()), 0)
+	}
+	if _, err := c.IsEligible(b.subtotal, quantity, productIDs); err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't apply coupon %v on order %v", c.ID(), o.id), 0)
+	}
+	if c.IsFreeShipping() {
+		//the shipping cost is waived once rated (on the discounted amount)
+		return nil, nil
+	}
+	couponLines := make([]*coupon.Line, 0, len(b.lines))
+	for _, line := range b.lines {
+		couponLines = append(couponLines, coupon.NewLine(line.productID, line.unitPrice, line.quantity, line.subtotal.Sub(line.discount)))
+	}
+	discounts, err := c.GetLineDiscounts(couponLines)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't apply coupon %v on order %v", c.ID(), o.id), 0)
+	}
+	discount, left := decimal.New(0, 0), decimal.New(0, 0)
+	for i, line := range b.lines {
+		if c.AppliesTo(line.productID) || false == discounts[i].IsZero() {
+			left = left.Add(couponLines[i].Amount())
+		}
+		discount = discount.Add(discounts[i])
+	}
+	if decimal.New(0, 0).GreaterThanOrEqual(left.Sub(discount)) {
+		return nil, errors.WrapPrefix(ErrInvalidAmount, fmt.Sprintf("Zero or less calculated amount of order with id %v (applied with coupon with id %v)", o.id, c.ID()), 0)
+	}
+	return discounts, nil
+}
+
+//Quote is a function for quoting the price breakdown a draft order would be submitted with
+func (o *Order) Quote(shippingRegion string, coupons ...*coupon.Coupon) (*Breakdown, *errors.Error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if false == o.machine.Editable(o.status) {
+		return nil, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't quote order %v, status is %v (not editable)", o.id, o.machine.Label(o.status)), 0)
 	}
 	if 0 == len(o.items) {
-		return false, errors.Wrap(fmt.Errorf("Can't submit: order %v has no item", o.id), 0)
+		return nil, errors.WrapPrefix(ErrNoItem, fmt.Sprintf("Can't quote: order %v has no item", o.id), 0)
+	}
+	stacked, err := o.stack(coupons)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't quote order %v", o.id), 0)
+	}
+	for _, c := range stacked {
+		if _, err := c.CanBeApplied(); err != nil {
+			return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't quote order %v with coupon %v", o.id, c.ID()), 0)
+		}
+	}
+	if "" == shippingRegion {
+		shippingRegion = o.shippingRegion
+	}
+	return o.price(shippingRegion, stacked)
+}
+
+//Submit is a function for submitting order
+func (o *Order) Submit(shippingName, shippingAddress string, coupons ...*coupon.Coupon) (bool, *errors.Error) {
+	//note: the order is locked through the whole submission, so a concurrent submission or cancellation of the same order
+	//sees either the draft or the submitted order
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	t, err := o.transition(EventSubmit)
+	if err != nil {
+		return false, err
+	}
+	if o.user != nil {
+		if "" == shippingName {
+			shippingName = o.user.Name()
+		}
+		if "" == shippingAddress {
+			shippingAddress = o.user.Address()
+		}
 	}
 	for _, val := range o.items {
 		if canOrder, err := val.Product().CanBeOrdered(val.Quantity()); false == canOrder {
-			return false, errors.Wrap(fmt.Errorf("Can't submit: order %v for item with product id %v: %v", o.id, val.Product().ID(), err), 0)
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v for item with product id %v", o.id, val.Product().ID()), 0)
 		}
 	}
-	//try applying coupon if exist
-	if coupon != nil {
-		_, err := o.applyCoupon(coupon)
+	stacked, err := o.stack(coupons)
+	if err != nil {
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v", o.id), 0)
+	}
+	//note: the previous breakdown holds the previously applied coupons, restored on failure
+	prevBreakdown := o.Breakdown()
+	//try applying coupons if exist
+	if 0 < len(stacked) {
+		_, err := o.applyCoupons(stacked)
 		if err != nil {
-			return false, errors.Wrap(fmt.Errorf("Can't submit: order %v can't apply coupon %v: %v", o.id, coupon.ID(), err), 0)
+			return false, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v can't apply coupons %v", o.id, couponIDs(stacked)), 0)
 		}
-		coupon.SetStock(coupon.Stock() - 1)
-	} else {
-		o.calculateAmount(nil)
+	} else if _, err := o.calculateAmount(nil); err != nil {
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v", o.id), 0)
+	}
+
+	//decrement product stocks and redeem coupons atomically (a concurrent submission may have taken them after the checks above)
+	allocations, err := o.submit(t, time.Now())
+	if err != nil {
+		o.setBreakdown(prevBreakdown)
+		return false, err
 	}
-	o.status = StatusSubmitted
-	o.submittedDate = time.Now()
-	o.shippingStatus = ShipStatusNone
 
+	//the priced unit price, the product name, status and tax category are captured, later pricing and reports don't follow product changes
 	for _, val := range o.items {
-		o.mu.Lock()
-		val.Product().SetStock(val.Product().Stock() - int64(val.Quantity()))
-		o.mu.Unlock()
+		val.allocation = allocations[val.Product()]
+		val.capture()
 	}
+	o.shippingName = shippingName
+	o.shippingAddress = shippingAddress
+	o.take(t)
 
 	return true, nil
 }
 
-//Process is a function for processing order
+//Process is a function for processing order (firing the process event)
 func (o *Order) Process() (bool, *errors.Error) {
-	if StatusSubmitted != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't process: order %v status is %v (not submitted)", o.id, o.status), 0)
-	}
-	o.status = StatusProcessed
-	o.processedDate = time.Now()
-	return true, nil
+	return o.Fire(EventProcess)
 }
 
 //Cancel is a function for canceling order
 func (o *Order) Cancel() (bool, *errors.Error) {
-	if StatusSubmitted != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't cancel: order %v status is %v (not submitted)", o.id, o.status), 0)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	t, err := o.transition(EventCancel)
+	if err != nil {
+		return false, err
+	}
+	if err := o.cancel(t, time.Now()); err != nil {
+		return false, err
 	}
-	o.status = StatusCanceled
+	o.take(t)
 	return true, nil
 }
 
-//ProcessShipping is a function for processing order shipping
-func (o *Order) ProcessShipping(trackingNo string) (bool, *errors.Error) {
-	if StatusProcessed != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't process: order %v status is %v (not processed)", o.id, o.status), 0)
+//quantities returns the ordered quantity of every product of an order
+func (o *Order) quantities() map[*product.Product]int {
+	quantities := make(map[*product.Product]int, len(o.items))
+	for _, val := range o.items {
+		quantities[val.Product()] = val.Quantity()
 	}
-	o.shippingTrackingID = trackingNo
-	o.shippingStatus = ShipStatusOnProcess
-	return true, nil
+	return quantities
 }
 
-//FinishOrder is a function for finishing order
-func (o *Order) FinishOrder() (bool, *errors.Error) {
-	if StatusProcessed != o.status {
-		return false, errors.Wrap(fmt.Errorf("Can't finish: order %v status is %v (not processed)", o.id, o.status), 0)
+//allocations returns the allocation of every product of a submitted order
+func (o *Order) allocations() Allocations {
+	allocations := make(Allocations, len(o.items))
+	for _, val := range o.items {
+		allocation := val.Allocation()
+		if 0 == len(allocation) {
+			allocation = product.Allocation{product.DefaultWarehouse: val.Quantity()}
+		}
+		allocations[val.Product()] = allocation
 	}
-	o.status = StatusDelivered
-	o.shippingStatus = ShipStatusDelivered
-	return true, nil
+	return allocations
+}
+
+//FinishOrder is a function for finishing order (firing the finish event)
+func (o *Order) FinishOrder() (bool, *errors.Error) {
+	return o.Fire(EventFinish)
 }
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"os"
 	"sstest/model/coupon"
+	"sstest/model/money"
 	"sstest/model/order"
 	"sstest/model/product"
+	"sstest/model/user"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-errors/errors"
 	"github.com/shopspring/decimal"
 )
 
@@ -552,3 +556,441 @@ func TestAddQuantity(t *testing.T) {
 		}
 	})
 }
+
+func TestConcurrentSubmitOrder(t *testing.T) {
+	//test setup for product with stock for only 10 of 50 concurrently submitted orders
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+
+	orders := make([]*order.Order, 50)
+	for i := range orders {
+		orders[i] = order.New(fmt.Sprintf("concurrentOrder%d", i))
+		orders[i].AddProduct(limitedProd, 2)
+	}
+
+	var wg sync.WaitGroup
+	for _, o := range orders {
+		wg.Add(1)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Submit("ship name", "ship address", nil)
+		}(o)
+	}
+	wg.Wait()
+
+	submitted := 0
+	for _, o := range orders {
+		if order.StatusSubmitted == o.Status() {
+			submitted++
+		}
+	}
+
+	t.Run("Only Orders With Enough Stock Must Be Submitted", func(t *testing.T) {
+		if 10 != submitted {
+			t.Errorf("want %v submitted orders, got %v", 10, submitted)
+		}
+	})
+	t.Run("Stock Must Not Be Oversold", func(t *testing.T) {
+		if 0 != limitedProd.Stock() {
+			t.Errorf("want %v for stock, got %v", 0, limitedProd.Stock())
+		}
+	})
+}
+
+func TestFailureReasons(t *testing.T) {
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(5)
+	limitedProd.SetPrice(decimal.New(100, 0))
+
+	noStockCoupon := coupon.New("noStockCoupon")
+	noStockCoupon.SetStatus(coupon.StatusActive)
+
+	draftOrder := order.New("draftOrder")
+	_, errInsufficientStock := draftOrder.AddProduct(limitedProd, 10)
+	_, errInvalidQuantity := draftOrder.AddProduct(limitedProd, 0)
+	_, errItemNotFound := draftOrder.DeleteProduct(limitedProd)
+	_, errNoItem := draftOrder.Submit("ship name", "ship address", nil)
+	draftOrder.AddProduct(limitedProd, 1)
+	_, errNoCouponStock := draftOrder.Submit("ship name", "ship address", noStockCoupon)
+	_, errInvalidStatus := draftOrder.Process()
+
+	var failureReasonTests = []struct {
+		testCase       string
+		expectedReason error
+		actualErr      error
+	}{
+		{"Insufficient Stock", product.ErrInsufficientStock, errInsufficientStock},
+		{"Invalid Quantity", product.ErrInvalidQuantity, errInvalidQuantity},
+		{"Item Not Found", order.ErrItemNotFound, errItemNotFound},
+		{"No Item", order.ErrNoItem, errNoItem},
+		{"No Coupon Stock", coupon.ErrNoStock, errNoCouponStock},
+		{"Invalid Status", order.ErrInvalidStatus, errInvalidStatus},
+	}
+
+	for _, test := range failureReasonTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if false == errors.Is(test.actualErr, test.expectedReason) {
+				t.Errorf("want %v for error reason, got %v", test.expectedReason, test.actualErr)
+			}
+		})
+	}
+}
+
+func TestSubmitOrderUser(t *testing.T) {
+	orderedProd := product.New("orderedProd", "Ordered Product")
+	orderedProd.SetStatus(product.StatusAvailable)
+	orderedProd.SetStock(10)
+	orderedProd.SetPrice(decimal.New(100, 0))
+
+	activeUser, _ := user.New("activeUser", "Active User", "Active Address")
+	activeUser.Activate()
+	inactiveUser, _ := user.New("inactiveUser", "Inactive User", "Inactive Address")
+	suspendedUser, _ := user.New("suspendedUser", "Suspended User", "Suspended Address")
+	suspendedUser.Suspend()
+
+	defaultShippingOrder := order.New("defaultShippingOrder").SetUser(activeUser)
+	defaultShippingOrder.AddProduct(orderedProd, 1)
+	defaultShippingOk, _ := defaultShippingOrder.Submit("", "", nil)
+
+	givenShippingOrder := order.New("givenShippingOrder").SetUser(activeUser)
+	givenShippingOrder.AddProduct(orderedProd, 1)
+	givenShippingOrder.Submit("ship name", "", nil)
+
+	inactiveUserOrder := order.New("inactiveUserOrder").SetUser(inactiveUser)
+	inactiveUserOrder.AddProduct(orderedProd, 1)
+	inactiveUserOk, errInactiveUser := inactiveUserOrder.Submit("ship name", "ship address", nil)
+
+	suspendedUserOrder := order.New("suspendedUserOrder").SetUser(suspendedUser)
+	suspendedUserOrder.AddProduct(orderedProd, 1)
+	suspendedUserOk, errSuspendedUser := suspendedUserOrder.Submit("ship name", "ship address", nil)
+
+	var submitOrderUserTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Active User Order Must Be Submitted", true, defaultShippingOk},
+		{"Order User", activeUser, defaultShippingOrder.User()},
+		{"Shipping Name Must Default To User Name", "Active User", defaultShippingOrder.ShippingName()},
+		{"Shipping Address Must Default To User Address", "Active Address", defaultShippingOrder.ShippingAddress()},
+		{"Given Shipping Name Must Be Kept", "ship name", givenShippingOrder.ShippingName()},
+		{"Missing Shipping Address Must Default To User Address", "Active Address", givenShippingOrder.ShippingAddress()},
+		{"Inactive User Order Must Not Be Submitted", false, inactiveUserOk},
+		{"Inactive User Order Must Stay Draft", order.StatusDraft, inactiveUserOrder.Status()},
+		{"Inactive User Failure Reason", true, errors.Is(errInactiveUser, user.ErrNotActive)},
+		{"Suspended User Order Must Not Be Submitted", false, suspendedUserOk},
+		{"Suspended User Failure Reason", true, errors.Is(errSuspendedUser, user.ErrNotActive)},
+		{"Product Stock Must Only Be Decremented By Submitted Orders", int64(8), orderedProd.Stock()},
+	}
+
+	for _, test := range submitOrderUserTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCancelOrderStock(t *testing.T) {
+	orderedProd := product.New("orderedProd", "Ordered Product")
+	orderedProd.SetStatus(product.StatusAvailable)
+	orderedProd.SetStock(10)
+	orderedProd.SetPrice(decimal.New(100, 0))
+
+	usedCoupon := coupon.New("usedCoupon")
+	usedCoupon.SetStatus(coupon.StatusActive)
+	usedCoupon.SetStock(5)
+	usedCoupon.SetKind(coupon.KindValue)
+	usedCoupon.SetValue(decimal.New(10, 0))
+
+	submittedOrder := order.New("submittedOrder")
+	submittedOrder.AddProduct(orderedProd, 3)
+	submittedOrder.Submit("ship name", "ship address", usedCoupon)
+	submittedStock, submittedCouponStock := orderedProd.Stock(), usedCoupon.Stock()
+	cancelOk, _ := submittedOrder.Cancel()
+	canceledStock, canceledCouponStock := orderedProd.Stock(), usedCoupon.Stock()
+	recancelOk, _ := submittedOrder.Cancel()
+
+	processedOrder := order.New("processedOrder")
+	processedOrder.AddProduct(orderedProd, 2)
+	processedOrder.Submit("ship name", "ship address", nil)
+	processedOrder.Process()
+	processedCancelOk, _ := processedOrder.Cancel()
+
+	shippedOrder := order.New("shippedOrder")
+	shippedOrder.AddProduct(orderedProd, 4)
+	shippedOrder.Submit("ship name", "ship address", nil)
+	shippedOrder.Process()
+	shippedOrder.ProcessShipping("dummyTrackingNo")
+	shippedCancelOk, _ := shippedOrder.Cancel()
+
+	var cancelOrderStockTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submitted Product Stock", int64(7), submittedStock},
+		{"Submitted Coupon Stock", int64(4), submittedCouponStock},
+		{"Submitted Order Must Be Canceled", true, cancelOk},
+		{"Canceled Product Stock Must Be Returned", int64(10), canceledStock},
+		{"Canceled Coupon Use Must Be Returned", int64(5), canceledCouponStock},
+		{"Canceled Order Must Not Be Canceled Again", false, recancelOk},
+		{"Processed Order Must Be Canceled", true, processedCancelOk},
+		{"Shipped Order Must Not Be Canceled", false, shippedCancelOk},
+		{"Only Shipped Order Stock Must Stay Taken", int64(6), orderedProd.Stock()},
+		{"Coupon Stock Must Be Returned Once", int64(5), usedCoupon.Stock()},
+	}
+
+	for _, test := range cancelOrderStockTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderAllocation(t *testing.T) {
+	splitProd := product.New("splitProd", "Split Product")
+	splitProd.SetStatus(product.StatusAvailable)
+	splitProd.SetStocks(map[string]int64{"east": 2, "west": 3})
+	splitProd.SetPrice(decimal.New(100, 0))
+
+	singleOrder := order.New("singleOrder").SetAllocator(product.SingleWarehouse)
+	singleOrder.AddProduct(splitProd, 4)
+	singleSubmitOk, errSingleSubmit := singleOrder.Submit("ship name", "ship address", nil)
+	singleStatus := singleOrder.Status()
+
+	splitOrder := order.New("splitOrder")
+	splitOrder.AddProduct(splitProd, 4)
+	draftAllocation := len(splitOrder.Items()["splitProd"].Allocation())
+	splitSubmitOk, _ := splitOrder.Submit("ship name", "ship address", nil)
+	splitAllocation := splitOrder.Items()["splitProd"].Allocation()
+	eastAfterSubmit, westAfterSubmit := splitProd.WarehouseStock("east"), splitProd.WarehouseStock("west")
+
+	splitOrder.Cancel()
+	eastAfterCancel, westAfterCancel := splitProd.WarehouseStock("east"), splitProd.WarehouseStock("west")
+
+	var orderAllocationTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Single Warehouse Order Must Not Be Submitted", false, singleSubmitOk},
+		{"Single Warehouse Failure Reason", true, nil != errSingleSubmit && errors.Is(errSingleSubmit, product.ErrInsufficientStock)},
+		{"Single Warehouse Order Must Stay Draft", order.StatusDraft, singleStatus},
+		{"Draft Item Must Not Be Allocated", 0, draftAllocation},
+		{"Split Order Must Be Submitted", true, splitSubmitOk},
+		{"Allocation From East", 1, splitAllocation["east"]},
+		{"Allocation From West", 3, splitAllocation["west"]},
+		{"East Stock After Submit", int64(1), eastAfterSubmit},
+		{"West Stock After Submit", int64(0), westAfterSubmit},
+		{"Canceled Stock Must Be Returned To East", int64(2), eastAfterCancel},
+		{"Canceled Stock Must Be Returned To West", int64(3), westAfterCancel},
+	}
+
+	for _, test := range orderAllocationTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestConcurrentCancelSubmitOrder(t *testing.T) {
+	//test setup for product and coupon with stock for 10 orders, taken by the submitted orders being canceled
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+
+	limitedCoupon := coupon.New("limitedCoupon")
+	limitedCoupon.SetStatus(coupon.StatusActive)
+	limitedCoupon.SetStock(10)
+	limitedCoupon.SetKind(coupon.KindValue)
+	limitedCoupon.SetValue(decimal.New(10, 0))
+
+	draftOrders := make([]*order.Order, 10)
+	submittedOrders := make([]*order.Order, 10)
+	for i := range submittedOrders {
+		draftOrders[i] = order.New(fmt.Sprintf("draftOrder%d", i))
+		draftOrders[i].AddProduct(limitedProd, 2)
+		submittedOrders[i] = order.New(fmt.Sprintf("submittedOrder%d", i))
+		submittedOrders[i].AddProduct(limitedProd, 2)
+	}
+	for _, o := range submittedOrders {
+		o.Submit("ship name", "ship address", limitedCoupon)
+	}
+
+	//every submitted order is canceled twice while the draft orders are submitted
+	var wg sync.WaitGroup
+	for i := range submittedOrders {
+		wg.Add(3)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Cancel()
+		}(submittedOrders[i])
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Cancel()
+		}(submittedOrders[i])
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Submit("ship name", "ship address", limitedCoupon)
+		}(draftOrders[i])
+	}
+	wg.Wait()
+
+	canceled, submitted := 0, 0
+	for i := range submittedOrders {
+		if order.StatusCanceled == submittedOrders[i].Status() {
+			canceled++
+		}
+		if order.StatusSubmitted == draftOrders[i].Status() {
+			submitted++
+		}
+	}
+
+	var concurrentCancelSubmitTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Every Submitted Order Must Be Canceled", 10, canceled},
+		{"Product Stock Must Only Be Taken By Submitted Orders", int64(20 - 2*submitted), limitedProd.Stock()},
+		{"Coupon Stock Must Only Be Taken By Submitted Orders", int64(10 - submitted), limitedCoupon.Stock()},
+	}
+
+	for _, test := range concurrentCancelSubmitTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestItemSnapshot(t *testing.T) {
+	snapshotProd := product.New("snapshotProd", "Snapshot Product")
+	snapshotProd.SetStatus(product.StatusAvailable)
+	snapshotProd.SetStock(10)
+	snapshotProd.SetPrice(decimal.New(100, 0))
+
+	snapshotOrder := order.New("snapshotOrder")
+	snapshotOrder.AddProduct(snapshotProd, 2)
+	item := snapshotOrder.Items()["snapshotProd"]
+	snapshotProd.SetPrice(decimal.New(120, 0))
+	draftCaptured, draftPrice := item.Captured(), item.UnitPrice().String()
+	failedOrder := order.New("failedOrder")
+	failedOrder.AddProduct(snapshotProd, 1)
+	failedOrder.Submit("ship name", "ship address", noStockCoupon)
+	submitOk, _ := snapshotOrder.Submit("ship name", "ship address", nil)
+	snapshotProd.SetPrice(decimal.New(150, 0))
+	snapshotProd.SetName("Renamed Product")
+	snapshotProd.SetStatus(product.StatusDiscontinued)
+	snapshotProd.SetTaxCategory("reduced")
+
+	var itemSnapshotTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Draft Item Must Not Be Captured", false, draftCaptured},
+		{"Draft Item Must Follow Product Price", "120", draftPrice},
+		{"Draft Item Must Follow Product Tax Category", "reduced", failedOrder.Items()["snapshotProd"].TaxCategory()},
+		{"Failed Submission Must Not Capture", false, failedOrder.Items()["snapshotProd"].Captured()},
+		{"Submit Must Succeed", true, submitOk},
+		{"Submitted Item Must Be Captured", true, item.Captured()},
+		{"Captured Unit Price", "120", item.UnitPrice().String()},
+		{"Captured Product Name", "Snapshot Product", item.ProductName()},
+		{"Captured Product Status", product.StatusAvailable, item.ProductStatus()},
+		{"Captured Tax Category", product.TaxCategoryStandard, item.TaxCategory()},
+		{"Amount Must Keep Captured Price", "240", snapshotOrder.Amount().String()},
+		{"Breakdown Must Keep Captured Price", "120", snapshotOrder.Breakdown().Lines()[0].UnitPrice().String()},
+	}
+
+	for _, test := range itemSnapshotTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderCurrency(t *testing.T) {
+	newEuroProduct := func(id string) *product.Product {
+		p := product.New(id, id)
+		p.SetStatus(product.StatusAvailable)
+		p.SetStock(10)
+		p.SetPrice(decimal.New(100, 0))
+		p.SetCurrency("EUR")
+		return p
+	}
+	euroProd, otherEuroProd, repricedProd := newEuroProduct("euroProd"), newEuroProduct("otherEuroProd"), newEuroProduct("repricedProd")
+	dollarProd := product.New("dollarProd", "Dollar Product")
+	dollarProd.SetStatus(product.StatusAvailable)
+	dollarProd.SetStock(10)
+	dollarProd.SetPrice(decimal.New(100, 0))
+	dollarCoupon := coupon.New("dollarCoupon")
+	dollarCoupon.SetStatus(coupon.StatusActive)
+	dollarCoupon.SetStock(10)
+	dollarCoupon.SetKind(coupon.KindValue)
+	dollarCoupon.SetValue(decimal.New(10, 0))
+	euroCoupon := coupon.New("euroCoupon")
+	euroCoupon.SetStatus(coupon.StatusActive)
+	euroCoupon.SetStock(10)
+	euroCoupon.SetKind(coupon.KindValue)
+	euroCoupon.SetValue(decimal.New(10, 0))
+	euroCoupon.SetCurrency("EUR")
+
+	euroOrder := order.New("euroOrder")
+	newCurrency := euroOrder.Currency()
+	euroOrder.AddProduct(euroProd, 1)
+	_, errMixed := euroOrder.AddProduct(dollarProd, 1)
+	otherOk, _ := euroOrder.AddProduct(otherEuroProd, 1)
+	_, errDollarCoupon := euroOrder.Quote("", dollarCoupon)
+	_, errSubmitDollarCoupon := euroOrder.Submit("ship name", "ship address", dollarCoupon)
+	submitOk, _ := euroOrder.Submit("ship name", "ship address", euroCoupon)
+	repricedOrder := order.New("repricedOrder")
+	repricedOrder.AddProduct(repricedProd, 1)
+	repricedProd.SetCurrency("USD")
+	_, errRepriced := repricedOrder.Submit("ship name", "ship address", nil)
+	//an order whose currency is set keeps it, its first item must be priced in it
+	chosenOrder := order.New("chosenOrder")
+	chosenOrder.ChangeCurrency("EUR")
+	chosenOk, errChosen := chosenOrder.AddProduct(dollarProd, 1)
+
+	var orderCurrencyTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"New Order Currency Must Be Default Currency", money.DefaultCurrency, newCurrency},
+		{"First Item Must Set Order Currency", "EUR", euroOrder.Currency()},
+		{"Product In Other Currency Must Not Be Added", true, errors.Is(errMixed, order.ErrCurrencyMismatch)},
+		{"Product In Order Currency Must Be Added", true, otherOk},
+		{"Value Coupon In Other Currency Must Not Be Quoted", true, errors.Is(errDollarCoupon, order.ErrCurrencyMismatch)},
+		{"Value Coupon In Other Currency Must Not Be Submitted", true, errors.Is(errSubmitDollarCoupon, order.ErrCurrencyMismatch)},
+		{"Value Coupon Stock Must Be Kept", int64(10), dollarCoupon.Stock()},
+		{"Value Coupon In Order Currency Must Be Submitted", true, submitOk},
+		{"Submitted Amount", "190", euroOrder.Amount().String()},
+		{"Repriced Product Must Not Be Submitted", true, errors.Is(errRepriced, order.ErrCurrencyMismatch)},
+		{"New Order Has No Currency", false, order.New("noCurrencyOrder").HasCurrency()},
+		{"First Item In Other Currency Than Set One Must Not Be Added", true, false == chosenOk && errors.Is(errChosen, order.ErrCurrencyMismatch)},
+		{"Set Currency Must Be Kept", "EUR", chosenOrder.Currency()},
+	}
+
+	for _, test := range orderCurrencyTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
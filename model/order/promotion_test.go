@@ -0,0 +1,101 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//newPromotionCoupon creates an active stackable coupon of a promotion kind with a stock of 10
+func newPromotionCoupon(id, kind string) *coupon.Coupon {
+	c := newConditionalCoupon(id, kind, 10)
+	c.SetStackable(true)
+	return c
+}
+
+func TestOrderPromotions(t *testing.T) {
+	ledger := repository.NewMemoryCouponRepository()
+	shirtProd := newTaxedProduct("shirtProd", 20, product.TaxCategoryStandard)
+	sockProd := newTaxedProduct("sockProd", 5, product.TaxCategoryStandard)
+	hatProd := newTaxedProduct("hatProd", 15, product.TaxCategoryStandard)
+	newOrder := func(id string) *order.Order {
+		o := order.New(id).SetShippingRate(order.NewFlatRate(decimal.New(10, 0), "USD")).SetLedger(ledger)
+		o.AddProduct(shirtProd, 3)
+		o.AddProduct(sockProd, 4)
+		return o
+	}
+
+	buyXGetYCoupon := newPromotionCoupon("BUY2GET1", coupon.KindBuyXGetY).SetProducts("shirtProd")
+	buyXGetYCoupon.SetBuyQuantity(2)
+	buyXGetYCoupon.SetFreeQuantity(1)
+	freeShippingCoupon := newPromotionCoupon("FREESHIP", coupon.KindFreeShipping)
+	bundleCoupon := newPromotionCoupon("OUTFIT", coupon.KindBundle).SetProducts("shirtProd", "sockProd")
+	bundleCoupon.SetValue(decimal.New(20, 0))
+	tieredCoupon := newPromotionCoupon("SPEND", coupon.KindTiered)
+	tieredCoupon.SetTiers(coupon.NewTier(decimal.New(50, 0), decimal.New(5, 0)), coupon.NewTier(decimal.New(100, 0), decimal.New(10, 0)))
+	percentageCoupon := newPromotionCoupon("PERCENT10", coupon.KindPercentage)
+	freeHatCoupon := newPromotionCoupon("FREEHAT", coupon.KindBuyXGetY).SetProducts("shirtProd").SetFreeProducts("hatProd")
+	freeHatCoupon.SetBuyQuantity(1)
+	freeHatCoupon.SetFreeQuantity(1)
+	manyCoupon := newPromotionCoupon("BUY5GET1", coupon.KindBuyXGetY).SetProducts("shirtProd")
+	manyCoupon.SetBuyQuantity(5)
+	manyCoupon.SetFreeQuantity(1)
+
+	quotedOrder := newOrder("quotedOrder")
+	buyXGetY, _ := quotedOrder.Quote("", buyXGetYCoupon)
+	freeShipping, _ := quotedOrder.Quote("", freeShippingCoupon, buyXGetYCoupon)
+	bundle, _ := quotedOrder.Quote("", bundleCoupon)
+	tiered, _ := quotedOrder.Quote("", tieredCoupon)
+	promotionFirst, _ := quotedOrder.Quote("", percentageCoupon, buyXGetYCoupon)
+	_, errNotEligible := quotedOrder.Quote("", manyCoupon)
+	freeHatOrder := newOrder("freeHatOrder")
+	freeHatOrder.AddProduct(hatProd, 1)
+	freeHat, errFreeHat := freeHatOrder.Quote("", freeHatCoupon)
+
+	ledger.Create(freeShippingCoupon)
+	ledger.Create(buyXGetYCoupon)
+	submittedOrder := newOrder("submittedOrder")
+	submittedOk, _ := submittedOrder.Submit("ship name", "ship address", freeShippingCoupon, buyXGetYCoupon)
+	freeShippingRedemptions, _ := ledger.FindRedemptions("FREESHIP")
+
+	var promotionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Buy X Get Y Discount", "20", buyXGetY.Discount().String()},
+		{"Buy X Get Y Free Item Line", "20", buyXGetY.Lines()[0].Discount().String()},
+		{"Buy X Get Y Total", "70", buyXGetY.Total().String()},
+		{"Free Shipping Waives Shipping Cost", "30", freeShipping.Discount().String()},
+		{"Free Shipping Keeps Rated Shipping Cost", "10", freeShipping.ShippingCost().String()},
+		{"Free Shipping Total", "60", freeShipping.Total().String()},
+		{"Free Shipping Applied Last", "FREESHIP", freeShipping.AppliedCoupons()[1].Coupon().ID()},
+		{"Free Shipping Applied Discount", "10", freeShipping.AppliedCoupons()[1].Discount().String()},
+		{"Bundle Discount", "15", bundle.Discount().String()},
+		{"Bundle Total", "75", bundle.Total().String()},
+		{"Tiered Discount", "4", tiered.Discount().String()},
+		{"Promotion Applied Before Percentage", "BUY2GET1", promotionFirst.AppliedCoupons()[0].Coupon().ID()},
+		{"Percentage Discounts Amount Left By Promotion", "6", promotionFirst.AppliedCoupons()[1].Discount().String()},
+		{"Buy X Get Y Without Free Item Must Fail", true, nil != errNotEligible && errors.Is(errNotEligible, coupon.ErrNotEligible)},
+		{"Free Item Line", true, nil == errFreeHat},
+		{"Free Item Line Discount", "15", freeHat.Lines()[0].Discount().String()},
+		{"Submit With Promotions", true, submittedOk},
+		{"Submitted Discount", "30", submittedOrder.Discount().String()},
+		{"Submitted Amount", "60", submittedOrder.Amount().String()},
+		{"Free Shipping Redemption Amount", "10", freeShippingRedemptions[0].Amount().String()},
+	}
+
+	for _, test := range promotionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
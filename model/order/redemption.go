@@ -0,0 +1,80 @@
+package order
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//Ledger is interface of the coupon redemption store, recording coupon redemptions on order submission and reversing them on order cancellation
+type Ledger interface {
+	//Redeem atomically records the redemptions of coupons (a redemption keyed by its coupon) and decrements every coupon's stock by one use
+	//only if every coupon has stock left (fails with coupon.ErrNoStock otherwise) and can be redeemed once more by its redemption's user,
+	//given the coupon's recorded redemptions (see coupon.Coupon.CanBeRedeemed, fails with coupon.ErrRedemptionLimit otherwise),
+	//either all or none of the redemptions are recorded (the given coupons' stocks are updated to the decremented values)
+	Redeem(redemptions map[*coupon.Coupon]*coupon.Redemption) *errors.Error
+	//Reverse atomically marks the unreversed redemption of a coupon by an order reversed at a date and increments the coupon's stock by its use
+	//(an order without redemption is skipped, the given coupon's stock is updated to the incremented value)
+	Reverse(c *coupon.Coupon, orderID string, date time.Time) *errors.Error
+}
+
+//couponLedger is the default Ledger of an order, recording the redemptions and changing the stock on the coupons themselves
+type couponLedger struct{}
+
+//Redeem is a function for decrementing the stock of coupons and recording redemptions on them, rolling back on failure
+func (couponLedger) Redeem(redemptions map[*coupon.Coupon]*coupon.Redemption) *errors.Error {
+	decremented := make([]*coupon.Coupon, 0, len(redemptions))
+	//rollback increments back the stocks that are already decremented
+	rollback := func() {
+		for _, c := range decremented {
+			c.IncrementStock()
+		}
+	}
+	for c := range redemptions {
+		if _, err := c.DecrementStock(); err != nil {
+			rollback()
+			return err
+		}
+		decremented = append(decremented, c)
+	}
+	if _, err := coupon.RedeemAll(redemptions); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
+
+//Reverse is a function for reversing the redemption of a coupon by an order recorded on the coupon and returning its use to the coupon's stock
+func (couponLedger) Reverse(c *coupon.Coupon, orderID string, date time.Time) *errors.Error {
+	if c.Reverse(orderID, date) {
+		c.IncrementStock()
+	}
+	return nil
+}
+
+//redemptions returns the redemptions of the coupons applied on a submitted order at a date, each of its discount on the order
+func (o *Order) redemptions(date time.Time) map[*coupon.Coupon]*coupon.Redemption {
+	userID := ""
+	if o.user != nil {
+		userID = o.user.ID()
+	}
+	redemptions := make(map[*coupon.Coupon]*coupon.Redemption, len(o.coupons))
+	for _, applied := range o.coupons {
+		c := applied.coupon
+		redemptions[c] = coupon.NewRedemption(c.ID(), userID, o.id, applied.discount, o.Currency(), date)
+	}
+	return redemptions
+}
+
+//redeem is a function for recording the redemptions of the coupons applied on a submitted order in its ledger
+func (o *Order) redeem(redemptions map[*coupon.Coupon]*coupon.Redemption) *errors.Error {
+	if 0 == len(redemptions) {
+		return nil
+	}
+	if err := o.ledger.Redeem(redemptions); err != nil {
+		return errors.WrapPrefix(err, fmt.Sprintf("Can't redeem the coupons of order %v", o.id), 0)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+func TestOrderRedemptions(t *testing.T) {
+	ledger := repository.NewMemoryCouponRepository()
+	limitedCoupon := newConditionalCoupon("LIMITED", coupon.KindValue, 10)
+	limitedCoupon.SetUserLimit(1)
+	limitedCoupon.SetRedemptionLimit(2)
+	ledger.Create(limitedCoupon)
+	redeemedProd := newTaxedProduct("redeemedProd", 100, product.TaxCategoryStandard)
+	firstUser, _ := user.New("firstUser", "First User", "First Address")
+	firstUser.Activate()
+	secondUser, _ := user.New("secondUser", "Second User", "Second Address")
+	secondUser.Activate()
+	newOrder := func(id string, u *user.User) *order.Order {
+		o := order.New(id).SetLedger(ledger)
+		if u != nil {
+			o.SetUser(u)
+		}
+		o.AddProduct(redeemedProd, 1)
+		return o
+	}
+
+	firstOrder := newOrder("firstOrder", firstUser)
+	firstOk, _ := firstOrder.Submit("ship name", "ship address", limitedCoupon)
+	repeatedOrder := newOrder("repeatedOrder", firstUser)
+	repeatedOk, errRepeated := repeatedOrder.Submit("ship name", "ship address", limitedCoupon)
+	repeatedStatus := repeatedOrder.Status()
+	repeatedCoupons := repeatedOrder.Coupons()
+	stockAfterRepeated := limitedCoupon.Stock()
+	productStockAfterRepeated := redeemedProd.Stock()
+	secondOrder := newOrder("secondOrder", secondUser)
+	secondOk, _ := secondOrder.Submit("ship name", "ship address", limitedCoupon)
+	anonymousOrder := newOrder("anonymousOrder", nil)
+	anonymousOk, errAnonymous := anonymousOrder.Submit("ship name", "ship address", limitedCoupon)
+	firstCancelOk, _ := firstOrder.Cancel()
+	afterCancelOk, _ := repeatedOrder.Submit("ship name", "ship address", limitedCoupon)
+	redemptions, _ := ledger.FindRedemptions("LIMITED")
+
+	defaultCoupon := newConditionalCoupon("DEFAULT", coupon.KindValue, 10)
+	defaultCoupon.SetUserLimit(1)
+	defaultOrder := order.New("defaultOrder").SetLedger(nil).SetUser(firstUser)
+	defaultOrder.AddProduct(redeemedProd, 1)
+	defaultOk, _ := defaultOrder.Submit("ship name", "ship address", defaultCoupon)
+	defaultRepeatedOrder := order.New("defaultRepeatedOrder").SetUser(firstUser)
+	defaultRepeatedOrder.AddProduct(redeemedProd, 1)
+	defaultRepeatedOk, _ := defaultRepeatedOrder.Submit("ship name", "ship address", defaultCoupon)
+	defaultOrder.Cancel()
+
+	var redemptionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submit With Coupon Must Redeem", true, firstOk},
+		{"Submit Over User Limit Must Fail", false, repeatedOk},
+		{"Submit Over User Limit Failure Reason", true, nil != errRepeated && errors.Is(errRepeated, coupon.ErrRedemptionLimit)},
+		{"Failed Submit Order Stays Draft", order.StatusDraft, repeatedStatus},
+		{"Failed Submit Order Has No Coupon", 0, len(repeatedCoupons)},
+		{"Failed Submit Coupon Stock Is Returned", int64(9), stockAfterRepeated},
+		{"Failed Submit Product Stock Is Returned", int64(99), productStockAfterRepeated},
+		{"Submit By Another User", true, secondOk},
+		{"Submit Over Redemption Limit Must Fail", false, anonymousOk},
+		{"Submit Over Redemption Limit Failure Reason", true, nil != errAnonymous && errors.Is(errAnonymous, coupon.ErrRedemptionLimit)},
+		{"Cancel Must Reverse Redemption", true, firstCancelOk},
+		{"Submit After Reversal", true, afterCancelOk},
+		{"Redemptions Are Recorded", 3, len(redemptions)},
+		{"Canceled Order Redemption Is Reversed", true, redemptions[0].IsReversed()},
+		{"Redemption User", "firstUser", redemptions[2].UserID()},
+		{"Redemption Order", "repeatedOrder", redemptions[2].OrderID()},
+		{"Redemption Amount Is Order Discount", repeatedOrder.Discount().String(), redemptions[2].Amount().String()},
+		{"Redemption Currency Is Order Currency", repeatedOrder.Currency(), redemptions[2].Currency()},
+		{"Default Ledger Redeems On Coupon", true, defaultOk},
+		{"Default Ledger Enforces User Limit", false, defaultRepeatedOk},
+		{"Default Ledger Reverses On Cancel", true, defaultCoupon.Redemptions()[0].IsReversed()},
+	}
+
+	for _, test := range redemptionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package order
+
+import (
+	"sstest/model/product"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//HoldDuration is the duration a draft order holds the stock of its items for (renewed on every change of an item)
+var HoldDuration = 15 * time.Minute
+
+//Reservations is interface of an Inventory also holding product stock for draft orders until the holds expire
+type Reservations interface {
+	Inventory
+	//Hold atomically places (or replaces) the hold of an order on a quantity of a product until the expiry date,
+	//only if the product's stock minus the other orders' unexpired holds is enough (otherwise fails with product.ErrInsufficientStock)
+	Hold(orderID string, p *product.Product, quantity int, expiryDate time.Time) *errors.Error
+	//Release removes the hold of an order on a product (or every hold of the order for an empty product id)
+	Release(orderID, productID string) *errors.Error
+	//ConvertHolds atomically decrements the stocks like DecrementStocks, disregarding the order's own holds,
+	//and removes the order's holds once the stocks are decremented
+	ConvertHolds(orderID string, quantities map[*product.Product]int, allocate product.Allocator) (Allocations, *errors.Error)
+}
+
+//hold is a function for holding the ordered quantity of a product of a draft order (when its inventory is a Reservations)
+func (o *Order) hold(p *product.Product, quantity int) *errors.Error {
+	if r, ok := o.inventory.(Reservations); ok {
+		return r.Hold(o.id, p, quantity, time.Now().Add(HoldDuration))
+	}
+	return nil
+}
+
+//release is a function for releasing the hold of a draft order on a product (when its inventory is a Reservations)
+func (o *Order) release(p *product.Product) *errors.Error {
+	if r, ok := o.inventory.(Reservations); ok {
+		return r.Release(o.id, p.ID())
+	}
+	return nil
+}
+
+//decrementStocks is a function for decrementing the stocks of the ordered quantities on submission
+func (o *Order) decrementStocks(quantities map[*product.Product]int) (Allocations, *errors.Error) {
+	if r, ok := o.inventory.(Reservations); ok {
+		return r.ConvertHolds(o.id, quantities, o.allocator)
+	}
+	return o.inventory.DecrementStocks(quantities, o.allocator)
+}
+
+//restoreStocks is a function for returning the decremented stocks of a failed submission
+func (o *Order) restoreStocks(allocations Allocations) *errors.Error {
+	if err := o.inventory.IncrementStocks(allocations); err != nil {
+		return err
+	}
+	for _, val := range o.items {
+		if err := o.hold(val.Product(), val.Quantity()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestOrderReservations(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+	heldProd := product.New("heldProd", "Held Product")
+	heldProd.SetStatus(product.StatusAvailable)
+	heldProd.SetStock(5)
+	heldProd.SetPrice(decimal.New(100, 0))
+	repo.Save(heldProd)
+
+	firstOrder := order.New("firstOrder").SetInventory(repo)
+	secondOrder := order.New("secondOrder").SetInventory(repo)
+	thirdOrder := order.New("thirdOrder").SetInventory(repo)
+
+	firstAddOk, _ := firstOrder.AddProduct(heldProd, 3)
+	availableAfterFirst, _ := repo.Available("heldProd")
+	secondAddOk, errSecondAdd := secondOrder.AddProduct(heldProd, 3)
+	secondPartialAddOk, _ := secondOrder.AddProduct(heldProd, 2)
+	availableAfterSecond, _ := repo.Available("heldProd")
+	_, errDecrementHeld := repo.DecrementStocks(map[*product.Product]int{heldProd: 1}, nil)
+	firstEditOk, errFirstEdit := firstOrder.EditProduct(heldProd, 1)
+	firstQuantity := firstOrder.Items()["heldProd"].Quantity()
+	firstDeleteOk, _ := firstOrder.DeleteProduct(heldProd)
+	availableAfterDelete, _ := repo.Available("heldProd")
+	thirdAddOk, _ := thirdOrder.AddProduct(heldProd, 3)
+	secondSubmitOk, _ := secondOrder.Submit("ship name", "ship address", nil)
+	stockAfterSubmit := heldProd.Stock()
+	availableAfterSubmit, _ := repo.Available("heldProd")
+
+	thirdOrder.DeleteProduct(heldProd)
+	repo.Hold("expiredOrder", heldProd, 1, time.Now().Add(-time.Second))
+	availableWithExpired, _ := repo.Available("heldProd")
+	released, _ := repo.ReleaseExpired(time.Now())
+	errHoldInvalid := repo.Hold("thirdOrder", heldProd, 0, time.Now().Add(order.HoldDuration))
+	_, errAvailableMissing := repo.Available("missingProd")
+
+	var reservationTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Add Product Must Hold Quantity", true, firstAddOk},
+		{"Held Quantity Must Not Be Available", int64(2), availableAfterFirst},
+		{"Add More Than Available Must Fail", false, secondAddOk},
+		{"Add More Than Available Failure Reason", true, nil != errSecondAdd && errors.Is(errSecondAdd, product.ErrInsufficientStock)},
+		{"Add Available Quantity Must Hold", true, secondPartialAddOk},
+		{"Fully Held Product Available Stock", int64(0), availableAfterSecond},
+		{"Decrement Held Stock Failure Reason", true, nil != errDecrementHeld && errors.Is(errDecrementHeld, product.ErrInsufficientStock)},
+		{"Edit More Than Available Must Fail", false, firstEditOk},
+		{"Edit More Than Available Failure Reason", true, nil != errFirstEdit && errors.Is(errFirstEdit, product.ErrInsufficientStock)},
+		{"Failed Edit Must Keep Quantity", 3, firstQuantity},
+		{"Delete Product Must Release Hold", true, firstDeleteOk},
+		{"Released Quantity Must Be Available", int64(3), availableAfterDelete},
+		{"Released Quantity Must Be Held Again", true, thirdAddOk},
+		{"Submit Must Convert Hold", true, secondSubmitOk},
+		{"Converted Hold Must Decrement Stock", int64(3), stockAfterSubmit},
+		{"Converted Hold Must Be Removed", int64(0), availableAfterSubmit},
+		{"Expired Hold Must Not Be Held", int64(3), availableWithExpired},
+		{"Expired Hold Must Be Released", int64(1), released},
+		{"Hold Invalid Quantity Failure Reason", true, nil != errHoldInvalid && errors.Is(errHoldInvalid, product.ErrInvalidQuantity)},
+		{"Available Missing Product Failure Reason", true, nil != errAvailableMissing && errors.Is(errAvailableMissing, repository.ErrNotFound)},
+	}
+
+	for _, test := range reservationTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestConcurrentOrderReservations(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+	repo.Save(limitedProd)
+
+	//50 drafts concurrently add a product with stock for only 10 of them, every draft holding it must then be submitted
+	orders := make([]*order.Order, 50)
+	var wg sync.WaitGroup
+	for i := range orders {
+		orders[i] = order.New(fmt.Sprintf("concurrentOrder%d", i)).SetInventory(repo)
+		wg.Add(1)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.AddProduct(limitedProd, 2)
+		}(orders[i])
+	}
+	wg.Wait()
+	holding, submitted := 0, 0
+	for _, o := range orders {
+		if 0 == len(o.Items()) {
+			continue
+		}
+		holding++
+		if ok, _ := o.Submit("ship name", "ship address", nil); ok {
+			submitted++
+		}
+	}
+
+	var concurrentReservationTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Only Drafts With Available Stock Must Hold", 10, holding},
+		{"Every Holding Draft Must Be Submitted", 10, submitted},
+		{"Held Stock Must Be Sold Out", int64(0), limitedProd.Stock()},
+	}
+
+	for _, test := range concurrentReservationTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+//errReturnRefused is the error of a refusingInventory returning stock
+var errReturnRefused = fmt.Errorf("stock return refused")
+
+//refusingInventory is an in-memory order.Reservations refusing to return any stock
+type refusingInventory struct {
+	*repository.MemoryProductRepository
+}
+
+//IncrementStocks is a function for refusing to return the stocks
+func (r refusingInventory) IncrementStocks(allocations order.Allocations) *errors.Error {
+	return errors.Wrap(errReturnRefused, 0)
+}
+
+func TestOrderReservationsFailedSubmit(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+	heldProd := product.New("heldProd", "Held Product")
+	heldProd.SetStatus(product.StatusAvailable)
+	heldProd.SetStock(5)
+	heldProd.SetPrice(decimal.New(100, 0))
+	repo.Save(heldProd)
+	//the coupon is not stored in the ledger, so recording its redemption fails once the stock is taken
+	unknownCoupon := coupon.New("unknownCoupon")
+	unknownCoupon.SetStatus(coupon.StatusActive)
+	unknownCoupon.SetStock(1)
+	unknownCoupon.SetKind(coupon.KindValue)
+	unknownCoupon.SetValue(decimal.New(10, 0))
+
+	restoredOrder := order.New("restoredOrder").SetInventory(repo).SetLedger(repository.NewMemoryCouponRepository())
+	restoredOrder.AddProduct(heldProd, 3)
+	restoredOk, errRestored := restoredOrder.Submit("ship name", "ship address", unknownCoupon)
+	stockAfterRestore := heldProd.Stock()
+	availableAfterRestore, _ := repo.Available("heldProd")
+	restoredOrder.DeleteProduct(heldProd)
+
+	refusedOrder := order.New("refusedOrder").SetInventory(refusingInventory{repo}).SetLedger(repository.NewMemoryCouponRepository())
+	refusedOrder.AddProduct(heldProd, 3)
+	refusedOk, errRefused := refusedOrder.Submit("ship name", "ship address", unknownCoupon)
+
+	var failedSubmitTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submit With Unrecorded Coupon Must Fail", false, restoredOk},
+		{"Failed Submit Reason", true, nil != errRestored && errors.Is(errRestored, repository.ErrNotFound)},
+		{"Failed Submit Must Keep Draft", order.StatusDraft, restoredOrder.Status()},
+		{"Failed Submit Must Return Stock", int64(5), stockAfterRestore},
+		{"Failed Submit Must Hold Quantity Again", int64(2), availableAfterRestore},
+		{"Submit With Refused Stock Return Must Fail", false, refusedOk},
+		{"Refused Stock Return Failure Reason", true, nil != errRefused && errors.Is(errRefused, repository.ErrNotFound)},
+		{"Refused Stock Return Must Be Reported", true, nil != errRefused && strings.Contains(errRefused.Error(), errReturnRefused.Error())},
+	}
+
+	for _, test := range failedSubmitTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
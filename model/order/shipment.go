@@ -0,0 +1,364 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"sstest/model/product"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+)
+
+//ShipmentStatusShipped is const for 'shipped' shipment status (handed to the carrier)
+const ShipmentStatusShipped string = "S"
+
+//ShipmentStatusInTransit is const for 'in transit' shipment status (the carrier tracks it, see TrackingEvent)
+const ShipmentStatusInTransit string = "T"
+
+//ShipmentStatusDelivered is const for 'delivered' shipment status
+const ShipmentStatusDelivered string = "D"
+
+//ShipmentStatusReturned is const for 'returned' shipment status (returned to sender, its quantities are unshipped again)
+const ShipmentStatusReturned string = "R"
+
+//shipmentStatusMap is a map of known shipment status code and its label pairs
+var shipmentStatusMap = map[string]string{
+	ShipmentStatusShipped:   "Shipped",
+	ShipmentStatusInTransit: "In Transit",
+	ShipmentStatusDelivered: "Delivered",
+	ShipmentStatusReturned:  "Returned",
+}
+
+//ErrShipmentNotFound is the error returned (wrapped) when an order has no shipment with a given id
+var ErrShipmentNotFound = fmt.Errorf("order shipment not found")
+
+//ErrInvalidShipment is the error returned (wrapped) when a shipment's quantities don't fit the order's unshipped quantities
+var ErrInvalidShipment = fmt.Errorf("invalid order shipment")
+
+//Shipment is business domain model definition of a package fulfilling (part of) an order
+type Shipment struct {
+	id            string
+	carrier       string
+	trackingID    string
+	status        string
+	items         map[string]int
+	shippedDate   time.Time
+	deliveredDate time.Time
+	events        []*TrackingEvent
+}
+
+//NewShipment creates a new shipped shipment model struct, initializes it's properties and returns a reference to it
+func NewShipment(id, carrier, trackingID string) *Shipment {
+	return &Shipment{
+		id,
+		carrier,
+		trackingID,
+		ShipmentStatusShipped,
+		make(map[string]int, 5),
+		time.Now(),
+		time.Unix(0, 0),
+		make([]*TrackingEvent, 0),
+	}
+}
+
+//ID is a getter function for returning a shipment's id
+func (s *Shipment) ID() string {
+	return s.id
+}
+
+//Carrier is a getter function for returning a shipment's carrier
+func (s *Shipment) Carrier() string {
+	return s.carrier
+}
+
+//TrackingID is a getter function for returning a shipment's tracking id
+func (s *Shipment) TrackingID() string {
+	return s.trackingID
+}
+
+//Status is a getter function for returning a shipment's status
+func (s *Shipment) Status() string {
+	return s.status
+}
+
+//Items is a getter function for returning a shipment's shipped quantities, keyed by product id
+func (s *Shipment) Items() map[string]int {
+	return s.items
+}
+
+//ProductIDs returns the ids of the products of a shipment, sorted
+func (s *Shipment) ProductIDs() []string {
+	productIDs := make([]string, 0, len(s.items))
+	for productID := range s.items {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+	return productIDs
+}
+
+//ShippedDate is a getter function for returning a shipment's shipped date
+func (s *Shipment) ShippedDate() time.Time {
+	return s.shippedDate
+}
+
+//DeliveredDate is a getter function for returning a shipment's delivered date
+func (s *Shipment) DeliveredDate() time.Time {
+	return s.deliveredDate
+}
+
+//SetCarrier is a setter function for setting a shipment's carrier
+func (s *Shipment) SetCarrier(carrier string) *Shipment {
+	s.carrier = carrier
+	return s
+}
+
+//SetTrackingID is a setter function for setting a shipment's tracking id
+func (s *Shipment) SetTrackingID(trackingID string) *Shipment {
+	s.trackingID = trackingID
+	return s
+}
+
+//SetStatus is a setter function for setting a shipment's status (regardless of its tracking events)
+func (s *Shipment) SetStatus(status string) (*Shipment, *errors.Error) {
+	if _, ok := shipmentStatusMap[status]; false == ok {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set unknown shipment status type: %v", status), 0)
+	}
+	s.status = status
+	return s, nil
+}
+
+//SetQuantity is a setter function for setting the shipped quantity of a product in a shipment
+func (s *Shipment) SetQuantity(productID string, quantity int) (*Shipment, *errors.Error) {
+	if quantity <= 0 {
+		return nil, errors.WrapPrefix(product.ErrInvalidQuantity, fmt.Sprintf("Can't ship quantity %d of product %v", quantity, productID), 0)
+	}
+	s.items[productID] = quantity
+	return s, nil
+}
+
+//SetShippedDate is a setter function for setting a shipment's shipped date
+func (s *Shipment) SetShippedDate(shippedDate time.Time) *Shipment {
+	s.shippedDate = shippedDate
+	return s
+}
+
+//SetDeliveredDate is a setter function for setting a shipment's delivered date
+func (s *Shipment) SetDeliveredDate(deliveredDate time.Time) *Shipment {
+	s.deliveredDate = deliveredDate
+	return s
+}
+
+//Shipments is a getter function for returning an order's shipments in the order they were shipped
+func (o *Order) Shipments() []*Shipment {
+	return o.shipments
+}
+
+//Shipment returns the shipment of an order with the given id, or false when there is none
+func (o *Order) Shipment(id string) (*Shipment, bool) {
+	for _, s := range o.shipments {
+		if id == s.id {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+//SetShipments is a setter function for setting an order's shipments
+func (o *Order) SetShipments(shipments []*Shipment) *Order {
+	o.shipments = shipments
+	return o
+}
+
+//ShippingStatus returns an order's shipping status, derived from its shipments
+func (o *Order) ShippingStatus() string {
+	ordered, shipped, active, delivered, returned := 0, 0, 0, 0, 0
+	for _, val := range o.items {
+		ordered += val.Quantity()
+	}
+	var latest *TrackingEvent
+	for _, s := range o.shipments {
+		if ShipmentStatusReturned == s.status {
+			returned++
+			continue
+		}
+		active++
+		if ShipmentStatusDelivered == s.status {
+			delivered++
+		}
+		for _, quantity := range s.items {
+			shipped += quantity
+		}
+		if e, ok := s.LatestEvent(); ok && ShipmentStatusDelivered != s.status && (nil == latest || e.date.After(latest.date)) {
+			latest = e
+		}
+	}
+	switch {
+	case 0 == active && 0 != returned:
+		return ShipStatusReturned
+	case 0 == active:
+		return ShipStatusNone
+	case shipped < ordered:
+		return ShipStatusPartiallyShipped
+	case delivered == active:
+		return ShipStatusDelivered
+	case nil == latest:
+		return ShipStatusOnProcess
+	case TrackingFailedAttempt == latest.kind:
+		return ShipStatusDeliveryFailed
+	case TrackingOutForDelivery == latest.kind:
+		return ShipStatusOutForDelivery
+	default:
+		return ShipStatusInTransit
+	}
+}
+
+//ShippingTrackingID returns the tracking id of an order's latest shipment (empty when nothing is shipped)
+func (o *Order) ShippingTrackingID() string {
+	if 0 == len(o.shipments) {
+		return ""
+	}
+	return o.shipments[len(o.shipments)-1].trackingID
+}
+
+//SetShippingStatus is a setter function for setting an order's shipping status on its default shipment
+func (o *Order) SetShippingStatus(status string) (*Order, *errors.Error) {
+	if _, ok := shipstatusMap[status]; false == ok {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set unknown shipping status type: %v", status), 0)
+	}
+	switch status {
+	case ShipStatusNone:
+		o.shipments = make([]*Shipment, 0)
+	case ShipStatusPartiallyShipped:
+		return nil, errors.WrapPrefix(ErrInvalidShipment, fmt.Sprintf("Can't set order %v shipping status to %v on a single shipment", o.id, shipstatusMap[status]), 0)
+	case ShipStatusOnProcess:
+		s := o.defaultShipment()
+		s.status, s.events = ShipmentStatusShipped, make([]*TrackingEvent, 0)
+	default:
+		o.defaultShipment().record(NewTrackingEvent(shipStatusEvents[status], time.Now(), "", ""))
+	}
+	return o, nil
+}
+
+//SetShippingTrackingID is a setter function for setting an order's shipping tracking id on its default shipment
+func (o *Order) SetShippingTrackingID(trackNo string) *Order {
+	if "" == trackNo && 0 == len(o.shipments) {
+		return o
+	}
+	o.defaultShipment().SetTrackingID(trackNo)
+	return o
+}
+
+//shipStatusEvents is a map of order shipping status code and the tracking event setting it on a shipment pairs
+var shipStatusEvents = map[string]string{
+	ShipStatusInTransit:      TrackingInTransit,
+	ShipStatusOutForDelivery: TrackingOutForDelivery,
+	ShipStatusDeliveryFailed: TrackingFailedAttempt,
+	ShipStatusReturned:       TrackingReturned,
+	ShipStatusDelivered:      TrackingDelivered,
+}
+
+//defaultShipment returns the latest shipment of an order, or a new one shipping every unshipped quantity when it has none
+func (o *Order) defaultShipment() *Shipment {
+	if 0 != len(o.shipments) {
+		return o.shipments[len(o.shipments)-1]
+	}
+	s := NewShipment(uuid.New().String(), "", "")
+	for productID, quantity := range o.Unshipped() {
+		s.items[productID] = quantity
+	}
+	o.shipments = append(o.shipments, s)
+	return s
+}
+
+//Unshipped returns the ordered quantity of every product of an order not shipped yet (or returned to sender), keyed by product id
+func (o *Order) Unshipped() map[string]int {
+	unshipped := make(map[string]int, len(o.items))
+	for productID, val := range o.items {
+		unshipped[productID] = val.Quantity()
+	}
+	for _, s := range o.shipments {
+		if ShipmentStatusReturned == s.status {
+			continue
+		}
+		for productID, quantity := range s.items {
+			unshipped[productID] -= quantity
+		}
+	}
+	for productID, quantity := range unshipped {
+		if quantity <= 0 {
+			delete(unshipped, productID)
+		}
+	}
+	return unshipped
+}
+
+//Ship is a function for shipping ordered quantities of a processed order in a new shipment
+//Returns the new shipment or an error describing the failure
+func (o *Order) Ship(carrier, trackingID string, quantities map[string]int) (*Shipment, *errors.Error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	t, err := o.transition(EventShip)
+	if err != nil {
+		return nil, err
+	}
+	unshipped := o.Unshipped()
+	if 0 == len(quantities) {
+		quantities = unshipped
+	}
+	s := NewShipment(uuid.New().String(), carrier, trackingID)
+	for productID, quantity := range quantities {
+		if _, ok := o.items[productID]; false == ok {
+			return nil, errors.WrapPrefix(ErrItemNotFound, fmt.Sprintf("Can't ship, order %v has no product with id: %v", o.id, productID), 0)
+		}
+		if quantity > unshipped[productID] {
+			return nil, errors.WrapPrefix(ErrInvalidShipment, fmt.Sprintf("Can't ship quantity %d of product %v in order %v, unshipped quantity is %d",
+				quantity, productID, o.id, unshipped[productID]), 0)
+		}
+		if _, err := s.SetQuantity(productID, quantity); err != nil {
+			return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't ship order %v", o.id), 0)
+		}
+	}
+	o.shipments = append(o.shipments, s)
+	o.take(t)
+	return s, nil
+}
+
+//ProcessShipping is a function for processing order shipping, shipping every unshipped quantity in a single shipment (firing the ship event)
+func (o *Order) ProcessShipping(trackingNo string) (bool, *errors.Error) {
+	if _, err := o.Ship("", trackingNo, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//DeliverShipment is a function for marking a shipment of an order as delivered
+//Returns true if the shipment is delivered or false and an error describing the failure
+func (o *Order) DeliverShipment(id string) (bool, *errors.Error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	s, ok := o.Shipment(id)
+	if false == ok {
+		return false, errors.WrapPrefix(ErrShipmentNotFound, fmt.Sprintf("Can't deliver, order %v has no shipment with id: %v", o.id, id), 0)
+	}
+	if ShipmentStatusDelivered == s.status || ShipmentStatusReturned == s.status {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't deliver, order %v shipment %v is %v",
+			o.id, id, shipmentStatusMap[s.status]), 0)
+	}
+	s.record(NewTrackingEvent(TrackingDelivered, time.Now(), "", ""))
+	o.finishDelivered()
+	return true, nil
+}
+
+//finishDelivered is a function for finishing an order (firing the finish event) once every ordered quantity is delivered
+func (o *Order) finishDelivered() {
+	if ShipStatusDelivered == o.ShippingStatus() {
+		if t, err := o.transition(EventFinish); nil == err {
+			o.take(t)
+		}
+	}
+}
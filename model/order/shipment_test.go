@@ -0,0 +1,155 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/order"
+	"sstest/model/product"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestShipOrder(t *testing.T) {
+	firstProd := product.New("firstProd", "First Product")
+	firstProd.SetStatus(product.StatusAvailable)
+	firstProd.SetStock(10)
+	firstProd.SetPrice(decimal.New(100, 0))
+	secondProd := product.New("secondProd", "Second Product")
+	secondProd.SetStatus(product.StatusAvailable)
+	secondProd.SetStock(10)
+	secondProd.SetPrice(decimal.New(50, 0))
+
+	splitOrder := order.New("splitOrder")
+	splitOrder.AddProduct(firstProd, 3)
+	splitOrder.AddProduct(secondProd, 1)
+	_, errShipDraft := splitOrder.Ship("carrier", "draftTrackingNo", nil)
+	splitOrder.SetStatus(order.StatusProcessed)
+
+	firstShipment, errFirstShip := splitOrder.Ship("firstCarrier", "firstTrackingNo", map[string]int{"firstProd": 2})
+	firstShipmentStatus, afterFirstStatus := firstShipment.Status(), splitOrder.ShippingStatus()
+	_, errOverShip := splitOrder.Ship("carrier", "overTrackingNo", map[string]int{"firstProd": 2})
+	_, errUnknownProduct := splitOrder.Ship("carrier", "unknownTrackingNo", map[string]int{"unknownProd": 1})
+	_, errZeroQuantity := splitOrder.Ship("carrier", "zeroTrackingNo", map[string]int{"secondProd": 0})
+	failedShipments := len(splitOrder.Shipments())
+	cancelOk, _ := splitOrder.Cancel()
+	_, errEarlyFinish := splitOrder.FinishOrder()
+	secondShipment, _ := splitOrder.Ship("secondCarrier", "secondTrackingNo", nil)
+	afterSecondStatus := splitOrder.ShippingStatus()
+	afterSecondEvents := strings.Join(splitOrder.AllowedEvents(), ",")
+	_, errNothingLeft := splitOrder.Ship("carrier", "emptyTrackingNo", nil)
+	firstDeliverOk, _ := splitOrder.DeliverShipment(firstShipment.ID())
+	afterFirstDeliveryStatus, afterFirstDeliveryOrderStatus := splitOrder.ShippingStatus(), splitOrder.Status()
+	_, errRedeliver := splitOrder.DeliverShipment(firstShipment.ID())
+	_, errDeliverMissing := splitOrder.DeliverShipment("missingShipment")
+	secondDeliverOk, _ := splitOrder.DeliverShipment(secondShipment.ID())
+
+	finishedOrder := order.New("finishedOrder")
+	finishedOrder.AddProduct(firstProd, 1)
+	finishedOrder.SetStatus(order.StatusProcessed)
+	finishedOrder.Ship("carrier", "finishedTrackingNo", nil)
+	finishOk, _ := finishedOrder.FinishOrder()
+
+	var shipOrderTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Ship Draft Order Failure Reason", true, nil != errShipDraft && errors.Is(errShipDraft, order.ErrInvalidStatus)},
+		{"First Shipment Error", true, nil == errFirstShip},
+		{"First Shipment Quantity", 2, firstShipment.Items()["firstProd"]},
+		{"First Shipment Carrier", "firstCarrier", firstShipment.Carrier()},
+		{"First Shipment Status", order.ShipmentStatusShipped, firstShipmentStatus},
+		{"Partially Shipped Status", order.ShipStatusPartiallyShipped, afterFirstStatus},
+		{"Over Shipment Failure Reason", true, nil != errOverShip && errors.Is(errOverShip, order.ErrInvalidShipment)},
+		{"Unknown Product Shipment Failure Reason", true, nil != errUnknownProduct && errors.Is(errUnknownProduct, order.ErrItemNotFound)},
+		{"Zero Quantity Shipment Failure Reason", true, nil != errZeroQuantity && errors.Is(errZeroQuantity, product.ErrInvalidQuantity)},
+		{"Failed Shipments Must Not Be Added", 1, failedShipments},
+		{"Partially Shipped Order Must Not Be Canceled", false, cancelOk},
+		{"Partially Shipped Order Finish Failure Reason", true, nil != errEarlyFinish && errors.Is(errEarlyFinish, order.ErrInvalidStatus)},
+		{"Second Shipment Must Ship Remaining Quantities", "firstProd,secondProd", strings.Join(secondShipment.ProductIDs(), ",")},
+		{"Second Shipment First Product Quantity", 1, secondShipment.Items()["firstProd"]},
+		{"Shipped Status", order.ShipStatusOnProcess, afterSecondStatus},
+		{"Shipped Order Allowed Events", "finish", afterSecondEvents},
+		{"Shipped Order Tracking ID", "secondTrackingNo", splitOrder.ShippingTrackingID()},
+		{"Nothing Left To Ship Failure Reason", true, nil != errNothingLeft && errors.Is(errNothingLeft, order.ErrInvalidStatus)},
+		{"First Delivery Must Succeed", true, firstDeliverOk},
+		{"Partially Delivered Shipping Status", order.ShipStatusOnProcess, afterFirstDeliveryStatus},
+		{"Partially Delivered Order Status", order.StatusProcessed, afterFirstDeliveryOrderStatus},
+		{"Redelivery Failure Reason", true, nil != errRedeliver && errors.Is(errRedeliver, order.ErrInvalidStatus)},
+		{"Missing Shipment Failure Reason", true, nil != errDeliverMissing && errors.Is(errDeliverMissing, order.ErrShipmentNotFound)},
+		{"Last Delivery Must Succeed", true, secondDeliverOk},
+		{"Delivered Shipping Status", order.ShipStatusDelivered, splitOrder.ShippingStatus()},
+		{"Last Delivery Must Finish Order", order.StatusDelivered, splitOrder.Status()},
+		{"Finish Must Succeed", true, finishOk},
+		{"Finish Must Deliver Shipments", order.ShipmentStatusDelivered, finishedOrder.Shipments()[0].Status()},
+		{"Finished Shipping Status", order.ShipStatusDelivered, finishedOrder.ShippingStatus()},
+	}
+
+	for _, test := range shipOrderTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestShippingSetters(t *testing.T) {
+	firstProd := product.New("firstProd", "First Product")
+	firstProd.SetStatus(product.StatusAvailable)
+	firstProd.SetStock(10)
+	firstProd.SetPrice(decimal.New(100, 0))
+
+	setOrder := order.New("setOrder")
+	setOrder.AddProduct(firstProd, 2)
+	setOrder.SetStatus(order.StatusProcessed)
+	emptyTrackingShipments := len(setOrder.SetShippingTrackingID("").Shipments())
+	setOrder.SetShippingTrackingID("setTrackingNo")
+	trackedShipments, trackedStatus, trackingID := len(setOrder.Shipments()), setOrder.ShippingStatus(), setOrder.ShippingTrackingID()
+	defaultQuantity := setOrder.Shipments()[0].Items()["firstProd"]
+	_, errUnknownStatus := setOrder.SetShippingStatus("unknown")
+	_, errPartiallyShipped := setOrder.SetShippingStatus(order.ShipStatusPartiallyShipped)
+	setOrder.SetShippingStatus(order.ShipStatusOutForDelivery)
+	outForDeliveryStatus := setOrder.ShippingStatus()
+	setOrder.SetShippingStatus(order.ShipStatusDeliveryFailed)
+	deliveryFailedStatus := setOrder.ShippingStatus()
+	setOrder.SetShippingStatus(order.ShipStatusOnProcess)
+	onProcessStatus := setOrder.ShippingStatus()
+	setOrder.SetShippingStatus(order.ShipStatusReturned)
+	returnedStatus := setOrder.ShippingStatus()
+	setOrder.SetShippingStatus(order.ShipStatusNone)
+	noneShipments, noneStatus := len(setOrder.Shipments()), setOrder.ShippingStatus()
+	setOrder.SetShippingStatus(order.ShipStatusDelivered)
+
+	var shippingSetterTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Empty Tracking ID Must Not Add Shipment", 0, emptyTrackingShipments},
+		{"Tracking ID Must Add Default Shipment", 1, trackedShipments},
+		{"Default Shipment Must Ship Unshipped Quantities", 2, defaultQuantity},
+		{"Tracked Shipping Status", order.ShipStatusOnProcess, trackedStatus},
+		{"Tracked Shipping Tracking ID", "setTrackingNo", trackingID},
+		{"Unknown Shipping Status Must Fail", false, nil == errUnknownStatus},
+		{"Partially Shipped Status Failure Reason", true, nil != errPartiallyShipped && errors.Is(errPartiallyShipped, order.ErrInvalidShipment)},
+		{"Out For Delivery Shipping Status", order.ShipStatusOutForDelivery, outForDeliveryStatus},
+		{"Delivery Failed Shipping Status", order.ShipStatusDeliveryFailed, deliveryFailedStatus},
+		{"On Process Shipping Status", order.ShipStatusOnProcess, onProcessStatus},
+		{"Returned Shipping Status", order.ShipStatusReturned, returnedStatus},
+		{"None Must Clear Shipments", 0, noneShipments},
+		{"None Shipping Status", order.ShipStatusNone, noneStatus},
+		{"Delivered Shipping Status", order.ShipStatusDelivered, setOrder.ShippingStatus()},
+		{"Delivered Shipment Status", order.ShipmentStatusDelivered, setOrder.Shipments()[0].Status()},
+	}
+
+	for _, test := range shippingSetterTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
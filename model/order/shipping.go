@@ -0,0 +1,142 @@
+package order
+
+import (
+	"fmt"
+	"sstest/model/money"
+	"sstest/model/product"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//ShippingRateProvider is interface of a shipping rate calculation, quoting the shipping cost of an order
+type ShippingRateProvider interface {
+	//Rate returns the shipping cost in the order's currency of the ordered quantities of every product of an order,
+	//subtotal is the order's items amount subtracted with its coupons' discounts, rates convert the provider's amounts
+	//into the order's currency (nil when they must be in it)
+	Rate(quantities map[*product.Product]int, subtotal decimal.Decimal, currency string, rates money.ExchangeRateProvider) (decimal.Decimal, *errors.Error)
+}
+
+//convertAmount converts an amount of a shipping rate into a currency with exchange rates, rounded to its minor unit
+func convertAmount(amount decimal.Decimal, from, to string, rates money.ExchangeRateProvider) (decimal.Decimal, *errors.Error) {
+	if from == to || amount.IsZero() {
+		return money.Round(amount, to), nil
+	}
+	if nil == rates {
+		return decimal.Zero, errors.WrapPrefix(ErrCurrencyMismatch, fmt.Sprintf("Can't convert shipping rate in %v into %v without exchange rates", from, to), 0)
+	}
+	rate, _, err := rates.Rate(from, to)
+	if err != nil {
+		return decimal.Zero, errors.WrapPrefix(err, fmt.Sprintf("Can't convert shipping rate in %v into %v", from, to), 0)
+	}
+	return money.Convert(amount, rate, to), nil
+}
+
+//FlatRate is a ShippingRateProvider charging the same cost for every order
+type FlatRate struct {
+	cost     decimal.Decimal
+	currency string
+}
+
+//NewFlatRate creates a new flat shipping rate of a cost in a currency and returns a reference to it
+func NewFlatRate(cost decimal.Decimal, currency string) *FlatRate {
+	return &FlatRate{cost, currency}
+}
+
+//Cost is a getter function for returning a flat shipping rate's cost
+func (r *FlatRate) Cost() decimal.Decimal {
+	return r.cost
+}
+
+//Currency is a getter function for returning the currency of a flat shipping rate's cost
+func (r *FlatRate) Currency() string {
+	return r.currency
+}
+
+//Rate returns the flat shipping cost converted into the order's currency
+func (r *FlatRate) Rate(quantities map[*product.Product]int, subtotal decimal.Decimal, currency string, rates money.ExchangeRateProvider) (decimal.Decimal, *errors.Error) {
+	return convertAmount(r.cost, r.currency, currency, rates)
+}
+
+//WeightRate is a ShippingRateProvider charging a base cost plus a cost per kilogram of the order's billable weight
+type WeightRate struct {
+	base     decimal.Decimal
+	perKg    decimal.Decimal
+	divisor  decimal.Decimal //cubic centimeters per kilogram, zero when only the weight is billed
+	currency string          //the currency of the base cost and the cost per kilogram
+}
+
+//NewWeightRate creates a new weight based shipping rate of costs in a currency and returns a reference to it
+func NewWeightRate(base, perKg, divisor decimal.Decimal, currency string) *WeightRate {
+	return &WeightRate{base, perKg, divisor, currency}
+}
+
+//Currency is a getter function for returning the currency of a weight based shipping rate's costs
+func (r *WeightRate) Currency() string {
+	return r.currency
+}
+
+//BillableWeight returns the billable weight (in kilograms) of the ordered quantities of every product of an order
+func (r *WeightRate) BillableWeight(quantities map[*product.Product]int) decimal.Decimal {
+	weight := decimal.New(0, 0)
+	for p, quantity := range quantities {
+		billable := p.Weight()
+		if false == r.divisor.IsZero() {
+			if volumetric := p.Volume().Div(r.divisor); volumetric.GreaterThan(billable) {
+				billable = volumetric
+			}
+		}
+		weight = weight.Add(billable.Mul(decimal.New(int64(quantity), 0)))
+	}
+	return weight
+}
+
+//Rate returns the base cost plus the cost of the billable weight, converted into the order's currency (rounded to its minor unit)
+func (r *WeightRate) Rate(quantities map[*product.Product]int, subtotal decimal.Decimal, currency string, rates money.ExchangeRateProvider) (decimal.Decimal, *errors.Error) {
+	return convertAmount(r.base.Add(r.perKg.Mul(r.BillableWeight(quantities))), r.currency, currency, rates)
+}
+
+//FreeOverThreshold is a ShippingRateProvider shipping for free an order having a subtotal of at least a threshold
+type FreeOverThreshold struct {
+	threshold decimal.Decimal
+	currency  string //the currency of the threshold
+	otherwise ShippingRateProvider
+}
+
+//NewFreeOverThreshold creates a new free over threshold shipping rate of a threshold in a currency and returns a reference to it
+func NewFreeOverThreshold(threshold decimal.Decimal, currency string, otherwise ShippingRateProvider) *FreeOverThreshold {
+	return &FreeOverThreshold{threshold, currency, otherwise}
+}
+
+//Currency is a getter function for returning the currency of a free over threshold shipping rate's threshold
+func (r *FreeOverThreshold) Currency() string {
+	return r.currency
+}
+
+//Rate returns zero for a subtotal of at least the threshold, the cost rated by the other provider otherwise
+func (r *FreeOverThreshold) Rate(quantities map[*product.Product]int, subtotal decimal.Decimal, currency string, rates money.ExchangeRateProvider) (decimal.Decimal, *errors.Error) {
+	threshold, err := convertAmount(r.threshold, r.currency, currency, rates)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if subtotal.GreaterThanOrEqual(threshold) {
+		return decimal.New(0, 0), nil
+	}
+	return r.otherwise.Rate(quantities, subtotal, currency, rates)
+}
+
+//defaultShippingRate is the shipping rate provider of newly created orders
+var defaultShippingRate ShippingRateProvider = NewFlatRate(decimal.New(0, 0), money.DefaultCurrency)
+
+//DefaultShippingRate returns the shipping rate provider of newly created orders
+func DefaultShippingRate() ShippingRateProvider {
+	return defaultShippingRate
+}
+
+//SetDefaultShippingRate sets the shipping rate provider of newly created orders, free shipping when nil
+func SetDefaultShippingRate(r ShippingRateProvider) {
+	if nil == r {
+		r = NewFlatRate(decimal.New(0, 0), money.DefaultCurrency)
+	}
+	defaultShippingRate = r
+}
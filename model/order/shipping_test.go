@@ -0,0 +1,148 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//failingRate is a ShippingRateProvider failing to rate any order
+type failingRate struct{}
+
+func (failingRate) Rate(quantities map[*product.Product]int, subtotal decimal.Decimal, currency string, rates money.ExchangeRateProvider) (decimal.Decimal, *errors.Error) {
+	return decimal.New(0, 0), errors.Wrap(fmt.Errorf("carrier rates unavailable"), 0)
+}
+
+//newSizedProduct creates an available product having a price, a weight (kilograms) and cubic dimensions (centimeters)
+func newSizedProduct(id string, price int64, weight decimal.Decimal, side int64) *product.Product {
+	p := product.New(id, id)
+	p.SetStatus(product.StatusAvailable)
+	p.SetStock(100)
+	p.SetPrice(decimal.New(price, 0))
+	p.SetWeight(weight)
+	p.SetDimensions(decimal.New(side, 0), decimal.New(side, 0), decimal.New(side, 0))
+	return p
+}
+
+func TestShippingRates(t *testing.T) {
+	heavyProd := newSizedProduct("heavyProd", 100, decimal.New(2, 0), 30)
+	lightProd := newSizedProduct("lightProd", 20, decimal.New(5, -1), 10)
+	quantities := map[*product.Product]int{heavyProd: 2, lightProd: 3}
+
+	rates := money.NewRateTable("USD", time.Now(), map[string]decimal.Decimal{"JPY": decimal.New(1109, -1)})
+	flatCost, _ := order.NewFlatRate(decimal.New(75, -1), "USD").Rate(quantities, decimal.New(260, 0), "USD", nil)
+	weightOnly := order.NewWeightRate(decimal.New(5, 0), decimal.New(2, 0), decimal.New(0, 0), "USD")
+	weightOnlyCost, _ := weightOnly.Rate(quantities, decimal.New(260, 0), "USD", nil)
+	volumetric := order.NewWeightRate(decimal.New(5, 0), decimal.New(2, 0), decimal.New(5000, 0), "USD")
+	volumetricCost, _ := volumetric.Rate(quantities, decimal.New(260, 0), "USD", nil)
+	freeOver := order.NewFreeOverThreshold(decimal.New(250, 0), "USD", order.NewFlatRate(decimal.New(75, -1), "USD"))
+	freeCost, _ := freeOver.Rate(quantities, decimal.New(250, 0), "USD", nil)
+	underThresholdCost, _ := freeOver.Rate(quantities, decimal.New(24999, -2), "USD", nil)
+	convertedFlatCost, _ := order.NewFlatRate(decimal.New(75, -1), "USD").Rate(quantities, decimal.New(28834, 0), "JPY", rates)
+	_, errUnconverted := order.NewFlatRate(decimal.New(75, -1), "USD").Rate(quantities, decimal.New(28834, 0), "JPY", nil)
+	convertedFreeCost, _ := freeOver.Rate(quantities, decimal.New(27725, 0), "JPY", rates)
+	convertedUnderThresholdCost, _ := freeOver.Rate(quantities, decimal.New(27724, 0), "JPY", rates)
+	convertedVolumetricCost, _ := volumetric.Rate(quantities, decimal.New(28834, 0), "JPY", rates)
+
+	var shippingRateTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Flat Rate Cost", "7.5", flatCost.String()},
+		{"Weight Only Billable Weight", "5.5", weightOnly.BillableWeight(quantities).String()},
+		{"Weight Only Cost", "16", weightOnlyCost.String()},
+		{"Volumetric Billable Weight Must Take Greater Weight", "12.3", volumetric.BillableWeight(quantities).String()},
+		{"Volumetric Cost", "29.6", volumetricCost.String()},
+		{"Subtotal At Threshold Must Ship For Free", "0", freeCost.String()},
+		{"Subtotal Under Threshold Must Be Rated", "7.5", underThresholdCost.String()},
+		{"Converted Flat Rate Cost Must Be Rounded To Currency Minor Unit", "832", convertedFlatCost.String()},
+		{"Flat Rate In Other Currency Without Exchange Rates Failure Reason", true, nil != errUnconverted && errors.Is(errUnconverted, order.ErrCurrencyMismatch)},
+		{"Subtotal At Converted Threshold Must Ship For Free", "0", convertedFreeCost.String()},
+		{"Subtotal Under Converted Threshold Must Be Rated", "832", convertedUnderThresholdCost.String()},
+		{"Converted Volumetric Cost", "3283", convertedVolumetricCost.String()},
+	}
+
+	for _, test := range shippingRateTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderShippingCost(t *testing.T) {
+	order.SetDefaultShippingRate(order.NewFlatRate(decimal.New(3, 0), "USD"))
+	t.Cleanup(func() { order.SetDefaultShippingRate(nil) })
+	heavyProd := newSizedProduct("heavyProd", 100, decimal.New(2, 0), 30)
+	lightProd := newSizedProduct("lightProd", 20, decimal.New(5, -1), 10)
+	rate := order.NewFreeOverThreshold(decimal.New(300, 0), "USD", order.NewWeightRate(decimal.New(5, 0), decimal.New(2, 0), decimal.New(5000, 0), "USD"))
+
+	defaultOrder := order.New("defaultOrder")
+	defaultOrder.AddProduct(lightProd, 1)
+	defaultOrder.Submit("ship name", "ship address", nil)
+
+	ratedOrder := order.New("ratedOrder").SetShippingRate(rate)
+	_, errQuoteEmpty := ratedOrder.Quote("", nil)
+	ratedOrder.AddProduct(heavyProd, 2)
+	ratedOrder.AddProduct(lightProd, 3)
+	quoted, errQuote := ratedOrder.Quote("", nil)
+	quoteAmountUnchanged := ratedOrder.Amount().String()
+	submitOk, _ := ratedOrder.Submit("ship name", "ship address", nil)
+	_, errQuoteSubmitted := ratedOrder.Quote("", nil)
+
+	freeOrder := order.New("freeOrder").SetShippingRate(rate)
+	freeOrder.AddProduct(heavyProd, 3)
+	freeOrder.Submit("ship name", "ship address", nil)
+
+	yenProd := newSizedProduct("yenProd", 1000, decimal.New(1, 0), 10)
+	yenProd.SetCurrency("JPY")
+	yenOrder := order.New("yenOrder").SetShippingRate(order.NewFlatRate(decimal.New(75, -1), "USD")).
+		SetExchangeRates(money.NewRateTable("USD", time.Now(), map[string]decimal.Decimal{"JPY": decimal.New(1109, -1)}))
+	yenOrder.ChangeCurrency("JPY")
+	yenOrder.AddProduct(yenProd, 1)
+	yenOrder.Submit("ship name", "ship address", nil)
+
+	failingOrder := order.New("failingOrder").SetShippingRate(failingRate{})
+	failingOrder.AddProduct(lightProd, 1)
+	failingSubmitOk, _ := failingOrder.Submit("ship name", "ship address", nil)
+
+	var orderShippingCostTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Default Shipping Rate Cost", "3", defaultOrder.ShippingCost().String()},
+		{"Default Shipping Rate Amount", "23", defaultOrder.Amount().String()},
+		{"Quote Without Item Failure Reason", true, nil != errQuoteEmpty && errors.Is(errQuoteEmpty, order.ErrNoItem)},
+		{"Quote Error", true, nil == errQuote},
+		{"Quoted Shipping Cost", "29.6", quoted.ShippingCost().String()},
+		{"Quoted Amount", "289.6", quoted.Total().String()},
+		{"Quote Must Not Change Amount", "0", quoteAmountUnchanged},
+		{"Submit Must Succeed", true, submitOk},
+		{"Submitted Amount Must Be Quoted Amount", quoted.Total().String(), ratedOrder.Amount().String()},
+		{"Submitted Shipping Cost Must Be Quoted Shipping Cost", quoted.ShippingCost().String(), ratedOrder.ShippingCost().String()},
+		{"Quote Submitted Order Failure Reason", true, nil != errQuoteSubmitted && errors.Is(errQuoteSubmitted, order.ErrInvalidStatus)},
+		{"Free Over Threshold Shipping Cost", "0", freeOrder.ShippingCost().String()},
+		{"Free Over Threshold Amount", "300", freeOrder.Amount().String()},
+		{"Shipping Cost Converted Into Order Currency", "832", yenOrder.ShippingCost().String()},
+		{"Converted Shipping Cost Amount", "1832", yenOrder.Amount().String()},
+		{"Failed Rating Must Fail Submit", false, failingSubmitOk},
+		{"Failed Rating Must Keep Draft", order.StatusDraft, failingOrder.Status()},
+	}
+
+	for _, test := range orderShippingCostTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
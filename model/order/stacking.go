@@ -0,0 +1,164 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"sstest/model/coupon"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//StackPercentageFirst is const for applying an order's stacked percentage coupons before its value coupons
+const StackPercentageFirst string = "percentage-first"
+
+//StackValueFirst is const for applying an order's stacked value coupons before its percentage coupons
+const StackValueFirst string = "value-first"
+
+//stackingMap is a map of known stacking order and its label pairs
+var stackingMap = map[string]string{
+	StackPercentageFirst: "Percentage First",
+	StackValueFirst:      "Value First",
+}
+
+//IsStacking returns whether a stacking order is a known stacking order
+func IsStacking(stacking string) bool {
+	_, ok := stackingMap[stacking]
+	return ok
+}
+
+//Stackings returns the known stacking orders, sorted
+func Stackings() []string {
+	stackings := make([]string, 0, len(stackingMap))
+	for stacking := range stackingMap {
+		stackings = append(stackings, stacking)
+	}
+	sort.Strings(stackings)
+	return stackings
+}
+
+//defaultStacking is the stacking order of newly created orders
+var defaultStacking = StackPercentageFirst
+
+//DefaultStacking returns the stacking order of newly created orders
+func DefaultStacking() string {
+	return defaultStacking
+}
+
+//SetDefaultStacking sets the stacking order of newly created orders, percentage-first when empty
+func SetDefaultStacking(stacking string) *errors.Error {
+	if "" == stacking {
+		stacking = StackPercentageFirst
+	}
+	if false == IsStacking(stacking) {
+		return errors.Wrap(fmt.Errorf("Can't set unknown stacking order %v, expected one of: %v", stacking, strings.Join(Stackings(), ", ")), 0)
+	}
+	defaultStacking = stacking
+	return nil
+}
+
+//Stacking is a getter function for returning the order an order's stacked coupons are applied in
+func (o *Order) Stacking() string {
+	return o.stacking
+}
+
+//SetStacking is a setter function for setting the order an order's stacked coupons are applied in
+func (o *Order) SetStacking(stacking string) (*Order, *errors.Error) {
+	if false == IsStacking(stacking) {
+		//note: defensive code, return nil when error is encountered (possible runtime error on caller code when method chaining)
+		return nil, errors.Wrap(fmt.Errorf("Can't set unknown stacking order %v, expected one of: %v", stacking, strings.Join(Stackings(), ", ")), 0)
+	}
+	o.stacking = stacking
+	return o, nil
+}
+
+//AppliedCoupon is a coupon applied on an order and its discount on the order
+type AppliedCoupon struct {
+	coupon   *coupon.Coupon
+	discount decimal.Decimal
+}
+
+//NewAppliedCoupon creates a new coupon applied on an order with its discount and returns a reference to it
+func NewAppliedCoupon(c *coupon.Coupon, discount decimal.Decimal) *AppliedCoupon {
+	return &AppliedCoupon{c, discount}
+}
+
+//Coupon is a getter function for returning the coupon applied on an order
+func (a *AppliedCoupon) Coupon() *coupon.Coupon {
+	return a.coupon
+}
+
+//Discount is a getter function for returning the discount of an applied coupon on its order
+func (a *AppliedCoupon) Discount() decimal.Decimal {
+	return a.discount
+}
+
+//Coupons is a getter function for returning the coupons applied on an order in their application order
+func (o *Order) Coupons() []*coupon.Coupon {
+	coupons := make([]*coupon.Coupon, 0, len(o.coupons))
+	for _, applied := range o.coupons {
+		coupons = append(coupons, applied.coupon)
+	}
+	return coupons
+}
+
+//AppliedCoupons is a getter function for returning the coupons applied on an order with their discounts, in their application order
+func (o *Order) AppliedCoupons() []*AppliedCoupon {
+	return append([]*AppliedCoupon{}, o.coupons...)
+}
+
+//SetAppliedCoupons is a setter function for setting the coupons applied on an order with their discounts, in their application order
+func (o *Order) SetAppliedCoupons(applied ...*AppliedCoupon) *Order {
+	o.coupons = append([]*AppliedCoupon{}, applied...)
+	return o
+}
+
+//stack is a function for ordering the coupons applied together on an order by its stacking order
+//Returns the coupons in their application order or an error describing why they can't be combined
+func (o *Order) stack(coupons []*coupon.Coupon) ([]*coupon.Coupon, *errors.Error) {
+	stacked := make([]*coupon.Coupon, 0, len(coupons))
+	for _, c := range coupons {
+		if nil == c {
+			continue
+		}
+		for _, prev := range stacked {
+			if _, err := prev.CanBeCombinedWith(c); err != nil {
+				return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't apply coupons on order %v", o.id), 0)
+			}
+		}
+		stacked = append(stacked, c)
+	}
+	sort.SliceStable(stacked, func(i, j int) bool {
+		return o.stackRank(stacked[i]) < o.stackRank(stacked[j])
+	})
+	return stacked, nil
+}
+
+//stackRank returns the rank of a coupon in the application order of an order's stacked coupons (lowest first)
+func (o *Order) stackRank(c *coupon.Coupon) int {
+	switch c.Kind() {
+	case coupon.KindBuyXGetY, coupon.KindBundle:
+		return 0
+	case coupon.KindFreeShipping:
+		return 3
+	case coupon.KindValue:
+		if StackValueFirst == o.stacking {
+			return 1
+		}
+		return 2
+	}
+	if StackValueFirst == o.stacking {
+		return 2
+	}
+	return 1
+}
+
+//couponIDs returns the ids of coupons in their given order
+func couponIDs(coupons []*coupon.Coupon) []string {
+	ids := make([]string, 0, len(coupons))
+	for _, c := range coupons {
+		ids = append(ids, c.ID())
+	}
+	return ids
+}
@@ -0,0 +1,142 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//newStackableCoupon creates an active stackable coupon of a kind and value with a stock of 10
+func newStackableCoupon(id, kind string, value int64) *coupon.Coupon {
+	return newConditionalCoupon(id, kind, value).SetStackable(true)
+}
+
+func TestOrderCouponStacking(t *testing.T) {
+	ledger := repository.NewMemoryCouponRepository()
+	percentageCoupon := newStackableCoupon("PERCENT10", coupon.KindPercentage, 10)
+	valueCoupon := newStackableCoupon("VALUE10", coupon.KindValue, 10)
+	bigCoupon := newStackableCoupon("VALUE60", coupon.KindValue, 60)
+	biggerCoupon := newStackableCoupon("VALUE50", coupon.KindValue, 50)
+	exclusiveCoupon := newConditionalCoupon("EXCLUSIVE", coupon.KindValue, 5)
+	limitedCoupon := newStackableCoupon("LIMITED", coupon.KindValue, 5)
+	limitedCoupon.SetRedemptionLimit(1)
+	for _, c := range []*coupon.Coupon{percentageCoupon, valueCoupon, bigCoupon, biggerCoupon, exclusiveCoupon, limitedCoupon} {
+		ledger.Create(c)
+	}
+	stackedProd := newTaxedProduct("stackedProd", 30, product.TaxCategoryStandard)
+	anotherStackedProd := newTaxedProduct("anotherStackedProd", 70, product.TaxCategoryStandard)
+	newOrder := func(id string) *order.Order {
+		o := order.New(id).SetShippingRate(order.NewFlatRate(decimal.New(0, 0), "USD")).SetLedger(ledger)
+		o.AddProduct(stackedProd, 1)
+		o.AddProduct(anotherStackedProd, 1)
+		return o
+	}
+
+	percentageFirstOrder := newOrder("percentageFirstOrder")
+	percentageFirst, _ := percentageFirstOrder.Quote("", valueCoupon, percentageCoupon)
+	valueFirstOrder := newOrder("valueFirstOrder")
+	_, errUnknownStacking := valueFirstOrder.SetStacking("unknown")
+	valueFirstOrder.SetStacking(order.StackValueFirst)
+	valueFirst, _ := valueFirstOrder.Quote("", percentageCoupon, valueCoupon)
+	errDefaultStacking := order.SetDefaultStacking("unknown")
+	order.SetDefaultStacking(order.StackValueFirst)
+	defaultStackingOrder := newOrder("defaultStackingOrder")
+	order.SetDefaultStacking("")
+
+	exclusiveOrder := newOrder("exclusiveOrder")
+	_, errExclusiveQuote := exclusiveOrder.Quote("", percentageCoupon, exclusiveCoupon)
+	exclusiveOk, errExclusive := exclusiveOrder.Submit("ship name", "ship address", percentageCoupon, exclusiveCoupon)
+	_, errTwice := exclusiveOrder.Quote("", percentageCoupon, percentageCoupon)
+	exclusiveAloneOk, _ := exclusiveOrder.Submit("ship name", "ship address", nil, exclusiveCoupon, nil)
+
+	overDiscountOrder := newOrder("overDiscountOrder")
+	overDiscountOk, errOverDiscount := overDiscountOrder.Submit("ship name", "ship address", bigCoupon, biggerCoupon)
+	overDiscountStock := bigCoupon.Stock()
+
+	submittedOrder := newOrder("submittedOrder")
+	submittedOk, _ := submittedOrder.Submit("ship name", "ship address", valueCoupon, percentageCoupon)
+	applied := submittedOrder.AppliedCoupons()
+	percentageStock, valueStock := percentageCoupon.Stock(), valueCoupon.Stock()
+	percentageRedemptions, _ := ledger.FindRedemptions("PERCENT10")
+	valueRedemptions, _ := ledger.FindRedemptions("VALUE10")
+	lineDiscounts := decimal.New(0, 0)
+	for _, line := range submittedOrder.Breakdown().Lines() {
+		lineDiscounts = lineDiscounts.Add(line.Discount())
+	}
+	canceledOk, _ := submittedOrder.Cancel()
+	canceledPercentageRedemptions, _ := ledger.FindRedemptions("PERCENT10")
+	canceledValueRedemptions, _ := ledger.FindRedemptions("VALUE10")
+
+	limitedOrder := newOrder("limitedOrder")
+	limitedOk, _ := limitedOrder.Submit("ship name", "ship address", limitedCoupon)
+	overLimitOrder := newOrder("overLimitOrder")
+	overLimitOk, errOverLimit := overLimitOrder.Submit("ship name", "ship address", valueCoupon, limitedCoupon)
+	overLimitValueRedemptions, _ := ledger.FindRedemptions("VALUE10")
+
+	var stackingTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Default Stacking", order.StackPercentageFirst, percentageFirstOrder.Stacking()},
+		{"Percentage First Discount", "20", percentageFirst.Discount().String()},
+		{"Percentage First Total", "80", percentageFirst.Total().String()},
+		{"Percentage First Applied Coupon", "PERCENT10", percentageFirst.AppliedCoupons()[0].Coupon().ID()},
+		{"Percentage First Applied Discount", "10", percentageFirst.AppliedCoupons()[0].Discount().String()},
+		{"Percentage First Then Value", "VALUE10", percentageFirst.AppliedCoupons()[1].Coupon().ID()},
+		{"Quote Doesn't Apply Coupons", 0, len(percentageFirstOrder.Coupons())},
+		{"Unknown Stacking Must Fail", true, nil != errUnknownStacking},
+		{"Value First Stacking", order.StackValueFirst, valueFirstOrder.Stacking()},
+		{"Value First Discount", "19", valueFirst.Discount().String()},
+		{"Value First Total", "81", valueFirst.Total().String()},
+		{"Value First Applied Coupon", "VALUE10", valueFirst.AppliedCoupons()[0].Coupon().ID()},
+		{"Value First Percentage Discounts The Amount Left", "9", valueFirst.AppliedCoupons()[1].Discount().String()},
+		{"Unknown Default Stacking Must Fail", true, nil != errDefaultStacking},
+		{"Configured Default Stacking", order.StackValueFirst, defaultStackingOrder.Stacking()},
+		{"Reset Default Stacking", order.StackPercentageFirst, order.DefaultStacking()},
+		{"Quote With Exclusive Coupon Must Fail", true, nil != errExclusiveQuote && errors.Is(errExclusiveQuote, coupon.ErrNotCombinable)},
+		{"Submit With Exclusive Coupon Must Fail", false, exclusiveOk},
+		{"Submit With Exclusive Coupon Failure Reason", true, nil != errExclusive && errors.Is(errExclusive, coupon.ErrNotCombinable)},
+		{"Same Coupon Twice Must Fail", true, nil != errTwice && errors.Is(errTwice, coupon.ErrNotCombinable)},
+		{"Submit With Exclusive Coupon Alone", true, exclusiveAloneOk},
+		{"Exclusive Coupon Applied Alone", 1, len(exclusiveOrder.Coupons())},
+		{"Zero Or Less Combined Amount Must Fail", false, overDiscountOk},
+		{"Zero Or Less Combined Amount Failure Reason", true, nil != errOverDiscount && errors.Is(errOverDiscount, order.ErrInvalidAmount)},
+		{"Zero Or Less Combined Amount Order Stays Draft", order.StatusDraft, overDiscountOrder.Status()},
+		{"Zero Or Less Combined Amount Coupon Stock Is Kept", int64(10), overDiscountStock},
+		{"Submit With Stacked Coupons", true, submittedOk},
+		{"Submitted Discount", "20", submittedOrder.Discount().String()},
+		{"Submitted Amount", "80", submittedOrder.Amount().String()},
+		{"Submitted Applied Coupons", 2, len(applied)},
+		{"Submitted Applied Coupon", percentageCoupon, applied[0].Coupon()},
+		{"Submitted Applied Discount", "10", applied[1].Discount().String()},
+		{"Line Discounts Reconcile With Discount", "20", lineDiscounts.String()},
+		{"Breakdown Applied Coupons", 2, len(submittedOrder.Breakdown().AppliedCoupons())},
+		{"Submit Decrements Every Coupon Stock", true, int64(9) == percentageStock && int64(9) == valueStock},
+		{"Submit Redeems Every Coupon", true, 1 == len(percentageRedemptions) && 1 == len(valueRedemptions)},
+		{"Redemption Amount Is Coupon Discount", "10", percentageRedemptions[0].Amount().String()},
+		{"Cancel With Stacked Coupons", true, canceledOk},
+		{"Cancel Returns Every Coupon Stock", true, int64(10) == percentageCoupon.Stock() && int64(10) == valueCoupon.Stock()},
+		{"Cancel Reverses Every Redemption", true, canceledPercentageRedemptions[0].IsReversed() && canceledValueRedemptions[0].IsReversed()},
+		{"Submit With Limited Coupon", true, limitedOk},
+		{"Submit Over A Coupon Limit Must Fail", false, overLimitOk},
+		{"Submit Over A Coupon Limit Failure Reason", true, nil != errOverLimit && errors.Is(errOverLimit, coupon.ErrRedemptionLimit)},
+		{"Submit Over A Coupon Limit Redeems No Coupon", 1, len(overLimitValueRedemptions)},
+		{"Submit Over A Coupon Limit Returns Every Coupon Stock", true, int64(10) == valueCoupon.Stock() && int64(9) == limitedCoupon.Stock()},
+		{"Submit Over A Coupon Limit Order Has No Coupon", 0, len(overLimitOrder.Coupons())},
+	}
+
+	for _, test := range stackingTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
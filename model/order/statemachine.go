@@ -0,0 +1,393 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//EventSubmit is const for the 'submit' order event (fired by Order.Submit)
+const EventSubmit string = "submit"
+
+//EventProcess is const for the 'process' order event (fired by Order.Process)
+const EventProcess string = "process"
+
+//EventCancel is const for the 'cancel' order event (fired by Order.Cancel, returning the order's stock)
+const EventCancel string = "cancel"
+
+//EventShip is const for the 'ship' order event (fired by Order.Ship and Order.ProcessShipping, adding a shipment)
+const EventShip string = "ship"
+
+//EventFinish is const for the 'finish' order event
+const EventFinish string = "finish"
+
+//methodEvents is the set of events needing arguments or stock changes, fired only by their own order method (not by Order.Fire)
+var methodEvents = map[string]string{
+	EventSubmit: "Submit",
+	EventCancel: "Cancel",
+	EventShip:   "Ship",
+}
+
+//Guard is a condition an order must meet to take a transition, returns an error describing why the order doesn't
+type Guard func(o *Order) *errors.Error
+
+//Effect is a side effect applied on an order taking a transition
+type Effect func(o *Order)
+
+//Transition is a declarative order status transition: an event moving an order from a status to another status
+type Transition struct {
+	From    string
+	Event   string
+	To      string
+	Guards  []Guard
+	Effects []Effect
+}
+
+//StateMachine is the transition table driving the order status changes
+type StateMachine struct {
+	initial     string
+	statuses    map[string]string
+	codes       []string
+	transitions []Transition
+	mu          sync.RWMutex
+}
+
+//NewStateMachine creates a new state machine having only its initial status and returns a reference to it
+func NewStateMachine(initial, label string) *StateMachine {
+	m := &StateMachine{
+		initial,
+		make(map[string]string, 5),
+		make([]string, 0, 5),
+		make([]Transition, 0, 5),
+		*new(sync.RWMutex),
+	}
+	return m.AddStatus(initial, label)
+}
+
+//NewDefaultStateMachine creates a new state machine of the standard order lifecycle and returns a reference to it
+func NewDefaultStateMachine() *StateMachine {
+	m := NewStateMachine(StatusDraft, statusMap[StatusDraft])
+	for _, status := range []string{StatusSubmitted, StatusProcessed, StatusDelivered, StatusCanceled} {
+		m.AddStatus(status, statusMap[status])
+	}
+	for _, t := range []Transition{
+		{StatusDraft, EventSubmit, StatusSubmitted, []Guard{hasItems, userCanOrder}, []Effect{setSubmittedDate}},
+		{StatusSubmitted, EventProcess, StatusProcessed, nil, []Effect{setProcessedDate}},
+		{StatusSubmitted, EventCancel, StatusCanceled, nil, nil},
+		{StatusProcessed, EventCancel, StatusCanceled, []Guard{notShipped}, nil},
+		{StatusProcessed, EventShip, StatusProcessed, []Guard{notFullyShipped}, nil},
+		{StatusProcessed, EventFinish, StatusDelivered, []Guard{fullyShipped}, []Effect{deliverShipments}},
+	} {
+		m.AddTransition(t)
+	}
+	return m
+}
+
+//defaultStateMachine is the state machine of newly created orders
+var defaultStateMachine = NewDefaultStateMachine()
+
+//defaultStateMachineMu guards defaultStateMachine
+var defaultStateMachineMu sync.RWMutex
+
+//DefaultStateMachine returns the state machine of newly created orders
+func DefaultStateMachine() *StateMachine {
+	defaultStateMachineMu.RLock()
+	defer defaultStateMachineMu.RUnlock()
+	return defaultStateMachine
+}
+
+//SetDefaultStateMachine sets the state machine of newly created orders, the standard order lifecycle when nil
+func SetDefaultStateMachine(m *StateMachine) {
+	if nil == m {
+		m = NewDefaultStateMachine()
+	}
+	defaultStateMachineMu.Lock()
+	defer defaultStateMachineMu.Unlock()
+	defaultStateMachine = m
+}
+
+//Initial is a getter function for returning a state machine's initial status
+func (m *StateMachine) Initial() string {
+	return m.initial
+}
+
+//Editable returns whether the items, coupons and currency of an order in a status can be changed (only in the initial status)
+func (m *StateMachine) Editable(status string) bool {
+	return m.initial == status
+}
+
+//Statuses returns a state machine's status codes in the order they were added
+func (m *StateMachine) Statuses() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.codes...)
+}
+
+//HasStatus returns whether a status is known by a state machine
+func (m *StateMachine) HasStatus(status string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.statuses[status]
+	return ok
+}
+
+//Label returns the label of a status (the status code itself for an unknown status)
+func (m *StateMachine) Label(status string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if label, ok := m.statuses[status]; ok {
+		return label
+	}
+	return status
+}
+
+//Transitions returns a state machine's transitions in the order they were added
+func (m *StateMachine) Transitions() []Transition {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Transition(nil), m.transitions...)
+}
+
+//Events returns the events of the transitions from a status (regardless of their guards)
+func (m *StateMachine) Events(from string) []string {
+	events := make([]string, 0, 3)
+	for _, t := range m.Transitions() {
+		if from == t.From {
+			events = append(events, t.Event)
+		}
+	}
+	return events
+}
+
+//HasEvent returns whether an event is fired by any transition of a state machine
+func (m *StateMachine) HasEvent(event string) bool {
+	for _, t := range m.Transitions() {
+		if event == t.Event {
+			return true
+		}
+	}
+	return false
+}
+
+//Find returns the transition fired by an event from a status, or false when there is none
+func (m *StateMachine) Find(from, event string) (Transition, bool) {
+	for _, t := range m.Transitions() {
+		if from == t.From && event == t.Event {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+//AddStatus is a function for adding a custom status (or relabeling a known status) to a state machine
+func (m *StateMachine) AddStatus(status, label string) *StateMachine {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.statuses[status]; false == ok {
+		m.codes = append(m.codes, status)
+	}
+	m.statuses[status] = label
+	return m
+}
+
+//AddTransition is a function for adding a transition between known statuses to a state machine
+func (m *StateMachine) AddTransition(t Transition) (*StateMachine, *errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if "" == t.Event {
+		return nil, errors.Wrap(fmt.Errorf("Can't add transition from %v to %v without event", t.From, t.To), 0)
+	}
+	_, fromOk := m.statuses[t.From]
+	_, toOk := m.statuses[t.To]
+	if false == fromOk || false == toOk {
+		return nil, errors.Wrap(fmt.Errorf("Can't add transition %v from %v to %v: unknown status", t.Event, t.From, t.To), 0)
+	}
+	for _, existing := range m.transitions {
+		if t.From == existing.From && t.Event == existing.Event {
+			return nil, errors.Wrap(fmt.Errorf("Can't add transition %v from %v: already exists", t.Event, t.From), 0)
+		}
+	}
+	m.transitions = append(m.transitions, t)
+	return m, nil
+}
+
+//RemoveTransition is a function for removing the transition fired by an event from a status
+func (m *StateMachine) RemoveTransition(from, event string) *StateMachine {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, t := range m.transitions {
+		if from == t.From && event == t.Event {
+			m.transitions = append(m.transitions[:i:i], m.transitions[i+1:]...)
+			break
+		}
+	}
+	return m
+}
+
+//Graphviz renders a state machine as a Graphviz (DOT) directed graph
+func (m *StateMachine) Graphviz() string {
+	var b strings.Builder
+	b.WriteString("digraph order {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, style=rounded];\n")
+	for _, status := range m.Statuses() {
+		fmt.Fprintf(&b, "\t%q [label=%q];\n", status, m.Label(status))
+	}
+	fmt.Fprintf(&b, "\t%q [shape=point];\n", "")
+	fmt.Fprintf(&b, "\t%q -> %q;\n", "", m.initial)
+	for _, t := range m.Transitions() {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", t.From, t.To, t.Event)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+//Mermaid renders a state machine as a Mermaid state diagram
+func (m *StateMachine) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	for _, status := range m.Statuses() {
+		fmt.Fprintf(&b, "\tstate %q as %v\n", m.Label(status), mermaidID(status))
+	}
+	fmt.Fprintf(&b, "\t[*] --> %v\n", mermaidID(m.initial))
+	for _, t := range m.Transitions() {
+		fmt.Fprintf(&b, "\t%v --> %v : %v\n", mermaidID(t.From), mermaidID(t.To), t.Event)
+	}
+	return b.String()
+}
+
+//mermaidID returns the Mermaid state id of a status (status codes may not be valid Mermaid ids)
+func mermaidID(status string) string {
+	return "s_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, status)
+}
+
+//transition is a function for finding the transition an order takes on an event and checking its guards
+//Returns the transition or an error wrapping ErrInvalidStatus when there is none from the order's status
+func (o *Order) transition(event string) (Transition, *errors.Error) {
+	t, ok := o.machine.Find(o.status, event)
+	if false == ok {
+		return t, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't %v: order %v status is %v (allowed events: %v)",
+			event, o.id, o.machine.Label(o.status), strings.Join(o.machine.Events(o.status), ", ")), 0)
+	}
+	for _, guard := range t.Guards {
+		if err := guard(o); err != nil {
+			return t, errors.WrapPrefix(err, fmt.Sprintf("Can't %v", event), 0)
+		}
+	}
+	return t, nil
+}
+
+//take is a function for moving an order to a transition's status and applying its side effects
+func (o *Order) take(t Transition) {
+	o.status = t.To
+	for _, effect := range t.Effects {
+		effect(o)
+	}
+}
+
+//Fire is a function for firing an event on an order, taking the transition of the event from the order's status
+//Returns true if the transition is taken or false and an error describing the failure
+func (o *Order) Fire(event string) (bool, *errors.Error) {
+	if method, ok := methodEvents[event]; ok {
+		return false, errors.Wrap(fmt.Errorf("Can't fire %v on order %v, use %v", event, o.id, method), 0)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	t, err := o.transition(event)
+	if err != nil {
+		return false, err
+	}
+	o.take(t)
+	return true, nil
+}
+
+//AllowedEvents returns the events an order can currently fire (those whose transition's guards pass)
+func (o *Order) AllowedEvents() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.allowedEvents()
+}
+
+//allowedEvents returns the events an order can currently fire, the caller holding the order's lock
+func (o *Order) allowedEvents() []string {
+	events := make([]string, 0, 3)
+	for _, event := range o.machine.Events(o.status) {
+		if _, err := o.transition(event); nil == err {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+//hasItems is a guard for an order having items
+func hasItems(o *Order) *errors.Error {
+	if 0 == len(o.items) {
+		return errors.WrapPrefix(ErrNoItem, fmt.Sprintf("order %v has no item", o.id), 0)
+	}
+	return nil
+}
+
+//userCanOrder is a guard for an order's user (if any) being able to order
+func userCanOrder(o *Order) *errors.Error {
+	if o.user != nil {
+		if canOrder, err := o.user.CanOrder(); false == canOrder {
+			return errors.WrapPrefix(err, fmt.Sprintf("order %v user %v can't order", o.id, o.user.ID()), 0)
+		}
+	}
+	return nil
+}
+
+//notShipped is a guard for an order having no shipment yet (or only shipments returned to sender)
+func notShipped(o *Order) *errors.Error {
+	for _, s := range o.shipments {
+		if ShipmentStatusReturned != s.status {
+			return errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("order %v shipping status is %v", o.id, shipstatusMap[o.ShippingStatus()]), 0)
+		}
+	}
+	return nil
+}
+
+//notFullyShipped is a guard for an order having some ordered quantity not shipped yet (or nothing shipped yet)
+func notFullyShipped(o *Order) *errors.Error {
+	if 0 == len(o.Unshipped()) && nil != notShipped(o) {
+		return errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("order %v has nothing left to ship", o.id), 0)
+	}
+	return nil
+}
+
+//fullyShipped is a guard for every ordered quantity of an order being shipped
+func fullyShipped(o *Order) *errors.Error {
+	if 0 != len(o.Unshipped()) || 0 == len(o.shipments) {
+		return errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("order %v shipping status is %v", o.id, shipstatusMap[o.ShippingStatus()]), 0)
+	}
+	return nil
+}
+
+//setSubmittedDate is an effect setting an order's submitted date to now
+func setSubmittedDate(o *Order) {
+	o.submittedDate = time.Now()
+}
+
+//setProcessedDate is an effect setting an order's processed date to now
+func setProcessedDate(o *Order) {
+	o.processedDate = time.Now()
+}
+
+//deliverShipments is an effect marking every shipment of an order not delivered (nor returned) yet as delivered now
+func deliverShipments(o *Order) {
+	for _, s := range o.shipments {
+		if ShipmentStatusDelivered != s.status && ShipmentStatusReturned != s.status {
+			s.record(NewTrackingEvent(TrackingDelivered, time.Now(), "", ""))
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/order"
+	"sstest/model/product"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//StatusOnHold is the custom 'on hold' order status of the tests
+const StatusOnHold string = "H"
+
+//newOnHoldStateMachine creates the standard state machine extended with an 'on hold' status for submitted orders
+func newOnHoldStateMachine() *order.StateMachine {
+	m := order.NewDefaultStateMachine().AddStatus(StatusOnHold, "On Hold")
+	m.AddTransition(order.Transition{From: order.StatusSubmitted, Event: "hold", To: StatusOnHold})
+	m.AddTransition(order.Transition{From: StatusOnHold, Event: "resume", To: order.StatusSubmitted})
+	m.AddTransition(order.Transition{From: StatusOnHold, Event: order.EventCancel, To: order.StatusCanceled})
+	return m
+}
+
+func TestDefaultStateMachine(t *testing.T) {
+	orderedProd := product.New("orderedProd", "Ordered Product")
+	orderedProd.SetStatus(product.StatusAvailable)
+	orderedProd.SetStock(10)
+	orderedProd.SetPrice(decimal.New(100, 0))
+
+	emptyOrder := order.New("emptyOrder")
+	draftOrder := order.New("draftOrder")
+	draftOrder.AddProduct(orderedProd, 1)
+
+	processedOrder := order.New("processedOrder")
+	processedOrder.SetStatus(order.StatusProcessed)
+	processedCancelOk, _ := processedOrder.Cancel()
+
+	shippedOrder := order.New("shippedOrder")
+	shippedOrder.AddProduct(orderedProd, 2)
+	shippedOrder.SetStatus(order.StatusProcessed)
+	shippedOrder.Ship("carrier", "dummyTrackingNo", map[string]int{"orderedProd": 1})
+	shippedCancelOk, errShippedCancel := shippedOrder.Cancel()
+
+	submittedOrder := order.New("submittedOrder")
+	submittedOrder.SetStatus(order.StatusSubmitted)
+	_, errFireSubmit := draftOrder.Fire(order.EventSubmit)
+	_, errFireUnknown := submittedOrder.Fire("unknown")
+
+	var defaultStateMachineTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Default Order State Machine", order.DefaultStateMachine(), draftOrder.StateMachine()},
+		{"Initial Status", order.StatusDraft, order.DefaultStateMachine().Initial()},
+		{"Draft Events", "submit", strings.Join(order.DefaultStateMachine().Events(order.StatusDraft), ",")},
+		{"Processed Events", "cancel,ship,finish", strings.Join(order.DefaultStateMachine().Events(order.StatusProcessed), ",")},
+		{"Delivered Events", "", strings.Join(order.DefaultStateMachine().Events(order.StatusDelivered), ",")},
+		{"Empty Order Allowed Events", "", strings.Join(emptyOrder.AllowedEvents(), ",")},
+		{"Draft Order Allowed Events", "submit", strings.Join(draftOrder.AllowedEvents(), ",")},
+		{"Partially Shipped Order Allowed Events", "ship", strings.Join(shippedOrder.AllowedEvents(), ",")},
+		{"Processed Order Must Be Canceled", true, processedCancelOk},
+		{"Canceled Processed Order Status", order.StatusCanceled, processedOrder.Status()},
+		{"Shipped Order Must Not Be Canceled", false, shippedCancelOk},
+		{"Shipped Order Cancel Failure Reason", true, errors.Is(errShippedCancel, order.ErrInvalidStatus)},
+		{"Submit Must Not Be Fired", true, errFireSubmit != nil},
+		{"Fired Submit Order Must Stay Draft", order.StatusDraft, draftOrder.Status()},
+		{"Unknown Event Failure Reason", true, errors.Is(errFireUnknown, order.ErrInvalidStatus)},
+		{"Unknown Status Must Not Be Set", true, nil == func() *order.Order { o, _ := order.New("o").SetStatus(StatusOnHold); return o }()},
+	}
+
+	for _, test := range defaultStateMachineTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestConcurrentAllowedEvents(t *testing.T) {
+	orderedProd := product.New("orderedProd", "Ordered Product")
+	orderedProd.SetStatus(product.StatusAvailable)
+	orderedProd.SetStock(10)
+	orderedProd.SetPrice(decimal.New(100, 0))
+	submittedOrder := order.New("submittedOrder")
+	submittedOrder.AddProduct(orderedProd, 1)
+
+	//the allowed events are read while the order is submitted (run with -race to detect unlocked reads)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			submittedOrder.AllowedEvents()
+		}()
+	}
+	submitOk, _ := submittedOrder.Submit("ship name", "ship address", nil)
+	wg.Wait()
+
+	var concurrentAllowedEventsTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submit Must Succeed", true, submitOk},
+		{"Submitted Order Allowed Events", strings.Join(order.DefaultStateMachine().Events(order.StatusSubmitted), ","), strings.Join(submittedOrder.AllowedEvents(), ",")},
+	}
+
+	for _, test := range concurrentAllowedEventsTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCustomStateMachine(t *testing.T) {
+	m := newOnHoldStateMachine()
+	_, errUnknownStatus := m.AddTransition(order.Transition{From: StatusOnHold, Event: "archive", To: "X"})
+	_, errDuplicate := m.AddTransition(order.Transition{From: StatusOnHold, Event: "resume", To: order.StatusProcessed})
+	_, errNoEvent := m.AddTransition(order.Transition{From: StatusOnHold, To: order.StatusProcessed})
+
+	heldOrder := order.New("heldOrder").SetStateMachine(m)
+	heldOrder.SetStatus(order.StatusSubmitted)
+	holdOk, _ := heldOrder.Fire("hold")
+	heldStatus := heldOrder.Status()
+	heldEvents := strings.Join(heldOrder.AllowedEvents(), ",")
+	_, errProcessHeld := heldOrder.Process()
+	resumeOk, _ := heldOrder.Fire("resume")
+	resumedStatus := heldOrder.Status()
+
+	effectCount := 0
+	counted := order.NewStateMachine(order.StatusDraft, "Draft").AddStatus(order.StatusSubmitted, "Submitted")
+	counted.AddTransition(order.Transition{
+		From:    order.StatusDraft,
+		Event:   "review",
+		To:      order.StatusSubmitted,
+		Guards:  []order.Guard{func(o *order.Order) *errors.Error { return nil }},
+		Effects: []order.Effect{func(o *order.Order) { effectCount++ }, func(o *order.Order) { effectCount++ }},
+	})
+	reviewedOrder := order.New("reviewedOrder").SetStateMachine(counted)
+	reviewedOrder.Fire("review")
+
+	refused := order.NewStateMachine(order.StatusDraft, "Draft").AddStatus(order.StatusSubmitted, "Submitted")
+	refused.AddTransition(order.Transition{
+		From:   order.StatusDraft,
+		Event:  "review",
+		To:     order.StatusSubmitted,
+		Guards: []order.Guard{func(o *order.Order) *errors.Error { return errors.Wrap(fmt.Errorf("not reviewable"), 0) }},
+	})
+	refusedOrder := order.New("refusedOrder").SetStateMachine(refused)
+	refusedOk, _ := refusedOrder.Fire("review")
+
+	removed := order.NewDefaultStateMachine().RemoveTransition(order.StatusProcessed, order.EventCancel)
+
+	//the items of an order can only be edited in its state machine's initial status
+	quoted := order.NewStateMachine("Q", "Quote").AddStatus(order.StatusDraft, "Draft")
+	quoted.AddTransition(order.Transition{From: "Q", Event: "accept", To: order.StatusDraft})
+	quotedProd := product.New("quotedProd", "Quoted Product")
+	quotedProd.SetStatus(product.StatusAvailable)
+	quotedProd.SetStock(10)
+	quotedProd.SetPrice(decimal.New(100, 0))
+	quotedOrder, _ := order.New("quotedOrder").SetStateMachine(quoted).SetStatus("Q")
+	quotedAddOk, _ := quotedOrder.AddProduct(quotedProd, 1)
+	quotedOrder.Fire("accept")
+	_, errAcceptedAdd := quotedOrder.AddProduct(quotedProd, 1)
+
+	var customStateMachineTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Unknown Status Transition Must Not Be Added", true, errUnknownStatus != nil},
+		{"Duplicate Transition Must Not Be Added", true, errDuplicate != nil},
+		{"Transition Without Event Must Not Be Added", true, errNoEvent != nil},
+		{"Custom Status Label", "On Hold", m.Label(StatusOnHold)},
+		{"Custom Status Count", 6, len(m.Statuses())},
+		{"Default State Machine Must Not Be Extended", false, order.DefaultStateMachine().HasStatus(StatusOnHold)},
+		{"Hold Must Be Fired", true, holdOk},
+		{"Held Order Status", StatusOnHold, heldStatus},
+		{"Held Order Allowed Events", "resume,cancel", heldEvents},
+		{"Held Order Process Failure Reason", true, errors.Is(errProcessHeld, order.ErrInvalidStatus)},
+		{"Resume Must Be Fired", true, resumeOk},
+		{"Resumed Order Status", order.StatusSubmitted, resumedStatus},
+		{"Custom Effects Must Be Applied", 2, effectCount},
+		{"Custom Transition Status", order.StatusSubmitted, reviewedOrder.Status()},
+		{"Refused Guard Must Not Fire", false, refusedOk},
+		{"Refused Order Must Stay Draft", order.StatusDraft, refusedOrder.Status()},
+		{"Removed Transition Events", "ship,finish", strings.Join(removed.Events(order.StatusProcessed), ",")},
+		{"Initial Status Must Be Editable", true, quotedOrder.StateMachine().Editable("Q")},
+		{"Other Status Must Not Be Editable", false, quotedOrder.StateMachine().Editable(order.StatusDraft)},
+		{"Add Product In Initial Status", true, quotedAddOk},
+		{"Add Product After Initial Status Failure Reason", true, errors.Is(errAcceptedAdd, order.ErrInvalidStatus)},
+	}
+
+	for _, test := range customStateMachineTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestConcurrentDefaultStateMachine(t *testing.T) {
+	t.Cleanup(func() { order.SetDefaultStateMachine(nil) })
+	quoted := order.NewStateMachine("Q", "Quote")
+
+	//orders are created while the default state machine is replaced, every order must start in its own machine's initial status
+	orders := make([]*order.Order, 50)
+	var wg sync.WaitGroup
+	for i := range orders {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if 0 == i%2 {
+				order.SetDefaultStateMachine(quoted)
+			} else {
+				order.SetDefaultStateMachine(nil)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			orders[i] = order.New(fmt.Sprintf("concurrentOrder%d", i))
+		}(i)
+	}
+	wg.Wait()
+	initial := 0
+	for _, o := range orders {
+		if o.StateMachine().Initial() == o.Status() {
+			initial++
+		}
+	}
+
+	t.Run("Every Order Must Start In Its Initial Status", func(t *testing.T) {
+		if len(orders) != initial {
+			t.Errorf("want %v, got %v", len(orders), initial)
+		}
+	})
+}
+
+func TestRenderStateMachine(t *testing.T) {
+	m := newOnHoldStateMachine()
+	graphviz := m.Graphviz()
+	mermaid := m.Mermaid()
+
+	var renderTests = []struct {
+		testCase  string
+		rendering string
+		expected  string
+	}{
+		{"Graphviz Graph", graphviz, "digraph order {"},
+		{"Graphviz Status", graphviz, `"DL" [label="Delivered"];`},
+		{"Graphviz Initial Status", graphviz, `"" -> "D";`},
+		{"Graphviz Transition", graphviz, `"S" -> "P" [label="process"];`},
+		{"Graphviz Custom Transition", graphviz, `"H" -> "S" [label="resume"];`},
+		{"Mermaid Diagram", mermaid, "stateDiagram-v2"},
+		{"Mermaid Status", mermaid, `state "On Hold" as s_H`},
+		{"Mermaid Initial Status", mermaid, "[*] --> s_D"},
+		{"Mermaid Transition", mermaid, "s_P --> s_DL : finish"},
+	}
+
+	for _, test := range renderTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if false == strings.Contains(test.rendering, test.expected) {
+				t.Errorf("want %v in rendering, got %v", test.expected, test.rendering)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package order
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/product"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//Submitter is interface of the order store recording an order's submission along with its taken stocks and coupon redemptions
+type Submitter interface {
+	//Submit changes the stored status of an order from a status to another only if it's still stored with the former (or fails with ErrInvalidStatus),
+	//takes the stock of the given products (removing the order's holds) and records the redemptions, either all or none of them
+	Submit(orderID, from, to string, quantities map[*product.Product]int, allocate product.Allocator,
+		redemptions map[*coupon.Coupon]*coupon.Redemption) (Allocations, *errors.Error)
+}
+
+//submit is a function for taking the stock of an order and redeeming its coupons at a date
+func (o *Order) submit(t Transition, date time.Time) (Allocations, *errors.Error) {
+	redemptions := o.redemptions(date)
+	if o.submitter != nil {
+		allocations, err := o.submitter.Submit(o.id, o.status, t.To, o.quantities(), o.allocator, redemptions)
+		if err != nil {
+			return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v", o.id), 0)
+		}
+		o.storedStatus = t.To
+		return allocations, nil
+	}
+	allocations, err := o.decrementStocks(o.quantities())
+	if err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v can't decrement product stock", o.id), 0)
+	}
+	//the coupons' stocks and redemption limits are checked when recording the redemptions (a concurrent submission may have used them up)
+	if err := o.redeem(redemptions); err != nil {
+		if restoreErr := o.restoreStocks(allocations); restoreErr != nil {
+			return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v can't apply coupons %v nor return its product stock (%v)", o.id, couponIDs(o.Coupons()), restoreErr), 0)
+		}
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't submit: order %v can't apply coupons %v", o.id, couponIDs(o.Coupons())), 0)
+	}
+	return allocations, nil
+}
@@ -0,0 +1,279 @@
+package order
+
+import (
+	"sort"
+	"sstest/model/money"
+
+	"github.com/shopspring/decimal"
+)
+
+//TaxRule is business domain model definition of a tax rate of a region applying to the items of a product tax category
+type TaxRule struct {
+	name     string
+	category string          //product tax category the rule applies to, empty for every category
+	rate     decimal.Decimal //e.g. 0.2 for 20%
+	compound bool            //applied on the item amount added with the taxes of the preceding rules (tax on tax)
+}
+
+//NewTaxRule creates a new tax rule and returns a reference to it (an empty category applies to every product tax category)
+func NewTaxRule(name, category string, rate decimal.Decimal, compound bool) *TaxRule {
+	return &TaxRule{name, category, rate, compound}
+}
+
+//Name is a getter function for returning a tax rule's name (e.g. VAT)
+func (r *TaxRule) Name() string {
+	return r.name
+}
+
+//Category is a getter function for returning the product tax category a tax rule applies to (empty for every category)
+func (r *TaxRule) Category() string {
+	return r.category
+}
+
+//Rate is a getter function for returning a tax rule's rate
+func (r *TaxRule) Rate() decimal.Decimal {
+	return r.rate
+}
+
+//Compound is a getter function for returning whether a tax rule is applied on the taxes of the preceding rules too
+func (r *TaxRule) Compound() bool {
+	return r.compound
+}
+
+//appliesTo returns whether a tax rule applies to a product tax category
+func (r *TaxRule) appliesTo(category string) bool {
+	return "" == r.category || category == r.category
+}
+
+//TaxRegion is business domain model definition of the tax rules of a shipping region
+type TaxRegion struct {
+	code      string
+	inclusive bool //whether the product prices of the orders shipped to the region include its taxes
+	rules     []*TaxRule
+	exempt    map[string]bool
+}
+
+//NewTaxRegion creates a new tax region with its ordered tax rules and returns a reference to it
+func NewTaxRegion(code string, inclusive bool, rules ...*TaxRule) *TaxRegion {
+	return &TaxRegion{code, inclusive, rules, make(map[string]bool)}
+}
+
+//Code is a getter function for returning a tax region's code (matched with an order's shipping region)
+func (r *TaxRegion) Code() string {
+	return r.code
+}
+
+//Inclusive is a getter function for returning whether the product prices include the taxes of a region
+func (r *TaxRegion) Inclusive() bool {
+	return r.inclusive
+}
+
+//Rules is a getter function for returning a tax region's ordered tax rules
+func (r *TaxRegion) Rules() []*TaxRule {
+	return r.rules
+}
+
+//Exemptions is a getter function for returning the product tax categories exempted in a tax region, sorted
+func (r *TaxRegion) Exemptions() []string {
+	categories := make([]string, 0, len(r.exempt))
+	for category := range r.exempt {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+//Exempt is a function for exempting product tax categories from every tax rule of a tax region
+func (r *TaxRegion) Exempt(categories ...string) *TaxRegion {
+	for _, category := range categories {
+		r.exempt[category] = true
+	}
+	return r
+}
+
+//IsExempt returns whether a product tax category is exempted in a tax region
+func (r *TaxRegion) IsExempt(category string) bool {
+	return r.exempt[category]
+}
+
+//Taxes is a function for calculating the taxes of an item amount in a currency of a product tax category
+//Returns the taxes of the applying rules in their order
+func (r *TaxRegion) Taxes(category, currency string, amount decimal.Decimal) []*Tax {
+	if r.IsExempt(category) {
+		return nil
+	}
+	rules := make([]*TaxRule, 0, len(r.rules))
+	factor := decimal.New(1, 0)
+	for _, rule := range r.rules {
+		if false == rule.appliesTo(category) {
+			continue
+		}
+		rules = append(rules, rule)
+		if rule.compound {
+			factor = factor.Mul(decimal.New(1, 0).Add(rule.rate))
+		} else {
+			factor = factor.Add(rule.rate)
+		}
+	}
+	if 0 == len(rules) {
+		return nil
+	}
+
+	places := money.MinorUnits(currency)
+	net := amount
+	if r.inclusive {
+		net = amount.Div(factor).Round(places)
+	}
+	taxes := make([]*Tax, 0, len(rules))
+	total := decimal.New(0, 0)
+	for _, rule := range rules {
+		base := net
+		if rule.compound {
+			base = base.Add(total)
+		}
+		tax := base.Mul(rule.rate).Round(places)
+		taxes = append(taxes, NewTax(rule.name, rule.rate, tax))
+		total = total.Add(tax)
+	}
+	if r.inclusive {
+		last := taxes[len(taxes)-1]
+		last.amount = last.amount.Add(amount.Sub(net).Sub(total))
+	}
+	return taxes
+}
+
+//TaxTable is business domain model definition of the tax regions an order can be shipped to, keyed by their code
+type TaxTable struct {
+	regions map[string]*TaxRegion
+}
+
+//NewTaxTable creates a new tax table of tax regions and returns a reference to it
+func NewTaxTable(regions ...*TaxRegion) *TaxTable {
+	t := &TaxTable{make(map[string]*TaxRegion, len(regions))}
+	for _, r := range regions {
+		t.regions[r.code] = r
+	}
+	return t
+}
+
+//Region returns the tax region of a tax table having a code, or false when there is none
+func (t *TaxTable) Region(code string) (*TaxRegion, bool) {
+	r, ok := t.regions[code]
+	return r, ok
+}
+
+//Regions is a getter function for returning the tax regions of a tax table ordered by their code
+func (t *TaxTable) Regions() []*TaxRegion {
+	regions := make([]*TaxRegion, 0, len(t.regions))
+	for _, r := range t.regions {
+		regions = append(regions, r)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].code < regions[j].code
+	})
+	return regions
+}
+
+//Tax is business domain model definition of the amount of a tax (of a tax rule) on an order item, or on a whole order
+type Tax struct {
+	name   string
+	rate   decimal.Decimal
+	amount decimal.Decimal
+}
+
+//NewTax creates a new tax model struct, initializes it's properties and returns a reference to it
+func NewTax(name string, rate, amount decimal.Decimal) *Tax {
+	return &Tax{name, rate, amount}
+}
+
+//Name is a getter function for returning a tax's name
+func (t *Tax) Name() string {
+	return t.name
+}
+
+//Rate is a getter function for returning a tax's rate
+func (t *Tax) Rate() decimal.Decimal {
+	return t.rate
+}
+
+//Amount is a getter function for returning a tax's amount
+func (t *Tax) Amount() decimal.Decimal {
+	return t.amount
+}
+
+//sumTaxes returns the total amount of taxes
+func sumTaxes(taxes []*Tax) decimal.Decimal {
+	total := decimal.New(0, 0)
+	for _, t := range taxes {
+		total = total.Add(t.amount)
+	}
+	return total
+}
+
+//defaultTaxTable is the tax table of newly created orders
+var defaultTaxTable = NewTaxTable()
+
+//DefaultTaxTable returns the tax table of newly created orders
+func DefaultTaxTable() *TaxTable {
+	return defaultTaxTable
+}
+
+//SetDefaultTaxTable sets the tax table of newly created orders, an empty one when nil
+func SetDefaultTaxTable(t *TaxTable) {
+	if nil == t {
+		t = NewTaxTable()
+	}
+	defaultTaxTable = t
+}
+
+//Taxes is a getter function for returning an order item's taxes in their rules' order (calculated on submission)
+func (i *Item) Taxes() []*Tax {
+	return i.taxes
+}
+
+//Tax returns the total amount of an order item's taxes
+func (i *Item) Tax() decimal.Decimal {
+	return sumTaxes(i.taxes)
+}
+
+//SetTaxes is a setter function for setting an order item's taxes
+func (i *Item) SetTaxes(taxes []*Tax) *Item {
+	i.taxes = taxes
+	return i
+}
+
+//Taxes returns an order's tax breakdown: its items' taxes summed by tax name and rate, ordered by name and rate
+func (o *Order) Taxes() []*Tax {
+	taxes := make([]*Tax, 0)
+	for _, val := range o.items {
+		for _, itemTax := range val.taxes {
+			found := false
+			for _, t := range taxes {
+				if t.name == itemTax.name && t.rate.Equal(itemTax.rate) {
+					t.amount = t.amount.Add(itemTax.amount)
+					found = true
+					break
+				}
+			}
+			if false == found {
+				taxes = append(taxes, NewTax(itemTax.name, itemTax.rate, itemTax.amount))
+			}
+		}
+	}
+	sort.Slice(taxes, func(i, j int) bool {
+		if taxes[i].name != taxes[j].name {
+			return taxes[i].name < taxes[j].name
+		}
+		return taxes[i].rate.LessThan(taxes[j].rate)
+	})
+	return taxes
+}
+
+//Tax returns the total amount of an order's taxes (included in its amount, or added to it, see TaxIncluded)
+func (o *Order) Tax() decimal.Decimal {
+	total := decimal.New(0, 0)
+	for _, val := range o.items {
+		total = total.Add(val.Tax())
+	}
+	return total
+}
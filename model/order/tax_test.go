@@ -0,0 +1,143 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+//newTaxedProduct creates an available product having a price and a tax category
+func newTaxedProduct(id string, price int64, category string) *product.Product {
+	p := product.New(id, id)
+	p.SetStatus(product.StatusAvailable)
+	p.SetStock(100)
+	p.SetPrice(decimal.New(price, 0))
+	p.SetTaxCategory(category)
+	return p
+}
+
+//formatTaxes formats taxes as name:rate=amount pairs
+func formatTaxes(taxes []*order.Tax) string {
+	formatted := make([]string, 0, len(taxes))
+	for _, t := range taxes {
+		formatted = append(formatted, fmt.Sprintf("%v:%v=%v", t.Name(), t.Rate(), t.Amount()))
+	}
+	return strings.Join(formatted, ",")
+}
+
+func TestTaxRegion(t *testing.T) {
+	exclusive := order.NewTaxRegion("CA-QC", false,
+		order.NewTaxRule("GST", "", decimal.New(5, -2), false),
+		order.NewTaxRule("QST", "", decimal.New(9975, -5), false)).Exempt("food")
+	inclusive := order.NewTaxRegion("QC-INC", true,
+		order.NewTaxRule("GST", "", decimal.New(5, -2), false),
+		order.NewTaxRule("QST", "", decimal.New(9975, -5), false))
+	compound := order.NewTaxRegion("CMP", false,
+		order.NewTaxRule("base", "", decimal.New(1, -1), false),
+		order.NewTaxRule("surtax", "", decimal.New(1, -1), true))
+	categorized := order.NewTaxRegion("FR", true,
+		order.NewTaxRule("VAT", product.TaxCategoryStandard, decimal.New(2, -1), false),
+		order.NewTaxRule("VAT", "reduced", decimal.New(55, -3), false))
+	table := order.NewTaxTable(exclusive, inclusive, compound, categorized)
+	_, foundMissing := table.Region("XX")
+	found, foundExclusive := table.Region("CA-QC")
+
+	var taxRegionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Exclusive Taxes Must Be Stacked And Rounded", "GST:0.05=5,QST:0.09975=9.98", formatTaxes(exclusive.Taxes(product.TaxCategoryStandard, "USD", decimal.New(100, 0)))},
+		{"Exempted Category Must Not Be Taxed", "", formatTaxes(exclusive.Taxes("food", "USD", decimal.New(100, 0)))},
+		{"Inclusive Taxes Must Add Up To Amount", "GST:0.05=0.44,QST:0.09975=0.86", formatTaxes(inclusive.Taxes(product.TaxCategoryStandard, "USD", decimal.New(10, 0)))},
+		{"Exclusive Taxes Must Be Rounded To Currency Minor Unit", "GST:0.05=62,QST:0.09975=123", formatTaxes(exclusive.Taxes(product.TaxCategoryStandard, "JPY", decimal.New(1234, 0)))},
+		{"Inclusive Taxes In Currency Without Minor Unit Must Add Up To Amount", "GST:0.05=44,QST:0.09975=86", formatTaxes(inclusive.Taxes(product.TaxCategoryStandard, "JPY", decimal.New(1000, 0)))},
+		{"Compound Tax Must Tax Preceding Taxes", "base:0.1=10,surtax:0.1=11", formatTaxes(compound.Taxes(product.TaxCategoryStandard, "USD", decimal.New(100, 0)))},
+		{"Standard Category Rule", "VAT:0.2=20", formatTaxes(categorized.Taxes(product.TaxCategoryStandard, "USD", decimal.New(120, 0)))},
+		{"Reduced Category Rule", "VAT:0.055=0.55", formatTaxes(categorized.Taxes("reduced", "USD", decimal.New(1055, -2)))},
+		{"Category Without Rule Must Not Be Taxed", "", formatTaxes(categorized.Taxes("food", "USD", decimal.New(100, 0)))},
+		{"Region Exemptions", "food", strings.Join(exclusive.Exemptions(), ",")},
+		{"Missing Region", false, foundMissing},
+		{"Found Region", true, foundExclusive && exclusive == found},
+		{"Regions Ordered By Code", 4, len(table.Regions())},
+		{"First Region Code", "CA-QC", table.Regions()[0].Code()},
+	}
+
+	for _, test := range taxRegionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderTaxes(t *testing.T) {
+	order.SetDefaultTaxTable(order.NewTaxTable(
+		order.NewTaxRegion("CA-QC", false,
+			order.NewTaxRule("GST", "", decimal.New(5, -2), false),
+			order.NewTaxRule("QST", "", decimal.New(9975, -5), false)).Exempt("food"),
+		order.NewTaxRegion("FR", true,
+			order.NewTaxRule("VAT", product.TaxCategoryStandard, decimal.New(2, -1), false),
+			order.NewTaxRule("VAT", "reduced", decimal.New(55, -3), false))))
+	t.Cleanup(func() { order.SetDefaultTaxTable(nil) })
+	bookProd := newTaxedProduct("bookProd", 10, "reduced")
+	foodProd := newTaxedProduct("foodProd", 20, "food")
+	gadgetProd := newTaxedProduct("gadgetProd", 100, product.TaxCategoryStandard)
+	valueCoupon := coupon.New("SAVE15")
+	valueCoupon.SetStatus(coupon.StatusActive)
+	valueCoupon.SetStock(10)
+	valueCoupon.SetKind(coupon.KindValue)
+	valueCoupon.SetValue(decimal.New(15, 0))
+	newOrder := func(id, region string) *order.Order {
+		o := order.New(id).SetShippingRegion(region)
+		o.AddProduct(bookProd, 3)
+		o.AddProduct(foodProd, 1)
+		o.AddProduct(gadgetProd, 1)
+		return o
+	}
+
+	exclusiveOrder := newOrder("exclusiveOrder", "CA-QC")
+	quoted, _ := exclusiveOrder.Quote("", valueCoupon)
+	quotedTax := exclusiveOrder.Tax().String()
+	exclusiveOrder.Submit("ship name", "ship address", valueCoupon)
+	inclusiveOrder := newOrder("inclusiveOrder", "FR")
+	inclusiveOrder.Submit("ship name", "ship address", nil)
+	untaxedOrder := newOrder("untaxedOrder", "XX")
+	untaxedOrder.Submit("ship name", "ship address", nil)
+
+	var orderTaxTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Quote Must Not Tax Order", "0", quotedTax},
+		{"Exclusive Amount Must Add Taxes", "152.52", exclusiveOrder.Amount().String()},
+		{"Exclusive Amount Must Be Quoted Amount", quoted.Total().String(), exclusiveOrder.Amount().String()},
+		{"Exclusive Tax", "17.52", exclusiveOrder.Tax().String()},
+		{"Exclusive Taxes Are Not Included", false, exclusiveOrder.TaxIncluded()},
+		{"Exclusive Order Taxes", "GST:0.05=5.85,QST:0.09975=11.67", formatTaxes(exclusiveOrder.Taxes())},
+		{"Discounted Item Taxes", "GST:0.05=1.35,QST:0.09975=2.69", formatTaxes(exclusiveOrder.Items()["bookProd"].Taxes())},
+		{"Last Item Takes Discount Remainder", "GST:0.05=4.5,QST:0.09975=8.98", formatTaxes(exclusiveOrder.Items()["gadgetProd"].Taxes())},
+		{"Exempted Item Taxes", "", formatTaxes(exclusiveOrder.Items()["foodProd"].Taxes())},
+		{"Inclusive Amount Must Include Taxes", "150", inclusiveOrder.Amount().String()},
+		{"Inclusive Tax", "18.23", inclusiveOrder.Tax().String()},
+		{"Inclusive Taxes Are Included", true, inclusiveOrder.TaxIncluded()},
+		{"Inclusive Order Taxes By Name And Rate", "VAT:0.055=1.56,VAT:0.2=16.67", formatTaxes(inclusiveOrder.Taxes())},
+		{"Untaxed Region Amount", "150", untaxedOrder.Amount().String()},
+		{"Untaxed Region Taxes", "", formatTaxes(untaxedOrder.Taxes())},
+	}
+
+	for _, test := range orderTaxTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
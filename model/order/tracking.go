@@ -0,0 +1,164 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//TrackingPickedUp is const for 'picked up' tracking event type (the carrier picked the shipment up)
+const TrackingPickedUp string = "PU"
+
+//TrackingInTransit is const for 'in transit' tracking event type
+const TrackingInTransit string = "IT"
+
+//TrackingOutForDelivery is const for 'out for delivery' tracking event type
+const TrackingOutForDelivery string = "OD"
+
+//TrackingDelivered is const for 'delivered' tracking event type
+const TrackingDelivered string = "DL"
+
+//TrackingFailedAttempt is const for 'failed attempt' tracking event type (a delivery attempt failed, the carrier tries again)
+const TrackingFailedAttempt string = "FA"
+
+//TrackingReturned is const for 'returned to sender' tracking event type
+const TrackingReturned string = "RS"
+
+//trackingEventMap is a map of known tracking event type code and its label pairs
+var trackingEventMap = map[string]string{
+	TrackingPickedUp:       "Picked Up",
+	TrackingInTransit:      "In Transit",
+	TrackingOutForDelivery: "Out For Delivery",
+	TrackingDelivered:      "Delivered",
+	TrackingFailedAttempt:  "Failed Attempt",
+	TrackingReturned:       "Returned To Sender",
+}
+
+//trackingShipmentStatus is a map of tracking event type code and the shipment status it drives pairs
+var trackingShipmentStatus = map[string]string{
+	TrackingPickedUp:       ShipmentStatusInTransit,
+	TrackingInTransit:      ShipmentStatusInTransit,
+	TrackingOutForDelivery: ShipmentStatusInTransit,
+	TrackingDelivered:      ShipmentStatusDelivered,
+	TrackingFailedAttempt:  ShipmentStatusInTransit,
+	TrackingReturned:       ShipmentStatusReturned,
+}
+
+//TrackingEvent is business domain model definition of a timestamped carrier tracking event of a shipment
+type TrackingEvent struct {
+	kind        string
+	date        time.Time
+	location    string
+	description string
+}
+
+//NewTrackingEvent creates a new tracking event model struct, initializes it's properties and returns a reference to it
+func NewTrackingEvent(kind string, date time.Time, location, description string) *TrackingEvent {
+	return &TrackingEvent{
+		kind,
+		date,
+		location,
+		description,
+	}
+}
+
+//Kind is a getter function for returning a tracking event's type
+func (e *TrackingEvent) Kind() string {
+	return e.kind
+}
+
+//Date is a getter function for returning a tracking event's date
+func (e *TrackingEvent) Date() time.Time {
+	return e.date
+}
+
+//Location is a getter function for returning a tracking event's location
+func (e *TrackingEvent) Location() string {
+	return e.location
+}
+
+//Description is a getter function for returning a tracking event's description
+func (e *TrackingEvent) Description() string {
+	return e.description
+}
+
+//Events is a getter function for returning a shipment's tracking events ordered by their date
+func (s *Shipment) Events() []*TrackingEvent {
+	return s.events
+}
+
+//SetEvents is a setter function for setting a shipment's tracking events (ordered by their date, regardless of its status)
+func (s *Shipment) SetEvents(events []*TrackingEvent) *Shipment {
+	s.events = events
+	return s
+}
+
+//LatestEvent returns the latest tracking event of a shipment, or false when it has none
+func (s *Shipment) LatestEvent() (*TrackingEvent, bool) {
+	if 0 == len(s.events) {
+		return nil, false
+	}
+	return s.events[len(s.events)-1], true
+}
+
+//hasEvent returns whether a shipment already has a tracking event identical to the given one
+func (s *Shipment) hasEvent(e *TrackingEvent) bool {
+	for _, existing := range s.events {
+		if e.kind == existing.kind && e.date.Equal(existing.date) && e.location == existing.location && e.description == existing.description {
+			return true
+		}
+	}
+	return false
+}
+
+//record is a function for inserting a tracking event in a shipment's events by its date
+func (s *Shipment) record(e *TrackingEvent) {
+	i := len(s.events)
+	for i > 0 && s.events[i-1].date.After(e.date) {
+		i--
+	}
+	s.events = append(s.events[:i], append([]*TrackingEvent{e}, s.events[i:]...)...)
+	if i == len(s.events)-1 {
+		s.status = trackingShipmentStatus[e.kind]
+		if ShipmentStatusDelivered == s.status {
+			s.deliveredDate = e.date
+		}
+	}
+}
+
+//TrackedShipment returns the shipment of an order having the given carrier and tracking id, or false when there is none
+func (o *Order) TrackedShipment(carrier, trackingID string) (*Shipment, bool) {
+	for _, s := range o.shipments {
+		if carrier == s.carrier && trackingID == s.trackingID {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+//TrackShipment is a function for recording a carrier tracking event of a shipment of an order
+//Returns true if the event is recorded or false and an error describing the failure
+func (o *Order) TrackShipment(id string, e *TrackingEvent) (bool, *errors.Error) {
+	if _, ok := trackingEventMap[e.kind]; false == ok {
+		return false, errors.Wrap(fmt.Errorf("Can't track unknown tracking event type: %v", e.kind), 0)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	s, ok := o.Shipment(id)
+	if false == ok {
+		return false, errors.WrapPrefix(ErrShipmentNotFound, fmt.Sprintf("Can't track, order %v has no shipment with id: %v", o.id, id), 0)
+	}
+	if s.hasEvent(e) {
+		return true, nil
+	}
+	latest, hasLatest := s.LatestEvent()
+	if (ShipmentStatusDelivered == s.status || ShipmentStatusReturned == s.status) && (false == hasLatest || false == e.date.Before(latest.date)) {
+		return false, errors.WrapPrefix(ErrInvalidStatus, fmt.Sprintf("Can't track %v, order %v shipment %v is already %v",
+			trackingEventMap[e.kind], o.id, id, shipmentStatusMap[s.status]), 0)
+	}
+	s.record(e)
+	o.finishDelivered()
+	return true, nil
+}
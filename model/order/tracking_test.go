@@ -0,0 +1,111 @@
+package order_test
+
+import (
+	"fmt"
+	"sstest/model/order"
+	"sstest/model/product"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestTrackShipment(t *testing.T) {
+	firstProd := product.New("firstProd", "First Product")
+	firstProd.SetStatus(product.StatusAvailable)
+	firstProd.SetStock(10)
+	firstProd.SetPrice(decimal.New(100, 0))
+	secondProd := product.New("secondProd", "Second Product")
+	secondProd.SetStatus(product.StatusAvailable)
+	secondProd.SetStock(10)
+	secondProd.SetPrice(decimal.New(50, 0))
+	base := time.Now()
+
+	trackedOrder := order.New("trackedOrder")
+	trackedOrder.AddProduct(firstProd, 2)
+	trackedOrder.AddProduct(secondProd, 1)
+	trackedOrder.SetStatus(order.StatusProcessed)
+	firstShipment, _ := trackedOrder.Ship("firstCarrier", "firstTrackingNo", map[string]int{"firstProd": 2})
+	secondShipment, _ := trackedOrder.Ship("secondCarrier", "secondTrackingNo", nil)
+	shippedStatus := trackedOrder.ShippingStatus()
+
+	pickedUpOk, _ := trackedOrder.TrackShipment(firstShipment.ID(), order.NewTrackingEvent(order.TrackingPickedUp, base, "Warehouse", ""))
+	pickedUpStatus, pickedUpShipmentStatus := trackedOrder.ShippingStatus(), firstShipment.Status()
+	trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent(order.TrackingOutForDelivery, base.Add(time.Hour), "Hub", ""))
+	outForDeliveryStatus := trackedOrder.ShippingStatus()
+	trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent(order.TrackingFailedAttempt, base.Add(2*time.Hour), "Home", "nobody home"))
+	failedStatus := trackedOrder.ShippingStatus()
+	duplicateOk, _ := trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent(order.TrackingFailedAttempt, base.Add(2*time.Hour), "Home", "nobody home"))
+	lateOk, _ := trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent(order.TrackingInTransit, base.Add(30*time.Minute), "Hub", ""))
+	lateStatus := trackedOrder.ShippingStatus()
+	secondEvents := make([]string, 0)
+	for _, e := range secondShipment.Events() {
+		secondEvents = append(secondEvents, e.Kind())
+	}
+	_, errUnknownEvent := trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent("XX", base, "", ""))
+	_, errMissingShipment := trackedOrder.TrackShipment("missingShipment", order.NewTrackingEvent(order.TrackingInTransit, base, "", ""))
+	returnedOk, _ := trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent(order.TrackingReturned, base.Add(3*time.Hour), "Warehouse", ""))
+	returnedStatus, returnedShipmentStatus := trackedOrder.ShippingStatus(), secondShipment.Status()
+	returnedUnshipped := trackedOrder.Unshipped()["secondProd"]
+	_, errAfterReturn := trackedOrder.TrackShipment(secondShipment.ID(), order.NewTrackingEvent(order.TrackingInTransit, base.Add(4*time.Hour), "", ""))
+	thirdShipment, errReship := trackedOrder.Ship("thirdCarrier", "thirdTrackingNo", nil)
+	trackedOrder.TrackShipment(firstShipment.ID(), order.NewTrackingEvent(order.TrackingDelivered, base.Add(5*time.Hour), "Home", ""))
+	firstDeliveredStatus, firstDeliveredDate := trackedOrder.ShippingStatus(), firstShipment.DeliveredDate()
+	firstDeliveredOrderStatus := trackedOrder.Status()
+	trackedOrder.DeliverShipment(thirdShipment.ID())
+	thirdLatest, _ := thirdShipment.LatestEvent()
+	foundShipment, found := trackedOrder.TrackedShipment("thirdCarrier", "thirdTrackingNo")
+	_, foundOtherCarrier := trackedOrder.TrackedShipment("firstCarrier", "thirdTrackingNo")
+
+	returnedOrder := order.New("returnedOrder")
+	returnedOrder.AddProduct(firstProd, 1)
+	returnedOrder.SetStatus(order.StatusProcessed)
+	returnedShipment, _ := returnedOrder.Ship("carrier", "returnedTrackingNo", nil)
+	returnedOrder.TrackShipment(returnedShipment.ID(), order.NewTrackingEvent(order.TrackingReturned, base, "", ""))
+	fullyReturnedStatus := returnedOrder.ShippingStatus()
+	returnedCancelOk, _ := returnedOrder.Cancel()
+
+	var trackShipmentTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Shipped Without Tracking Event Status", order.ShipStatusOnProcess, shippedStatus},
+		{"Picked Up Must Be Recorded", true, pickedUpOk},
+		{"Picked Up Shipping Status", order.ShipStatusInTransit, pickedUpStatus},
+		{"Picked Up Shipment Status", order.ShipmentStatusInTransit, pickedUpShipmentStatus},
+		{"Out For Delivery Shipping Status", order.ShipStatusOutForDelivery, outForDeliveryStatus},
+		{"Failed Attempt Shipping Status", order.ShipStatusDeliveryFailed, failedStatus},
+		{"Duplicate Event Must Succeed", true, duplicateOk},
+		{"Earlier Event Must Be Recorded", true, lateOk},
+		{"Earlier Event Must Not Drive Status", order.ShipStatusDeliveryFailed, lateStatus},
+		{"Events Must Be Ordered By Date Once", "IT,OD,FA", strings.Join(secondEvents, ",")},
+		{"Unknown Event Must Fail", true, nil != errUnknownEvent},
+		{"Missing Shipment Failure Reason", true, nil != errMissingShipment && errors.Is(errMissingShipment, order.ErrShipmentNotFound)},
+		{"Returned Must Be Recorded", true, returnedOk},
+		{"Returned Shipment Status", order.ShipmentStatusReturned, returnedShipmentStatus},
+		{"Returned Quantity Shipping Status", order.ShipStatusPartiallyShipped, returnedStatus},
+		{"Returned Quantity Must Be Unshipped", 1, returnedUnshipped},
+		{"Event After Return Failure Reason", true, nil != errAfterReturn && errors.Is(errAfterReturn, order.ErrInvalidStatus)},
+		{"Returned Quantity Must Be Reshipped", true, nil == errReship},
+		{"Delivered Event Must Set Delivered Date", true, base.Add(5 * time.Hour).Equal(firstDeliveredDate)},
+		{"Undelivered Reshipment Shipping Status", order.ShipStatusOnProcess, firstDeliveredStatus},
+		{"Undelivered Reshipment Order Status", order.StatusProcessed, firstDeliveredOrderStatus},
+		{"Deliver Must Record Delivered Event", order.TrackingDelivered, thirdLatest.Kind()},
+		{"Last Delivered Shipment Must Finish Order", order.StatusDelivered, trackedOrder.Status()},
+		{"Tracked Shipment Must Be Found", true, found && thirdShipment == foundShipment},
+		{"Tracked Shipment Of Other Carrier", false, foundOtherCarrier},
+		{"Fully Returned Shipping Status", order.ShipStatusReturned, fullyReturnedStatus},
+		{"Fully Returned Order Must Be Canceled", true, returnedCancelOk},
+	}
+
+	for _, test := range trackShipmentTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package product
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-errors/errors"
+)
+
+//Allocation is the quantity of a product taken from every warehouse fulfilling an ordered quantity, keyed by warehouse id
+type Allocation map[string]int
+
+//Quantity returns the total quantity of an allocation
+func (a Allocation) Quantity() int {
+	quantity := 0
+	for _, allocated := range a {
+		quantity += allocated
+	}
+	return quantity
+}
+
+//Warehouses returns the ids of the warehouses of an allocation, sorted
+func (a Allocation) Warehouses() []string {
+	warehouses := make([]string, 0, len(a))
+	for warehouse := range a {
+		warehouses = append(warehouses, warehouse)
+	}
+	sort.Strings(warehouses)
+	return warehouses
+}
+
+//Validate is a function for checking that an allocation has at least one warehouse and only positive quantities
+//Returns true if the allocation is valid or false and an error wrapping ErrInvalidQuantity
+func (a Allocation) Validate() (bool, *errors.Error) {
+	if 0 == len(a) {
+		return false, errors.WrapPrefix(ErrInvalidQuantity, "Empty allocation", 0)
+	}
+	for warehouse, allocated := range a {
+		if allocated <= 0 {
+			return false, errors.WrapPrefix(ErrInvalidQuantity, fmt.Sprintf("Allocation of quantity %d in warehouse %v", allocated, warehouse), 0)
+		}
+	}
+	return true, nil
+}
+
+//Allocator is the strategy picking the warehouses a quantity of a product is taken from, given the stock of every warehouse
+//Returns the allocation of the whole quantity or an error wrapping ErrInsufficientStock
+type Allocator func(stocks map[string]int64, quantity int) (Allocation, *errors.Error)
+
+//Allocate is a function for allocating a quantity on warehouse stocks with an allocator, checking the allocator's result
+//Returns the allocation or an error describing the failure
+func Allocate(allocate Allocator, stocks map[string]int64, quantity int) (Allocation, *errors.Error) {
+	if nil == allocate {
+		allocate = DefaultAllocator
+	}
+	allocation, err := allocate(stocks, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := allocation.Validate(); err != nil {
+		return nil, err
+	}
+	if allocation.Quantity() != quantity {
+		return nil, errors.Wrap(fmt.Errorf("Allocation of quantity %d does not fulfil quantity %d", allocation.Quantity(), quantity), 0)
+	}
+	for warehouse, allocated := range allocation {
+		if stocks[warehouse] < int64(allocated) {
+			return nil, errors.WrapPrefix(ErrInsufficientStock, fmt.Sprintf("Stock %d in warehouse %v does not have enough quantity %d", stocks[warehouse], warehouse, allocated), 0)
+		}
+	}
+	return allocation, nil
+}
+
+//DefaultAllocator is the allocator used when none is given (see PreferSingleWarehouse)
+var DefaultAllocator Allocator = PreferSingleWarehouse
+
+//PreferSingleWarehouse is an allocator taking the quantity from the warehouses by decreasing stock
+func PreferSingleWarehouse(stocks map[string]int64, quantity int) (Allocation, *errors.Error) {
+	allocation := make(Allocation)
+	remaining := int64(quantity)
+	for _, warehouse := range byDecreasingStock(stocks) {
+		if remaining <= 0 {
+			break
+		}
+		taken := stocks[warehouse]
+		if taken > remaining {
+			taken = remaining
+		}
+		allocation[warehouse] = int(taken)
+		remaining -= taken
+	}
+	if remaining > 0 {
+		return nil, errors.WrapPrefix(ErrInsufficientStock, fmt.Sprintf("Stock %d does not have enough quantity %d", int64(quantity)-remaining, quantity), 0)
+	}
+	return allocation, nil
+}
+
+//SingleWarehouse is an allocator taking the whole quantity from a single warehouse (the one with the most stock), never splitting it
+func SingleWarehouse(stocks map[string]int64, quantity int) (Allocation, *errors.Error) {
+	warehouses := byDecreasingStock(stocks)
+	if 0 == len(warehouses) || stocks[warehouses[0]] < int64(quantity) {
+		return nil, errors.WrapPrefix(ErrInsufficientStock, fmt.Sprintf("No single warehouse stock has enough quantity %d", quantity), 0)
+	}
+	return Allocation{warehouses[0]: quantity}, nil
+}
+
+//byDecreasingStock returns the ids of the warehouses having stock sorted by decreasing stock, then by id
+func byDecreasingStock(stocks map[string]int64) []string {
+	warehouses := make([]string, 0, len(stocks))
+	for warehouse, stock := range stocks {
+		if stock > 0 {
+			warehouses = append(warehouses, warehouse)
+		}
+	}
+	sort.Slice(warehouses, func(i, j int) bool {
+		if stocks[warehouses[i]] != stocks[warehouses[j]] {
+			return stocks[warehouses[i]] > stocks[warehouses[j]]
+		}
+		return warehouses[i] < warehouses[j]
+	})
+	return warehouses
+}
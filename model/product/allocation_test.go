@@ -0,0 +1,115 @@
+package product_test
+
+import (
+	"fmt"
+	"sstest/model/product"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+func TestAllocators(t *testing.T) {
+	stocks := map[string]int64{"east": 4, "main": 6, "west": 6, "empty": 0}
+
+	singleAllocation, _ := product.PreferSingleWarehouse(stocks, 5)
+	splitAllocation, _ := product.PreferSingleWarehouse(stocks, 14)
+	_, errPreferInsufficient := product.PreferSingleWarehouse(stocks, 17)
+	singleOnlyAllocation, _ := product.SingleWarehouse(stocks, 6)
+	_, errSingleOnly := product.SingleWarehouse(stocks, 7)
+	defaultAllocation, _ := product.Allocate(nil, stocks, 3)
+	_, errOverAllocated := product.Allocate(func(stocks map[string]int64, quantity int) (product.Allocation, *errors.Error) {
+		return product.Allocation{"east": quantity}, nil
+	}, stocks, 5)
+	_, errShortAllocated := product.Allocate(func(stocks map[string]int64, quantity int) (product.Allocation, *errors.Error) {
+		return product.Allocation{"main": quantity - 1}, nil
+	}, stocks, 5)
+	_, errInvalidAllocation := product.Allocate(func(stocks map[string]int64, quantity int) (product.Allocation, *errors.Error) {
+		return product.Allocation{"main": quantity, "east": 0}, nil
+	}, stocks, 5)
+
+	var allocatorTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Enough Stock In One Warehouse Must Not Split", "main=5", formatAllocation(singleAllocation)},
+		{"Split Must Take From Largest Stocks First", "east=2 main=6 west=6", formatAllocation(splitAllocation)},
+		{"Split Insufficient Stock Failure Reason", true, nil != errPreferInsufficient && errors.Is(errPreferInsufficient, product.ErrInsufficientStock)},
+		{"Single Warehouse Allocation", "main=6", formatAllocation(singleOnlyAllocation)},
+		{"Single Warehouse Must Not Split", true, nil != errSingleOnly && errors.Is(errSingleOnly, product.ErrInsufficientStock)},
+		{"Default Allocator Allocation", "main=3", formatAllocation(defaultAllocation)},
+		{"Allocation Over Warehouse Stock Failure Reason", true, nil != errOverAllocated && errors.Is(errOverAllocated, product.ErrInsufficientStock)},
+		{"Allocation Not Fulfilling Quantity Must Fail", true, nil != errShortAllocated},
+		{"Allocation With Zero Quantity Failure Reason", true, nil != errInvalidAllocation && errors.Is(errInvalidAllocation, product.ErrInvalidQuantity)},
+	}
+
+	for _, test := range allocatorTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestWarehouseStock(t *testing.T) {
+	stockProd := product.New("warehouseProd", "Warehouse Product")
+	stockProd.SetStatus(product.StatusAvailable)
+	stockProd.SetStocks(map[string]int64{"east": 3, "west": 4, "empty": 0})
+
+	totalStock, warehouseCount := stockProd.Stock(), len(stockProd.Warehouses())
+	canBeOrdered, _ := stockProd.CanBeOrdered(7)
+	cannotBeOrdered, _ := stockProd.CanBeOrdered(8)
+	taken, _ := stockProd.TakeStock(5, nil)
+	eastAfterTake, westAfterTake := stockProd.WarehouseStock("east"), stockProd.WarehouseStock("west")
+	_, errTakeSingle := stockProd.TakeStock(3, product.SingleWarehouse)
+	returnOk, _ := stockProd.ReturnStock(taken)
+	eastAfterReturn, westAfterReturn := stockProd.WarehouseStock("east"), stockProd.WarehouseStock("west")
+	invalidReturnOk, errInvalidReturn := stockProd.ReturnStock(product.Allocation{})
+	stockProd.SetWarehouseStock("east", 0)
+	eastDeleted := len(stockProd.Warehouses())
+	stockProd.SetStock(9)
+	replacedWarehouses := fmt.Sprintf("%v", stockProd.Warehouses())
+
+	var warehouseStockTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Stock Must Be The Total Of Warehouses", int64(7), totalStock},
+		{"Warehouses Without Stock Must Be Dropped", 2, warehouseCount},
+		{"Total Stock Can Be Ordered", true, canBeOrdered},
+		{"More Than Total Stock Can Not Be Ordered", false, cannotBeOrdered},
+		{"Take Must Split Over Warehouses", "east=1 west=4", formatAllocation(taken)},
+		{"Take East Stock", int64(2), eastAfterTake},
+		{"Take West Stock", int64(0), westAfterTake},
+		{"Single Warehouse Take Failure Reason", true, nil != errTakeSingle && errors.Is(errTakeSingle, product.ErrInsufficientStock)},
+		{"Return Must Succeed", true, returnOk},
+		{"Return East Stock", int64(3), eastAfterReturn},
+		{"Return West Stock", int64(4), westAfterReturn},
+		{"Empty Return Must Fail", false, invalidReturnOk},
+		{"Empty Return Failure Reason", true, nil != errInvalidReturn && errors.Is(errInvalidReturn, product.ErrInvalidQuantity)},
+		{"Zero Warehouse Stock Must Be Deleted", 1, eastDeleted},
+		{"Set Stock Must Replace Warehouses", "[main]", replacedWarehouses},
+	}
+
+	for _, test := range warehouseStockTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+//formatAllocation formats an allocation by warehouse id for comparison
+func formatAllocation(allocation product.Allocation) string {
+	formatted := ""
+	for _, warehouse := range allocation.Warehouses() {
+		if formatted != "" {
+			formatted += " "
+		}
+		formatted += fmt.Sprintf("%v=%d", warehouse, allocation[warehouse])
+	}
+	return formatted
+}
@@ -3,6 +3,8 @@ package product
 
 import (
 	"fmt"
+	"sort"
+	"sstest/model/money"
 	"sync"
 
 	"github.com/go-errors/errors"
@@ -25,14 +27,35 @@ var statusSlice = map[string]string{
 	StatusDiscontinued: "Discontinued",
 }
 
+//ErrInvalidQuantity is the error returned (wrapped) when a requested quantity is zero or less
+var ErrInvalidQuantity = fmt.Errorf("invalid quantity")
+
+//ErrNotAvailable is the error returned (wrapped) when a product's status is not available
+var ErrNotAvailable = fmt.Errorf("product not available")
+
+//ErrInsufficientStock is the error returned (wrapped) when a product's stock is not enough for a requested quantity
+var ErrInsufficientStock = fmt.Errorf("insufficient stock")
+
+//TaxCategoryStandard is the tax category of a product taxed at the standard rates (the default tax category)
+const TaxCategoryStandard string = "standard"
+
+//DefaultWarehouse is the id of the warehouse holding the stock of a single location product (see SetStock)
+const DefaultWarehouse string = "main"
+
 //Product is business domain model definition of product
 type Product struct {
-	id     string
-	name   string
-	status string
-	price  decimal.Decimal
-	stock  int64
-	mu     sync.Mutex
+	id       string
+	name     string
+	status   string
+	price    decimal.Decimal
+	currency string           //ISO 4217 code of the price's currency
+	stocks   map[string]int64 //keyed by warehouse id (warehouses without stock are left out)
+	weight   decimal.Decimal  //shipping weight in kilograms
+	length   decimal.Decimal  //shipping dimensions in centimeters
+	width    decimal.Decimal
+	height   decimal.Decimal
+	tax      string //tax category, selecting the tax rules of a region applying to the product
+	mu       sync.Mutex
 }
 
 //New creates a new product model struct, initializes it's properties and returns a reference to it
@@ -42,7 +65,13 @@ func New(id, name string) *Product {
 		name,
 		StatusPrototype,
 		decimal.New(0, 0),
-		0,
+		money.DefaultCurrency,
+		make(map[string]int64),
+		decimal.New(0, 0),
+		decimal.New(0, 0),
+		decimal.New(0, 0),
+		decimal.New(0, 0),
+		TaxCategoryStandard,
 		*new(sync.Mutex),
 	}
 }
@@ -67,9 +96,66 @@ func (p *Product) Price() decimal.Decimal {
 	return p.price
 }
 
-//Stock is a getter function for returning a product's stock
+//Currency is a getter function for returning the ISO 4217 code of a product's price currency
+func (p *Product) Currency() string {
+	return p.currency
+}
+
+//Stock is a getter function for returning a product's total stock across its warehouses
 func (p *Product) Stock() int64 {
-	return p.stock
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.total()
+}
+
+//Stocks is a getter function for returning a copy of a product's stock in every warehouse having stock, keyed by warehouse id
+func (p *Product) Stocks() map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.copyStocks()
+}
+
+//WarehouseStock is a getter function for returning a product's stock in a warehouse
+func (p *Product) WarehouseStock(warehouse string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stocks[warehouse]
+}
+
+//Warehouses is a getter function for returning the ids of the warehouses having stock of a product, sorted
+func (p *Product) Warehouses() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	warehouses := make([]string, 0, len(p.stocks))
+	for warehouse := range p.stocks {
+		warehouses = append(warehouses, warehouse)
+	}
+	sort.Strings(warehouses)
+	return warehouses
+}
+
+//Weight is a getter function for returning a product's shipping weight (in kilograms)
+func (p *Product) Weight() decimal.Decimal {
+	return p.weight
+}
+
+//Dimensions is a getter function for returning a product's shipping dimensions (length, width and height in centimeters)
+func (p *Product) Dimensions() (decimal.Decimal, decimal.Decimal, decimal.Decimal) {
+	return p.length, p.width, p.height
+}
+
+//Volume returns a product's shipping volume (in cubic centimeters) from its dimensions
+func (p *Product) Volume() decimal.Decimal {
+	return p.length.Mul(p.width).Mul(p.height)
+}
+
+//TaxCategory is a getter function for returning a product's tax category
+func (p *Product) TaxCategory() string {
+	return p.tax
 }
 
 //SetID is a setter function for setting a product's id
@@ -84,9 +170,35 @@ func (p *Product) SetName(name string) *Product {
 	return p
 }
 
-//SetStock is a setter function for setting a product's stock
+//SetStock is a setter function for setting a product's stock as a single location stock
 func (p *Product) SetStock(stock int64) *Product {
-	p.stock = stock
+	return p.SetStocks(map[string]int64{DefaultWarehouse: stock})
+}
+
+//SetStocks is a setter function for setting a product's stock in every warehouse, replacing the stock of every warehouse
+func (p *Product) SetStocks(stocks map[string]int64) *Product {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stocks = make(map[string]int64, len(stocks))
+	for warehouse, stock := range stocks {
+		if stock != 0 {
+			p.stocks[warehouse] = stock
+		}
+	}
+	return p
+}
+
+//SetWarehouseStock is a setter function for setting a product's stock in a warehouse (a zero stock removes the warehouse)
+func (p *Product) SetWarehouseStock(warehouse string, stock int64) *Product {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if 0 == stock {
+		delete(p.stocks, warehouse)
+	} else {
+		p.stocks[warehouse] = stock
+	}
 	return p
 }
 
@@ -99,6 +211,42 @@ func (p *Product) SetPrice(price decimal.Decimal) (*Product, *errors.Error) {
 	return p, nil
 }
 
+//SetCurrency is a setter function for setting the ISO 4217 code of a product's price currency
+func (p *Product) SetCurrency(currency string) (*Product, *errors.Error) {
+	if false == money.IsCurrency(currency) {
+		return nil, errors.Wrap(fmt.Errorf("Can't set unknown currency: %v", currency), 0)
+	}
+	p.currency = currency
+	return p, nil
+}
+
+//SetWeight is a setter function for setting a product's shipping weight (in kilograms)
+func (p *Product) SetWeight(weight decimal.Decimal) (*Product, *errors.Error) {
+	if zero := decimal.New(0, 0); zero.GreaterThan(weight) {
+		return nil, errors.Wrap(fmt.Errorf("Can't set negative value %v for weight", weight.String()), 0)
+	}
+	p.weight = weight
+	return p, nil
+}
+
+//SetDimensions is a setter function for setting a product's shipping dimensions (length, width and height in centimeters)
+func (p *Product) SetDimensions(length, width, height decimal.Decimal) (*Product, *errors.Error) {
+	if zero := decimal.New(0, 0); zero.GreaterThan(length) || zero.GreaterThan(width) || zero.GreaterThan(height) {
+		return nil, errors.Wrap(fmt.Errorf("Can't set negative value %vx%vx%v for dimensions", length.String(), width.String(), height.String()), 0)
+	}
+	p.length, p.width, p.height = length, width, height
+	return p, nil
+}
+
+//SetTaxCategory is a setter function for setting a product's tax category (e.g. standard, reduced or food)
+func (p *Product) SetTaxCategory(category string) (*Product, *errors.Error) {
+	if "" == category {
+		return nil, errors.Wrap(fmt.Errorf("Can't set empty tax category"), 0)
+	}
+	p.tax = category
+	return p, nil
+}
+
 //SetStatus is a setter function for setting a product's status
 func (p *Product) SetStatus(status string) (*Product, *errors.Error) {
 	if _, ok := statusSlice[status]; false == ok {
@@ -118,13 +266,80 @@ func (p *Product) CanBeOrdered(quantity int) (bool, *errors.Error) {
 	defer p.mu.Unlock()
 
 	if quantity <= 0 {
-		return false, errors.Wrap(fmt.Errorf("Can't order product (id: %v) with quantity %d", p.id, quantity), 0)
+		return false, errors.WrapPrefix(ErrInvalidQuantity, fmt.Sprintf("Can't order product (id: %v) with quantity %d", p.id, quantity), 0)
 	}
 	if p.status != StatusAvailable {
-		return false, errors.Wrap(fmt.Errorf("Product (id: %v) status is not available", p.id), 0)
+		return false, errors.WrapPrefix(ErrNotAvailable, fmt.Sprintf("Product (id: %v) status is not available", p.id), 0)
+	}
+	if stock := p.total(); stock-int64(quantity) < 0 {
+		return false, errors.WrapPrefix(ErrInsufficientStock, fmt.Sprintf("Product (id: %v) stock %d does not have enough quantity %d", p.id, stock, quantity), 0)
+	}
+	return true, nil
+}
+
+//DecrementStock is a function for atomically decrementing a product's stock by a quantity
+//returns true if stock is decremented or false and an error describing the failure
+func (p *Product) DecrementStock(quantity int) (bool, *errors.Error) {
+	if _, err := p.TakeStock(quantity, DefaultAllocator); err != nil {
+		return false, err
 	}
-	if p.stock-int64(quantity) < 0 {
-		return false, errors.Wrap(fmt.Errorf("Product (id: %v) stock %d does not have enough quantity %d", p.id, p.stock, quantity), 0)
+	return true, nil
+}
+
+//IncrementStock is a function for atomically incrementing a product's stock by a quantity (added to the default warehouse)
+func (p *Product) IncrementStock(quantity int) (bool, *errors.Error) {
+	return p.ReturnStock(Allocation{DefaultWarehouse: quantity})
+}
+
+//TakeStock is a function for atomically decrementing a product's warehouse stocks by a quantity allocated by an allocator
+//returns the allocation of the quantity or an error describing the failure
+func (p *Product) TakeStock(quantity int, allocate Allocator) (Allocation, *errors.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if quantity <= 0 {
+		return nil, errors.WrapPrefix(ErrInvalidQuantity, fmt.Sprintf("Can't decrement product (id: %v) stock by %d", p.id, quantity), 0)
+	}
+	allocation, err := Allocate(allocate, p.copyStocks(), quantity)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, fmt.Sprintf("Product (id: %v) stock %d can't be allocated", p.id, p.total()), 0)
+	}
+	for warehouse, allocated := range allocation {
+		if p.stocks[warehouse] -= int64(allocated); 0 == p.stocks[warehouse] {
+			delete(p.stocks, warehouse)
+		}
+	}
+	return allocation, nil
+}
+
+//ReturnStock is a function for atomically incrementing a product's warehouse stocks by an allocation (e.g. returning stock of a canceled order)
+func (p *Product) ReturnStock(allocation Allocation) (bool, *errors.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := allocation.Validate(); err != nil {
+		return false, errors.WrapPrefix(err, fmt.Sprintf("Can't increment product (id: %v) stock", p.id), 0)
+	}
+	for warehouse, quantity := range allocation {
+		p.stocks[warehouse] += int64(quantity)
 	}
 	return true, nil
 }
+
+//copyStocks returns a copy of a product's warehouse stocks (the caller holds the lock)
+func (p *Product) copyStocks() map[string]int64 {
+	stocks := make(map[string]int64, len(p.stocks))
+	for warehouse, stock := range p.stocks {
+		stocks[warehouse] = stock
+	}
+	return stocks
+}
+
+//total returns a product's total stock across its warehouses (the caller holds the lock)
+func (p *Product) total() int64 {
+	var stock int64
+	for _, warehouseStock := range p.stocks {
+		stock += warehouseStock
+	}
+	return stock
+}
@@ -4,9 +4,13 @@ package product_test
 import (
 	"fmt"
 	"os"
+	"sstest/model/money"
 	"sstest/model/product"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/go-errors/errors"
 	"github.com/shopspring/decimal"
 )
 
@@ -77,6 +81,71 @@ func TestSetPrice(t *testing.T) {
 	})
 }
 
+func TestSetShippingSize(t *testing.T) {
+	sizedProd := product.New("sizedProd", "Sized Product")
+	t.Run("Weight Must Be Zero", func(t *testing.T) {
+		if false == sizedProd.Weight().IsZero() {
+			t.Errorf("expected %v but got %v", 0, sizedProd.Weight().String())
+		}
+	})
+	t.Run("Set Negative Weight", func(t *testing.T) {
+		if _, err := sizedProd.SetWeight(decimal.New(-1, 0)); err == nil {
+			t.Error("expected error but got none\n")
+		}
+	})
+	t.Run("Set Negative Dimension", func(t *testing.T) {
+		if _, err := sizedProd.SetDimensions(decimal.New(10, 0), decimal.New(-1, 0), decimal.New(10, 0)); err == nil {
+			t.Error("expected error but got none\n")
+		}
+	})
+	t.Run("Set Dimensions Volume", func(t *testing.T) {
+		sizedProd.SetDimensions(decimal.New(10, 0), decimal.New(20, 0), decimal.New(30, 0))
+		if false == sizedProd.Volume().Equal(decimal.New(6000, 0)) {
+			t.Errorf("expected %v but got %v", 6000, sizedProd.Volume().String())
+		}
+	})
+}
+
+func TestSetTaxCategory(t *testing.T) {
+	taxedProd := product.New("taxedProd", "Taxed Product")
+	t.Run("Tax Category Must Be Standard", func(t *testing.T) {
+		if product.TaxCategoryStandard != taxedProd.TaxCategory() {
+			t.Errorf("expected %v but got %v", product.TaxCategoryStandard, taxedProd.TaxCategory())
+		}
+	})
+	t.Run("Set Empty Tax Category", func(t *testing.T) {
+		if _, err := taxedProd.SetTaxCategory(""); err == nil {
+			t.Error("expected error but got none\n")
+		}
+	})
+	t.Run("Set Tax Category", func(t *testing.T) {
+		taxedProd.SetTaxCategory("food")
+		if "food" != taxedProd.TaxCategory() {
+			t.Errorf("expected %v but got %v", "food", taxedProd.TaxCategory())
+		}
+	})
+}
+
+func TestSetCurrency(t *testing.T) {
+	pricedProd := product.New("pricedProd", "Priced Product")
+	t.Run("Currency Must Be Default Currency", func(t *testing.T) {
+		if money.DefaultCurrency != pricedProd.Currency() {
+			t.Errorf("expected %v but got %v", money.DefaultCurrency, pricedProd.Currency())
+		}
+	})
+	t.Run("Set Unknown Currency", func(t *testing.T) {
+		if _, err := pricedProd.SetCurrency("XYZ"); err == nil {
+			t.Error("expected error but got none\n")
+		}
+	})
+	t.Run("Set Currency", func(t *testing.T) {
+		pricedProd.SetCurrency("EUR")
+		if "EUR" != pricedProd.Currency() {
+			t.Errorf("expected %v but got %v", "EUR", pricedProd.Currency())
+		}
+	})
+}
+
 func TestCanBeOrdered(t *testing.T) {
 	validProductOrder, _ := availableProd.CanBeOrdered(10)
 	notEnoughStockProductOrder, _ := availableProd.CanBeOrdered(9999)
@@ -102,3 +171,51 @@ func TestCanBeOrdered(t *testing.T) {
 		})
 	}
 }
+
+func TestDecrementStock(t *testing.T) {
+	stockProd := product.New("stockProd", "Stock Product")
+	stockProd.SetStatus(product.StatusAvailable)
+	stockProd.SetStock(100)
+
+	//100 concurrent decrements of 3 from stock 100 must only let 33 of them succeed
+	var wg sync.WaitGroup
+	var succeeded int64
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := stockProd.DecrementStock(3); ok {
+				atomic.AddInt64(&succeeded, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, errInsufficient := stockProd.DecrementStock(2)
+	invalidDecrement, _ := stockProd.DecrementStock(0)
+
+	t.Run("Concurrent Decrements Must Not Oversell", func(t *testing.T) {
+		if 33 != succeeded {
+			t.Errorf("want %v succeeded decrements, got %v", 33, succeeded)
+		}
+		if 1 != stockProd.Stock() {
+			t.Errorf("want %v for stock, got %v", 1, stockProd.Stock())
+		}
+	})
+	t.Run("Insufficient Stock Decrement", func(t *testing.T) {
+		if nil == errInsufficient || false == errors.Is(errInsufficient, product.ErrInsufficientStock) {
+			t.Errorf("want insufficient stock error, got %v", errInsufficient)
+		}
+	})
+	t.Run("Zero Quantity Decrement", func(t *testing.T) {
+		if false != invalidDecrement {
+			t.Errorf("want %v got %v", false, invalidDecrement)
+		}
+	})
+	t.Run("Increment Stock", func(t *testing.T) {
+		stockProd.IncrementStock(9)
+		if 10 != stockProd.Stock() {
+			t.Errorf("want %v for stock, got %v", 10, stockProd.Stock())
+		}
+	})
+}
@@ -28,6 +28,9 @@ const StatusInactive string = "I"
 //StatusSuspended is const for 'suspended' user status
 const StatusSuspended string = "S"
 
+//ErrNotActive is the error returned (wrapped) when a user's status is not active for an operation (e.g. ordering)
+var ErrNotActive = fmt.Errorf("user not active")
+
 //statusSlice is a map of known status code and its label pairs
 var statusSlice = map[string]string{
 	StatusActive:    "Active",
@@ -52,6 +55,22 @@ func New(id, name, address string) (*User, *errors.Error) {
 	}, nil
 }
 
+//NewFromHash creates a new user model struct with an existing password hash and returns a reference to it
+func NewFromHash(id, name, address string, hash []byte) (*User, *errors.Error) {
+	u := &User{
+		id,
+		nil,
+		name,
+		address,
+		StatusInactive,
+		*new(sync.Mutex),
+	}
+	if _, err := u.SetPasswordHash(hash); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Failed creating user: %v", err.Error()), 0)
+	}
+	return u, nil
+}
+
 //ID is a getter function for returning a user's id
 func (u *User) ID() string {
 	return u.id
@@ -159,7 +178,7 @@ func (u *User) CanOrder() (bool, error) {
 	defer u.mu.Unlock()
 
 	if StatusActive != u.status {
-		return false, errors.Wrap(fmt.Errorf("User status is not active"), 0)
+		return false, errors.WrapPrefix(ErrNotActive, fmt.Sprintf("User (id: %v) status is not active", u.id), 0)
 	}
 	return true, nil
 }
@@ -96,3 +96,30 @@ func TestCanOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFromHash(t *testing.T) {
+	storedUser, errStored := user.NewFromHash("storedUser", "Stored User", "Stored User Address", activeUser.Password())
+	_, errInvalid := user.NewFromHash("invalidUser", "Invalid User", "Invalid User Address", []byte("anInvalidPasswordHash"))
+
+	t.Run("Valid Hash Must Be Kept Untouched", func(t *testing.T) {
+		if errStored != nil {
+			t.Fatalf("creating user got error %v", errStored)
+		}
+		if string(activeUser.Password()) != string(storedUser.Password()) {
+			t.Errorf("expected %s but got %s", activeUser.Password(), storedUser.Password())
+		}
+		if match, _ := storedUser.ValidatePassword("changeme"); false == match {
+			t.Error("password does not match\n")
+		}
+	})
+	t.Run("Status Must Be Inactive", func(t *testing.T) {
+		if user.StatusInactive != storedUser.Status() {
+			t.Errorf("expected %v but got %v", user.StatusInactive, storedUser.Status())
+		}
+	})
+	t.Run("Invalid Hash", func(t *testing.T) {
+		if errInvalid == nil {
+			t.Error("expected error but got none\n")
+		}
+	})
+}
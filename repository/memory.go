@@ -0,0 +1,711 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//MemoryOrderRepository is an in-memory implementation of OrderRepository (intended for tests)
+type MemoryOrderRepository struct {
+	orders   map[string]*order.Order
+	products *MemoryProductRepository //the inventory a submitted order's stock is taken from and a canceled order's stock is returned to
+	coupons  *MemoryCouponRepository  //the ledger a submitted order's coupon redemptions are recorded and a canceled order's are reversed in
+	mu       sync.Mutex
+}
+
+//NewMemoryOrderRepository creates a new in-memory order repository over product and coupon repositories and returns a reference to it
+func NewMemoryOrderRepository(products *MemoryProductRepository, coupons *MemoryCouponRepository) *MemoryOrderRepository {
+	return &MemoryOrderRepository{
+		make(map[string]*order.Order),
+		products,
+		coupons,
+		*new(sync.Mutex),
+	}
+}
+
+//Save is a function for storing an order
+func (r *MemoryOrderRepository) Save(o *order.Order) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.orders[o.ID()]; ok && stored != o && "" != o.StoredStatus() && stored.Status() != o.StoredStatus() {
+		return errors.WrapPrefix(order.ErrInvalidStatus, fmt.Sprintf("Can't save order %v, it's no longer stored with status %v", o.ID(), o.StoredStatus()), 0)
+	}
+	r.orders[o.ID()] = o
+	o.SetStoredStatus(o.Status())
+	return nil
+}
+
+//FindByID is a function for returning the order with the given id
+func (r *MemoryOrderRepository) FindByID(id string) (*order.Order, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[id]
+	if false == ok {
+		return nil, NotFound("order", id)
+	}
+	return o, nil
+}
+
+//FindByStatus is a function for returning all orders having the given status ordered by their created date
+func (r *MemoryOrderRepository) FindByStatus(status string) ([]*order.Order, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orders := make([]*order.Order, 0)
+	for _, o := range r.orders {
+		if status == o.Status() {
+			orders = append(orders, o)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedDate().Before(orders[j].CreatedDate())
+	})
+	return orders, nil
+}
+
+//FindByUser is a function for returning all orders of the user with the given id ordered by their created date
+func (r *MemoryOrderRepository) FindByUser(userID string) ([]*order.Order, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orders := make([]*order.Order, 0)
+	for _, o := range r.orders {
+		if o.User() != nil && userID == o.User().ID() {
+			orders = append(orders, o)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedDate().Before(orders[j].CreatedDate())
+	})
+	return orders, nil
+}
+
+//FindByTracking is a function for returning the order having a shipment with the given carrier and tracking id
+func (r *MemoryOrderRepository) FindByTracking(carrier, trackingID string) (*order.Order, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, o := range r.orders {
+		if _, ok := o.TrackedShipment(carrier, trackingID); ok {
+			return o, nil
+		}
+	}
+	return nil, NotFound("order shipment", fmt.Sprintf("%v/%v", carrier, trackingID))
+}
+
+//Cancel is a function for changing the status of a stored order from a status to another only if it still has the former
+func (r *MemoryOrderRepository) Cancel(orderID, from, to string, allocations order.Allocations, coupons []*coupon.Coupon, date time.Time) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[orderID]
+	if false == ok {
+		return NotFound("order", orderID)
+	}
+	if from != o.Status() {
+		return errors.WrapPrefix(order.ErrInvalidStatus, fmt.Sprintf("Can't change order %v status to %v, its stored status is %v (not %v)", orderID, to, o.Status(), from), 0)
+	}
+	if err := r.products.IncrementStocks(allocations); err != nil {
+		return err
+	}
+	for _, c := range coupons {
+		if err := r.coupons.Reverse(c, orderID, date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Submit is a function for changing the status of a stored order from a status to another only if it still has the former
+func (r *MemoryOrderRepository) Submit(orderID, from, to string, quantities map[*product.Product]int, allocate product.Allocator,
+	redemptions map[*coupon.Coupon]*coupon.Redemption) (order.Allocations, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[orderID]
+	if false == ok {
+		return nil, NotFound("order", orderID)
+	}
+	if from != o.Status() {
+		return nil, errors.WrapPrefix(order.ErrInvalidStatus, fmt.Sprintf("Can't change order %v status to %v, its stored status is %v (not %v)", orderID, to, o.Status(), from), 0)
+	}
+	r.products.mu.Lock()
+	defer r.products.mu.Unlock()
+	if 0 != len(redemptions) {
+		r.coupons.mu.Lock()
+		defer r.coupons.mu.Unlock()
+		if err := r.coupons.redeemable(redemptions); err != nil {
+			return nil, err
+		}
+	}
+	allocations, err := r.products.decrementStocks(orderID, quantities, allocate)
+	if err != nil {
+		return nil, err
+	}
+	r.products.release(orderID, "")
+	if 0 != len(redemptions) {
+		r.coupons.redeem(redemptions)
+	}
+	return allocations, nil
+}
+
+//Delete is a function for removing the order with the given id
+func (r *MemoryOrderRepository) Delete(id string) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.orders[id]; false == ok {
+		return NotFound("order", id)
+	}
+	delete(r.orders, id)
+	return nil
+}
+
+//MemoryProductRepository is an in-memory implementation of ProductRepository (intended for tests)
+type MemoryProductRepository struct {
+	products map[string]*product.Product
+	holds    map[string]map[string]hold //keyed by product id then order id
+	mu       sync.Mutex
+}
+
+//hold is a draft order's reservation of a quantity of a product's stock until its expiry date
+type hold struct {
+	quantity   int
+	expiryDate time.Time
+}
+
+//NewMemoryProductRepository creates a new in-memory product repository and returns a reference to it
+func NewMemoryProductRepository() *MemoryProductRepository {
+	return &MemoryProductRepository{
+		make(map[string]*product.Product),
+		make(map[string]map[string]hold),
+		*new(sync.Mutex),
+	}
+}
+
+//Save is a function for storing a product
+func (r *MemoryProductRepository) Save(p *product.Product) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.products[p.ID()] = p
+	return nil
+}
+
+//Update is a function for storing an existing product, keeping the stored product's stocks
+func (r *MemoryProductRepository) Update(p *product.Product) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[p.ID()]
+	if false == ok {
+		return NotFound("product", p.ID())
+	}
+	p.SetStocks(stored.Stocks())
+	r.products[p.ID()] = p
+	return nil
+}
+
+//FindByID is a function for returning the product with the given id
+func (r *MemoryProductRepository) FindByID(id string) (*product.Product, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[id]
+	if false == ok {
+		return nil, NotFound("product", id)
+	}
+	return p, nil
+}
+
+//FindAll is a function for returning all products ordered by their id
+func (r *MemoryProductRepository) FindAll() ([]*product.Product, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := make([]*product.Product, 0, len(r.products))
+	for _, p := range r.products {
+		products = append(products, p)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ID() < products[j].ID()
+	})
+	return products, nil
+}
+
+//Delete is a function for removing the product with the given id (and its holds)
+func (r *MemoryProductRepository) Delete(id string) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[id]; false == ok {
+		return NotFound("product", id)
+	}
+	delete(r.products, id)
+	delete(r.holds, id)
+	return nil
+}
+
+//DecrementStocks is a function for atomically decrementing the stored available-to-sell stock of the given products, all or none of them
+func (r *MemoryProductRepository) DecrementStocks(quantities map[*product.Product]int, allocate product.Allocator) (order.Allocations, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.decrementStocks("", quantities, allocate)
+}
+
+//ConvertHolds is a function for atomically decrementing the stored stock of the given products and removing the order's holds
+func (r *MemoryProductRepository) ConvertHolds(orderID string, quantities map[*product.Product]int, allocate product.Allocator) (order.Allocations, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	allocations, err := r.decrementStocks(orderID, quantities, allocate)
+	if err != nil {
+		return nil, err
+	}
+	r.release(orderID, "")
+	return allocations, nil
+}
+
+//decrementStocks decrements the stored stock of the given products by their quantity, all or none of them (the caller holds the lock)
+func (r *MemoryProductRepository) decrementStocks(orderID string, quantities map[*product.Product]int, allocate product.Allocator) (order.Allocations, *errors.Error) {
+	products := order.SortedProducts(quantities)
+	allocations := make(order.Allocations, len(products))
+	now := time.Now()
+	//rollback increments back the stocks that are already decremented
+	rollback := func() {
+		for p, allocation := range allocations {
+			r.products[p.ID()].ReturnStock(allocation)
+		}
+	}
+	for _, p := range products {
+		stored, ok := r.products[p.ID()]
+		if false == ok {
+			rollback()
+			return nil, NotFound("product", p.ID())
+		}
+		//note: a non positive quantity is left to the product's own validation
+		if available := stored.Stock() - r.held(p.ID(), orderID, now); quantities[p] > 0 && available < int64(quantities[p]) {
+			rollback()
+			return nil, errors.WrapPrefix(product.ErrInsufficientStock, fmt.Sprintf("Product (id: %v) available stock %d does not have enough quantity %d", p.ID(), available, quantities[p]), 0)
+		}
+		allocation, err := stored.TakeStock(quantities[p], allocate)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		allocations[p] = allocation
+	}
+	//keep given products (possibly loaded separately from the stored ones) in sync with the stored stock
+	for _, p := range products {
+		if stored := r.products[p.ID()]; stored != p {
+			p.SetStocks(stored.Stocks())
+		}
+	}
+	return allocations, nil
+}
+
+//IncrementStocks is a function for atomically incrementing the stored warehouse stocks of the given products
+func (r *MemoryProductRepository) IncrementStocks(allocations order.Allocations) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := allocations.Products()
+	for _, p := range products {
+		if _, err := allocations[p].Validate(); err != nil {
+			return errors.WrapPrefix(err, fmt.Sprintf("Can't increment product (id: %v) stock", p.ID()), 0)
+		}
+	}
+	for _, p := range products {
+		stored, ok := r.products[p.ID()]
+		if false == ok {
+			continue
+		}
+		stored.ReturnStock(allocations[p])
+		//keep given products (possibly loaded separately from the stored ones) in sync with the stored stock
+		if stored != p {
+			p.SetStocks(stored.Stocks())
+		}
+	}
+	return nil
+}
+
+//Hold is a function for placing (or replacing) the hold of an order on a quantity of a stored product until the expiry date
+func (r *MemoryProductRepository) Hold(orderID string, p *product.Product, quantity int, expiryDate time.Time) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if quantity <= 0 {
+		return errors.WrapPrefix(product.ErrInvalidQuantity, fmt.Sprintf("Can't hold product (id: %v) quantity %d", p.ID(), quantity), 0)
+	}
+	stored, ok := r.products[p.ID()]
+	if false == ok {
+		return NotFound("product", p.ID())
+	}
+	if available := stored.Stock() - r.held(p.ID(), orderID, time.Now()); available < int64(quantity) {
+		return errors.WrapPrefix(product.ErrInsufficientStock, fmt.Sprintf("Product (id: %v) available stock %d does not have enough quantity %d", p.ID(), available, quantity), 0)
+	}
+	if _, ok := r.holds[p.ID()]; false == ok {
+		r.holds[p.ID()] = make(map[string]hold)
+	}
+	r.holds[p.ID()][orderID] = hold{quantity, expiryDate}
+	return nil
+}
+
+//Release is a function for removing the hold of an order on a product (or every hold of the order for an empty product id)
+func (r *MemoryProductRepository) Release(orderID, productID string) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.release(orderID, productID)
+	return nil
+}
+
+//release removes the hold of an order on a product, or every hold of the order for an empty product id (the caller holds the lock)
+func (r *MemoryProductRepository) release(orderID, productID string) {
+	for id, holds := range r.holds {
+		if "" == productID || id == productID {
+			delete(holds, orderID)
+			if 0 == len(holds) {
+				delete(r.holds, id)
+			}
+		}
+	}
+}
+
+//Available is a function for returning the available-to-sell stock of the product with the given id
+func (r *MemoryProductRepository) Available(id string) (int64, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[id]
+	if false == ok {
+		return 0, NotFound("product", id)
+	}
+	return stored.Stock() - r.held(id, "", time.Now()), nil
+}
+
+//ReleaseExpired is a function for removing every hold expired at the given time
+func (r *MemoryProductRepository) ReleaseExpired(now time.Time) (int64, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var released int64
+	for id, holds := range r.holds {
+		for orderID, h := range holds {
+			if false == h.expiryDate.After(now) {
+				delete(holds, orderID)
+				released++
+			}
+		}
+		if 0 == len(holds) {
+			delete(r.holds, id)
+		}
+	}
+	return released, nil
+}
+
+//held returns the quantity of a product held by the unexpired holds of the other orders than the given one (the caller holds the lock)
+func (r *MemoryProductRepository) held(productID, exceptOrderID string, now time.Time) int64 {
+	var quantity int64
+	for orderID, h := range r.holds[productID] {
+		if orderID != exceptOrderID && h.expiryDate.After(now) {
+			quantity += int64(h.quantity)
+		}
+	}
+	return quantity
+}
+
+//MemoryCouponRepository is an in-memory implementation of CouponRepository (intended for tests)
+type MemoryCouponRepository struct {
+	coupons     map[string]*coupon.Coupon //keyed by normalized code
+	redemptions []*coupon.Redemption      //in the order they were redeemed
+	mu          sync.Mutex
+}
+
+//NewMemoryCouponRepository creates a new in-memory coupon repository and returns a reference to it
+func NewMemoryCouponRepository() *MemoryCouponRepository {
+	return &MemoryCouponRepository{
+		make(map[string]*coupon.Coupon),
+		make([]*coupon.Redemption, 0),
+		*new(sync.Mutex),
+	}
+}
+
+//Create is a function for storing a new coupon
+func (r *MemoryCouponRepository) Create(c *coupon.Coupon) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.coupons[NormalizeCode(c.ID())]; ok {
+		return Duplicate("coupon", c.ID())
+	}
+	r.coupons[NormalizeCode(c.ID())] = c
+	return nil
+}
+
+//Save is a function for storing an existing coupon
+func (r *MemoryCouponRepository) Save(c *coupon.Coupon) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.coupons[NormalizeCode(c.ID())]; false == ok || stored.ID() != c.ID() {
+		return NotFound("coupon", c.ID())
+	}
+	r.coupons[NormalizeCode(c.ID())] = c
+	return nil
+}
+
+//Update is a function for storing an existing coupon, keeping the stored coupon's stock
+func (r *MemoryCouponRepository) Update(c *coupon.Coupon) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.coupons[NormalizeCode(c.ID())]
+	if false == ok || stored.ID() != c.ID() {
+		return NotFound("coupon", c.ID())
+	}
+	c.SetStock(stored.Stock())
+	r.coupons[NormalizeCode(c.ID())] = c
+	return nil
+}
+
+//FindByID is a function for returning the coupon with the given id
+func (r *MemoryCouponRepository) FindByID(id string) (*coupon.Coupon, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.coupons[NormalizeCode(id)]
+	if false == ok || c.ID() != id {
+		return nil, NotFound("coupon", id)
+	}
+	return c, nil
+}
+
+//FindByCode is a function for returning the coupon having the given code
+func (r *MemoryCouponRepository) FindByCode(code string) (*coupon.Coupon, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.coupons[NormalizeCode(code)]
+	if false == ok {
+		return nil, NotFound("coupon", code)
+	}
+	return c, nil
+}
+
+//FindAll is a function for returning all coupons ordered by their id
+func (r *MemoryCouponRepository) FindAll() ([]*coupon.Coupon, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coupons := make([]*coupon.Coupon, 0, len(r.coupons))
+	for _, c := range r.coupons {
+		coupons = append(coupons, c)
+	}
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].ID() < coupons[j].ID()
+	})
+	return coupons, nil
+}
+
+//Promotions is a function for returning the automatic promotions (coupons flagged automatic) ordered by their id
+func (r *MemoryCouponRepository) Promotions() ([]*coupon.Coupon, *errors.Error) {
+	coupons, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	promotions := make([]*coupon.Coupon, 0)
+	for _, c := range coupons {
+		if c.Automatic() {
+			promotions = append(promotions, c)
+		}
+	}
+	return promotions, nil
+}
+
+//Delete is a function for removing the coupon with the given id
+func (r *MemoryCouponRepository) Delete(id string) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.coupons[NormalizeCode(id)]; false == ok || c.ID() != id {
+		return NotFound("coupon", id)
+	}
+	delete(r.coupons, NormalizeCode(id))
+	return nil
+}
+
+//Redeem is a function for atomically recording the redemptions of coupons and decrementing the stored coupons' stocks by one use
+func (r *MemoryCouponRepository) Redeem(redemptions map[*coupon.Coupon]*coupon.Redemption) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.redeemable(redemptions); err != nil {
+		return err
+	}
+	r.redeem(redemptions)
+	return nil
+}
+
+//redeemable checks every stored coupon of the redemptions can be redeemed (the caller holds the lock)
+func (r *MemoryCouponRepository) redeemable(redemptions map[*coupon.Coupon]*coupon.Redemption) *errors.Error {
+	for _, c := range sortedCoupons(redemptions) {
+		stored, ok := r.coupons[NormalizeCode(c.ID())]
+		if false == ok || stored.ID() != c.ID() {
+			return NotFound("coupon", c.ID())
+		}
+		if 0 >= stored.Stock() {
+			return errors.WrapPrefix(coupon.ErrNoStock, fmt.Sprintf("Can't redeem coupon (id: %v), stock is %d (no stock)", c.ID(), stored.Stock()), 0)
+		}
+		if ok, err := c.CanBeRedeemed(redemptions[c].UserID(), r.redemptions); false == ok {
+			return err
+		}
+	}
+	return nil
+}
+
+//redeem records the redemptions and decrements the stored coupons' stocks by one use (the caller holds the lock)
+func (r *MemoryCouponRepository) redeem(redemptions map[*coupon.Coupon]*coupon.Redemption) {
+	for _, c := range sortedCoupons(redemptions) {
+		stored := r.coupons[NormalizeCode(c.ID())]
+		stored.DecrementStock()
+		//keep given coupons (possibly loaded separately from the stored ones) in sync with the stored stock
+		if stored != c {
+			c.SetStock(stored.Stock())
+		}
+		r.redemptions = append(r.redemptions, redemptions[c])
+	}
+}
+
+//sortedCoupons returns the coupons of redemptions (keyed by their coupon) ordered by their id
+func sortedCoupons(redemptions map[*coupon.Coupon]*coupon.Redemption) []*coupon.Coupon {
+	coupons := make([]*coupon.Coupon, 0, len(redemptions))
+	for c := range redemptions {
+		coupons = append(coupons, c)
+	}
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].ID() < coupons[j].ID()
+	})
+	return coupons
+}
+
+//Reverse is a function for reversing the unreversed redemption of a coupon by an order, returning its use to the stored coupon's stock
+func (r *MemoryCouponRepository) Reverse(c *coupon.Coupon, orderID string, date time.Time) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, redemption := range r.redemptions {
+		if redemption.CouponID() == c.ID() && redemption.OrderID() == orderID && false == redemption.IsReversed() {
+			redemption.SetReversedDate(date)
+			if stored, ok := r.coupons[NormalizeCode(c.ID())]; ok && stored.ID() == c.ID() {
+				stored.IncrementStock()
+				if stored != c {
+					c.SetStock(stored.Stock())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//FindRedemptions is a function for returning the redemptions of the coupon with the given id
+func (r *MemoryCouponRepository) FindRedemptions(couponID string) ([]*coupon.Redemption, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	redemptions := make([]*coupon.Redemption, 0)
+	for _, redemption := range r.redemptions {
+		if redemption.CouponID() == couponID {
+			redemptions = append(redemptions, redemption)
+		}
+	}
+	return redemptions, nil
+}
+
+//MemoryUserRepository is an in-memory implementation of UserRepository (intended for tests)
+type MemoryUserRepository struct {
+	users map[string]*user.User
+	mu    sync.Mutex
+}
+
+//NewMemoryUserRepository creates a new in-memory user repository and returns a reference to it
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		make(map[string]*user.User),
+		*new(sync.Mutex),
+	}
+}
+
+//Create is a function for storing a new user
+func (r *MemoryUserRepository) Create(u *user.User) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[u.ID()]; ok {
+		return Duplicate("user", u.ID())
+	}
+	r.users[u.ID()] = u
+	return nil
+}
+
+//Save is a function for storing an existing user
+func (r *MemoryUserRepository) Save(u *user.User) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[u.ID()]; false == ok {
+		return NotFound("user", u.ID())
+	}
+	r.users[u.ID()] = u
+	return nil
+}
+
+//FindByID is a function for returning the user with the given id
+func (r *MemoryUserRepository) FindByID(id string) (*user.User, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if false == ok {
+		return nil, NotFound("user", id)
+	}
+	return u, nil
+}
+
+//FindAll is a function for returning all users ordered by their id
+func (r *MemoryUserRepository) FindAll() ([]*user.User, *errors.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := make([]*user.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID() < users[j].ID()
+	})
+	return users, nil
+}
+
+//Delete is a function for removing the user with the given id
+func (r *MemoryUserRepository) Delete(id string) *errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; false == ok {
+		return NotFound("user", id)
+	}
+	delete(r.users, id)
+	return nil
+}
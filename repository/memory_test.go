@@ -0,0 +1,475 @@
+//repository_test provides unit tests for the in-memory repositories
+package repository_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestMemoryOrderRepository(t *testing.T) {
+	repo := repository.NewMemoryOrderRepository(repository.NewMemoryProductRepository(), repository.NewMemoryCouponRepository())
+
+	draftOrder := order.New("draftOrder")
+	submittedOrder1 := order.New("submittedOrder1")
+	submittedOrder1.SetStatus(order.StatusSubmitted)
+	submittedOrder2 := order.New("submittedOrder2")
+	submittedOrder2.SetStatus(order.StatusSubmitted)
+	submittedOrder2.SetCreatedDate(submittedOrder1.CreatedDate().Add(-1 * time.Hour))
+	customer, _ := user.New("customer", "Customer", "Customer Address")
+	submittedOrder1.SetUser(customer)
+	draftOrder.SetUser(customer)
+	submittedOrder2.SetShipments([]*order.Shipment{order.NewShipment("shipment", "dummyCarrier", "dummyTrackingNo")})
+
+	repo.Save(draftOrder)
+	repo.Save(submittedOrder1)
+	repo.Save(submittedOrder2)
+
+	foundOrder, errFind := repo.FindByID("draftOrder")
+	_, errFindMissing := repo.FindByID("missingOrder")
+	submittedOrders, errFindByStatus := repo.FindByStatus(order.StatusSubmitted)
+	canceledOrders, errFindByStatusNone := repo.FindByStatus(order.StatusCanceled)
+	userOrders, errFindByUser := repo.FindByUser("customer")
+	trackedOrder, errFindByTracking := repo.FindByTracking("dummyCarrier", "dummyTrackingNo")
+	_, errFindByTrackingMissing := repo.FindByTracking("otherCarrier", "dummyTrackingNo")
+	//a stale copy loaded as a draft must not replace the submitted order
+	staleOrder := order.New("submittedOrder1").SetStoredStatus(order.StatusDraft)
+	errSaveStale := repo.Save(staleOrder)
+	errDelete := repo.Delete("draftOrder")
+	errDeleteMissing := repo.Delete("draftOrder")
+	_, errFindDeleted := repo.FindByID("draftOrder")
+
+	var memoryOrderTests = []struct {
+		testCase         string
+		expectedErrIsNil bool
+		actualErrIsNil   bool
+		expectedNotFound bool
+		actualNotFound   bool
+	}{
+		{"Find Existing Order", true, errFind == nil, false, isNotFound(errFind)},
+		{"Find Missing Order", false, errFindMissing == nil, true, isNotFound(errFindMissing)},
+		{"Find Orders By Status", true, errFindByStatus == nil, false, isNotFound(errFindByStatus)},
+		{"Find Orders By Status Without Match", true, errFindByStatusNone == nil, false, isNotFound(errFindByStatusNone)},
+		{"Find Orders By User", true, errFindByUser == nil, false, isNotFound(errFindByUser)},
+		{"Find Order By Tracking", true, errFindByTracking == nil, false, isNotFound(errFindByTracking)},
+		{"Find Order By Tracking Of Other Carrier", false, errFindByTrackingMissing == nil, true, isNotFound(errFindByTrackingMissing)},
+		{"Save Stale Order", false, errSaveStale == nil, false, isNotFound(errSaveStale)},
+		{"Delete Existing Order", true, errDelete == nil, false, isNotFound(errDelete)},
+		{"Delete Missing Order", false, errDeleteMissing == nil, true, isNotFound(errDeleteMissing)},
+		{"Find Deleted Order", false, errFindDeleted == nil, true, isNotFound(errFindDeleted)},
+	}
+
+	for _, test := range memoryOrderTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedErrIsNil != test.actualErrIsNil {
+				t.Errorf("want %v for error, got %v", test.expectedErrIsNil, test.actualErrIsNil)
+			}
+			if test.expectedNotFound != test.actualNotFound {
+				t.Errorf("want %v for not found, got %v", test.expectedNotFound, test.actualNotFound)
+			}
+		})
+	}
+
+	t.Run("Stale Order Save Must Be Invalid Status", func(t *testing.T) {
+		if false == errors.Is(errSaveStale, order.ErrInvalidStatus) {
+			t.Errorf("want %v, got %v", order.ErrInvalidStatus, errSaveStale)
+		}
+	})
+	t.Run("Found Order Must Be Saved Order", func(t *testing.T) {
+		if foundOrder != draftOrder {
+			t.Errorf("want %v, got %v", draftOrder.ID(), foundOrder.ID())
+		}
+	})
+	t.Run("Order By Tracking Must Be Shipping Order", func(t *testing.T) {
+		if trackedOrder != submittedOrder2 {
+			t.Errorf("want %v, got %v", submittedOrder2.ID(), trackedOrder)
+		}
+	})
+	t.Run("Orders By Status Must Be Ordered By Created Date", func(t *testing.T) {
+		if 2 != len(submittedOrders) {
+			t.Fatalf("want %v orders, got %v", 2, len(submittedOrders))
+		}
+		if "submittedOrder2" != submittedOrders[0].ID() || "submittedOrder1" != submittedOrders[1].ID() {
+			t.Errorf("want %v, %v, got %v, %v", "submittedOrder2", "submittedOrder1", submittedOrders[0].ID(), submittedOrders[1].ID())
+		}
+	})
+	t.Run("Orders By Status Without Match Must Be Empty", func(t *testing.T) {
+		if 0 != len(canceledOrders) {
+			t.Errorf("want %v orders, got %v", 0, len(canceledOrders))
+		}
+	})
+	t.Run("Orders By User Must Be The User's Orders", func(t *testing.T) {
+		if 2 != len(userOrders) {
+			t.Errorf("want %v orders, got %v", 2, len(userOrders))
+		}
+	})
+}
+
+//isNotFound returns whether the given repository error is a not found error
+func isNotFound(err *errors.Error) bool {
+	return err != nil && errors.Is(err, repository.ErrNotFound)
+}
+
+func TestMemoryProductRepository(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+	anotherProd := product.New("anotherProd", "Another Product")
+	anotherProd.SetStatus(product.StatusAvailable)
+	anotherProd.SetStock(100)
+	anotherProd.SetPrice(decimal.New(50, 0))
+	unsavedProd := product.New("unsavedProd", "Unsaved Product")
+	unsavedProd.SetStatus(product.StatusAvailable)
+	unsavedProd.SetStock(100)
+
+	repo.Save(limitedProd)
+	repo.Save(anotherProd)
+
+	//50 concurrent submissions of orders with stock for only 10 of them
+	orders := make([]*order.Order, 50)
+	for i := range orders {
+		orders[i] = order.New(fmt.Sprintf("concurrentOrder%d", i))
+		orders[i].AddProduct(anotherProd, 1)
+		orders[i].AddProduct(limitedProd, 2)
+		//the inventory is set after adding the items, so the drafts hold no stock and only race at submission
+		orders[i].SetInventory(repo)
+	}
+	var wg sync.WaitGroup
+	for _, o := range orders {
+		wg.Add(1)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Submit("ship name", "ship address", nil)
+		}(o)
+	}
+	wg.Wait()
+	submitted := 0
+	for _, o := range orders {
+		if order.StatusSubmitted == o.Status() {
+			submitted++
+		}
+	}
+
+	_, errUnsaved := repo.DecrementStocks(map[*product.Product]int{anotherProd: 1, unsavedProd: 1}, nil)
+	allProducts, errFindAll := repo.FindAll()
+	errDelete := repo.Delete("anotherProd")
+	errDeleteMissing := repo.Delete("anotherProd")
+
+	var memoryProductTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Only Orders With Enough Stock Must Be Submitted", 10, submitted},
+		{"Limited Stock Must Not Be Oversold", int64(0), limitedProd.Stock()},
+		{"Other Stock Must Only Be Decremented By Submitted Orders", int64(90), anotherProd.Stock()},
+		{"Decrement Unsaved Product Must Be Not Found", true, isNotFound(errUnsaved)},
+		{"Failed Decrement Must Not Decrement Any Stock", int64(90), anotherProd.Stock()},
+		{"Find All Error", true, errFindAll == nil},
+		{"Find All Count", 2, len(allProducts)},
+		{"Delete Existing Product", true, errDelete == nil},
+		{"Delete Missing Product", true, isNotFound(errDeleteMissing)},
+	}
+
+	for _, test := range memoryProductTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestMemoryProductRepositoryWarehouses(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+
+	splitProd := product.New("splitProd", "Split Product")
+	splitProd.SetStatus(product.StatusAvailable)
+	splitProd.SetStocks(map[string]int64{"east": 2, "west": 3})
+	repo.Save(splitProd)
+
+	_, errSingle := repo.DecrementStocks(map[*product.Product]int{splitProd: 4}, product.SingleWarehouse)
+	allocations, errSplit := repo.DecrementStocks(map[*product.Product]int{splitProd: 4}, nil)
+	decrementedWarehouses := len(splitProd.Warehouses())
+	errIncrement := repo.IncrementStocks(allocations)
+	errInvalidIncrement := repo.IncrementStocks(order.Allocations{splitProd: {"east": 0}})
+
+	var memoryWarehouseTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Single Warehouse Decrement Failure Reason", true, nil != errSingle && errors.Is(errSingle, product.ErrInsufficientStock)},
+		{"Split Decrement Error", true, nil == errSplit},
+		{"Split East Allocation", 1, allocations[splitProd]["east"]},
+		{"Split West Allocation", 3, allocations[splitProd]["west"]},
+		{"Emptied Warehouse Must Be Removed", 1, decrementedWarehouses},
+		{"Increment Error", true, nil == errIncrement},
+		{"Incremented East Stock", int64(2), splitProd.WarehouseStock("east")},
+		{"Incremented West Stock", int64(3), splitProd.WarehouseStock("west")},
+		{"Invalid Increment Failure Reason", true, nil != errInvalidIncrement && errors.Is(errInvalidIncrement, product.ErrInvalidQuantity)},
+		{"Invalid Increment Must Not Increment Stock", int64(5), splitProd.Stock()},
+	}
+
+	for _, test := range memoryWarehouseTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestMemoryProductRepositoryCancel(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+	unsavedProd := product.New("unsavedProd", "Unsaved Product")
+	repo.Save(limitedProd)
+
+	//10 submitted orders are canceled twice while 10 other orders are submitted, concurrently
+	draftOrders := make([]*order.Order, 10)
+	submittedOrders := make([]*order.Order, 10)
+	for i := range submittedOrders {
+		draftOrders[i] = order.New(fmt.Sprintf("draftOrder%d", i))
+		draftOrders[i].AddProduct(limitedProd, 2)
+		//the inventory is set after adding the items, so the drafts hold no stock and only race at submission
+		draftOrders[i].SetInventory(repo)
+		submittedOrders[i] = order.New(fmt.Sprintf("submittedOrder%d", i))
+		submittedOrders[i].AddProduct(limitedProd, 2)
+		submittedOrders[i].SetInventory(repo)
+		submittedOrders[i].Submit("ship name", "ship address", nil)
+	}
+	var wg sync.WaitGroup
+	for i := range submittedOrders {
+		wg.Add(3)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Cancel()
+		}(submittedOrders[i])
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Cancel()
+		}(submittedOrders[i])
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Submit("ship name", "ship address", nil)
+		}(draftOrders[i])
+	}
+	wg.Wait()
+	canceled, submitted := 0, 0
+	for i := range submittedOrders {
+		if order.StatusCanceled == submittedOrders[i].Status() {
+			canceled++
+		}
+		if order.StatusSubmitted == draftOrders[i].Status() {
+			submitted++
+		}
+	}
+	stock := limitedProd.Stock()
+
+	errUnsaved := repo.IncrementStocks(order.Allocations{limitedProd: {product.DefaultWarehouse: 1}, unsavedProd: {product.DefaultWarehouse: 1}})
+	errInvalidQuantity := repo.IncrementStocks(order.Allocations{limitedProd: {product.DefaultWarehouse: 0}})
+
+	var memoryProductCancelTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Every Submitted Order Must Be Canceled", 10, canceled},
+		{"Stock Must Only Be Taken By Submitted Orders", int64(20 - 2*submitted), stock},
+		{"Increment Unsaved Product Must Be Skipped", true, nil == errUnsaved},
+		{"Increment Saved Product Stock", stock + 1, limitedProd.Stock()},
+		{"Unsaved Product Stock Must Not Be Incremented", int64(0), unsavedProd.Stock()},
+		{"Increment Invalid Quantity", true, nil != errInvalidQuantity && errors.Is(errInvalidQuantity, product.ErrInvalidQuantity)},
+	}
+
+	for _, test := range memoryProductCancelTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestMemoryCouponRepository(t *testing.T) {
+	repo := repository.NewMemoryCouponRepository()
+
+	summerCoupon := coupon.New("SUMMER10")
+	summerCoupon.SetStatus(coupon.StatusActive)
+	summerCoupon.SetStock(10)
+
+	errCreate := repo.Create(summerCoupon)
+	errCreateDuplicate := repo.Create(coupon.New("SUMMER10"))
+	errCreateDuplicateCase := repo.Create(coupon.New("summer10"))
+	errSave := repo.Save(summerCoupon)
+	errSaveMissing := repo.Save(coupon.New("WINTER10"))
+	foundByID, errFindByID := repo.FindByID("SUMMER10")
+	_, errFindByIDCase := repo.FindByID("summer10")
+	foundByCode, errFindByCode := repo.FindByCode(" summer10 ")
+	_, errFindByCodeMissing := repo.FindByCode("winter10")
+	allCoupons, _ := repo.FindAll()
+	repo.Create(coupon.New("AUTO").SetAutomatic(true))
+	promotions, _ := repo.Promotions()
+	errDeleteCase := repo.Delete("summer10")
+	errDelete := repo.Delete("SUMMER10")
+
+	var memoryCouponTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Coupon", true, errCreate == nil},
+		{"Create Duplicate Coupon", true, isDuplicate(errCreateDuplicate)},
+		{"Create Duplicate Coupon Ignoring Case", true, isDuplicate(errCreateDuplicateCase)},
+		{"Save Existing Coupon", true, errSave == nil},
+		{"Save Missing Coupon", true, isNotFound(errSaveMissing)},
+		{"Find By ID", true, errFindByID == nil && foundByID == summerCoupon},
+		{"Find By ID Is Case Sensitive", true, isNotFound(errFindByIDCase)},
+		{"Find By Code Ignoring Case And Spaces", true, errFindByCode == nil && foundByCode == summerCoupon},
+		{"Find By Missing Code", true, isNotFound(errFindByCodeMissing)},
+		{"Find All Count", 1, len(allCoupons)},
+		{"Promotions Count", 1, len(promotions)},
+		{"Promotion", "AUTO", promotions[0].ID()},
+		{"Delete By Code With Other Case", true, isNotFound(errDeleteCase)},
+		{"Delete Existing Coupon", true, errDelete == nil},
+	}
+
+	for _, test := range memoryCouponTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestMemoryCouponRepositoryRedemptions(t *testing.T) {
+	repo := repository.NewMemoryCouponRepository()
+	limitedCoupon := coupon.New("LIMITED")
+	limitedCoupon.SetUserLimit(2)
+	limitedCoupon.SetRedemptionLimit(3)
+	limitedCoupon.SetStock(10)
+	repo.Create(limitedCoupon)
+	usedUpCoupon := coupon.New("USEDUP")
+	repo.Create(usedUpCoupon)
+	redeemedDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	redeemed, limitErrors := 0, 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", fmt.Sprintf("user%d", i%2), fmt.Sprintf("order%d", i), decimal.New(10, 0), "USD", redeemedDate)})
+			mu.Lock()
+			defer mu.Unlock()
+			if nil == err {
+				redeemed++
+			} else if errors.Is(err, coupon.ErrRedemptionLimit) {
+				limitErrors++
+			}
+		}(i)
+	}
+	wg.Wait()
+	concurrentRedemptions, _ := repo.FindRedemptions("LIMITED")
+	reversedOrderID := concurrentRedemptions[0].OrderID()
+	errReverse := repo.Reverse(limitedCoupon, reversedOrderID, redeemedDate.AddDate(0, 0, 1))
+	errReverseMissing := repo.Reverse(limitedCoupon, "missingOrder", redeemedDate)
+	errAfterReversal := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", "", "order10", decimal.New(10, 0), "USD", redeemedDate)})
+	errUsedUp := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{usedUpCoupon: coupon.NewRedemption("USEDUP", "", "order11", decimal.New(10, 0), "USD", redeemedDate)})
+	usedUpRedemptions, _ := repo.FindRedemptions("USEDUP")
+	redemptions, errFind := repo.FindRedemptions("LIMITED")
+	otherRedemptions, _ := repo.FindRedemptions("OTHER")
+
+	var memoryRedemptionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Concurrent Redemptions Within Limit", 3, redeemed},
+		{"Concurrent Redemptions Over Limit Must Fail", 7, limitErrors},
+		{"Reverse Redemption", true, nil == errReverse},
+		{"Reverse Missing Redemption Is Skipped", true, nil == errReverseMissing},
+		{"Reversed Redemption Must Not Count", true, nil == errAfterReversal},
+		{"Find Redemptions", true, nil == errFind},
+		{"Reversed Redemption Is Kept", 4, len(redemptions)},
+		{"Reversed Redemption Is Reversed", true, redemptions[0].IsReversed()},
+		{"Other Coupon Redemptions", 0, len(otherRedemptions)},
+		{"Redemptions Decrement Stock And Reversal Increments It", int64(7), limitedCoupon.Stock()},
+		{"Redeem Without Stock", true, nil != errUsedUp && errors.Is(errUsedUp, coupon.ErrNoStock)},
+		{"Redeem Without Stock Records None", 0, len(usedUpRedemptions)},
+	}
+
+	for _, test := range memoryRedemptionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestMemoryUserRepository(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+
+	activeUser, _ := user.New("activeUser", "Active User", "Active User Address")
+	activeUser.Activate()
+	duplicateUser, _ := user.NewFromHash("activeUser", "Duplicate User", "Duplicate User Address", activeUser.Password())
+	missingUser, _ := user.NewFromHash("missingUser", "Missing User", "Missing User Address", activeUser.Password())
+
+	errCreate := repo.Create(activeUser)
+	errCreateDuplicate := repo.Create(duplicateUser)
+	errSave := repo.Save(activeUser)
+	errSaveMissing := repo.Save(missingUser)
+	foundUser, errFind := repo.FindByID("activeUser")
+	allUsers, _ := repo.FindAll()
+	errDelete := repo.Delete("activeUser")
+	errDeleteMissing := repo.Delete("activeUser")
+
+	var memoryUserTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create User", true, errCreate == nil},
+		{"Create Duplicate User", true, isDuplicate(errCreateDuplicate)},
+		{"Duplicate Must Not Replace User", "Active User", foundUser.Name()},
+		{"Save Existing User", true, errSave == nil},
+		{"Save Missing User", true, isNotFound(errSaveMissing)},
+		{"Find User", true, errFind == nil},
+		{"Find All Count", 1, len(allUsers)},
+		{"Delete Existing User", true, errDelete == nil},
+		{"Delete Missing User", true, isNotFound(errDeleteMissing)},
+	}
+
+	for _, test := range memoryUserTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+//isDuplicate returns whether the given repository error is a duplicate id error
+func isDuplicate(err *errors.Error) bool {
+	return err != nil && errors.Is(err, repository.ErrDuplicate)
+}
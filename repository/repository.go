@@ -0,0 +1,142 @@
+//Package repository provides the persistence interfaces of the business domain models and their in-memory implementations
+package repository
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//ErrNotFound is the error returned (wrapped) when a requested model does not exist in a repository
+var ErrNotFound = fmt.Errorf("not found")
+
+//ErrDuplicate is the error returned (wrapped) when creating a model with an id that already exists in a repository
+var ErrDuplicate = fmt.Errorf("duplicate id")
+
+//OrderRepository is interface for loading and saving orders
+type OrderRepository interface {
+	order.Canceler
+	order.Submitter
+	//Save stores an order, replacing any previously stored order with the same id
+	Save(o *order.Order) *errors.Error
+	//FindByID returns the order with the given id or an error wrapping ErrNotFound
+	FindByID(id string) (*order.Order, *errors.Error)
+	//FindByStatus returns all orders having the given status ordered by their created date
+	FindByStatus(status string) ([]*order.Order, *errors.Error)
+	//FindByUser returns all orders of the user with the given id ordered by their created date
+	FindByUser(userID string) ([]*order.Order, *errors.Error)
+	//FindByTracking returns the order having a shipment with the given carrier and tracking id or an error wrapping ErrNotFound
+	FindByTracking(carrier, trackingID string) (*order.Order, *errors.Error)
+	//Delete removes the order with the given id or returns an error wrapping ErrNotFound
+	Delete(id string) *errors.Error
+}
+
+//ProductRepository is interface for loading and saving products
+type ProductRepository interface {
+	ProductFinder
+	order.Reservations
+	//Available returns the available-to-sell stock of the product with the given id (its stock minus its unexpired holds)
+	//or an error wrapping ErrNotFound
+	Available(id string) (int64, *errors.Error)
+	//ReleaseExpired removes every hold expired at the given time and returns the number of removed holds
+	ReleaseExpired(now time.Time) (int64, *errors.Error)
+	//Save stores a product, replacing any previously stored product with the same id (its stocks included)
+	Save(p *product.Product) *errors.Error
+	//Update stores an existing product leaving its stored stocks unchanged (only changed by Save and the order.Inventory),
+	//the given product's stocks being updated to the stored ones, or returns an error wrapping ErrNotFound
+	Update(p *product.Product) *errors.Error
+	//FindAll returns all products ordered by their id
+	FindAll() ([]*product.Product, *errors.Error)
+	//Delete removes the product with the given id or returns an error wrapping ErrNotFound
+	Delete(id string) *errors.Error
+}
+
+//CouponRepository is interface for loading and saving coupons
+type CouponRepository interface {
+	CouponFinder
+	order.Ledger
+	order.PromotionProvider
+	//FindRedemptions returns the redemptions of the coupon with the given id (reversed ones included) ordered by their redeemed date
+	FindRedemptions(couponID string) ([]*coupon.Redemption, *errors.Error)
+	//Create stores a new coupon or returns an error wrapping ErrDuplicate if its id (code) is already used
+	Create(c *coupon.Coupon) *errors.Error
+	//Save stores an existing coupon (its stock included) or returns an error wrapping ErrNotFound
+	Save(c *coupon.Coupon) *errors.Error
+	//Update stores an existing coupon leaving its stored stock unchanged (only changed by Save and the order.Ledger),
+	//the given coupon's stock being updated to the stored one, or returns an error wrapping ErrNotFound
+	Update(c *coupon.Coupon) *errors.Error
+	//FindByCode returns the coupon having the given code (ignoring letter case and surrounding spaces) or an error wrapping ErrNotFound
+	FindByCode(code string) (*coupon.Coupon, *errors.Error)
+	//FindAll returns all coupons ordered by their id
+	FindAll() ([]*coupon.Coupon, *errors.Error)
+	//Delete removes the coupon with the given id or returns an error wrapping ErrNotFound
+	Delete(id string) *errors.Error
+}
+
+//UserRepository is interface for loading and saving users
+type UserRepository interface {
+	UserFinder
+	//Create stores a new user or returns an error wrapping ErrDuplicate if its id is already used
+	Create(u *user.User) *errors.Error
+	//Save stores an existing user or returns an error wrapping ErrNotFound
+	Save(u *user.User) *errors.Error
+	//FindAll returns all users ordered by their id
+	FindAll() ([]*user.User, *errors.Error)
+	//Delete removes the user with the given id or returns an error wrapping ErrNotFound
+	Delete(id string) *errors.Error
+}
+
+//Store is the set of repositories of every business domain model (shared by the API servers and tools)
+type Store struct {
+	Orders   OrderRepository
+	Products ProductRepository
+	Coupons  CouponRepository
+	Users    UserRepository
+}
+
+//NewMemoryStore creates a new store of in-memory repositories and returns a reference to it
+func NewMemoryStore() *Store {
+	products, coupons := NewMemoryProductRepository(), NewMemoryCouponRepository()
+	return &Store{
+		NewMemoryOrderRepository(products, coupons),
+		products,
+		coupons,
+		NewMemoryUserRepository(),
+	}
+}
+
+//ProductFinder is interface for looking up a product by its id (used for resolving order items)
+type ProductFinder interface {
+	FindByID(id string) (*product.Product, *errors.Error)
+}
+
+//CouponFinder is interface for looking up a coupon by its id (used for resolving an order's coupon)
+type CouponFinder interface {
+	FindByID(id string) (*coupon.Coupon, *errors.Error)
+}
+
+//UserFinder is interface for looking up a user by its id (used for resolving an order's user)
+type UserFinder interface {
+	FindByID(id string) (*user.User, *errors.Error)
+}
+
+//NotFound returns an error wrapping ErrNotFound describing the missing model
+func NotFound(kind, id string) *errors.Error {
+	return errors.WrapPrefix(ErrNotFound, fmt.Sprintf("%v with id %v", kind, id), 1)
+}
+
+//Duplicate returns an error wrapping ErrDuplicate describing the already existing model
+func Duplicate(kind, id string) *errors.Error {
+	return errors.WrapPrefix(ErrDuplicate, fmt.Sprintf("%v with id %v", kind, id), 1)
+}
+
+//NormalizeCode returns the normalized form of a coupon code (as coupon codes are compared)
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
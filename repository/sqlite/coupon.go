@@ -0,0 +1,562 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sstest/model/coupon"
+	"sstest/repository"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//CouponRepository is SQLite implementation of repository.CouponRepository
+type CouponRepository struct {
+	db *sql.DB
+}
+
+//NewCouponRepository creates a new SQLite coupon repository and returns a reference to it
+func NewCouponRepository(db *sql.DB) *CouponRepository {
+	return &CouponRepository{db}
+}
+
+//couponColumns is the list of selected coupons table columns (in the order scanned by scanCoupons)
+const couponColumns = `id, status, stock, kind, value, start_date, end_date, currency, min_subtotal, min_quantity, max_discount,
+	user_limit, redemption_limit, stackable, buy_quantity, free_quantity, automatic, priority, excludes_coupons`
+
+//Create is a function for storing a new coupon along with its applicable, excluded and free products and its spend tiers
+func (r *CouponRepository) Create(c *coupon.Coupon) *errors.Error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't create coupon %v: %v", c.ID(), err), 0)
+	}
+	_, err = tx.Exec("INSERT INTO coupons ("+couponColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		c.ID(), c.Status(), c.Stock(), c.Kind(), c.Value().String(), c.StartDate().UnixNano(), c.EndDate().UnixNano(), c.Currency(),
+		c.MinSubtotal().String(), c.MinQuantity(), c.MaxDiscount().String(), c.UserLimit(), c.RedemptionLimit(), c.Stackable(),
+		c.BuyQuantity(), c.FreeQuantity(), c.Automatic(), c.Priority(), c.ExcludesCoupons())
+	if isUniqueViolation(err) {
+		tx.Rollback()
+		return repository.Duplicate("coupon", c.ID())
+	}
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't create coupon %v: %v", c.ID(), err), 0)
+	}
+	if err := writeCouponProducts(tx, c); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't create coupon %v: %v", c.ID(), err), 0)
+	}
+	return nil
+}
+
+//Save is a function for storing an existing coupon and replacing its stored applicable, excluded and free products and its spend tiers
+func (r *CouponRepository) Save(c *coupon.Coupon) *errors.Error {
+	return r.update(c, sql.NullInt64{Int64: c.Stock(), Valid: true})
+}
+
+//Update is a function for storing an existing coupon like Save but leaving its stored stock unchanged
+func (r *CouponRepository) Update(c *coupon.Coupon) *errors.Error {
+	return r.update(c, sql.NullInt64{})
+}
+
+//update stores an existing coupon with a stock and replaces its stored products and spend tiers
+func (r *CouponRepository) update(c *coupon.Coupon, stock sql.NullInt64) *errors.Error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save coupon %v: %v", c.ID(), err), 0)
+	}
+	res, err := tx.Exec(`UPDATE coupons SET status = ?, stock = COALESCE(?, stock), kind = ?, value = ?, start_date = ?, end_date = ?,
+		currency = ?, min_subtotal = ?, min_quantity = ?, max_discount = ?, user_limit = ?, redemption_limit = ?,
+		stackable = ?, buy_quantity = ?, free_quantity = ?, automatic = ?, priority = ?, excludes_coupons = ? WHERE id = ? COLLATE BINARY`,
+		c.Status(), stock, c.Kind(), c.Value().String(), c.StartDate().UnixNano(), c.EndDate().UnixNano(), c.Currency(),
+		c.MinSubtotal().String(), c.MinQuantity(), c.MaxDiscount().String(), c.UserLimit(), c.RedemptionLimit(), c.Stackable(),
+		c.BuyQuantity(), c.FreeQuantity(), c.Automatic(), c.Priority(), c.ExcludesCoupons(), c.ID())
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save coupon %v: %v", c.ID(), err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		tx.Rollback()
+		return repository.NotFound("coupon", c.ID())
+	}
+	for _, table := range []string{"coupon_products", "coupon_free_products", "coupon_tiers"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE coupon_id = ? COLLATE BINARY", c.ID()); err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't save coupon %v products: %v", c.ID(), err), 0)
+		}
+	}
+	if err := writeCouponProducts(tx, c); err != nil {
+		tx.Rollback()
+		return err
+	}
+	var stored int64
+	if err = tx.QueryRow("SELECT stock FROM coupons WHERE id = ? COLLATE BINARY", c.ID()).Scan(&stored); err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v stock: %v", c.ID(), err), 0)
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save coupon %v: %v", c.ID(), err), 0)
+	}
+	c.SetStock(stored)
+	return nil
+}
+
+//writeCouponProducts inserts the applicable, excluded and free products and the spend tiers of a coupon
+func writeCouponProducts(tx *sql.Tx, c *coupon.Coupon) *errors.Error {
+	for excluded, productIDs := range [][]string{c.Products(), c.ExcludedProducts()} {
+		for _, productID := range productIDs {
+			_, err := tx.Exec("INSERT OR IGNORE INTO coupon_products (coupon_id, product_id, excluded) VALUES (?, ?, ?)", c.ID(), productID, excluded)
+			if err != nil {
+				return errors.Wrap(fmt.Errorf("Can't save coupon %v product %v: %v", c.ID(), productID, err), 0)
+			}
+		}
+	}
+	for _, productID := range c.FreeProducts() {
+		_, err := tx.Exec("INSERT OR IGNORE INTO coupon_free_products (coupon_id, product_id) VALUES (?, ?)", c.ID(), productID)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't save coupon %v free product %v: %v", c.ID(), productID, err), 0)
+		}
+	}
+	for _, tier := range c.Tiers() {
+		_, err := tx.Exec("INSERT INTO coupon_tiers (coupon_id, min_spend, percentage) VALUES (?, ?, ?)", c.ID(), tier.MinSpend().String(), tier.Percentage().String())
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't save coupon %v tier %v: %v", c.ID(), tier.MinSpend().String(), err), 0)
+		}
+	}
+	return nil
+}
+
+//FindByID is a function for returning the coupon with the given id
+func (r *CouponRepository) FindByID(id string) (*coupon.Coupon, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+couponColumns+" FROM coupons WHERE id = ? COLLATE BINARY", id)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find coupon %v: %v", id, err), 0)
+	}
+	coupons, findErr := r.loadCoupons(rows)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if 0 == len(coupons) {
+		return nil, repository.NotFound("coupon", id)
+	}
+	return coupons[0], nil
+}
+
+//FindByCode is a function for returning the coupon having the given code (ignoring letter case and surrounding spaces)
+func (r *CouponRepository) FindByCode(code string) (*coupon.Coupon, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+couponColumns+" FROM coupons WHERE id = ?", strings.TrimSpace(code))
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find coupon %v: %v", code, err), 0)
+	}
+	coupons, findErr := r.loadCoupons(rows)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if 0 == len(coupons) {
+		return nil, repository.NotFound("coupon", code)
+	}
+	return coupons[0], nil
+}
+
+//FindAll is a function for returning all coupons ordered by their id
+func (r *CouponRepository) FindAll() ([]*coupon.Coupon, *errors.Error) {
+	rows, err := r.db.Query("SELECT " + couponColumns + " FROM coupons ORDER BY id COLLATE BINARY")
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find coupons: %v", err), 0)
+	}
+	return r.loadCoupons(rows)
+}
+
+//Promotions is a function for returning the automatic promotions (coupons flagged automatic) ordered by their id
+func (r *CouponRepository) Promotions() ([]*coupon.Coupon, *errors.Error) {
+	rows, err := r.db.Query("SELECT " + couponColumns + " FROM coupons WHERE automatic = 1 ORDER BY id COLLATE BINARY")
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find automatic promotions: %v", err), 0)
+	}
+	return r.loadCoupons(rows)
+}
+
+//Delete is a function for removing the coupon with the given id
+func (r *CouponRepository) Delete(id string) *errors.Error {
+	res, err := r.db.Exec("DELETE FROM coupons WHERE id = ? COLLATE BINARY", id)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't delete coupon %v: %v", id, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return repository.NotFound("coupon", id)
+	}
+	return nil
+}
+
+//redemptionColumns is the list of selected coupon_redemptions columns (in the order scanned by readRedemptions)
+const redemptionColumns = "coupon_id, order_id, user_id, amount, currency, redeemed_date, reversed_date"
+
+//Redeem is a function for atomically recording the redemptions of coupons and decrementing their stocks by one use
+func (r *CouponRepository) Redeem(redemptions map[*coupon.Coupon]*coupon.Redemption) *errors.Error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't redeem coupons: %v", err), 0)
+	}
+	coupons, stocks, redeemErr := redeemCoupons(tx, redemptions)
+	if redeemErr != nil {
+		tx.Rollback()
+		return redeemErr
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't redeem coupons: %v", err), 0)
+	}
+	for i, c := range coupons {
+		c.SetStock(stocks[i])
+	}
+	return nil
+}
+
+//redeemCoupons records the redemptions of coupons and decrements their stocks by one use
+//Returns the coupons sorted by id and their decremented stocks
+func redeemCoupons(tx *sql.Tx, redemptions map[*coupon.Coupon]*coupon.Redemption) ([]*coupon.Coupon, []int64, *errors.Error) {
+	coupons := make([]*coupon.Coupon, 0, len(redemptions))
+	for c := range redemptions {
+		coupons = append(coupons, c)
+	}
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].ID() < coupons[j].ID()
+	})
+	stocks := make([]int64, len(coupons))
+	for i, c := range coupons {
+		redemption := redemptions[c]
+		var stockErr *errors.Error
+		if stocks[i], stockErr = decrementCouponStock(tx, c.ID()); stockErr != nil {
+			return nil, nil, stockErr
+		}
+		recorded, findErr := readRedemptions(tx, c.ID())
+		if findErr != nil {
+			return nil, nil, findErr
+		}
+		if ok, err := c.CanBeRedeemed(redemption.UserID(), recorded); false == ok {
+			return nil, nil, err
+		}
+		//note: a recorded redemption is unreversed
+		_, err := tx.Exec("INSERT INTO coupon_redemptions ("+redemptionColumns+") VALUES (?, ?, ?, ?, ?, ?, 0)",
+			c.ID(), redemption.OrderID(), redemption.UserID(), redemption.Amount().String(), redemption.Currency(), redemption.RedeemedDate().UnixNano())
+		if err != nil {
+			return nil, nil, errors.Wrap(fmt.Errorf("Can't redeem coupon %v by order %v: %v", c.ID(), redemption.OrderID(), err), 0)
+		}
+	}
+	return coupons, stocks, nil
+}
+
+//decrementCouponStock decrements the stock of a coupon by one use only if it has stock left (the update itself checks the stock)
+//Returns the decremented stock
+func decrementCouponStock(tx *sql.Tx, couponID string) (int64, *errors.Error) {
+	var stock int64
+	err := tx.QueryRow("SELECT stock FROM coupons WHERE id = ? COLLATE BINARY", couponID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, repository.NotFound("coupon", couponID)
+	}
+	if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("Can't read coupon %v stock: %v", couponID, err), 0)
+	}
+	res, err := tx.Exec("UPDATE coupons SET stock = stock - 1 WHERE id = ? COLLATE BINARY AND stock > 0", couponID)
+	if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("Can't decrement coupon %v stock: %v", couponID, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return 0, errors.WrapPrefix(coupon.ErrNoStock, fmt.Sprintf("Can't redeem coupon (id: %v), stock is %d (no stock)", couponID, stock), 0)
+	}
+	return stock - 1, nil
+}
+
+//Reverse is a function for atomically reversing the unreversed redemption of a coupon by an order and returning its use to the coupon's stock
+func (r *CouponRepository) Reverse(c *coupon.Coupon, orderID string, date time.Time) *errors.Error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't reverse coupon %v redemption by order %v: %v", c.ID(), orderID, err), 0)
+	}
+	stock, reversed, reverseErr := reverseRedemption(tx, c.ID(), orderID, date)
+	if reverseErr != nil {
+		tx.Rollback()
+		return reverseErr
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't reverse coupon %v redemption by order %v: %v", c.ID(), orderID, err), 0)
+	}
+	if reversed {
+		c.SetStock(stock)
+	}
+	return nil
+}
+
+//reverseRedemption marks the unreversed redemption of a coupon by an order reversed at a date and increments the coupon's stock by its use
+//Returns the incremented stock and whether a redemption was reversed (an order without redemption is skipped)
+func reverseRedemption(tx *sql.Tx, couponID, orderID string, date time.Time) (int64, bool, *errors.Error) {
+	res, err := tx.Exec("UPDATE coupon_redemptions SET reversed_date = ? WHERE coupon_id = ? AND order_id = ? AND 0 = reversed_date",
+		date.UnixNano(), couponID, orderID)
+	if err != nil {
+		return 0, false, errors.Wrap(fmt.Errorf("Can't reverse coupon %v redemption by order %v: %v", couponID, orderID, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return 0, false, nil
+	}
+	//note: a coupon no longer stored is skipped
+	if _, err := tx.Exec("UPDATE coupons SET stock = stock + 1 WHERE id = ? COLLATE BINARY", couponID); err != nil {
+		return 0, false, errors.Wrap(fmt.Errorf("Can't increment coupon %v stock: %v", couponID, err), 0)
+	}
+	var stock int64
+	err = tx.QueryRow("SELECT stock FROM coupons WHERE id = ? COLLATE BINARY", couponID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(fmt.Errorf("Can't read coupon %v stock: %v", couponID, err), 0)
+	}
+	return stock, true, nil
+}
+
+//FindRedemptions is a function for returning the redemptions of the coupon with the given id ordered by their redeemed date
+func (r *CouponRepository) FindRedemptions(couponID string) ([]*coupon.Redemption, *errors.Error) {
+	return readRedemptions(r.db, couponID)
+}
+
+//readRedemptions reads the redemptions of a coupon ordered by their redeemed date
+func readRedemptions(q queryer, couponID string) ([]*coupon.Redemption, *errors.Error) {
+	rows, err := q.Query("SELECT "+redemptionColumns+" FROM coupon_redemptions WHERE coupon_id = ? ORDER BY redeemed_date, order_id", couponID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v redemptions: %v", couponID, err), 0)
+	}
+	defer rows.Close()
+
+	redemptions := make([]*coupon.Redemption, 0)
+	for rows.Next() {
+		var couponID, orderID, userID, amount, currency string
+		var redeemed, reversed int64
+		if err := rows.Scan(&couponID, &orderID, &userID, &amount, &currency, &redeemed, &reversed); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon redemption: %v", err), 0)
+		}
+		decAmount, err := decimal.NewFromString(amount)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v redemption amount %v: %v", couponID, amount, err), 0)
+		}
+		redemption := coupon.NewRedemption(couponID, userID, orderID, decAmount, currency, time.Unix(0, redeemed))
+		if 0 != reversed {
+			redemption.SetReversedDate(time.Unix(0, reversed))
+		}
+		redemptions = append(redemptions, redemption)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v redemptions: %v", couponID, err), 0)
+	}
+	return redemptions, nil
+}
+
+//loadCoupons reads all coupon rows (closing them) and loads each coupon's applicable, excluded and free products and its spend tiers
+func (r *CouponRepository) loadCoupons(rows *sql.Rows) ([]*coupon.Coupon, *errors.Error) {
+	coupons, err := scanCoupons(rows)
+	if err != nil {
+		return nil, err
+	}
+	//note: products are loaded after the coupon rows are closed (the database has a single connection)
+	for _, c := range coupons {
+		if err := readCouponProducts(r.db, c); err != nil {
+			return nil, err
+		}
+		if err := readCouponFreeProducts(r.db, c); err != nil {
+			return nil, err
+		}
+		if err := readCouponTiers(r.db, c); err != nil {
+			return nil, err
+		}
+	}
+	return coupons, nil
+}
+
+//readCouponProducts reads the applicable and excluded products of a coupon (ordered by their id)
+func readCouponProducts(q queryer, c *coupon.Coupon) *errors.Error {
+	rows, err := q.Query("SELECT product_id, excluded FROM coupon_products WHERE coupon_id = ? COLLATE BINARY ORDER BY product_id", c.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v products: %v", c.ID(), err), 0)
+	}
+	defer rows.Close()
+
+	var products, excludedProducts []string
+	for rows.Next() {
+		var productID string
+		var excluded bool
+		if err := rows.Scan(&productID, &excluded); err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read coupon %v products: %v", c.ID(), err), 0)
+		}
+		if excluded {
+			excludedProducts = append(excludedProducts, productID)
+		} else {
+			products = append(products, productID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v products: %v", c.ID(), err), 0)
+	}
+	c.SetProducts(products...).SetExcludedProducts(excludedProducts...)
+	return nil
+}
+
+//readCouponFreeProducts reads the free products of a coupon (ordered by their id)
+func readCouponFreeProducts(q queryer, c *coupon.Coupon) *errors.Error {
+	rows, err := q.Query("SELECT product_id FROM coupon_free_products WHERE coupon_id = ? COLLATE BINARY ORDER BY product_id", c.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v free products: %v", c.ID(), err), 0)
+	}
+	defer rows.Close()
+
+	var freeProducts []string
+	for rows.Next() {
+		var productID string
+		if err := rows.Scan(&productID); err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read coupon %v free products: %v", c.ID(), err), 0)
+		}
+		freeProducts = append(freeProducts, productID)
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v free products: %v", c.ID(), err), 0)
+	}
+	c.SetFreeProducts(freeProducts...)
+	return nil
+}
+
+//readCouponTiers reads the spend tiers of a coupon
+func readCouponTiers(q queryer, c *coupon.Coupon) *errors.Error {
+	rows, err := q.Query("SELECT min_spend, percentage FROM coupon_tiers WHERE coupon_id = ? COLLATE BINARY", c.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v tiers: %v", c.ID(), err), 0)
+	}
+	defer rows.Close()
+
+	var tiers []*coupon.Tier
+	for rows.Next() {
+		var minSpend, percentage string
+		if err := rows.Scan(&minSpend, &percentage); err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read coupon %v tiers: %v", c.ID(), err), 0)
+		}
+		decMinSpend, err := decimal.NewFromString(minSpend)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read coupon %v tier minimum spend %v: %v", c.ID(), minSpend, err), 0)
+		}
+		decPercentage, err := decimal.NewFromString(percentage)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't read coupon %v tier percentage %v: %v", c.ID(), percentage, err), 0)
+		}
+		tiers = append(tiers, coupon.NewTier(decMinSpend, decPercentage))
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v tiers: %v", c.ID(), err), 0)
+	}
+	//note: the tiers are ordered by minimum spend through the coupon's setter (stored as text, the column can't order them)
+	if _, err := c.SetTiers(tiers...); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", c.ID(), err), 0)
+	}
+	return nil
+}
+
+//scanCoupons reads all coupon rows (closing them)
+func scanCoupons(rows *sql.Rows) ([]*coupon.Coupon, *errors.Error) {
+	defer rows.Close()
+
+	coupons := make([]*coupon.Coupon, 0)
+	for rows.Next() {
+		var id, status, kind, value, currency, minSubtotal, maxDiscount string
+		var stock, start, end, minQuantity, userLimit, redemptionLimit, buyQuantity, freeQuantity, priority int64
+		var stackable, automatic, excludesCoupons bool
+		if err := rows.Scan(&id, &status, &stock, &kind, &value, &start, &end, &currency, &minSubtotal, &minQuantity, &maxDiscount,
+			&userLimit, &redemptionLimit, &stackable, &buyQuantity, &freeQuantity, &automatic, &priority, &excludesCoupons); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon: %v", err), 0)
+		}
+		c, err := loadCoupon(id, status, stock, kind, value, currency, time.Unix(0, start), time.Unix(0, end))
+		if err != nil {
+			return nil, err
+		}
+		if err := loadConditions(c, minSubtotal, int(minQuantity), maxDiscount); err != nil {
+			return nil, err
+		}
+		if _, err := c.SetUserLimit(int(userLimit)); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+		}
+		if _, err := c.SetRedemptionLimit(int(redemptionLimit)); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+		}
+		c.SetStackable(stackable)
+		if _, err := c.SetBuyQuantity(int(buyQuantity)); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+		}
+		if _, err := c.SetFreeQuantity(int(freeQuantity)); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+		}
+		c.SetAutomatic(automatic).SetPriority(int(priority)).SetExcludesCoupons(excludesCoupons)
+		coupons = append(coupons, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupons: %v", err), 0)
+	}
+	return coupons, nil
+}
+
+//loadConditions sets a coupon's stored conditions (validated through the coupon's setters)
+func loadConditions(c *coupon.Coupon, minSubtotal string, minQuantity int, maxDiscount string) *errors.Error {
+	decMinSubtotal, err := decimal.NewFromString(minSubtotal)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v minimum subtotal %v: %v", c.ID(), minSubtotal, err), 0)
+	}
+	decMaxDiscount, err := decimal.NewFromString(maxDiscount)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v maximum discount %v: %v", c.ID(), maxDiscount, err), 0)
+	}
+	if _, err := c.SetMinSubtotal(decMinSubtotal); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", c.ID(), err), 0)
+	}
+	if _, err := c.SetMinQuantity(minQuantity); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", c.ID(), err), 0)
+	}
+	if _, err := c.SetMaxDiscount(decMaxDiscount); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", c.ID(), err), 0)
+	}
+	return nil
+}
+
+//loadCoupon creates a coupon from its stored values (validated through the coupon's setters)
+func loadCoupon(id, status string, stock int64, kind, value, currency string, startDate, endDate time.Time) (*coupon.Coupon, *errors.Error) {
+	decValue, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v value %v: %v", id, value, err), 0)
+	}
+	c := coupon.New(id).SetStock(stock)
+	if _, err := c.SetStatus(status); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+	}
+	//note: kind is set before value, the default value is valid for every kind
+	if _, err := c.SetKind(kind); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+	}
+	if _, err := c.SetValue(decValue); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+	}
+	if _, err := c.SetCurrency(currency); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, err), 0)
+	}
+	//note: dates are set in the order keeping start date before end date at every step
+	var dateErr *errors.Error
+	if startDate.After(c.EndDate()) {
+		if _, dateErr = c.SetEndDate(endDate); nil == dateErr {
+			_, dateErr = c.SetStartDate(startDate)
+		}
+	} else {
+		if _, dateErr = c.SetStartDate(startDate); nil == dateErr {
+			_, dateErr = c.SetEndDate(endDate)
+		}
+	}
+	if dateErr != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read coupon %v: %v", id, dateErr), 0)
+	}
+	return c, nil
+}
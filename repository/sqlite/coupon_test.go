@@ -0,0 +1,220 @@
+package sqlite_test
+
+import (
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/repository"
+	"sstest/repository/sqlite"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestCouponRepository(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	repo := sqlite.NewCouponRepository(db)
+
+	//a coupon starting after the default end date must round trip too
+	startDate := time.Now().AddDate(0, 6, 0)
+	endDate := startDate.AddDate(0, 1, 0)
+	summerCoupon := coupon.New("SUMMER10")
+	summerCoupon.SetStatus(coupon.StatusActive)
+	summerCoupon.SetStock(10)
+	summerCoupon.SetKind(coupon.KindValue)
+	summerCoupon.SetValue(decimal.New(15000, -2))
+	summerCoupon.SetCurrency("EUR")
+	summerCoupon.SetEndDate(endDate)
+	summerCoupon.SetStartDate(startDate)
+	summerCoupon.SetMinSubtotal(decimal.New(5000, -2))
+	summerCoupon.SetMinQuantity(2)
+	summerCoupon.SetProducts("shirt", "pants")
+	summerCoupon.SetExcludedProducts("pants")
+
+	errCreate := repo.Create(summerCoupon)
+	errCreateDuplicate := repo.Create(coupon.New("SUMMER10"))
+	errCreateDuplicateCase := repo.Create(coupon.New("summer10"))
+	summerCoupon.SetStock(9)
+	summerCoupon.SetMaxDiscount(decimal.New(25, 0))
+	summerCoupon.SetProducts("shirt", "hat")
+	summerCoupon.SetStackable(true)
+	summerCoupon.SetBuyQuantity(2)
+	summerCoupon.SetFreeQuantity(1)
+	summerCoupon.SetFreeProducts("socks")
+	summerCoupon.SetTiers(coupon.NewTier(decimal.New(200, 0), decimal.New(10, 0)), coupon.NewTier(decimal.New(50, 0), decimal.New(5, 0)))
+	summerCoupon.SetAutomatic(true).SetPriority(7).SetExcludesCoupons(true)
+	errSave := repo.Save(summerCoupon)
+	errSaveMissing := repo.Save(coupon.New("WINTER10"))
+	//a coupon loaded before a redemption and updated after it keeps the decremented stock
+	staleCoupon, _ := repo.FindByID("SUMMER10")
+	redeemedCoupon, _ := repo.FindByID("SUMMER10")
+	repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{redeemedCoupon: coupon.NewRedemption("SUMMER10", "user1", "order1", decimal.New(10, 0), "EUR", startDate)})
+	staleCoupon.SetMinQuantity(3)
+	errUpdate := repo.Update(staleCoupon)
+	errUpdateMissing := repo.Update(coupon.New("WINTER10"))
+	foundByID, errFindByID := repo.FindByID("SUMMER10")
+	_, errFindByIDCase := repo.FindByID("summer10")
+	foundByCode, errFindByCode := repo.FindByCode(" summer10 ")
+	_, errFindByCodeMissing := repo.FindByCode("winter10")
+	allCoupons, _ := repo.FindAll()
+	promotions, _ := repo.Promotions()
+	errDeleteCase := repo.Delete("summer10")
+	errDelete := repo.Delete("SUMMER10")
+
+	if errFindByID != nil || errFindByCode != nil {
+		t.Fatalf("can't find coupon: %v %v", errFindByID, errFindByCode)
+	}
+
+	var couponRepositoryTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Coupon", true, errCreate == nil},
+		{"Create Duplicate Coupon", true, nil != errCreateDuplicate && errors.Is(errCreateDuplicate, repository.ErrDuplicate)},
+		{"Create Duplicate Coupon Ignoring Case", true, nil != errCreateDuplicateCase && errors.Is(errCreateDuplicateCase, repository.ErrDuplicate)},
+		{"Save Existing Coupon", true, errSave == nil},
+		{"Save Missing Coupon", true, nil != errSaveMissing && errors.Is(errSaveMissing, repository.ErrNotFound)},
+		{"Find By ID Is Case Sensitive", true, nil != errFindByIDCase && errors.Is(errFindByIDCase, repository.ErrNotFound)},
+		{"Find By Code Ignoring Case And Spaces", "SUMMER10", foundByCode.ID()},
+		{"Find By Missing Code", true, nil != errFindByCodeMissing && errors.Is(errFindByCodeMissing, repository.ErrNotFound)},
+		{"Round Trip Status", summerCoupon.Status(), foundByID.Status()},
+		{"Update Existing Coupon", true, errUpdate == nil},
+		{"Update Missing Coupon", true, nil != errUpdateMissing && errors.Is(errUpdateMissing, repository.ErrNotFound)},
+		{"Update Keeps Stored Stock", int64(8), foundByID.Stock()},
+		{"Updated Coupon Stock Kept In Sync", int64(8), staleCoupon.Stock()},
+		{"Round Trip Kind", summerCoupon.Kind(), foundByID.Kind()},
+		{"Round Trip Value", summerCoupon.Value().String(), foundByID.Value().String()},
+		{"Round Trip Currency", "EUR", foundByID.Currency()},
+		{"Round Trip Start Date", startDate.UnixNano(), foundByID.StartDate().UnixNano()},
+		{"Round Trip End Date", endDate.UnixNano(), foundByID.EndDate().UnixNano()},
+		{"Round Trip Minimum Subtotal", "50", foundByID.MinSubtotal().String()},
+		{"Round Trip Minimum Quantity", 3, foundByID.MinQuantity()},
+		{"Round Trip Maximum Discount", "25", foundByID.MaxDiscount().String()},
+		{"Round Trip Saved Products", "hat,shirt", strings.Join(foundByID.Products(), ",")},
+		{"Round Trip Excluded Products", "pants", strings.Join(foundByID.ExcludedProducts(), ",")},
+		{"Round Trip Stackable", true, foundByID.Stackable()},
+		{"Round Trip Buy Quantity", 2, foundByID.BuyQuantity()},
+		{"Round Trip Free Quantity", 1, foundByID.FreeQuantity()},
+		{"Round Trip Free Products", "socks", strings.Join(foundByID.FreeProducts(), ",")},
+		{"Round Trip Tiers Count", 2, len(foundByID.Tiers())},
+		{"Round Trip Tiers Ordered By Minimum Spend", "50", foundByID.Tiers()[0].MinSpend().String()},
+		{"Round Trip Tier Percentage", "10", foundByID.Tiers()[1].Percentage().String()},
+		{"Round Trip Automatic", true, foundByID.Automatic()},
+		{"Round Trip Priority", 7, foundByID.Priority()},
+		{"Round Trip Excludes Coupons", true, foundByID.ExcludesCoupons()},
+		{"Find By Code Loads Products", 2, len(foundByCode.Products())},
+		{"Promotions Count", 1, len(promotions)},
+		{"Find All Count", 1, len(allCoupons)},
+		{"Delete By Code With Other Case", true, nil != errDeleteCase && errors.Is(errDeleteCase, repository.ErrNotFound)},
+		{"Delete Existing Coupon", true, errDelete == nil},
+	}
+
+	for _, test := range couponRepositoryTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponRedemptions(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	repo := sqlite.NewCouponRepository(db)
+
+	limitedCoupon := coupon.New("LIMITED")
+	limitedCoupon.SetUserLimit(1)
+	limitedCoupon.SetRedemptionLimit(2)
+	limitedCoupon.SetStock(10)
+	repo.Create(limitedCoupon)
+	usedUpCoupon := coupon.New("USEDUP")
+	repo.Create(usedUpCoupon)
+	lastUseCoupon := coupon.New("LASTUSE")
+	lastUseCoupon.SetStock(1)
+	repo.Create(lastUseCoupon)
+	redeemedDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	reversedDate := redeemedDate.AddDate(0, 0, 1)
+
+	errFirst := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", "user1", "order1", decimal.New(10, 0), "EUR", redeemedDate)})
+	errSameUser := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", "user1", "order2", decimal.New(10, 0), "EUR", redeemedDate)})
+	errOtherUser := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", "user2", "order3", decimal.New(10, 0), "EUR", redeemedDate)})
+	errOverLimit := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", "", "order4", decimal.New(10, 0), "EUR", redeemedDate)})
+	errReverse := repo.Reverse(limitedCoupon, "order1", reversedDate)
+	errReverseMissing := repo.Reverse(limitedCoupon, "order9", reversedDate)
+	errAfterReversal := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{limitedCoupon: coupon.NewRedemption("LIMITED", "user1", "order5", decimal.New(10, 0), "EUR", redeemedDate.AddDate(0, 0, 2))})
+	//the limited coupon is redeemed as many times as its limit allows, none of the stacked coupons' redemptions must be recorded
+	otherCoupon := coupon.New("OTHER")
+	otherCoupon.SetStock(1)
+	repo.Create(otherCoupon)
+	errStackedOverLimit := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{
+		limitedCoupon: coupon.NewRedemption("LIMITED", "user3", "order6", decimal.New(10, 0), "EUR", redeemedDate),
+		otherCoupon:   coupon.NewRedemption("OTHER", "user3", "order6", decimal.New(5, 0), "EUR", redeemedDate),
+	})
+	errUsedUp := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{usedUpCoupon: coupon.NewRedemption("USEDUP", "user1", "order7", decimal.New(10, 0), "EUR", redeemedDate)})
+	//the last use is taken once even when the coupon is loaded (with its stock) by both submissions before either redeems it
+	firstLoaded, _ := repo.FindByID("LASTUSE")
+	secondLoaded, _ := repo.FindByID("LASTUSE")
+	errFirstLoaded := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{firstLoaded: coupon.NewRedemption("LASTUSE", "user1", "order8", decimal.New(10, 0), "EUR", redeemedDate)})
+	errSecondLoaded := repo.Redeem(map[*coupon.Coupon]*coupon.Redemption{secondLoaded: coupon.NewRedemption("LASTUSE", "user2", "order9", decimal.New(10, 0), "EUR", redeemedDate)})
+	otherRedemptions, _ := repo.FindRedemptions("OTHER")
+	usedUpRedemptions, _ := repo.FindRedemptions("USEDUP")
+	redemptions, errFind := repo.FindRedemptions("LIMITED")
+	found, _ := repo.FindByID("LIMITED")
+	foundOther, _ := repo.FindByID("OTHER")
+
+	if errFind != nil || 3 != len(redemptions) {
+		t.Fatalf("can't find 3 redemptions: %v %v", len(redemptions), errFind)
+	}
+
+	var couponRedemptionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"First Redemption", true, nil == errFirst},
+		{"Same User Over User Limit", true, nil != errSameUser && errors.Is(errSameUser, coupon.ErrRedemptionLimit)},
+		{"Other User", true, nil == errOtherUser},
+		{"Over Redemption Limit", true, nil != errOverLimit && errors.Is(errOverLimit, coupon.ErrRedemptionLimit)},
+		{"Reverse", true, nil == errReverse},
+		{"Reverse Without Redemption", true, nil == errReverseMissing},
+		{"Redeem After Reversal", true, nil == errAfterReversal},
+		{"Stacked Redemptions Over Limit", true, nil != errStackedOverLimit && errors.Is(errStackedOverLimit, coupon.ErrRedemptionLimit)},
+		{"Stacked Redemptions Over Limit Record None", 0, len(otherRedemptions)},
+		{"Stacked Redemptions Over Limit Decrement No Stock", int64(1), foundOther.Stock()},
+		{"Redeem Without Stock", true, nil != errUsedUp && errors.Is(errUsedUp, coupon.ErrNoStock)},
+		{"Redeem Without Stock Records None", 0, len(usedUpRedemptions)},
+		{"Last Use Redeemed", true, nil == errFirstLoaded},
+		{"Last Use Redeemed Twice Must Fail", true, nil != errSecondLoaded && errors.Is(errSecondLoaded, coupon.ErrNoStock)},
+		{"Last Use Redeemed Stock", int64(0), firstLoaded.Stock()},
+		{"Redemptions Decrement Stock And Reversal Increments It", int64(8), found.Stock()},
+		{"Redeemed Coupon Stock Is Updated", int64(8), limitedCoupon.Stock()},
+		{"Round Trip Order", "order1", redemptions[0].OrderID()},
+		{"Round Trip User", "user1", redemptions[0].UserID()},
+		{"Round Trip Amount", "10", redemptions[0].Amount().String()},
+		{"Round Trip Currency", "EUR", redemptions[0].Currency()},
+		{"Round Trip Redeemed Date", redeemedDate.UnixNano(), redemptions[0].RedeemedDate().UnixNano()},
+		{"Round Trip Reversed Date", reversedDate.UnixNano(), redemptions[0].ReversedDate().UnixNano()},
+		{"Unreversed Redemption", false, redemptions[1].IsReversed()},
+		{"Round Trip User Limit", 1, found.UserLimit()},
+		{"Round Trip Redemption Limit", 2, found.RedemptionLimit()},
+	}
+
+	for _, test := range couponRedemptionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,649 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//OrderRepository is SQLite implementation of repository.OrderRepository
+type OrderRepository struct {
+	db       *sql.DB
+	products repository.ProductFinder
+	coupons  repository.CouponFinder
+	users    repository.UserFinder
+}
+
+//NewOrderRepository creates a new SQLite order repository and returns a reference to it
+func NewOrderRepository(db *sql.DB, products repository.ProductFinder, coupons repository.CouponFinder, users repository.UserFinder) *OrderRepository {
+	return &OrderRepository{db, products, coupons, users}
+}
+
+//orderColumns is the list of selected orders table columns (in the order scanned by scanOrder)
+const orderColumns = `id, created_date, submitted_date, processed_date, status, amount,
+	shipping_name, shipping_address, shipping_status, shipping_tracking_id, user_id, shipping_cost, shipping_region, tax_included,
+	subtotal, discount, currency`
+
+//Save is a function for storing an order and replacing its stored items and shipments
+func (r *OrderRepository) Save(o *order.Order) *errors.Error {
+	currency := ""
+	if o.HasCurrency() {
+		currency = o.Currency()
+	}
+	var userID sql.NullString
+	if o.User() != nil {
+		userID = sql.NullString{String: o.User().ID(), Valid: true}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save order %v: %v", o.ID(), err), 0)
+	}
+	res, err := tx.Exec(`INSERT INTO orders (`+orderColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			created_date = excluded.created_date,
+			submitted_date = excluded.submitted_date,
+			processed_date = excluded.processed_date,
+			status = excluded.status,
+			amount = excluded.amount,
+			shipping_name = excluded.shipping_name,
+			shipping_address = excluded.shipping_address,
+			shipping_status = excluded.shipping_status,
+			shipping_tracking_id = excluded.shipping_tracking_id,
+			user_id = excluded.user_id,
+			shipping_cost = excluded.shipping_cost,
+			shipping_region = excluded.shipping_region,
+			tax_included = excluded.tax_included,
+			subtotal = excluded.subtotal,
+			discount = excluded.discount,
+			currency = excluded.currency
+		WHERE '' = ? OR orders.status = ?`,
+		o.ID(), o.CreatedDate().UnixNano(), o.SubmittedDate().UnixNano(), o.ProcessedDate().UnixNano(), o.Status(),
+		o.Amount().String(), o.ShippingName(), o.ShippingAddress(), o.ShippingStatus(), o.ShippingTrackingID(), userID,
+		o.ShippingCost().String(), o.ShippingRegion(), o.TaxIncluded(), o.Subtotal().String(), o.Discount().String(),
+		currency, o.StoredStatus(), o.StoredStatus())
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save order %v: %v", o.ID(), err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		tx.Rollback()
+		return errors.WrapPrefix(order.ErrInvalidStatus, fmt.Sprintf("Can't save order %v, it's no longer stored with status %v", o.ID(), o.StoredStatus()), 0)
+	}
+	if _, err = tx.Exec("DELETE FROM order_coupons WHERE order_id = ?", o.ID()); err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save order %v coupons: %v", o.ID(), err), 0)
+	}
+	coupons, promotions := o.AppliedCoupons(), o.AppliedPromotions()
+	for seq, applied := range append(coupons, promotions...) {
+		_, err = tx.Exec("INSERT INTO order_coupons (order_id, seq, coupon_id, discount, automatic) VALUES (?, ?, ?, ?, ?)",
+			o.ID(), seq, applied.Coupon().ID(), applied.Discount().String(), len(coupons) <= seq)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't save order %v coupon %v: %v", o.ID(), applied.Coupon().ID(), err), 0)
+		}
+	}
+	if _, err = tx.Exec("DELETE FROM order_items WHERE order_id = ?", o.ID()); err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save order %v items: %v", o.ID(), err), 0)
+	}
+	//note: the items' prices are stored as priced (zero until the order is submitted),
+	//the captured product name and status are stored empty (and the tax category standard) until the order is submitted
+	lines := make(map[string]*order.Line, len(o.Items()))
+	for _, line := range o.Breakdown().Lines() {
+		lines[line.ProductID()] = line
+	}
+	for productID, item := range o.Items() {
+		name, status, taxCategory := "", "", product.TaxCategoryStandard
+		if item.Captured() {
+			name, status, taxCategory = item.ProductName(), item.ProductStatus(), item.TaxCategory()
+		}
+		line := lines[productID]
+		_, err = tx.Exec(`INSERT INTO order_items (id, order_id, product_id, quantity, unit_price, subtotal, discount, product_name, product_status,
+			tax_category, exchange_rate, rate_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, item.ID(), o.ID(), item.Product().ID(), item.Quantity(),
+			line.UnitPrice().String(), line.Subtotal().String(), line.Discount().String(), name, status, taxCategory,
+			line.ExchangeRate().String(), line.RateDate().UnixNano())
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't save order %v item %v: %v", o.ID(), item.ID(), err), 0)
+		}
+		for warehouse, quantity := range item.Allocation() {
+			_, err = tx.Exec("INSERT INTO order_item_allocations (item_id, warehouse_id, quantity) VALUES (?, ?, ?)", item.ID(), warehouse, quantity)
+			if err != nil {
+				tx.Rollback()
+				return errors.Wrap(fmt.Errorf("Can't save order %v item %v allocation: %v", o.ID(), item.ID(), err), 0)
+			}
+		}
+		for seq, tax := range item.Taxes() {
+			_, err = tx.Exec("INSERT INTO order_item_taxes (item_id, seq, name, rate, amount) VALUES (?, ?, ?, ?, ?)",
+				item.ID(), seq, tax.Name(), tax.Rate().String(), tax.Amount().String())
+			if err != nil {
+				tx.Rollback()
+				return errors.Wrap(fmt.Errorf("Can't save order %v item %v tax: %v", o.ID(), item.ID(), err), 0)
+			}
+		}
+	}
+	if _, err = tx.Exec("DELETE FROM shipments WHERE order_id = ?", o.ID()); err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save order %v shipments: %v", o.ID(), err), 0)
+	}
+	for _, shipment := range o.Shipments() {
+		_, err = tx.Exec(`INSERT INTO shipments (id, order_id, carrier, tracking_id, status, shipped_date, delivered_date)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, shipment.ID(), o.ID(), shipment.Carrier(), shipment.TrackingID(), shipment.Status(),
+			shipment.ShippedDate().UnixNano(), shipment.DeliveredDate().UnixNano())
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't save order %v shipment %v: %v", o.ID(), shipment.ID(), err), 0)
+		}
+		for productID, quantity := range shipment.Items() {
+			_, err = tx.Exec("INSERT INTO shipment_items (shipment_id, product_id, quantity) VALUES (?, ?, ?)", shipment.ID(), productID, quantity)
+			if err != nil {
+				tx.Rollback()
+				return errors.Wrap(fmt.Errorf("Can't save order %v shipment %v items: %v", o.ID(), shipment.ID(), err), 0)
+			}
+		}
+		for seq, e := range shipment.Events() {
+			_, err = tx.Exec("INSERT INTO shipment_events (shipment_id, seq, kind, date, location, description) VALUES (?, ?, ?, ?, ?, ?)",
+				shipment.ID(), seq, e.Kind(), e.Date().UnixNano(), e.Location(), e.Description())
+			if err != nil {
+				tx.Rollback()
+				return errors.Wrap(fmt.Errorf("Can't save order %v shipment %v events: %v", o.ID(), shipment.ID(), err), 0)
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save order %v: %v", o.ID(), err), 0)
+	}
+	o.SetStoredStatus(o.Status())
+	return nil
+}
+
+//FindByID is a function for returning the order with the given id
+func (r *OrderRepository) FindByID(id string) (*order.Order, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+orderColumns+" FROM orders WHERE id = ?", id)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find order %v: %v", id, err), 0)
+	}
+	orders, findErr := r.scanOrders(rows)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if 0 == len(orders) {
+		return nil, repository.NotFound("order", id)
+	}
+	return orders[0], nil
+}
+
+//FindByStatus is a function for returning all orders having the given status ordered by their created date
+func (r *OrderRepository) FindByStatus(status string) ([]*order.Order, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+orderColumns+" FROM orders WHERE status = ? ORDER BY created_date", status)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find orders with status %v: %v", status, err), 0)
+	}
+	return r.scanOrders(rows)
+}
+
+//FindByUser is a function for returning all orders of the user with the given id ordered by their created date
+func (r *OrderRepository) FindByUser(userID string) ([]*order.Order, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+orderColumns+" FROM orders WHERE user_id = ? ORDER BY created_date", userID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find orders of user %v: %v", userID, err), 0)
+	}
+	return r.scanOrders(rows)
+}
+
+//FindByTracking is a function for returning the order having a shipment with the given carrier and tracking id
+func (r *OrderRepository) FindByTracking(carrier, trackingID string) (*order.Order, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+orderColumns+` FROM orders
+		WHERE id = (SELECT order_id FROM shipments WHERE carrier = ? AND tracking_id = ? LIMIT 1)`, carrier, trackingID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find order of shipment %v/%v: %v", carrier, trackingID, err), 0)
+	}
+	orders, findErr := r.scanOrders(rows)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if 0 == len(orders) {
+		return nil, repository.NotFound("order shipment", fmt.Sprintf("%v/%v", carrier, trackingID))
+	}
+	return orders[0], nil
+}
+
+//Cancel is a function for atomically changing the stored status of an order from a status to another only if it's still stored with the former
+func (r *OrderRepository) Cancel(orderID, from, to string, allocations order.Allocations, coupons []*coupon.Coupon, date time.Time) *errors.Error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't cancel order %v: %v", orderID, err), 0)
+	}
+	if statusErr := changeStatus(tx, orderID, from, to); statusErr != nil {
+		tx.Rollback()
+		return statusErr
+	}
+	products := allocations.Products()
+	stocks, incrementErr := incrementStocks(tx, products, allocations)
+	if incrementErr != nil {
+		tx.Rollback()
+		return incrementErr
+	}
+	couponStocks := make([]int64, len(coupons))
+	reversed := make([]bool, len(coupons))
+	for i, c := range coupons {
+		var reverseErr *errors.Error
+		if couponStocks[i], reversed[i], reverseErr = reverseRedemption(tx, c.ID(), orderID, date); reverseErr != nil {
+			tx.Rollback()
+			return reverseErr
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't cancel order %v: %v", orderID, err), 0)
+	}
+	setStocks(products, stocks)
+	for i, c := range coupons {
+		if reversed[i] {
+			c.SetStock(couponStocks[i])
+		}
+	}
+	return nil
+}
+
+//Submit is a function for atomically changing the stored status of an order from a status to another only if it's still stored with the former
+func (r *OrderRepository) Submit(orderID, from, to string, quantities map[*product.Product]int, allocate product.Allocator,
+	redemptions map[*coupon.Coupon]*coupon.Redemption) (order.Allocations, *errors.Error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't submit order %v: %v", orderID, err), 0)
+	}
+	if statusErr := changeStatus(tx, orderID, from, to); statusErr != nil {
+		tx.Rollback()
+		return nil, statusErr
+	}
+	products, allocations, stocks, takeErr := takeStocks(tx, orderID, quantities, allocate)
+	if takeErr != nil {
+		tx.Rollback()
+		return nil, takeErr
+	}
+	coupons, couponStocks, redeemErr := redeemCoupons(tx, redemptions)
+	if redeemErr != nil {
+		tx.Rollback()
+		return nil, redeemErr
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't submit order %v: %v", orderID, err), 0)
+	}
+	setStocks(products, stocks)
+	for i, c := range coupons {
+		c.SetStock(couponStocks[i])
+	}
+	return allocations, nil
+}
+
+//changeStatus changes the stored status of an order from a status to another only if it's still stored with the former
+func changeStatus(tx *sql.Tx, orderID, from, to string) *errors.Error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM orders WHERE id = ?", orderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return repository.NotFound("order", orderID)
+	}
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't change order %v status: %v", orderID, err), 0)
+	}
+	res, err := tx.Exec("UPDATE orders SET status = ? WHERE id = ? AND status = ?", to, orderID, from)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't change order %v status: %v", orderID, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return errors.WrapPrefix(order.ErrInvalidStatus, fmt.Sprintf("Can't change order %v status to %v, its stored status is %v (not %v)", orderID, to, status, from), 0)
+	}
+	return nil
+}
+
+//Delete is a function for removing the order (and its items and shipments) with the given id
+func (r *OrderRepository) Delete(id string) *errors.Error {
+	res, err := r.db.Exec("DELETE FROM orders WHERE id = ?", id)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't delete order %v: %v", id, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return repository.NotFound("order", id)
+	}
+	return nil
+}
+
+//scanOrders reads all order rows (closing them) and loads each order's coupons, user, items and shipments
+func (r *OrderRepository) scanOrders(rows *sql.Rows) ([]*order.Order, *errors.Error) {
+	type orderRow struct {
+		order  *order.Order
+		status string
+		userID sql.NullString
+	}
+
+	orderRows := make([]orderRow, 0)
+	for rows.Next() {
+		var id, status, amount, shipName, shipAddress, derivedShipStatus, derivedTrackingID, shippingCost, shipRegion, subtotal, discount, currency string
+		var created, submitted, processed int64
+		var taxIncluded bool
+		var userID sql.NullString
+		if err := rows.Scan(&id, &created, &submitted, &processed, &status, &amount,
+			&shipName, &shipAddress, &derivedShipStatus, &derivedTrackingID, &userID, &shippingCost, &shipRegion, &taxIncluded,
+			&subtotal, &discount, &currency); err != nil {
+			rows.Close()
+			return nil, errors.Wrap(fmt.Errorf("Can't read order: %v", err), 0)
+		}
+		decAmount, err := decimal.NewFromString(amount)
+		if err != nil {
+			rows.Close()
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v amount %v: %v", id, amount, err), 0)
+		}
+		decShippingCost, err := decimal.NewFromString(shippingCost)
+		if err != nil {
+			rows.Close()
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v shipping cost %v: %v", id, shippingCost, err), 0)
+		}
+		decSubtotal, err := decimal.NewFromString(subtotal)
+		if err != nil {
+			rows.Close()
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v subtotal %v: %v", id, subtotal, err), 0)
+		}
+		decDiscount, err := decimal.NewFromString(discount)
+		if err != nil {
+			rows.Close()
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v discount %v: %v", id, discount, err), 0)
+		}
+		o := order.New(id)
+		o.SetCreatedDate(time.Unix(0, created)).
+			SetSubmittedDate(time.Unix(0, submitted)).
+			SetProcessedDate(time.Unix(0, processed)).
+			SetAmount(decAmount).
+			SetShippingCost(decShippingCost).
+			SetSubtotal(decSubtotal).
+			SetDiscount(decDiscount).
+			SetTaxIncluded(taxIncluded).
+			SetShippingName(shipName).
+			SetShippingAddress(shipAddress).
+			SetShippingRegion(shipRegion).
+			SetCurrency(currency)
+		orderRows = append(orderRows, orderRow{o, status, userID})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, errors.Wrap(fmt.Errorf("Can't read orders: %v", err), 0)
+	}
+	rows.Close()
+
+	//note: items, shipments, coupons and user are loaded after the order rows are closed (the database has a single connection)
+	orders := make([]*order.Order, 0, len(orderRows))
+	for _, row := range orderRows {
+		if err := r.loadItems(row.order); err != nil {
+			return nil, err
+		}
+		if err := r.loadShipments(row.order); err != nil {
+			return nil, err
+		}
+		if err := r.loadCoupons(row.order); err != nil {
+			return nil, err
+		}
+		if row.userID.Valid {
+			if nil == r.users {
+				return nil, errors.Wrap(fmt.Errorf("Can't load order %v user %v: no user finder", row.order.ID(), row.userID.String), 0)
+			}
+			u, err := r.users.FindByID(row.userID.String)
+			if err != nil {
+				return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't load order %v user", row.order.ID()), 0)
+			}
+			row.order.SetUser(u)
+		}
+		//note: status is set last, after the items are in place
+		if _, err := row.order.SetStatus(row.status); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v: %v", row.order.ID(), err), 0)
+		}
+		row.order.SetStoredStatus(row.status)
+		orders = append(orders, row.order)
+	}
+	return orders, nil
+}
+
+//loadCoupons reads the stored coupons and automatic promotions applied on an order (in their application order) and resolves them
+func (r *OrderRepository) loadCoupons(o *order.Order) *errors.Error {
+	rows, err := r.db.Query("SELECT coupon_id, discount, automatic FROM order_coupons WHERE order_id = ? ORDER BY seq", o.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't load order %v coupons: %v", o.ID(), err), 0)
+	}
+	type couponRow struct {
+		couponID  string
+		discount  decimal.Decimal
+		automatic bool
+	}
+	couponRows := make([]couponRow, 0)
+	for rows.Next() {
+		var couponID, discount string
+		var automatic bool
+		if err := rows.Scan(&couponID, &discount, &automatic); err != nil {
+			rows.Close()
+			return errors.Wrap(fmt.Errorf("Can't load order %v coupons: %v", o.ID(), err), 0)
+		}
+		decDiscount, err := decimal.NewFromString(discount)
+		if err != nil {
+			rows.Close()
+			return errors.Wrap(fmt.Errorf("Can't read order %v coupon %v discount %v: %v", o.ID(), couponID, discount, err), 0)
+		}
+		couponRows = append(couponRows, couponRow{couponID, decDiscount, automatic})
+	}
+	rows.Close()
+
+	applied, promotions := make([]*order.AppliedCoupon, 0, len(couponRows)), make([]*order.AppliedCoupon, 0)
+	for _, row := range couponRows {
+		if nil == r.coupons {
+			return errors.Wrap(fmt.Errorf("Can't load order %v coupon %v: no coupon finder", o.ID(), row.couponID), 0)
+		}
+		c, err := r.coupons.FindByID(row.couponID)
+		if err != nil {
+			return errors.WrapPrefix(err, fmt.Sprintf("Can't load order %v coupon", o.ID()), 0)
+		}
+		if row.automatic {
+			promotions = append(promotions, order.NewAppliedCoupon(c, row.discount))
+			continue
+		}
+		applied = append(applied, order.NewAppliedCoupon(c, row.discount))
+	}
+	o.SetAppliedCoupons(applied...).SetAppliedPromotions(promotions...)
+	return nil
+}
+
+//loadItems reads the stored items of an order and resolves their products
+func (r *OrderRepository) loadItems(o *order.Order) *errors.Error {
+	rows, err := r.db.Query(`SELECT id, product_id, quantity, unit_price, subtotal, discount, product_name, product_status,
+		tax_category, exchange_rate, rate_date FROM order_items WHERE order_id = ?`, o.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't load order %v items: %v", o.ID(), err), 0)
+	}
+	type itemRow struct {
+		id          string
+		productID   string
+		quantity    int
+		prices      [4]decimal.Decimal //unit price, subtotal, discount and exchange rate
+		name        string
+		status      string
+		taxCategory string
+		rateDate    int64
+	}
+	itemRows := make([]itemRow, 0)
+	for rows.Next() {
+		var row itemRow
+		var prices [4]string
+		if err := rows.Scan(&row.id, &row.productID, &row.quantity, &prices[0], &prices[1], &prices[2], &row.name, &row.status,
+			&row.taxCategory, &prices[3], &row.rateDate); err != nil {
+			rows.Close()
+			return errors.Wrap(fmt.Errorf("Can't load order %v items: %v", o.ID(), err), 0)
+		}
+		for i, price := range prices {
+			dec, err := decimal.NewFromString(price)
+			if err != nil {
+				rows.Close()
+				return errors.Wrap(fmt.Errorf("Can't read order %v item %v price %v: %v", o.ID(), row.id, price, err), 0)
+			}
+			row.prices[i] = dec
+		}
+		itemRows = append(itemRows, row)
+	}
+	rows.Close()
+
+	allocations, allocErr := r.loadAllocations(o)
+	if allocErr != nil {
+		return allocErr
+	}
+	taxes, taxErr := r.loadTaxes(o)
+	if taxErr != nil {
+		return taxErr
+	}
+	for _, row := range itemRows {
+		if nil == r.products {
+			return errors.Wrap(fmt.Errorf("Can't load order %v item %v: no product finder", o.ID(), row.id), 0)
+		}
+		p, err := r.products.FindByID(row.productID)
+		if err != nil {
+			return errors.WrapPrefix(err, fmt.Sprintf("Can't load order %v item %v", o.ID(), row.id), 0)
+		}
+		o.Items()[p.ID()] = order.NewItem(row.id, o, p).SetQuantity(row.quantity).SetAllocation(allocations[row.id]).SetTaxes(taxes[row.id]).
+			SetUnitPrice(row.prices[0]).SetSubtotal(row.prices[1]).SetDiscount(row.prices[2]).SetProductName(row.name).SetProductStatus(row.status).
+			SetTaxCategory(row.taxCategory).SetExchangeRate(row.prices[3]).SetRateDate(time.Unix(0, row.rateDate))
+	}
+	return nil
+}
+
+//loadTaxes reads the stored taxes of the items of an order (in their rules' order), keyed by item id
+func (r *OrderRepository) loadTaxes(o *order.Order) (map[string][]*order.Tax, *errors.Error) {
+	rows, err := r.db.Query(`SELECT t.item_id, t.name, t.rate, t.amount FROM order_item_taxes t
+		JOIN order_items i ON i.id = t.item_id WHERE i.order_id = ? ORDER BY t.item_id, t.seq`, o.ID())
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't load order %v taxes: %v", o.ID(), err), 0)
+	}
+	defer rows.Close()
+
+	taxes := make(map[string][]*order.Tax)
+	for rows.Next() {
+		var itemID, name, rate, amount string
+		if err := rows.Scan(&itemID, &name, &rate, &amount); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't load order %v taxes: %v", o.ID(), err), 0)
+		}
+		decRate, err := decimal.NewFromString(rate)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v tax %v rate %v: %v", o.ID(), name, rate, err), 0)
+		}
+		decAmount, err := decimal.NewFromString(amount)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read order %v tax %v amount %v: %v", o.ID(), name, amount, err), 0)
+		}
+		taxes[itemID] = append(taxes[itemID], order.NewTax(name, decRate, decAmount))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't load order %v taxes: %v", o.ID(), err), 0)
+	}
+	return taxes, nil
+}
+
+//loadAllocations reads the stored warehouse allocations of the items of an order, keyed by item id
+func (r *OrderRepository) loadAllocations(o *order.Order) (map[string]product.Allocation, *errors.Error) {
+	rows, err := r.db.Query(`SELECT a.item_id, a.warehouse_id, a.quantity FROM order_item_allocations a
+		JOIN order_items i ON i.id = a.item_id WHERE i.order_id = ?`, o.ID())
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't load order %v allocations: %v", o.ID(), err), 0)
+	}
+	defer rows.Close()
+
+	allocations := make(map[string]product.Allocation)
+	for rows.Next() {
+		var itemID, warehouse string
+		var quantity int
+		if err := rows.Scan(&itemID, &warehouse, &quantity); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't load order %v allocations: %v", o.ID(), err), 0)
+		}
+		if nil == allocations[itemID] {
+			allocations[itemID] = make(product.Allocation)
+		}
+		allocations[itemID][warehouse] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't load order %v allocations: %v", o.ID(), err), 0)
+	}
+	return allocations, nil
+}
+
+//loadShipments reads the stored shipments of an order (in the order they were shipped) with their items and tracking events
+func (r *OrderRepository) loadShipments(o *order.Order) *errors.Error {
+	rows, err := r.db.Query(`SELECT s.id, s.carrier, s.tracking_id, s.status, s.shipped_date, s.delivered_date, i.product_id, i.quantity
+		FROM shipments s JOIN shipment_items i ON i.shipment_id = s.id WHERE s.order_id = ? ORDER BY s.shipped_date, s.id`, o.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't load order %v shipments: %v", o.ID(), err), 0)
+	}
+
+	shipments := make([]*order.Shipment, 0)
+	for rows.Next() {
+		var id, carrier, trackingID, status, productID string
+		var shipped, delivered int64
+		var quantity int
+		if err := rows.Scan(&id, &carrier, &trackingID, &status, &shipped, &delivered, &productID, &quantity); err != nil {
+			rows.Close()
+			return errors.Wrap(fmt.Errorf("Can't load order %v shipments: %v", o.ID(), err), 0)
+		}
+		if 0 == len(shipments) || id != shipments[len(shipments)-1].ID() {
+			s, statusErr := order.NewShipment(id, carrier, trackingID).SetStatus(status)
+			if statusErr != nil {
+				rows.Close()
+				return errors.Wrap(fmt.Errorf("Can't load order %v shipment %v: %v", o.ID(), id, statusErr), 0)
+			}
+			shipments = append(shipments, s.SetShippedDate(time.Unix(0, shipped)).SetDeliveredDate(time.Unix(0, delivered)))
+		}
+		if _, quantityErr := shipments[len(shipments)-1].SetQuantity(productID, quantity); quantityErr != nil {
+			rows.Close()
+			return errors.WrapPrefix(quantityErr, fmt.Sprintf("Can't load order %v shipment %v", o.ID(), id), 0)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return errors.Wrap(fmt.Errorf("Can't load order %v shipments: %v", o.ID(), err), 0)
+	}
+	rows.Close()
+
+	events, eventsErr := r.loadEvents(o)
+	if eventsErr != nil {
+		return eventsErr
+	}
+	for _, s := range shipments {
+		if shipmentEvents, ok := events[s.ID()]; ok {
+			s.SetEvents(shipmentEvents)
+		}
+	}
+	o.SetShipments(shipments)
+	return nil
+}
+
+//loadEvents reads the stored tracking events of the shipments of an order (ordered by their date), keyed by shipment id
+func (r *OrderRepository) loadEvents(o *order.Order) (map[string][]*order.TrackingEvent, *errors.Error) {
+	rows, err := r.db.Query(`SELECT e.shipment_id, e.kind, e.date, e.location, e.description FROM shipment_events e
+		JOIN shipments s ON s.id = e.shipment_id WHERE s.order_id = ? ORDER BY e.shipment_id, e.seq`, o.ID())
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't load order %v tracking events: %v", o.ID(), err), 0)
+	}
+	defer rows.Close()
+
+	events := make(map[string][]*order.TrackingEvent)
+	for rows.Next() {
+		var shipmentID, kind, location, description string
+		var date int64
+		if err := rows.Scan(&shipmentID, &kind, &date, &location, &description); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't load order %v tracking events: %v", o.ID(), err), 0)
+		}
+		events[shipmentID] = append(events[shipmentID], order.NewTrackingEvent(kind, time.Unix(0, date), location, description))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't load order %v tracking events: %v", o.ID(), err), 0)
+	}
+	return events, nil
+}
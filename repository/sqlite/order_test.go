@@ -0,0 +1,536 @@
+//sqlite_test provides unit tests for the SQLite repositories
+package sqlite_test
+
+import (
+	"database/sql"
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"sstest/repository/sqlite"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//productMap is a map based repository.ProductFinder
+type productMap map[string]*product.Product
+
+func (m productMap) FindByID(id string) (*product.Product, *errors.Error) {
+	if p, ok := m[id]; ok {
+		return p, nil
+	}
+	return nil, repository.NotFound("product", id)
+}
+
+//couponMap is a map based repository.CouponFinder and order.PromotionProvider
+type couponMap map[string]*coupon.Coupon
+
+func (m couponMap) FindByID(id string) (*coupon.Coupon, *errors.Error) {
+	if c, ok := m[id]; ok {
+		return c, nil
+	}
+	return nil, repository.NotFound("coupon", id)
+}
+
+func (m couponMap) Promotions() ([]*coupon.Coupon, *errors.Error) {
+	promotions := make([]*coupon.Coupon, 0)
+	for _, c := range m {
+		if c.Automatic() {
+			promotions = append(promotions, c)
+		}
+	}
+	return promotions, nil
+}
+
+//userMap is a map based repository.UserFinder
+type userMap map[string]*user.User
+
+func (m userMap) FindByID(id string) (*user.User, *errors.Error) {
+	if u, ok := m[id]; ok {
+		return u, nil
+	}
+	return nil, repository.NotFound("user", id)
+}
+
+func TestMigrate(t *testing.T) {
+	db, err := sqlite.Open(":memory:")
+	if err != nil {
+		t.Fatalf("can't open database: %v", err)
+	}
+	defer db.Close()
+
+	version, _ := sqlite.SchemaVersion(db)
+	t.Run("Open Must Migrate To Latest Version", func(t *testing.T) {
+		if 0 == version {
+			t.Errorf("want schema version more than %v, got %v", 0, version)
+		}
+	})
+	t.Run("Migrate Must Be Idempotent", func(t *testing.T) {
+		if err := sqlite.Migrate(db); err != nil {
+			t.Errorf("want %v for error, got %v", nil, err)
+		}
+		if againVersion, _ := sqlite.SchemaVersion(db); version != againVersion {
+			t.Errorf("want %v for schema version, got %v", version, againVersion)
+		}
+	})
+}
+
+func TestMigrateCapturesSubmittedItems(t *testing.T) {
+	db, openErr := sql.Open("sqlite3", ":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := sqlite.MigrateTo(db, 19); err != nil {
+		t.Fatalf("can't migrate database: %v", err)
+	}
+	//an order submitted (and one left draft) before the items captured their product's values
+	for _, stmt := range []string{
+		`INSERT INTO products (id, name, status, price, stock, tax_category) VALUES ('oldProd', 'Old Name', 'A', '100', 10, 'standard'),
+			('centProd', 'Cent Name', 'A', '12.35', 10, 'reduced')`,
+		`INSERT INTO orders (id, created_date, submitted_date, processed_date, status, amount, shipping_name, shipping_address, shipping_status, shipping_tracking_id)
+		VALUES ('oldSubmittedOrder', 0, 0, 0, 'S', '200', 'ship name', 'ship address', 'N', ''),
+			('oldDraftOrder', 0, 0, 0, 'D', '0', '', '', 'N', '')`,
+		`INSERT INTO order_items (id, order_id, product_id, quantity) VALUES ('oldSubmittedItem', 'oldSubmittedOrder', 'oldProd', 2),
+			('centSubmittedItem', 'oldSubmittedOrder', 'centProd', 3), ('oldDraftItem', 'oldDraftOrder', 'oldProd', 1)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("can't insert rows: %v", err)
+		}
+	}
+	migrateErr := sqlite.Migrate(db)
+	unknownVersionErr := sqlite.MigrateTo(db, 1000)
+
+	//the product changed after the migration
+	changedProd := product.New("oldProd", "New Name")
+	changedProd.SetStatus(product.StatusDiscontinued)
+	changedProd.SetPrice(decimal.New(120, 0))
+	changedCentProd := product.New("centProd", "Cent Name")
+	changedCentProd.SetStatus(product.StatusAvailable)
+	changedCentProd.SetPrice(decimal.New(1235, -2))
+	changedCentProd.SetTaxCategory(product.TaxCategoryStandard)
+	repo := sqlite.NewOrderRepository(db, productMap{changedProd.ID(): changedProd, changedCentProd.ID(): changedCentProd}, couponMap{}, userMap{})
+	submittedOrder, _ := repo.FindByID("oldSubmittedOrder")
+	draftOrder, _ := repo.FindByID("oldDraftOrder")
+
+	var migrateCapturesSubmittedItemsTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Migrate Error", true, nil == migrateErr},
+		{"Unknown Version Must Fail", true, nil != unknownVersionErr},
+		{"Submitted Item Must Be Captured", true, submittedOrder.Items()["oldProd"].Captured()},
+		{"Submitted Item Unit Price", "100", submittedOrder.Items()["oldProd"].UnitPrice().String()},
+		{"Submitted Item Product Name", "Old Name", submittedOrder.Items()["oldProd"].ProductName()},
+		{"Submitted Item Product Status", product.StatusAvailable, submittedOrder.Items()["oldProd"].ProductStatus()},
+		{"Submitted Item Subtotal", "200", submittedOrder.Items()["oldProd"].Subtotal().String()},
+		{"Submitted Item Decimal Subtotal", "37.05", submittedOrder.Items()["centProd"].Subtotal().String()},
+		{"Submitted Item Tax Category", "reduced", submittedOrder.Items()["centProd"].TaxCategory()},
+		{"Draft Item Must Not Be Captured", false, draftOrder.Items()["oldProd"].Captured()},
+		{"Draft Item Unit Price", "120", draftOrder.Items()["oldProd"].UnitPrice().String()},
+		{"Draft Item Tax Category", product.TaxCategoryStandard, draftOrder.Items()["oldProd"].TaxCategory()},
+	}
+
+	for _, test := range migrateCapturesSubmittedItemsTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderRepository(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+
+	availableProd := product.New("availableProd", "Available Product")
+	availableProd.SetStatus(product.StatusAvailable)
+	availableProd.SetStock(100)
+	availableProd.SetPrice(decimal.New(100, 0))
+	availableProd.SetCurrency("EUR")
+
+	anotherAvailableProd := product.New("anotherAvailableProd", "Another Available Product")
+	anotherAvailableProd.SetStatus(product.StatusAvailable)
+	anotherAvailableProd.SetStock(50)
+	anotherAvailableProd.SetPrice(decimal.New(150, 0))
+	anotherAvailableProd.SetCurrency("EUR")
+
+	activeCoupon := coupon.New("activeCoupon")
+	activeCoupon.SetStatus(coupon.StatusActive)
+	activeCoupon.SetStock(100)
+	activeCoupon.SetKind(coupon.KindValue)
+	activeCoupon.SetValue(decimal.New(100, 0))
+	activeCoupon.SetCurrency("EUR")
+	activeCoupon.SetStackable(true)
+
+	stackedCoupon := coupon.New("stackedCoupon")
+	stackedCoupon.SetStatus(coupon.StatusActive)
+	stackedCoupon.SetStock(100)
+	stackedCoupon.SetStackable(true)
+
+	autoPromotion := coupon.New("autoPromotion").SetAutomatic(true).SetStackable(true)
+	autoPromotion.SetStatus(coupon.StatusActive)
+	coupons := couponMap{activeCoupon.ID(): activeCoupon, stackedCoupon.ID(): stackedCoupon, autoPromotion.ID(): autoPromotion}
+
+	activeUser, _ := user.New("activeUser", "Active User", "Active Address")
+	activeUser.Activate()
+
+	repo := sqlite.NewOrderRepository(db,
+		productMap{availableProd.ID(): availableProd, anotherAvailableProd.ID(): anotherAvailableProd},
+		coupons,
+		userMap{activeUser.ID(): activeUser})
+
+	submittedOrder := order.New("submittedOrder").SetUser(activeUser).SetShippingRate(order.NewFlatRate(decimal.New(499, -2), "EUR")).
+		SetTaxTable(order.NewTaxTable(order.NewTaxRegion("EU", true, order.NewTaxRule("VAT", "", decimal.New(2, -1), false)))).
+		SetShippingRegion("EU").SetPromotions(coupons)
+	submittedOrder.AddProduct(availableProd, 5)
+	submittedOrder.AddProduct(anotherAvailableProd, 3)
+	submittedOrder.Submit("ship name", "ship address", activeCoupon, stackedCoupon)
+	partialShipment := order.NewShipment("partialShipment", "dummyCarrier", "dummyTrackingNo")
+	partialShipment.SetQuantity(availableProd.ID(), 2)
+	partialShipment.SetStatus(order.ShipmentStatusInTransit)
+	partialShipment.SetEvents([]*order.TrackingEvent{
+		order.NewTrackingEvent(order.TrackingPickedUp, partialShipment.ShippedDate(), "Warehouse", ""),
+		order.NewTrackingEvent(order.TrackingInTransit, partialShipment.ShippedDate().Add(time.Hour), "Hub", "sorted"),
+	})
+	submittedOrder.SetShipments([]*order.Shipment{partialShipment})
+
+	draftOrder := order.New("draftOrder")
+	draftOrder.AddProduct(availableProd, 1)
+
+	rateDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	convertedOrder := order.New("convertedOrder").
+		SetExchangeRates(money.NewRateTable("USD", rateDate, map[string]decimal.Decimal{"EUR": decimal.New(8, -1)}))
+	convertedOrder.AddProduct(availableProd, 1)
+
+	//an empty order without currency must still take its first item's currency once loaded, an empty order with one must keep it
+	emptyOrder := order.New("emptyOrder")
+	chosenOrder := order.New("chosenOrder")
+	chosenOrder.ChangeCurrency("JPY")
+	repo.Save(emptyOrder)
+	repo.Save(chosenOrder)
+	loadedEmptyOrder, _ := repo.FindByID("emptyOrder")
+	loadedEmptyOrder.AddProduct(availableProd, 1)
+	loadedChosenOrder, _ := repo.FindByID("chosenOrder")
+	_, errChosenMismatch := loadedChosenOrder.AddProduct(availableProd, 1)
+
+	errSaveSubmitted := repo.Save(submittedOrder)
+	errSaveConverted := repo.Save(convertedOrder)
+	errSaveDraft := repo.Save(draftOrder)
+	//saving again must replace the stored order and items
+	draftOrder.EditProduct(availableProd, 2)
+	draftOrder.AddProduct(anotherAvailableProd, 1)
+	errSaveDraftAgain := repo.Save(draftOrder)
+	//the submitted order's items must keep the values captured on submission
+	anotherAvailableProd.SetName("Renamed Product")
+	anotherAvailableProd.SetPrice(decimal.New(175, 0))
+	anotherAvailableProd.SetStatus(product.StatusDiscontinued)
+
+	loadedOrder, errFind := repo.FindByID("submittedOrder")
+	loadedDraftOrder, errFindDraft := repo.FindByID("draftOrder")
+	loadedConvertedOrder, errFindConverted := repo.FindByID("convertedOrder")
+	_, errFindMissing := repo.FindByID("missingOrder")
+	submittedOrders, errFindByStatus := repo.FindByStatus(order.StatusSubmitted)
+	userOrders, errFindByUser := repo.FindByUser(activeUser.ID())
+	trackedOrder, errFindByTracking := repo.FindByTracking("dummyCarrier", "dummyTrackingNo")
+	_, errFindByTrackingMissing := repo.FindByTracking("otherCarrier", "dummyTrackingNo")
+
+	var orderRepositoryTests = []struct {
+		testCase         string
+		expectedErrIsNil bool
+		actualErrIsNil   bool
+	}{
+		{"Save Submitted Order", true, errSaveSubmitted == nil},
+		{"Save Draft Order", true, errSaveDraft == nil},
+		{"Save Converted Order", true, errSaveConverted == nil},
+		{"Save Draft Order Again", true, errSaveDraftAgain == nil},
+		{"Find Submitted Order", true, errFind == nil},
+		{"Find Draft Order", true, errFindDraft == nil},
+		{"Find Converted Order", true, errFindConverted == nil},
+		{"Find Missing Order", false, errFindMissing == nil},
+		{"Find Orders By Status", true, errFindByStatus == nil},
+		{"Find Orders By User", true, errFindByUser == nil},
+		{"Find Order By Tracking", true, errFindByTracking == nil},
+		{"Find Order By Tracking Of Other Carrier", false, errFindByTrackingMissing == nil},
+	}
+
+	for _, test := range orderRepositoryTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedErrIsNil != test.actualErrIsNil {
+				t.Errorf("want %v for error, got %v", test.expectedErrIsNil, test.actualErrIsNil)
+			}
+		})
+	}
+	if errFind != nil || errFindDraft != nil || errFindConverted != nil || errFindByTracking != nil {
+		t.FailNow()
+	}
+
+	var roundTripTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Status", submittedOrder.Status(), loadedOrder.Status()},
+		{"Created Date", submittedOrder.CreatedDate().UnixNano(), loadedOrder.CreatedDate().UnixNano()},
+		{"Submitted Date", submittedOrder.SubmittedDate().UnixNano(), loadedOrder.SubmittedDate().UnixNano()},
+		{"Processed Date", submittedOrder.ProcessedDate().UnixNano(), loadedOrder.ProcessedDate().UnixNano()},
+		{"Amount", submittedOrder.Amount().String(), loadedOrder.Amount().String()},
+		{"Currency", "EUR", loadedOrder.Currency()},
+		{"Shipping Cost", "4.99", loadedOrder.ShippingCost().String()},
+		{"Shipping Region", "EU", loadedOrder.ShippingRegion()},
+		{"Tax Included", true, loadedOrder.TaxIncluded()},
+		{"Tax", submittedOrder.Tax().String(), loadedOrder.Tax().String()},
+		{"Taxes", 1, len(loadedOrder.Taxes())},
+		{"Item Tax Name", "VAT", loadedOrder.Items()["availableProd"].Taxes()[0].Name()},
+		{"Item Tax Rate", "0.2", loadedOrder.Items()["availableProd"].Taxes()[0].Rate().String()},
+		{"Item Tax Amount", submittedOrder.Items()["availableProd"].Tax().String(), loadedOrder.Items()["availableProd"].Tax().String()},
+		{"Subtotal", submittedOrder.Subtotal().String(), loadedOrder.Subtotal().String()},
+		{"Discount", submittedOrder.Discount().String(), loadedOrder.Discount().String()},
+		{"Item Unit Price", submittedOrder.Items()["availableProd"].UnitPrice().String(), loadedOrder.Items()["availableProd"].UnitPrice().String()},
+		{"Item Captured", true, loadedOrder.Items()["anotherAvailableProd"].Captured()},
+		{"Item Captured Unit Price", "150", loadedOrder.Items()["anotherAvailableProd"].UnitPrice().String()},
+		{"Item Captured Product Name", "Another Available Product", loadedOrder.Items()["anotherAvailableProd"].ProductName()},
+		{"Item Captured Product Status", product.StatusAvailable, loadedOrder.Items()["anotherAvailableProd"].ProductStatus()},
+		{"Draft Item Not Captured", false, loadedDraftOrder.Items()["anotherAvailableProd"].Captured()},
+		{"Draft Item Product Name", "Renamed Product", loadedDraftOrder.Items()["anotherAvailableProd"].ProductName()},
+		{"Item Exchange Rate", "1", loadedOrder.Items()["availableProd"].ExchangeRate().String()},
+		{"Converted Order Currency", "USD", loadedConvertedOrder.Currency()},
+		{"Loaded Order Without Currency Takes First Item Currency", "EUR", loadedEmptyOrder.Currency()},
+		{"Loaded Order Currency", "JPY", loadedChosenOrder.Currency()},
+		{"Loaded Order Currency Must Not Be Replaced", true, nil != errChosenMismatch && errors.Is(errChosenMismatch, order.ErrCurrencyMismatch)},
+		{"Converted Item Exchange Rate", "1.25", loadedConvertedOrder.Items()["availableProd"].ExchangeRate().String()},
+		{"Converted Item Rate Date", rateDate.UnixNano(), loadedConvertedOrder.Items()["availableProd"].RateDate().UnixNano()},
+		{"Converted Item Unit Price", "125", loadedConvertedOrder.Items()["availableProd"].UnitPrice().String()},
+		{"Item Subtotal", submittedOrder.Items()["availableProd"].Subtotal().String(), loadedOrder.Items()["availableProd"].Subtotal().String()},
+		{"Item Discount", submittedOrder.Items()["availableProd"].Discount().String(), loadedOrder.Items()["availableProd"].Discount().String()},
+		{"Breakdown Total Must Be Amount", loadedOrder.Amount().String(), loadedOrder.Breakdown().Total().String()},
+		{"Coupon Count", 2, len(loadedOrder.Coupons())},
+		{"Coupon Application Order", stackedCoupon, loadedOrder.Coupons()[0]},
+		{"Coupon", activeCoupon, loadedOrder.Coupons()[1]},
+		{"Coupon Discount", submittedOrder.AppliedCoupons()[0].Discount().String(), loadedOrder.AppliedCoupons()[0].Discount().String()},
+		{"Promotion Count", 1, len(loadedOrder.AppliedPromotions())},
+		{"Promotion", autoPromotion, loadedOrder.AppliedPromotions()[0].Coupon()},
+		{"Promotion Discount", submittedOrder.AppliedPromotions()[0].Discount().String(), loadedOrder.AppliedPromotions()[0].Discount().String()},
+		{"User", activeUser, loadedOrder.User()},
+		{"Shipping Name", submittedOrder.ShippingName(), loadedOrder.ShippingName()},
+		{"Shipping Address", submittedOrder.ShippingAddress(), loadedOrder.ShippingAddress()},
+		{"Shipping Status", submittedOrder.ShippingStatus(), loadedOrder.ShippingStatus()},
+		{"Shipping Tracking ID", submittedOrder.ShippingTrackingID(), loadedOrder.ShippingTrackingID()},
+		{"Partially Shipped Status", order.ShipStatusPartiallyShipped, loadedOrder.ShippingStatus()},
+		{"Shipment Count", 1, len(loadedOrder.Shipments())},
+		{"Shipment Carrier", "dummyCarrier", loadedOrder.Shipments()[0].Carrier()},
+		{"Shipment Status", order.ShipmentStatusInTransit, loadedOrder.Shipments()[0].Status()},
+		{"Shipment Shipped Date", partialShipment.ShippedDate().UnixNano(), loadedOrder.Shipments()[0].ShippedDate().UnixNano()},
+		{"Shipment Quantity", 2, loadedOrder.Shipments()[0].Items()[availableProd.ID()]},
+		{"Shipment Event Count", 2, len(loadedOrder.Shipments()[0].Events())},
+		{"Shipment Latest Event", order.TrackingInTransit, loadedOrder.Shipments()[0].Events()[1].Kind()},
+		{"Shipment Event Date", partialShipment.Events()[1].Date().UnixNano(), loadedOrder.Shipments()[0].Events()[1].Date().UnixNano()},
+		{"Shipment Event Location", "Hub", loadedOrder.Shipments()[0].Events()[1].Location()},
+		{"Shipment Event Description", "sorted", loadedOrder.Shipments()[0].Events()[1].Description()},
+		{"Order By Tracking", "submittedOrder", trackedOrder.ID()},
+		{"Unshipped Quantity", 3, loadedOrder.Unshipped()[availableProd.ID()]},
+		{"Draft Shipment Count", 0, len(loadedDraftOrder.Shipments())},
+		{"Item Count", len(submittedOrder.Items()), len(loadedOrder.Items())},
+		{"Item Quantity", submittedOrder.Items()[availableProd.ID()].Quantity(), loadedOrder.Items()[availableProd.ID()].Quantity()},
+		{"Item ID", submittedOrder.Items()[availableProd.ID()].ID(), loadedOrder.Items()[availableProd.ID()].ID()},
+		{"Item Product", availableProd, loadedOrder.Items()[availableProd.ID()].Product()},
+		{"Item Order", loadedOrder, loadedOrder.Items()[availableProd.ID()].Order()},
+		{"Draft Coupon Count", 0, len(loadedDraftOrder.Coupons())},
+		{"Draft Promotion Count", 0, len(loadedDraftOrder.AppliedPromotions())},
+		{"Draft User", (*user.User)(nil), loadedDraftOrder.User()},
+		{"Draft Item Count", 2, len(loadedDraftOrder.Items())},
+		{"Draft Edited Item Quantity", 3, loadedDraftOrder.Items()[availableProd.ID()].Quantity()},
+		{"Orders By Status Count", 1, len(submittedOrders)},
+		{"Orders By User Count", 1, len(userOrders)},
+	}
+
+	for _, test := range roundTripTests {
+		t.Run(fmt.Sprintf("Round Trip %s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+
+	errDelete := repo.Delete("submittedOrder")
+	errDeleteMissing := repo.Delete("submittedOrder")
+	_, errFindDeleted := repo.FindByID("submittedOrder")
+
+	t.Run("Delete Existing Order", func(t *testing.T) {
+		if errDelete != nil {
+			t.Errorf("want %v for error, got %v", nil, errDelete)
+		}
+	})
+	t.Run("Delete Missing Order", func(t *testing.T) {
+		if nil == errDeleteMissing || false == errors.Is(errDeleteMissing, repository.ErrNotFound) {
+			t.Errorf("want not found error, got %v", errDeleteMissing)
+		}
+	})
+	t.Run("Find Deleted Order", func(t *testing.T) {
+		if nil == errFindDeleted || false == errors.Is(errFindDeleted, repository.ErrNotFound) {
+			t.Errorf("want not found error, got %v", errFindDeleted)
+		}
+	})
+}
+
+func TestOrderRepositoryConcurrentSubmit(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	store := sqlite.NewStore(db)
+
+	submittedProd := product.New("submittedProd", "Submitted Product")
+	submittedProd.SetStatus(product.StatusAvailable)
+	submittedProd.SetStock(10)
+	submittedProd.SetPrice(decimal.New(100, 0))
+	store.Products.Save(submittedProd)
+	submittedCoupon := coupon.New("submittedCoupon")
+	submittedCoupon.SetStatus(coupon.StatusActive)
+	submittedCoupon.SetStock(5)
+	store.Coupons.Create(submittedCoupon)
+	draftOrder := order.New("draftOrder")
+	draftOrder.AddProduct(submittedProd, 3)
+	store.Orders.Save(draftOrder)
+
+	//the same draft order is loaded several times before any load is submitted, then every load is submitted concurrently
+	const submissions = 8
+	loads := make([]*order.Order, submissions)
+	loadedCoupons := make([]*coupon.Coupon, submissions)
+	for i := range loads {
+		loads[i], _ = store.Orders.FindByID("draftOrder")
+		loadedCoupons[i], _ = store.Coupons.FindByID("submittedCoupon")
+	}
+	var wg sync.WaitGroup
+	results := make(chan *errors.Error, submissions)
+	for i := range loads {
+		wg.Add(1)
+		go func(loaded *order.Order, loadedCoupon *coupon.Coupon) {
+			defer wg.Done()
+			_, err := loaded.SetInventory(store.Products).SetLedger(store.Coupons).SetSubmitter(store.Orders).Submit("ship name", "ship address", loadedCoupon)
+			if nil == err {
+				err = store.Orders.Save(loaded)
+			}
+			results <- err
+		}(loads[i], loadedCoupons[i])
+	}
+	wg.Wait()
+	close(results)
+	submitted, rejected := 0, 0
+	for err := range results {
+		if nil == err {
+			submitted++
+		} else if errors.Is(err, order.ErrInvalidStatus) {
+			rejected++
+		}
+	}
+	storedOrder, _ := store.Orders.FindByID("draftOrder")
+	storedProd, _ := store.Products.FindByID("submittedProd")
+	storedCoupon, _ := store.Coupons.FindByID("submittedCoupon")
+	redemptions, _ := store.Coupons.FindRedemptions("submittedCoupon")
+	errMissing := func() *errors.Error {
+		_, err := store.Orders.Submit("missingOrder", order.StatusDraft, order.StatusSubmitted, map[*product.Product]int{}, nil, nil)
+		return err
+	}()
+
+	var orderRepositoryConcurrentSubmitTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submitted Once", 1, submitted},
+		{"Other Submissions Must Fail", submissions - 1, rejected},
+		{"Stored Status", order.StatusSubmitted, storedOrder.Status()},
+		{"Product Stock Taken Once", int64(7), storedProd.Stock()},
+		{"Coupon Stock Taken Once", int64(4), storedCoupon.Stock()},
+		{"Coupon Redeemed Once", 1, len(redemptions)},
+		{"Submit Missing Order", true, nil != errMissing && errors.Is(errMissing, repository.ErrNotFound)},
+	}
+
+	for _, test := range orderRepositoryConcurrentSubmitTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderRepositoryCancel(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	store := sqlite.NewStore(db)
+
+	canceledProd := product.New("canceledProd", "Canceled Product")
+	canceledProd.SetStatus(product.StatusAvailable)
+	canceledProd.SetStock(10)
+	canceledProd.SetPrice(decimal.New(100, 0))
+	store.Products.Save(canceledProd)
+	canceledCoupon := coupon.New("canceledCoupon")
+	canceledCoupon.SetStatus(coupon.StatusActive)
+	canceledCoupon.SetStock(5)
+	store.Coupons.Create(canceledCoupon)
+
+	submittedOrder := order.New("submittedOrder").SetInventory(store.Products).SetLedger(store.Coupons)
+	submittedOrder.AddProduct(canceledProd, 3)
+	submittedOrder.Submit("ship name", "ship address", canceledCoupon)
+	store.Orders.Save(submittedOrder)
+	submittedProd, _ := store.Products.FindByID("canceledProd")
+	submittedCoupon, _ := store.Coupons.FindByID("canceledCoupon")
+
+	//the same submitted order is loaded twice before either load is canceled
+	firstLoaded, _ := store.Orders.FindByID("submittedOrder")
+	secondLoaded, _ := store.Orders.FindByID("submittedOrder")
+	firstOk, errFirst := firstLoaded.SetCanceler(store.Orders).Cancel()
+	store.Orders.Save(firstLoaded)
+	secondOk, errSecond := secondLoaded.SetCanceler(store.Orders).Cancel()
+	//saving the stale load must not undo the cancellation
+	errStale := store.Orders.Save(secondLoaded)
+	canceledOrder, _ := store.Orders.FindByID("submittedOrder")
+	storedProd, _ := store.Products.FindByID("canceledProd")
+	storedCoupon, _ := store.Coupons.FindByID("canceledCoupon")
+	errMissing := store.Orders.Cancel("missingOrder", order.StatusSubmitted, order.StatusCanceled, order.Allocations{}, nil, time.Now())
+
+	var orderRepositoryCancelTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submission Takes Product Stock", int64(7), submittedProd.Stock()},
+		{"Submission Takes Coupon Stock", int64(4), submittedCoupon.Stock()},
+		{"First Cancel", true, firstOk && nil == errFirst},
+		{"Second Cancel Of Stale Order Must Fail", true, false == secondOk && nil != errSecond && errors.Is(errSecond, order.ErrInvalidStatus)},
+		{"Stale Order Status Is Kept", order.StatusSubmitted, secondLoaded.Status()},
+		{"Stale Order Save Must Fail", true, nil != errStale && errors.Is(errStale, order.ErrInvalidStatus)},
+		{"Stored Status", order.StatusCanceled, canceledOrder.Status()},
+		{"Product Stock Returned Once", int64(10), storedProd.Stock()},
+		{"Coupon Stock Returned Once", int64(5), storedCoupon.Stock()},
+		{"Canceled Order Products Kept In Sync", int64(10), firstLoaded.Items()["canceledProd"].Product().Stock()},
+		{"Cancel Missing Order", true, nil != errMissing && errors.Is(errMissing, repository.ErrNotFound)},
+	}
+
+	for _, test := range orderRepositoryCancelTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//ProductRepository is SQLite implementation of repository.ProductRepository
+type ProductRepository struct {
+	db *sql.DB
+}
+
+//NewProductRepository creates a new SQLite product repository and returns a reference to it
+func NewProductRepository(db *sql.DB) *ProductRepository {
+	return &ProductRepository{db}
+}
+
+//Save is a function for storing a product and replacing its stored warehouse stocks
+func (r *ProductRepository) Save(p *product.Product) *errors.Error {
+	stocks := p.Stocks()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save product %v: %v", p.ID(), err), 0)
+	}
+	length, width, height := p.Dimensions()
+	_, err = tx.Exec(`INSERT INTO products (id, name, status, price, stock, weight, length, width, height, tax_category, currency)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			status = excluded.status,
+			price = excluded.price,
+			stock = excluded.stock,
+			weight = excluded.weight,
+			length = excluded.length,
+			width = excluded.width,
+			height = excluded.height,
+			tax_category = excluded.tax_category,
+			currency = excluded.currency`,
+		p.ID(), p.Name(), p.Status(), p.Price().String(), total(stocks), p.Weight().String(), length.String(), width.String(), height.String(),
+		p.TaxCategory(), p.Currency())
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save product %v: %v", p.ID(), err), 0)
+	}
+	if _, err = tx.Exec("DELETE FROM product_stocks WHERE product_id = ?", p.ID()); err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't save product %v stocks: %v", p.ID(), err), 0)
+	}
+	for warehouse, stock := range stocks {
+		_, err = tx.Exec("INSERT INTO product_stocks (product_id, warehouse_id, stock) VALUES (?, ?, ?)", p.ID(), warehouse, stock)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't save product %v stock in warehouse %v: %v", p.ID(), warehouse, err), 0)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save product %v: %v", p.ID(), err), 0)
+	}
+	return nil
+}
+
+//Update is a function for storing an existing product leaving its stored stocks unchanged
+func (r *ProductRepository) Update(p *product.Product) *errors.Error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't update product %v: %v", p.ID(), err), 0)
+	}
+	length, width, height := p.Dimensions()
+	res, err := tx.Exec(`UPDATE products SET name = ?, status = ?, price = ?, weight = ?, length = ?, width = ?, height = ?, tax_category = ?, currency = ?
+		WHERE id = ?`,
+		p.Name(), p.Status(), p.Price().String(), p.Weight().String(), length.String(), width.String(), height.String(), p.TaxCategory(), p.Currency(), p.ID())
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't update product %v: %v", p.ID(), err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		tx.Rollback()
+		return repository.NotFound("product", p.ID())
+	}
+	stocks, readErr := readStocks(tx, p.ID())
+	if readErr != nil {
+		tx.Rollback()
+		return readErr
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't update product %v: %v", p.ID(), err), 0)
+	}
+	p.SetStocks(stocks)
+	return nil
+}
+
+//FindByID is a function for returning the product with the given id
+func (r *ProductRepository) FindByID(id string) (*product.Product, *errors.Error) {
+	rows, err := r.db.Query("SELECT "+productColumns+" FROM products WHERE id = ?", id)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find product %v: %v", id, err), 0)
+	}
+	products, findErr := r.loadProducts(rows)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if 0 == len(products) {
+		return nil, repository.NotFound("product", id)
+	}
+	return products[0], nil
+}
+
+//FindAll is a function for returning all products ordered by their id
+func (r *ProductRepository) FindAll() ([]*product.Product, *errors.Error) {
+	rows, err := r.db.Query("SELECT " + productColumns + " FROM products ORDER BY id")
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find products: %v", err), 0)
+	}
+	return r.loadProducts(rows)
+}
+
+//Delete is a function for removing the product with the given id
+func (r *ProductRepository) Delete(id string) *errors.Error {
+	res, err := r.db.Exec("DELETE FROM products WHERE id = ?", id)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't delete product %v: %v", id, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return repository.NotFound("product", id)
+	}
+	return nil
+}
+
+//productColumns is the ordered list of products columns read by scanProducts
+const productColumns = "id, name, status, price, stock, weight, length, width, height, tax_category, currency"
+
+//heldQuantity is the SQL expression of the quantity of a product held by the unexpired holds of the other orders than a given one
+const heldQuantity = `(SELECT COALESCE(SUM(quantity), 0) FROM reservations
+	WHERE product_id = products.id AND order_id <> ? AND expiry_date > ?)`
+
+//DecrementStocks is a function for atomically decrementing the stored available-to-sell stock of the given products, all or none of them
+func (r *ProductRepository) DecrementStocks(quantities map[*product.Product]int, allocate product.Allocator) (order.Allocations, *errors.Error) {
+	return r.decrementStocks("", quantities, allocate)
+}
+
+//ConvertHolds is a function for atomically decrementing the stored stock of the given products and removing the order's holds
+func (r *ProductRepository) ConvertHolds(orderID string, quantities map[*product.Product]int, allocate product.Allocator) (order.Allocations, *errors.Error) {
+	return r.decrementStocks(orderID, quantities, allocate)
+}
+
+//decrementStocks decrements the stored stock of the given products inside a single transaction (see takeStocks)
+func (r *ProductRepository) decrementStocks(orderID string, quantities map[*product.Product]int, allocate product.Allocator) (order.Allocations, *errors.Error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't decrement product stocks: %v", err), 0)
+	}
+	products, allocations, stocks, takeErr := takeStocks(tx, orderID, quantities, allocate)
+	if takeErr != nil {
+		tx.Rollback()
+		return nil, takeErr
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't decrement product stocks: %v", err), 0)
+	}
+	setStocks(products, stocks)
+	return allocations, nil
+}
+
+//takeStocks decrements the stored stock of the given products by their quantity and removes the given order's holds
+//Returns the products sorted by id, their allocations and their decremented warehouse stocks
+func takeStocks(tx *sql.Tx, orderID string, quantities map[*product.Product]int, allocate product.Allocator) ([]*product.Product, order.Allocations, []map[string]int64, *errors.Error) {
+	products := order.SortedProducts(quantities)
+	for _, p := range products {
+		if quantities[p] <= 0 {
+			return nil, nil, nil, errors.WrapPrefix(product.ErrInvalidQuantity, fmt.Sprintf("Can't decrement product (id: %v) stock by %d", p.ID(), quantities[p]), 0)
+		}
+	}
+	allocations := make(order.Allocations, len(products))
+	stocks := make([]map[string]int64, len(products))
+	now := time.Now().UnixNano()
+	for i, p := range products {
+		var available int64
+		if err := tx.QueryRow("SELECT stock - "+heldQuantity+" FROM products WHERE id = ?", orderID, now, p.ID()).Scan(&available); err == sql.ErrNoRows {
+			return nil, nil, nil, repository.NotFound("product", p.ID())
+		} else if err != nil {
+			return nil, nil, nil, errors.Wrap(fmt.Errorf("Can't decrement product %v stock: %v", p.ID(), err), 0)
+		}
+		if available < int64(quantities[p]) {
+			return nil, nil, nil, errors.WrapPrefix(product.ErrInsufficientStock, fmt.Sprintf("Product (id: %v) available stock %d does not have enough quantity %d", p.ID(), available, quantities[p]), 0)
+		}
+		var readErr *errors.Error
+		if stocks[i], readErr = readStocks(tx, p.ID()); readErr != nil {
+			return nil, nil, nil, readErr
+		}
+		allocation, allocErr := product.Allocate(allocate, stocks[i], quantities[p])
+		if allocErr != nil {
+			return nil, nil, nil, errors.WrapPrefix(allocErr, fmt.Sprintf("Product (id: %v) stock %d can't be allocated", p.ID(), total(stocks[i])), 0)
+		}
+		for warehouse, allocated := range allocation {
+			if _, err := tx.Exec("UPDATE product_stocks SET stock = stock - ? WHERE product_id = ? AND warehouse_id = ?", allocated, p.ID(), warehouse); err != nil {
+				return nil, nil, nil, errors.Wrap(fmt.Errorf("Can't decrement product %v stock in warehouse %v: %v", p.ID(), warehouse, err), 0)
+			}
+			stocks[i][warehouse] -= int64(allocated)
+		}
+		if _, err := tx.Exec("DELETE FROM product_stocks WHERE product_id = ? AND 0 = stock", p.ID()); err != nil {
+			return nil, nil, nil, errors.Wrap(fmt.Errorf("Can't decrement product %v stock: %v", p.ID(), err), 0)
+		}
+		if _, err := tx.Exec("UPDATE products SET stock = stock - ? WHERE id = ?", quantities[p], p.ID()); err != nil {
+			return nil, nil, nil, errors.Wrap(fmt.Errorf("Can't decrement product %v stock: %v", p.ID(), err), 0)
+		}
+		allocations[p] = allocation
+	}
+	if "" != orderID {
+		if _, err := tx.Exec("DELETE FROM reservations WHERE order_id = ?", orderID); err != nil {
+			return nil, nil, nil, errors.Wrap(fmt.Errorf("Can't release order %v holds: %v", orderID, err), 0)
+		}
+	}
+	return products, allocations, stocks, nil
+}
+
+//IncrementStocks is a function for atomically incrementing the stored warehouse stocks of the given products
+func (r *ProductRepository) IncrementStocks(allocations order.Allocations) *errors.Error {
+	products := allocations.Products()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't increment product stocks: %v", err), 0)
+	}
+	stocks, incrementErr := incrementStocks(tx, products, allocations)
+	if incrementErr != nil {
+		tx.Rollback()
+		return incrementErr
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't increment product stocks: %v", err), 0)
+	}
+	setStocks(products, stocks)
+	return nil
+}
+
+//incrementStocks increments the stored warehouse stocks of the given products by their allocation
+//Returns the incremented warehouse stocks of every product
+func incrementStocks(tx *sql.Tx, products []*product.Product, allocations order.Allocations) ([]map[string]int64, *errors.Error) {
+	for _, p := range products {
+		if _, err := allocations[p].Validate(); err != nil {
+			return nil, errors.WrapPrefix(err, fmt.Sprintf("Can't increment product (id: %v) stock", p.ID()), 0)
+		}
+	}
+	stocks := make([]map[string]int64, len(products))
+	for i, p := range products {
+		res, err := tx.Exec("UPDATE products SET stock = stock + ? WHERE id = ?", allocations[p].Quantity(), p.ID())
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't increment product %v stock: %v", p.ID(), err), 0)
+		}
+		if affected, _ := res.RowsAffected(); 0 == affected {
+			continue
+		}
+		for warehouse, allocated := range allocations[p] {
+			_, err := tx.Exec(`INSERT INTO product_stocks (product_id, warehouse_id, stock) VALUES (?, ?, ?)
+				ON CONFLICT (product_id, warehouse_id) DO UPDATE SET stock = stock + excluded.stock`, p.ID(), warehouse, allocated)
+			if err != nil {
+				return nil, errors.Wrap(fmt.Errorf("Can't increment product %v stock in warehouse %v: %v", p.ID(), warehouse, err), 0)
+			}
+		}
+		var readErr *errors.Error
+		if stocks[i], readErr = readStocks(tx, p.ID()); readErr != nil {
+			return nil, readErr
+		}
+	}
+	return stocks, nil
+}
+
+//setStocks keeps given products in sync with their stored stocks (once committed, skipping products without stocks)
+func setStocks(products []*product.Product, stocks []map[string]int64) {
+	for i, p := range products {
+		if stocks[i] != nil {
+			p.SetStocks(stocks[i])
+		}
+	}
+}
+
+//Hold is a function for placing the hold of an order on a quantity of a stored product until the expiry date
+func (r *ProductRepository) Hold(orderID string, p *product.Product, quantity int, expiryDate time.Time) *errors.Error {
+	if quantity <= 0 {
+		return errors.WrapPrefix(product.ErrInvalidQuantity, fmt.Sprintf("Can't hold product (id: %v) quantity %d", p.ID(), quantity), 0)
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't hold product %v: %v", p.ID(), err), 0)
+	}
+	var available int64
+	if err := tx.QueryRow("SELECT stock - "+heldQuantity+" FROM products WHERE id = ?", orderID, time.Now().UnixNano(), p.ID()).Scan(&available); err == sql.ErrNoRows {
+		tx.Rollback()
+		return repository.NotFound("product", p.ID())
+	} else if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't hold product %v: %v", p.ID(), err), 0)
+	}
+	if available < int64(quantity) {
+		tx.Rollback()
+		return errors.WrapPrefix(product.ErrInsufficientStock, fmt.Sprintf("Product (id: %v) available stock %d does not have enough quantity %d", p.ID(), available, quantity), 0)
+	}
+	_, err = tx.Exec(`INSERT INTO reservations (order_id, product_id, quantity, expiry_date) VALUES (?, ?, ?, ?)
+		ON CONFLICT (order_id, product_id) DO UPDATE SET
+			quantity = excluded.quantity,
+			expiry_date = excluded.expiry_date`,
+		orderID, p.ID(), quantity, expiryDate.UnixNano())
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(fmt.Errorf("Can't hold product %v: %v", p.ID(), err), 0)
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't hold product %v: %v", p.ID(), err), 0)
+	}
+	return nil
+}
+
+//Release is a function for removing the hold of an order on a product (or every hold of the order for an empty product id)
+func (r *ProductRepository) Release(orderID, productID string) *errors.Error {
+	_, err := r.db.Exec("DELETE FROM reservations WHERE order_id = ? AND (? = '' OR product_id = ?)", orderID, productID, productID)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't release order %v holds: %v", orderID, err), 0)
+	}
+	return nil
+}
+
+//Available is a function for returning the available-to-sell stock of the product with the given id
+func (r *ProductRepository) Available(id string) (int64, *errors.Error) {
+	var available int64
+	if err := r.db.QueryRow("SELECT stock - "+heldQuantity+" FROM products WHERE id = ?", "", time.Now().UnixNano(), id).Scan(&available); err == sql.ErrNoRows {
+		return 0, repository.NotFound("product", id)
+	} else if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("Can't find product %v available stock: %v", id, err), 0)
+	}
+	return available, nil
+}
+
+//ReleaseExpired is a function for removing every hold expired at the given time
+func (r *ProductRepository) ReleaseExpired(now time.Time) (int64, *errors.Error) {
+	res, err := r.db.Exec("DELETE FROM reservations WHERE expiry_date <= ?", now.UnixNano())
+	if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("Can't release expired holds: %v", err), 0)
+	}
+	released, _ := res.RowsAffected()
+	return released, nil
+}
+
+//queryer is the query function shared by a database and a transaction
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+//readStocks reads the stored stock of a product in every warehouse having stock
+func readStocks(q queryer, productID string) (map[string]int64, *errors.Error) {
+	rows, err := q.Query("SELECT warehouse_id, stock FROM product_stocks WHERE product_id = ? AND stock > 0", productID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read product %v stocks: %v", productID, err), 0)
+	}
+	defer rows.Close()
+
+	stocks := make(map[string]int64)
+	for rows.Next() {
+		var warehouse string
+		var stock int64
+		if err := rows.Scan(&warehouse, &stock); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v stocks: %v", productID, err), 0)
+		}
+		stocks[warehouse] = stock
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read product %v stocks: %v", productID, err), 0)
+	}
+	return stocks, nil
+}
+
+//total returns the total of warehouse stocks
+func total(stocks map[string]int64) int64 {
+	var stock int64
+	for _, warehouseStock := range stocks {
+		stock += warehouseStock
+	}
+	return stock
+}
+
+//loadProducts reads all product rows (closing them) and loads each product's warehouse stocks
+func (r *ProductRepository) loadProducts(rows *sql.Rows) ([]*product.Product, *errors.Error) {
+	products, err := scanProducts(rows)
+	if err != nil {
+		return nil, err
+	}
+	//note: stocks are loaded after the product rows are closed (the database has a single connection)
+	for _, p := range products {
+		stocks, err := readStocks(r.db, p.ID())
+		if err != nil {
+			return nil, err
+		}
+		p.SetStocks(stocks)
+	}
+	return products, nil
+}
+
+//scanProducts reads all product rows (closing them)
+func scanProducts(rows *sql.Rows) ([]*product.Product, *errors.Error) {
+	defer rows.Close()
+
+	products := make([]*product.Product, 0)
+	for rows.Next() {
+		var id, name, status, price, weight, length, width, height, taxCategory, currency string
+		var stock int64 //note: the total stock, replaced by the warehouse stocks once loaded
+		if err := rows.Scan(&id, &name, &status, &price, &stock, &weight, &length, &width, &height, &taxCategory, &currency); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product: %v", err), 0)
+		}
+		decPrice, err := decimal.NewFromString(price)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v price %v: %v", id, price, err), 0)
+		}
+		sizes := make([]decimal.Decimal, 4)
+		for i, size := range []string{weight, length, width, height} {
+			if sizes[i], err = decimal.NewFromString(size); err != nil {
+				return nil, errors.Wrap(fmt.Errorf("Can't read product %v shipping size %v: %v", id, size, err), 0)
+			}
+		}
+		p := product.New(id, name).SetStock(stock)
+		if _, err := p.SetPrice(decPrice); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v: %v", id, err), 0)
+		}
+		if _, err := p.SetWeight(sizes[0]); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v: %v", id, err), 0)
+		}
+		if _, err := p.SetDimensions(sizes[1], sizes[2], sizes[3]); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v: %v", id, err), 0)
+		}
+		if _, err := p.SetTaxCategory(taxCategory); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v: %v", id, err), 0)
+		}
+		if _, err := p.SetCurrency(currency); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v: %v", id, err), 0)
+		}
+		if _, err := p.SetStatus(status); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read product %v: %v", id, err), 0)
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read products: %v", err), 0)
+	}
+	return products, nil
+}
@@ -0,0 +1,419 @@
+package sqlite_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"sstest/repository/sqlite"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestProductRepository(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "sstest")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "store.db")
+
+	//two databases opened on the same file act as two processes sharing the store
+	db1, openErr := sqlite.Open(path)
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db1.Close()
+	db2, openErr := sqlite.Open(path)
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db2.Close()
+	repo1 := sqlite.NewProductRepository(db1)
+	repo2 := sqlite.NewProductRepository(db2)
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(12550, -2))
+	limitedProd.SetWeight(decimal.New(125, -2))
+	limitedProd.SetDimensions(decimal.New(30, 0), decimal.New(20, 0), decimal.New(105, -1))
+	limitedProd.SetTaxCategory("reduced")
+	limitedProd.SetCurrency("EUR")
+	anotherProd := product.New("anotherProd", "Another Product")
+	anotherProd.SetStatus(product.StatusAvailable)
+	anotherProd.SetStock(100)
+	anotherProd.SetPrice(decimal.New(50, 0))
+	anotherProd.SetCurrency("EUR")
+
+	errSave := repo1.Save(limitedProd)
+	repo1.Save(anotherProd)
+
+	//50 concurrent submissions (each with its own loaded products) with stock for only 10 of them
+	orders := make([]*order.Order, 50)
+	for i := range orders {
+		repo := repo1
+		if 1 == i%2 {
+			repo = repo2
+		}
+		loadedLimitedProd, _ := repo.FindByID("limitedProd")
+		loadedAnotherProd, _ := repo.FindByID("anotherProd")
+		orders[i] = order.New(fmt.Sprintf("concurrentOrder%d", i))
+		orders[i].AddProduct(loadedAnotherProd, 1)
+		orders[i].AddProduct(loadedLimitedProd, 2)
+		//the inventory is set after adding the items, so the drafts hold no stock and only race at submission
+		orders[i].SetInventory(repo)
+	}
+	var wg sync.WaitGroup
+	for _, o := range orders {
+		wg.Add(1)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Submit("ship name", "ship address", nil)
+		}(o)
+	}
+	wg.Wait()
+	submitted := 0
+	for _, o := range orders {
+		if order.StatusSubmitted == o.Status() {
+			submitted++
+		}
+	}
+
+	storedLimitedProd, errFind := repo2.FindByID("limitedProd")
+	storedAnotherProd, _ := repo2.FindByID("anotherProd")
+	_, errInsufficient := repo1.DecrementStocks(map[*product.Product]int{storedAnotherProd: 1, storedLimitedProd: 1}, nil)
+	afterFailedProd, _ := repo1.FindByID("anotherProd")
+	//a product loaded before a submission and updated after it keeps the decremented stock
+	staleProd, _ := repo2.FindByID("anotherProd")
+	submittedProd, _ := repo1.FindByID("anotherProd")
+	repo1.DecrementStocks(map[*product.Product]int{submittedProd: 5}, nil)
+	staleProd.SetName("Renamed Product")
+	errUpdate := repo2.Update(staleProd)
+	updatedProd, _ := repo1.FindByID("anotherProd")
+	errUpdateMissing := repo1.Update(product.New("missingProd", "Missing Product"))
+	_, errFindMissing := repo1.FindByID("missingProd")
+	allProducts, errFindAll := repo1.FindAll()
+	errDelete := repo1.Delete("anotherProd")
+	errDeleteMissing := repo1.Delete("anotherProd")
+
+	if errFind != nil {
+		t.Fatalf("can't find product: %v", errFind)
+	}
+
+	var productRepositoryTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Save Product", true, errSave == nil},
+		{"Only Orders With Enough Stock Must Be Submitted", 10, submitted},
+		{"Limited Stock Must Not Be Oversold", int64(0), storedLimitedProd.Stock()},
+		{"Other Stock Must Only Be Decremented By Submitted Orders", int64(90), storedAnotherProd.Stock()},
+		{"Round Trip Name", limitedProd.Name(), storedLimitedProd.Name()},
+		{"Round Trip Status", limitedProd.Status(), storedLimitedProd.Status()},
+		{"Round Trip Price", limitedProd.Price().String(), storedLimitedProd.Price().String()},
+		{"Round Trip Weight", "1.25", storedLimitedProd.Weight().String()},
+		{"Round Trip Volume", "6300", storedLimitedProd.Volume().String()},
+		{"Round Trip Tax Category", "reduced", storedLimitedProd.TaxCategory()},
+		{"Round Trip Currency", "EUR", storedLimitedProd.Currency()},
+		{"Decrement Insufficient Stock", true, nil != errInsufficient && errors.Is(errInsufficient, product.ErrInsufficientStock)},
+		{"Failed Decrement Must Not Decrement Any Stock", int64(90), afterFailedProd.Stock()},
+		{"Update Product", true, errUpdate == nil},
+		{"Update Keeps Stored Stock", int64(85), updatedProd.Stock()},
+		{"Updated Product Stock Kept In Sync", int64(85), staleProd.Stock()},
+		{"Update Round Trip Name", "Renamed Product", updatedProd.Name()},
+		{"Update Missing Product", true, nil != errUpdateMissing && errors.Is(errUpdateMissing, repository.ErrNotFound)},
+		{"Find Missing Product", true, nil != errFindMissing && errors.Is(errFindMissing, repository.ErrNotFound)},
+		{"Find All Error", true, errFindAll == nil},
+		{"Find All Count", 2, len(allProducts)},
+		{"Delete Existing Product", true, errDelete == nil},
+		{"Delete Missing Product", true, nil != errDeleteMissing && errors.Is(errDeleteMissing, repository.ErrNotFound)},
+	}
+
+	for _, test := range productRepositoryTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestProductRepositoryCancel(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "sstest")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "store.db")
+
+	//two databases opened on the same file act as two processes sharing the store
+	db1, openErr := sqlite.Open(path)
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db1.Close()
+	db2, openErr := sqlite.Open(path)
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db2.Close()
+	repo1 := sqlite.NewProductRepository(db1)
+	repo2 := sqlite.NewProductRepository(db2)
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+	repo1.Save(limitedProd)
+
+	//10 submitted orders are canceled twice while 10 other orders are submitted, concurrently (each with its own loaded products)
+	draftOrders := make([]*order.Order, 10)
+	submittedOrders := make([]*order.Order, 10)
+	for i := range submittedOrders {
+		loadedDraftProd, _ := repo1.FindByID("limitedProd")
+		draftOrders[i] = order.New(fmt.Sprintf("draftOrder%d", i))
+		draftOrders[i].AddProduct(loadedDraftProd, 2)
+		//the inventory is set after adding the items, so the drafts hold no stock and only race at submission
+		draftOrders[i].SetInventory(repo1)
+		loadedSubmittedProd, _ := repo2.FindByID("limitedProd")
+		submittedOrders[i] = order.New(fmt.Sprintf("submittedOrder%d", i))
+		submittedOrders[i].AddProduct(loadedSubmittedProd, 2)
+		submittedOrders[i].SetInventory(repo2)
+	}
+	for _, o := range submittedOrders {
+		o.Submit("ship name", "ship address", nil)
+	}
+	var wg sync.WaitGroup
+	for i := range submittedOrders {
+		wg.Add(3)
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Cancel()
+		}(submittedOrders[i])
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Cancel()
+		}(submittedOrders[i])
+		go func(o *order.Order) {
+			defer wg.Done()
+			o.Submit("ship name", "ship address", nil)
+		}(draftOrders[i])
+	}
+	wg.Wait()
+	canceled, submitted := 0, 0
+	for i := range submittedOrders {
+		if order.StatusCanceled == submittedOrders[i].Status() {
+			canceled++
+		}
+		if order.StatusSubmitted == draftOrders[i].Status() {
+			submitted++
+		}
+	}
+	storedProd, errFind := repo1.FindByID("limitedProd")
+	if errFind != nil {
+		t.Fatalf("can't find product: %v", errFind)
+	}
+
+	stock := storedProd.Stock()
+	missingProd := product.New("missingProd", "Missing Product")
+	errMissing := repo1.IncrementStocks(order.Allocations{storedProd: {product.DefaultWarehouse: 1}, missingProd: {product.DefaultWarehouse: 1}})
+	incrementedProd, _ := repo2.FindByID("limitedProd")
+	errInvalidQuantity := repo1.IncrementStocks(order.Allocations{storedProd: {product.DefaultWarehouse: -1}})
+
+	var productRepositoryCancelTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Every Submitted Order Must Be Canceled", 10, canceled},
+		{"Stock Must Only Be Taken By Submitted Orders", int64(20 - 2*submitted), stock},
+		{"Increment Missing Product Must Be Skipped", true, nil == errMissing},
+		{"Increment Stored Product Stock", stock + 1, incrementedProd.Stock()},
+		{"Incremented Product Must Be Kept In Sync", stock + 1, storedProd.Stock()},
+		{"Missing Product Stock Must Not Be Incremented", int64(0), missingProd.Stock()},
+		{"Increment Invalid Quantity", true, nil != errInvalidQuantity && errors.Is(errInvalidQuantity, product.ErrInvalidQuantity)},
+	}
+
+	for _, test := range productRepositoryCancelTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestProductRepositoryHolds(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "sstest")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "store.db")
+
+	//two databases opened on the same file act as two processes sharing the store
+	db1, openErr := sqlite.Open(path)
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db1.Close()
+	db2, openErr := sqlite.Open(path)
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db2.Close()
+	repo1 := sqlite.NewProductRepository(db1)
+	repo2 := sqlite.NewProductRepository(db2)
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(20)
+	limitedProd.SetPrice(decimal.New(100, 0))
+	deletedProd := product.New("deletedProd", "Deleted Product")
+	deletedProd.SetStatus(product.StatusAvailable)
+	deletedProd.SetStock(5)
+	repo1.Save(limitedProd)
+	repo1.Save(deletedProd)
+
+	//50 drafts (in two processes) concurrently add a product with stock for only 10 of them
+	orders := make([]*order.Order, 50)
+	var wg sync.WaitGroup
+	for i := range orders {
+		repo := repo1
+		if 1 == i%2 {
+			repo = repo2
+		}
+		loadedProd, _ := repo.FindByID("limitedProd")
+		orders[i] = order.New(fmt.Sprintf("concurrentOrder%d", i)).SetInventory(repo)
+		wg.Add(1)
+		go func(o *order.Order, p *product.Product) {
+			defer wg.Done()
+			o.AddProduct(p, 2)
+		}(orders[i], loadedProd)
+	}
+	wg.Wait()
+	var holding []*order.Order
+	for _, o := range orders {
+		if len(o.Items()) > 0 {
+			holding = append(holding, o)
+		}
+	}
+	availableHeld, _ := repo2.Available("limitedProd")
+	_, errDecrementHeld := repo1.DecrementStocks(map[*product.Product]int{limitedProd: 1}, nil)
+	errHoldMore := repo2.Hold("otherOrder", limitedProd, 1, time.Now().Add(order.HoldDuration))
+
+	//the first holding draft releases its hold, the second one is submitted
+	errRelease := repo1.Release(holding[0].ID(), "")
+	availableReleased, _ := repo2.Available("limitedProd")
+	submitOk, _ := holding[1].Submit("ship name", "ship address", nil)
+	storedProd, _ := repo2.FindByID("limitedProd")
+	availableSubmitted, _ := repo2.Available("limitedProd")
+
+	repo1.Hold("expiredOrder", deletedProd, 2, time.Now().Add(-time.Second))
+	repo1.Hold("deletedOrder", deletedProd, 3, time.Now().Add(order.HoldDuration))
+	availableExpired, _ := repo2.Available("deletedProd")
+	released, errReleaseExpired := repo2.ReleaseExpired(time.Now())
+	repo1.Delete("deletedProd")
+	repo1.Save(deletedProd)
+	availableRecreated, _ := repo2.Available("deletedProd")
+	errHoldMissing := repo1.Hold("otherOrder", product.New("missingProd", "Missing Product"), 1, time.Now().Add(order.HoldDuration))
+	_, errAvailableMissing := repo1.Available("missingProd")
+
+	var productRepositoryHoldTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Only Drafts With Available Stock Must Hold", 10, len(holding)},
+		{"Fully Held Product Available Stock", int64(0), availableHeld},
+		{"Decrement Held Stock Failure Reason", true, nil != errDecrementHeld && errors.Is(errDecrementHeld, product.ErrInsufficientStock)},
+		{"Hold More Than Available Failure Reason", true, nil != errHoldMore && errors.Is(errHoldMore, product.ErrInsufficientStock)},
+		{"Release Error", true, nil == errRelease},
+		{"Released Quantity Must Be Available", int64(2), availableReleased},
+		{"Holding Draft Must Be Submitted", true, submitOk},
+		{"Converted Hold Must Decrement Stock", int64(18), storedProd.Stock()},
+		{"Converted Hold Must Be Removed", int64(2), availableSubmitted},
+		{"Expired Hold Must Not Be Held", int64(2), availableExpired},
+		{"Release Expired Error", true, nil == errReleaseExpired},
+		{"Expired Hold Must Be Released", int64(1), released},
+		{"Deleted Product Holds Must Be Removed", int64(5), availableRecreated},
+		{"Hold Missing Product Failure Reason", true, nil != errHoldMissing && errors.Is(errHoldMissing, repository.ErrNotFound)},
+		{"Available Missing Product Failure Reason", true, nil != errAvailableMissing && errors.Is(errAvailableMissing, repository.ErrNotFound)},
+	}
+
+	for _, test := range productRepositoryHoldTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestProductRepositoryWarehouses(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	store := sqlite.NewStore(db)
+
+	splitProd := product.New("splitProd", "Split Product")
+	splitProd.SetStatus(product.StatusAvailable)
+	splitProd.SetStocks(map[string]int64{"east": 2, "west": 3})
+	splitProd.SetPrice(decimal.New(100, 0))
+	errSave := store.Products.Save(splitProd)
+	loadedProd, _ := store.Products.FindByID("splitProd")
+	loadedStock, loadedEast, loadedWest := loadedProd.Stock(), loadedProd.WarehouseStock("east"), loadedProd.WarehouseStock("west")
+
+	splitOrder := order.New("splitOrder").SetInventory(store.Products)
+	splitOrder.AddProduct(loadedProd, 4)
+	submitOk, _ := splitOrder.Submit("ship name", "ship address", nil)
+	errSaveOrder := store.Orders.Save(splitOrder)
+	submittedProd, _ := store.Products.FindByID("splitProd")
+	submittedWarehouses := len(submittedProd.Warehouses())
+	storedOrder, errFindOrder := store.Orders.FindByID("splitOrder")
+	if errFindOrder != nil {
+		t.Fatalf("can't find order: %v", errFindOrder)
+	}
+	storedAllocation := storedOrder.Items()["splitProd"].Allocation()
+
+	storedOrder.SetInventory(store.Products)
+	cancelOk, _ := storedOrder.Cancel()
+	canceledProd, _ := store.Products.FindByID("splitProd")
+	canceledEast, canceledWest := canceledProd.WarehouseStock("east"), canceledProd.WarehouseStock("west")
+
+	canceledProd.SetWarehouseStock("east", 0)
+	store.Products.Save(canceledProd)
+	resavedProd, _ := store.Products.FindByID("splitProd")
+
+	var warehouseTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Save Product", true, errSave == nil},
+		{"Round Trip Stock", int64(5), loadedStock},
+		{"Round Trip East Stock", int64(2), loadedEast},
+		{"Round Trip West Stock", int64(3), loadedWest},
+		{"Split Order Must Be Submitted", true, submitOk},
+		{"Save Order", true, errSaveOrder == nil},
+		{"Submitted Stock", int64(1), submittedProd.Stock()},
+		{"Emptied Warehouse Must Be Removed", 1, submittedWarehouses},
+		{"Round Trip East Allocation", 1, storedAllocation["east"]},
+		{"Round Trip West Allocation", 3, storedAllocation["west"]},
+		{"Stored Order Must Be Canceled", true, cancelOk},
+		{"Canceled East Stock", int64(2), canceledEast},
+		{"Canceled West Stock", int64(3), canceledWest},
+		{"Saved Stocks Must Replace Warehouses", int64(0), resavedProd.WarehouseStock("east")},
+		{"Saved Stock Total", int64(3), resavedProd.Stock()},
+	}
+
+	for _, test := range warehouseTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
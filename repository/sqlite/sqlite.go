@@ -0,0 +1,316 @@
+//Package sqlite provides the embedded SQLite implementations of the repository interfaces
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sstest/repository"
+
+	"github.com/go-errors/errors"
+	//also registers the "sqlite3" database/sql driver
+	"github.com/mattn/go-sqlite3"
+)
+
+//migrations is the ordered list of schema migrations, a migration's schema version is its index + 1
+//note: never edit or reorder an existing migration, append a new one instead
+var migrations = []string{
+	//1: orders and order items
+	`CREATE TABLE orders (
+		id                   TEXT PRIMARY KEY,
+		created_date         INTEGER NOT NULL,
+		submitted_date       INTEGER NOT NULL,
+		processed_date       INTEGER NOT NULL,
+		status               TEXT NOT NULL,
+		coupon_id            TEXT,
+		amount               TEXT NOT NULL,
+		shipping_name        TEXT NOT NULL,
+		shipping_address     TEXT NOT NULL,
+		shipping_status      TEXT NOT NULL,
+		shipping_tracking_id TEXT NOT NULL
+	);
+	CREATE INDEX orders_status ON orders (status, created_date);
+	CREATE TABLE order_items (
+		id         TEXT PRIMARY KEY,
+		order_id   TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+		product_id TEXT NOT NULL,
+		quantity   INTEGER NOT NULL
+	);
+	CREATE INDEX order_items_order ON order_items (order_id);`,
+	//2: products
+	`CREATE TABLE products (
+		id     TEXT PRIMARY KEY,
+		name   TEXT NOT NULL,
+		status TEXT NOT NULL,
+		price  TEXT NOT NULL,
+		stock  INTEGER NOT NULL CHECK (stock >= 0)
+	);`,
+	//3: coupons (codes unique regardless of letter case) and users
+	`CREATE TABLE coupons (
+		id         TEXT PRIMARY KEY COLLATE NOCASE,
+		status     TEXT NOT NULL,
+		stock      INTEGER NOT NULL,
+		kind       TEXT NOT NULL,
+		value      TEXT NOT NULL,
+		start_date INTEGER NOT NULL,
+		end_date   INTEGER NOT NULL
+	);
+	CREATE TABLE users (
+		id       TEXT PRIMARY KEY,
+		password BLOB NOT NULL,
+		name     TEXT NOT NULL,
+		address  TEXT NOT NULL,
+		status   TEXT NOT NULL
+	);`,
+	//4: order users (orders without user keep a NULL user id)
+	`ALTER TABLE orders ADD COLUMN user_id TEXT;
+	CREATE INDEX orders_user ON orders (user_id, created_date);`,
+	//5: draft order stock reservations (holds), removed along with their product
+	`CREATE TABLE reservations (
+		order_id    TEXT NOT NULL,
+		product_id  TEXT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+		quantity    INTEGER NOT NULL CHECK (quantity > 0),
+		expiry_date INTEGER NOT NULL,
+		PRIMARY KEY (order_id, product_id)
+	);
+	CREATE INDEX reservations_product ON reservations (product_id, expiry_date);
+	CREATE INDEX reservations_expiry ON reservations (expiry_date);`,
+	//6: product stock per warehouse (products.stock is kept as the total of the warehouse stocks, the existing stock being
+	//moved to the default warehouse) and order item allocations on the warehouses
+	`CREATE TABLE product_stocks (
+		product_id   TEXT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+		warehouse_id TEXT NOT NULL,
+		stock        INTEGER NOT NULL CHECK (stock >= 0),
+		PRIMARY KEY (product_id, warehouse_id)
+	);
+	INSERT INTO product_stocks (product_id, warehouse_id, stock) SELECT id, 'main', stock FROM products WHERE stock > 0;
+	CREATE TABLE order_item_allocations (
+		item_id      TEXT NOT NULL REFERENCES order_items (id) ON DELETE CASCADE,
+		warehouse_id TEXT NOT NULL,
+		quantity     INTEGER NOT NULL CHECK (quantity > 0),
+		PRIMARY KEY (item_id, warehouse_id)
+	);`,
+	//7: order shipments (the orders shipping status and tracking id are kept as derived from the shipments),
+	//an order already shipped gets a single shipment of all its items
+	`CREATE TABLE shipments (
+		id             TEXT PRIMARY KEY,
+		order_id       TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+		carrier        TEXT NOT NULL,
+		tracking_id    TEXT NOT NULL,
+		status         TEXT NOT NULL,
+		shipped_date   INTEGER NOT NULL,
+		delivered_date INTEGER NOT NULL
+	);
+	CREATE INDEX shipments_order ON shipments (order_id, shipped_date);
+	CREATE TABLE shipment_items (
+		shipment_id TEXT NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+		product_id  TEXT NOT NULL,
+		quantity    INTEGER NOT NULL CHECK (quantity > 0),
+		PRIMARY KEY (shipment_id, product_id)
+	);
+	INSERT INTO shipments (id, order_id, carrier, tracking_id, status, shipped_date, delivered_date)
+		SELECT id || '-shipment', id, '', shipping_tracking_id, CASE shipping_status WHEN 'D' THEN 'D' ELSE 'S' END,
+			processed_date, CASE shipping_status WHEN 'D' THEN processed_date ELSE 0 END
+		FROM orders WHERE shipping_status <> 'N';
+	INSERT INTO shipment_items (shipment_id, product_id, quantity)
+		SELECT o.id || '-shipment', i.product_id, i.quantity FROM order_items i JOIN orders o ON o.id = i.order_id
+		WHERE o.shipping_status <> 'N' AND i.quantity > 0;`,
+	//8: shipment tracking events (in date order by seq) and shipments lookup by carrier tracking id
+	`CREATE TABLE shipment_events (
+		shipment_id TEXT NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+		seq         INTEGER NOT NULL,
+		kind        TEXT NOT NULL,
+		date        INTEGER NOT NULL,
+		location    TEXT NOT NULL,
+		description TEXT NOT NULL,
+		PRIMARY KEY (shipment_id, seq)
+	);
+	CREATE INDEX shipments_tracking ON shipments (carrier, tracking_id);`,
+	//9: product shipping weight and dimensions, order shipping cost (included in its amount)
+	`ALTER TABLE products ADD COLUMN weight TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE products ADD COLUMN length TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE products ADD COLUMN width TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE products ADD COLUMN height TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE orders ADD COLUMN shipping_cost TEXT NOT NULL DEFAULT '0';`,
+	//10: product tax category, order shipping (tax) region and item taxes (in their rules' order by seq)
+	`ALTER TABLE products ADD COLUMN tax_category TEXT NOT NULL DEFAULT 'standard';
+	ALTER TABLE orders ADD COLUMN shipping_region TEXT NOT NULL DEFAULT '';
+	ALTER TABLE orders ADD COLUMN tax_included INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE order_item_taxes (
+		item_id TEXT NOT NULL REFERENCES order_items (id) ON DELETE CASCADE,
+		seq     INTEGER NOT NULL,
+		name    TEXT NOT NULL,
+		rate    TEXT NOT NULL,
+		amount  TEXT NOT NULL,
+		PRIMARY KEY (item_id, seq)
+	);`,
+	//11: order and item price breakdowns (the order's total is its amount, the item's taxes are in order_item_taxes)
+	`ALTER TABLE orders ADD COLUMN subtotal TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE orders ADD COLUMN discount TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE order_items ADD COLUMN unit_price TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE order_items ADD COLUMN subtotal TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE order_items ADD COLUMN discount TEXT NOT NULL DEFAULT '0';`,
+	//12: product name and status captured on the items' submission (empty until then, the unit price is captured in unit_price)
+	`ALTER TABLE order_items ADD COLUMN product_name TEXT NOT NULL DEFAULT '';
+	ALTER TABLE order_items ADD COLUMN product_status TEXT NOT NULL DEFAULT '';`,
+	//13: currencies of product prices, coupon values and order amounts (stored rows are in the default currency)
+	`ALTER TABLE products ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+	ALTER TABLE coupons ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+	ALTER TABLE orders ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';`,
+	//14: exchange rates converting the items' product prices into their order's currency and the time they were quoted at
+	`ALTER TABLE order_items ADD COLUMN exchange_rate TEXT NOT NULL DEFAULT '1';
+	ALTER TABLE order_items ADD COLUMN rate_date INTEGER NOT NULL DEFAULT 0;`,
+	//15: coupon conditions (stored coupons have none) and the products a coupon discounts or excludes
+	`ALTER TABLE coupons ADD COLUMN min_subtotal TEXT NOT NULL DEFAULT '0';
+	ALTER TABLE coupons ADD COLUMN min_quantity INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE coupons ADD COLUMN max_discount TEXT NOT NULL DEFAULT '0';
+	CREATE TABLE coupon_products (
+		coupon_id  TEXT NOT NULL REFERENCES coupons (id) ON DELETE CASCADE,
+		product_id TEXT NOT NULL,
+		excluded   INTEGER NOT NULL,
+		PRIMARY KEY (coupon_id, excluded, product_id)
+	);`,
+	//16: coupon redemption limits (stored coupons have none) and redemption ledger
+	//(kept when an order or coupon is deleted, an unreversed redemption has a zero reversed date)
+	`ALTER TABLE coupons ADD COLUMN user_limit INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE coupons ADD COLUMN redemption_limit INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE coupon_redemptions (
+		coupon_id     TEXT NOT NULL,
+		order_id      TEXT NOT NULL,
+		user_id       TEXT NOT NULL,
+		amount        TEXT NOT NULL,
+		currency      TEXT NOT NULL,
+		redeemed_date INTEGER NOT NULL,
+		reversed_date INTEGER NOT NULL,
+		PRIMARY KEY (coupon_id, order_id)
+	);
+	CREATE INDEX coupon_redemptions_user ON coupon_redemptions (user_id, redeemed_date);`,
+	//17: stackable coupons (stored coupons are exclusive) and the coupons applied on an order in their application order,
+	//each with its discount (an order's single coupon is moved, orders.coupon_id is no longer used)
+	`ALTER TABLE coupons ADD COLUMN stackable INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE order_coupons (
+		order_id  TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+		seq       INTEGER NOT NULL,
+		coupon_id TEXT NOT NULL,
+		discount  TEXT NOT NULL,
+		PRIMARY KEY (order_id, seq)
+	);
+	INSERT INTO order_coupons (order_id, seq, coupon_id, discount) SELECT id, 0, coupon_id, discount FROM orders WHERE coupon_id IS NOT NULL;`,
+	//18: promotion terms of buy X get Y coupons (stored coupons have none), their free products and the spend tiers of tiered coupons
+	`ALTER TABLE coupons ADD COLUMN buy_quantity INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE coupons ADD COLUMN free_quantity INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE coupon_free_products (
+		coupon_id  TEXT NOT NULL REFERENCES coupons (id) ON DELETE CASCADE,
+		product_id TEXT NOT NULL,
+		PRIMARY KEY (coupon_id, product_id)
+	);
+	CREATE TABLE coupon_tiers (
+		coupon_id  TEXT NOT NULL REFERENCES coupons (id) ON DELETE CASCADE,
+		min_spend  TEXT NOT NULL,
+		percentage TEXT NOT NULL,
+		PRIMARY KEY (coupon_id, min_spend)
+	);`,
+	//19: automatic promotions (stored coupons are entered by their code) and the promotions applied on an order,
+	//stored along with its coupons (numbered after them)
+	`ALTER TABLE coupons ADD COLUMN automatic INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE coupons ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE coupons ADD COLUMN excludes_coupons INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE order_coupons ADD COLUMN automatic INTEGER NOT NULL DEFAULT 0;`,
+	//20: capture the product price, name and status on the items of the orders submitted before 11 and 12 (the products' current values
+	//are the closest to the submitted ones, the items of deleted products are left uncaptured) and their subtotal (computed on the decimal digits)
+	`UPDATE order_items SET
+		unit_price = (SELECT price FROM products WHERE products.id = order_items.product_id),
+		product_name = (SELECT name FROM products WHERE products.id = order_items.product_id),
+		product_status = (SELECT status FROM products WHERE products.id = order_items.product_id)
+	WHERE product_status = ''
+		AND order_id IN (SELECT id FROM orders WHERE status <> 'D')
+		AND product_id IN (SELECT id FROM products);
+	UPDATE order_items SET subtotal = CASE WHEN 0 = instr(unit_price, '.') THEN CAST(CAST(unit_price AS INTEGER) * quantity AS TEXT)
+		ELSE printf('%d.%0*d',
+			CAST(replace(unit_price, '.', '') AS INTEGER) * quantity / CAST('1' || substr('000000000000000000', 1, length(unit_price) - instr(unit_price, '.')) AS INTEGER),
+			length(unit_price) - instr(unit_price, '.'),
+			CAST(replace(unit_price, '.', '') AS INTEGER) * quantity % CAST('1' || substr('000000000000000000', 1, length(unit_price) - instr(unit_price, '.')) AS INTEGER))
+		END
+	WHERE subtotal = '0' AND product_status <> '';`,
+	//21: the product tax category captured on the items of submitted orders (back-filled from the products' current one)
+	`ALTER TABLE order_items ADD COLUMN tax_category TEXT NOT NULL DEFAULT 'standard';
+	UPDATE order_items SET tax_category = (SELECT tax_category FROM products WHERE products.id = order_items.product_id)
+	WHERE product_status <> '' AND product_id IN (SELECT id FROM products);`,
+}
+
+//Open opens (creating if needed) the SQLite database in the given file path and migrates its schema to the latest version
+func Open(path string) (*sql.DB, *errors.Error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%v?_foreign_keys=1&_busy_timeout=5000&_txlock=immediate", path))
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't open database %v: %v", path, err), 0)
+	}
+	//note: a single connection serializes writers (and keeps ":memory:" databases from being opened per connection),
+	//transactions take the write lock immediately so concurrent processes wait on busy timeout instead of deadlocking
+	db.SetMaxOpenConns(1)
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+//NewStore creates a new store of SQLite repositories sharing the given database and returns a reference to it
+func NewStore(db *sql.DB) *repository.Store {
+	products := NewProductRepository(db)
+	coupons := NewCouponRepository(db)
+	users := NewUserRepository(db)
+	return &repository.Store{
+		Orders:   NewOrderRepository(db, products, coupons, users),
+		Products: products,
+		Coupons:  coupons,
+		Users:    users,
+	}
+}
+
+//Migrate applies all pending schema migrations on the given database
+func Migrate(db *sql.DB) *errors.Error {
+	return MigrateTo(db, len(migrations))
+}
+
+//MigrateTo applies the pending schema migrations up to a schema version on the given database
+func MigrateTo(db *sql.DB, target int) *errors.Error {
+	version, verErr := SchemaVersion(db)
+	if verErr != nil {
+		return verErr
+	}
+	if target > len(migrations) {
+		return errors.Wrap(fmt.Errorf("Can't migrate to unknown schema version %d, latest is %d", target, len(migrations)), 0)
+	}
+	for i := version; i < target; i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("Can't apply migration %d: %v", i+1, err), 0)
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't apply migration %d: %v", i+1, err), 0)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return errors.Wrap(fmt.Errorf("Can't apply migration %d: %v", i+1, err), 0)
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.Wrap(fmt.Errorf("Can't apply migration %d: %v", i+1, err), 0)
+		}
+	}
+	return nil
+}
+
+//SchemaVersion returns the schema version the given database is migrated to
+func SchemaVersion(db *sql.DB) (int, *errors.Error) {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, errors.Wrap(fmt.Errorf("Can't read schema version: %v", err), 0)
+	}
+	return version, nil
+}
+
+//isUniqueViolation returns whether an error is a SQLite primary key or unique constraint violation
+func isUniqueViolation(err error) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+	return ok && (sqlite3.ErrConstraintPrimaryKey == sqliteErr.ExtendedCode || sqlite3.ErrConstraintUnique == sqliteErr.ExtendedCode)
+}
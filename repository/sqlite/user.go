@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sstest/model/user"
+	"sstest/repository"
+
+	"github.com/go-errors/errors"
+)
+
+//UserRepository is SQLite implementation of repository.UserRepository
+type UserRepository struct {
+	db *sql.DB
+}
+
+//NewUserRepository creates a new SQLite user repository and returns a reference to it
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db}
+}
+
+//Create is a function for storing a new user (with its password hash untouched)
+func (r *UserRepository) Create(u *user.User) *errors.Error {
+	_, err := r.db.Exec("INSERT INTO users (id, password, name, address, status) VALUES (?, ?, ?, ?, ?)",
+		u.ID(), u.Password(), u.Name(), u.Address(), u.Status())
+	if isUniqueViolation(err) {
+		return repository.Duplicate("user", u.ID())
+	}
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't create user %v: %v", u.ID(), err), 0)
+	}
+	return nil
+}
+
+//Save is a function for storing an existing user (with its password hash untouched)
+func (r *UserRepository) Save(u *user.User) *errors.Error {
+	res, err := r.db.Exec("UPDATE users SET password = ?, name = ?, address = ?, status = ? WHERE id = ?",
+		u.Password(), u.Name(), u.Address(), u.Status(), u.ID())
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't save user %v: %v", u.ID(), err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return repository.NotFound("user", u.ID())
+	}
+	return nil
+}
+
+//FindByID is a function for returning the user with the given id
+func (r *UserRepository) FindByID(id string) (*user.User, *errors.Error) {
+	rows, err := r.db.Query("SELECT id, password, name, address, status FROM users WHERE id = ?", id)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find user %v: %v", id, err), 0)
+	}
+	users, findErr := scanUsers(rows)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if 0 == len(users) {
+		return nil, repository.NotFound("user", id)
+	}
+	return users[0], nil
+}
+
+//FindAll is a function for returning all users ordered by their id
+func (r *UserRepository) FindAll() ([]*user.User, *errors.Error) {
+	rows, err := r.db.Query("SELECT id, password, name, address, status FROM users ORDER BY id")
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't find users: %v", err), 0)
+	}
+	return scanUsers(rows)
+}
+
+//Delete is a function for removing the user with the given id
+func (r *UserRepository) Delete(id string) *errors.Error {
+	res, err := r.db.Exec("DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("Can't delete user %v: %v", id, err), 0)
+	}
+	if affected, _ := res.RowsAffected(); 0 == affected {
+		return repository.NotFound("user", id)
+	}
+	return nil
+}
+
+//scanUsers reads all user rows (closing them)
+func scanUsers(rows *sql.Rows) ([]*user.User, *errors.Error) {
+	defer rows.Close()
+
+	users := make([]*user.User, 0)
+	for rows.Next() {
+		var id, name, address, status string
+		var password []byte
+		if err := rows.Scan(&id, &password, &name, &address, &status); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read user: %v", err), 0)
+		}
+		u, err := user.NewFromHash(id, name, address, password)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read user %v: %v", id, err), 0)
+		}
+		if _, err := u.SetStatus(status); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("Can't read user %v: %v", id, err), 0)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("Can't read users: %v", err), 0)
+	}
+	return users, nil
+}
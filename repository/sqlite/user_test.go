@@ -0,0 +1,68 @@
+package sqlite_test
+
+import (
+	"fmt"
+	"sstest/model/user"
+	"sstest/repository"
+	"sstest/repository/sqlite"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+func TestUserRepository(t *testing.T) {
+	db, openErr := sqlite.Open(":memory:")
+	if openErr != nil {
+		t.Fatalf("can't open database: %v", openErr)
+	}
+	defer db.Close()
+	repo := sqlite.NewUserRepository(db)
+
+	activeUser, _ := user.New("activeUser", "Active User", "Active User Address")
+	activeUser.SetPassword("secretPassword")
+	activeUser.Activate()
+	duplicateUser, _ := user.NewFromHash("activeUser", "Duplicate User", "Duplicate User Address", activeUser.Password())
+	missingUser, _ := user.NewFromHash("missingUser", "Missing User", "Missing User Address", activeUser.Password())
+
+	errCreate := repo.Create(activeUser)
+	errCreateDuplicate := repo.Create(duplicateUser)
+	activeUser.SetAddress("Moved User Address")
+	errSave := repo.Save(activeUser)
+	errSaveMissing := repo.Save(missingUser)
+	foundUser, errFind := repo.FindByID("activeUser")
+	allUsers, _ := repo.FindAll()
+	errDelete := repo.Delete("activeUser")
+	errDeleteMissing := repo.Delete("activeUser")
+
+	if errFind != nil {
+		t.Fatalf("can't find user: %v", errFind)
+	}
+	passwordMatch, _ := foundUser.ValidatePassword("secretPassword")
+
+	var userRepositoryTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create User", true, errCreate == nil},
+		{"Create Duplicate User", true, nil != errCreateDuplicate && errors.Is(errCreateDuplicate, repository.ErrDuplicate)},
+		{"Save Existing User", true, errSave == nil},
+		{"Save Missing User", true, nil != errSaveMissing && errors.Is(errSaveMissing, repository.ErrNotFound)},
+		{"Round Trip Password Hash Untouched", string(activeUser.Password()), string(foundUser.Password())},
+		{"Round Trip Password Validates", true, passwordMatch},
+		{"Round Trip Name", activeUser.Name(), foundUser.Name()},
+		{"Round Trip Address", "Moved User Address", foundUser.Address()},
+		{"Round Trip Status", user.StatusActive, foundUser.Status()},
+		{"Find All Count", 1, len(allUsers)},
+		{"Delete Existing User", true, errDelete == nil},
+		{"Delete Missing User", true, nil != errDeleteMissing && errors.Is(errDeleteMissing, repository.ErrNotFound)},
+	}
+
+	for _, test := range userRepositoryTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+//Sweeper is a background worker periodically releasing the expired holds of a product repository
+type Sweeper struct {
+	products ProductRepository
+	interval time.Duration
+	onError  func(err *errors.Error) //called with the error of a failed sweep (nil ignores errors)
+	stop     chan struct{}
+	done     chan struct{}
+	mu       sync.Mutex
+}
+
+//NewSweeper creates a new stopped sweeper of a product repository's holds, sweeping at the given interval, and returns a reference to it
+func NewSweeper(products ProductRepository, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		products,
+		interval,
+		nil,
+		nil,
+		nil,
+		*new(sync.Mutex),
+	}
+}
+
+//SetErrorHandler is a setter function for setting the function called with the error of a failed background sweep
+func (s *Sweeper) SetErrorHandler(onError func(err *errors.Error)) *Sweeper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = onError
+	return s
+}
+
+//Sweep is a function for releasing the holds expired now, returning the number of released holds
+func (s *Sweeper) Sweep() (int64, *errors.Error) {
+	return s.products.ReleaseExpired(time.Now())
+}
+
+//Start is a function for starting the background sweeps (a started sweeper is left running)
+func (s *Sweeper) Start() *Sweeper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return s
+	}
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	go s.run(s.stop, s.done)
+	return s
+}
+
+//Stop is a function for stopping the background sweeps, waiting for a running sweep to end (a stopped sweeper is left stopped)
+func (s *Sweeper) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+//run sweeps at every interval until stopped
+func (s *Sweeper) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(); err != nil {
+				s.mu.Lock()
+				onError := s.onError
+				s.mu.Unlock()
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}
+}
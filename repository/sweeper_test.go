@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"fmt"
+	"sstest/model/product"
+	"sstest/repository"
+	"testing"
+	"time"
+)
+
+func TestSweeper(t *testing.T) {
+	repo := repository.NewMemoryProductRepository()
+	heldProd := product.New("heldProd", "Held Product")
+	heldProd.SetStatus(product.StatusAvailable)
+	heldProd.SetStock(10)
+	repo.Save(heldProd)
+
+	repo.Hold("expiredOrder", heldProd, 2, time.Now().Add(-time.Second))
+	repo.Hold("expiringOrder", heldProd, 3, time.Now().Add(50*time.Millisecond))
+	repo.Hold("heldOrder", heldProd, 4, time.Now().Add(time.Hour))
+
+	sweeper := repository.NewSweeper(repo, 10*time.Millisecond)
+	swept, errSweep := sweeper.Sweep()
+	availableAfterSweep, _ := repo.Available("heldProd")
+
+	sweeper.Start().Start()
+	//the expiring hold expires and is released by a background sweep meanwhile
+	time.Sleep(200 * time.Millisecond)
+	sweeper.Stop()
+	sweeper.Stop()
+	sweptAfterStop, _ := sweeper.Sweep()
+	stillHeld, _ := repo.ReleaseExpired(time.Now().Add(2 * time.Hour))
+
+	var sweeperTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Sweep Error", true, nil == errSweep},
+		{"Sweep Must Release Expired Holds Only", int64(1), swept},
+		{"Available Stock After Sweep", int64(3), availableAfterSweep},
+		{"Background Sweep Must Release Expiring Hold", int64(0), sweptAfterStop},
+		{"Unexpired Hold Must Be Kept", int64(1), stillHeld},
+	}
+
+	for _, test := range sweeperTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"sstest/model/coupon"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//couponResponse is the JSON representation of a coupon
+type couponResponse struct {
+	ID               string          `json:"id"`
+	Status           string          `json:"status"`
+	Stock            int64           `json:"stock"`
+	Kind             string          `json:"kind"`
+	Value            decimal.Decimal `json:"value"`
+	Currency         string          `json:"currency"` //ISO 4217 code of a value coupon's value currency
+	StartDate        time.Time       `json:"startDate"`
+	EndDate          time.Time       `json:"endDate"`
+	MinSubtotal      decimal.Decimal `json:"minSubtotal"` //zero for none
+	MinQuantity      int             `json:"minQuantity"` //zero for none
+	MaxDiscount      decimal.Decimal `json:"maxDiscount"` //maximum discount of a percentage coupon, zero for no cap
+	Products         []string        `json:"products"`    //ids of the discounted products, every product when empty
+	ExcludedProducts []string        `json:"excludedProducts"`
+	UserLimit        int             `json:"userLimit"`       //maximum redemptions by a user, zero for no limit
+	RedemptionLimit  int             `json:"redemptionLimit"` //maximum redemptions by every user, zero for no limit
+	Stackable        bool            `json:"stackable"`       //combinable with other stackable coupons, exclusive otherwise
+	BuyQuantity      int             `json:"buyQuantity"`     //items bought to get free items of a buy X get Y coupon
+	FreeQuantity     int             `json:"freeQuantity"`    //free items got for every bought items of a buy X get Y coupon
+	FreeProducts     []string        `json:"freeProducts"`    //ids of the free products, the bought products when empty
+	Tiers            []tierBody      `json:"tiers"`           //spend tiers of a tiered coupon, ordered by minimum spend
+	Automatic        bool            `json:"automatic"`       //applied on every eligible draft order without its code being entered
+	Priority         int             `json:"priority"`        //rank of an automatic promotion, the highest applied first
+	ExcludesCoupons  bool            `json:"excludesCoupons"` //automatic promotion skipped on orders having entered coupons
+}
+
+//tierBody is the JSON representation of a tiered coupon's spend tier
+type tierBody struct {
+	MinSpend   decimal.Decimal `json:"minSpend"`
+	Percentage decimal.Decimal `json:"percentage"`
+}
+
+//newTierBodies creates the JSON representation of spend tiers
+func newTierBodies(tiers []*coupon.Tier) []tierBody {
+	bodies := make([]tierBody, 0, len(tiers))
+	for _, tier := range tiers {
+		bodies = append(bodies, tierBody{tier.MinSpend(), tier.Percentage()})
+	}
+	return bodies
+}
+
+//newCouponResponse creates the JSON representation of a coupon
+func newCouponResponse(c *coupon.Coupon) couponResponse {
+	return couponResponse{c.ID(), c.Status(), c.Stock(), c.Kind(), c.Value(), c.Currency(), c.StartDate(), c.EndDate(),
+		c.MinSubtotal(), c.MinQuantity(), c.MaxDiscount(), append([]string{}, c.Products()...), append([]string{}, c.ExcludedProducts()...),
+		c.UserLimit(), c.RedemptionLimit(), c.Stackable(), c.BuyQuantity(), c.FreeQuantity(), append([]string{}, c.FreeProducts()...),
+		newTierBodies(c.Tiers()), c.Automatic(), c.Priority(), c.ExcludesCoupons()}
+}
+
+//couponRequest is the JSON body of a coupon creation or update (omitted fields are left unchanged)
+type couponRequest struct {
+	ID               string           `json:"id"`
+	Status           *string          `json:"status"`
+	Stock            *int64           `json:"stock"`
+	Kind             *string          `json:"kind"`
+	Value            *decimal.Decimal `json:"value"`
+	Currency         *string          `json:"currency"`
+	StartDate        *time.Time       `json:"startDate"`
+	EndDate          *time.Time       `json:"endDate"`
+	MinSubtotal      *decimal.Decimal `json:"minSubtotal"`
+	MinQuantity      *int             `json:"minQuantity"`
+	MaxDiscount      *decimal.Decimal `json:"maxDiscount"`
+	Products         *[]string        `json:"products"`
+	ExcludedProducts *[]string        `json:"excludedProducts"`
+	UserLimit        *int             `json:"userLimit"`
+	RedemptionLimit  *int             `json:"redemptionLimit"`
+	Stackable        *bool            `json:"stackable"`
+	BuyQuantity      *int             `json:"buyQuantity"`
+	FreeQuantity     *int             `json:"freeQuantity"`
+	FreeProducts     *[]string        `json:"freeProducts"`
+	Tiers            *[]tierBody      `json:"tiers"`
+	Automatic        *bool            `json:"automatic"`
+	Priority         *int             `json:"priority"`
+	ExcludesCoupons  *bool            `json:"excludesCoupons"`
+}
+
+//build creates the coupon resulting from applying the request on a current coupon (nil on creation)
+func (req couponRequest) build(id string, current *coupon.Coupon) (*coupon.Coupon, *errors.Error) {
+	c := coupon.New(id)
+	if nil == current {
+		current = c
+	}
+	status, stock, kind, value, currency := current.Status(), current.Stock(), current.Kind(), current.Value(), current.Currency()
+	startDate, endDate := current.StartDate(), current.EndDate()
+	minSubtotal, minQuantity, maxDiscount := current.MinSubtotal(), current.MinQuantity(), current.MaxDiscount()
+	products, excludedProducts := current.Products(), current.ExcludedProducts()
+	userLimit, redemptionLimit, stackable := current.UserLimit(), current.RedemptionLimit(), current.Stackable()
+	buyQuantity, freeQuantity, freeProducts, tiers := current.BuyQuantity(), current.FreeQuantity(), current.FreeProducts(), current.Tiers()
+	automatic, priority, excludesCoupons := current.Automatic(), current.Priority(), current.ExcludesCoupons()
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if req.Stock != nil {
+		stock = *req.Stock
+	}
+	if req.Kind != nil {
+		kind = *req.Kind
+	}
+	if req.Value != nil {
+		value = *req.Value
+	}
+	if req.Currency != nil {
+		currency = *req.Currency
+	}
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		endDate = *req.EndDate
+	}
+	if req.MinSubtotal != nil {
+		minSubtotal = *req.MinSubtotal
+	}
+	if req.MinQuantity != nil {
+		minQuantity = *req.MinQuantity
+	}
+	if req.MaxDiscount != nil {
+		maxDiscount = *req.MaxDiscount
+	}
+	if req.Products != nil {
+		products = *req.Products
+	}
+	if req.ExcludedProducts != nil {
+		excludedProducts = *req.ExcludedProducts
+	}
+	if req.UserLimit != nil {
+		userLimit = *req.UserLimit
+	}
+	if req.RedemptionLimit != nil {
+		redemptionLimit = *req.RedemptionLimit
+	}
+	if req.Stackable != nil {
+		stackable = *req.Stackable
+	}
+	if req.BuyQuantity != nil {
+		buyQuantity = *req.BuyQuantity
+	}
+	if req.FreeQuantity != nil {
+		freeQuantity = *req.FreeQuantity
+	}
+	if req.FreeProducts != nil {
+		freeProducts = *req.FreeProducts
+	}
+	if req.Tiers != nil {
+		tiers = make([]*coupon.Tier, 0, len(*req.Tiers))
+		for _, tier := range *req.Tiers {
+			tiers = append(tiers, coupon.NewTier(tier.MinSpend, tier.Percentage))
+		}
+	}
+	if req.Automatic != nil {
+		automatic = *req.Automatic
+	}
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+	if req.ExcludesCoupons != nil {
+		excludesCoupons = *req.ExcludesCoupons
+	}
+
+	if stock < 0 {
+		return nil, errors.Wrap(fmt.Errorf("Can't set negative value %d for stock", stock), 0)
+	}
+	c.SetStock(stock)
+	if _, err := c.SetStatus(status); err != nil {
+		return nil, err
+	}
+	//note: value is set under the value kind (accepting any positive value), the final kind then validates it
+	c.SetKind(coupon.KindValue)
+	if _, err := c.SetValue(value); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetKind(kind); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetCurrency(currency); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetMinSubtotal(minSubtotal); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetMinQuantity(minQuantity); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetMaxDiscount(maxDiscount); err != nil {
+		return nil, err
+	}
+	c.SetProducts(products...).SetExcludedProducts(excludedProducts...)
+	if _, err := c.SetUserLimit(userLimit); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetRedemptionLimit(redemptionLimit); err != nil {
+		return nil, err
+	}
+	c.SetStackable(stackable)
+	if _, err := c.SetBuyQuantity(buyQuantity); err != nil {
+		return nil, err
+	}
+	if _, err := c.SetFreeQuantity(freeQuantity); err != nil {
+		return nil, err
+	}
+	c.SetFreeProducts(freeProducts...)
+	if _, err := c.SetTiers(tiers...); err != nil {
+		return nil, err
+	}
+	c.SetAutomatic(automatic).SetPriority(priority).SetExcludesCoupons(excludesCoupons)
+	//note: dates are set in the order keeping start date before end date at every step
+	if startDate.After(c.EndDate()) {
+		if _, err := c.SetEndDate(endDate); err != nil {
+			return nil, err
+		}
+		if _, err := c.SetStartDate(startDate); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := c.SetStartDate(startDate); err != nil {
+			return nil, err
+		}
+		if _, err := c.SetEndDate(endDate); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+//redemptionResponse is the JSON representation of a coupon redemption
+type redemptionResponse struct {
+	CouponID     string          `json:"couponId"`
+	UserID       string          `json:"userId"` //empty for an order without user
+	OrderID      string          `json:"orderId"`
+	Amount       decimal.Decimal `json:"amount"`
+	Currency     string          `json:"currency"`
+	RedeemedDate time.Time       `json:"redeemedDate"`
+	ReversedDate time.Time       `json:"reversedDate"` //zero until the order is canceled
+}
+
+//newRedemptionResponse creates the JSON representation of a coupon redemption
+func newRedemptionResponse(r *coupon.Redemption) redemptionResponse {
+	return redemptionResponse{r.CouponID(), r.UserID(), r.OrderID(), r.Amount(), r.Currency(), r.RedeemedDate(), r.ReversedDate()}
+}
+
+//serveCoupons dispatches a coupon request by its path segments (after "coupons"):
+//
+//	GET    /coupons                    list coupons
+//	POST   /coupons                    create a coupon
+//	GET    /coupons/{id}               get a coupon
+//	PUT    /coupons/{id}               update a coupon
+//	DELETE /coupons/{id}               delete a coupon
+//	GET    /coupons/{id}/redemptions   list a coupon's redemptions
+func (s *Server) serveCoupons(w http.ResponseWriter, r *http.Request, segments []string) {
+	if 2 != len(segments) {
+		resource{s.listCoupons, s.createCoupon, s.getCoupon, s.updateCoupon, s.removeCoupon}.serve(w, r, segments)
+		return
+	}
+	if "redemptions" != segments[1] {
+		writeError(w, unknownResource(r), http.StatusNotFound)
+		return
+	}
+	if http.MethodGet != r.Method {
+		methodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	s.listCouponRedemptions(w, r, segments[0])
+}
+
+//listCoupons handles listing all coupons
+func (s *Server) listCoupons(w http.ResponseWriter, r *http.Request) {
+	coupons, err := s.store.Coupons.FindAll()
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	responses := make([]couponResponse, 0, len(coupons))
+	for _, c := range coupons {
+		responses = append(responses, newCouponResponse(c))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+//listCouponRedemptions handles listing a coupon's redemptions, the reversed ones included
+func (s *Server) listCouponRedemptions(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.store.Coupons.FindByID(id); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	redemptions, err := s.store.Coupons.FindRedemptions(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	responses := make([]redemptionResponse, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		responses = append(responses, newRedemptionResponse(redemption))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+//createCoupon handles creating a new coupon (its id being its code)
+func (s *Server) createCoupon(w http.ResponseWriter, r *http.Request) {
+	var req couponRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if "" == req.ID {
+		writeError(w, errors.Wrap(fmt.Errorf("Can't create coupon without id"), 0), http.StatusBadRequest)
+		return
+	}
+	c, err := req.build(req.ID, nil)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := s.store.Coupons.Create(c); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newCouponResponse(c))
+}
+
+//getCoupon handles getting a coupon
+func (s *Server) getCoupon(w http.ResponseWriter, r *http.Request, id string) {
+	c, err := s.store.Coupons.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCouponResponse(c))
+}
+
+//updateCoupon handles updating a coupon's status, stock, kind, value, dates, conditions or limits
+func (s *Server) updateCoupon(w http.ResponseWriter, r *http.Request, id string) {
+	var req couponRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	current, err := s.store.Coupons.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	c, err := req.build(id, current)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	//the stored stock is left unchanged (orders may have used the coupon since it was loaded) unless the request replaces it
+	save := s.store.Coupons.Update
+	if req.Stock != nil {
+		save = s.store.Coupons.Save
+	}
+	if err := save(c); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCouponResponse(c))
+}
+
+//removeCoupon handles deleting a coupon
+func (s *Server) removeCoupon(w http.ResponseWriter, r *http.Request, id string) {
+	if err := s.store.Coupons.Delete(id); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,353 @@
+package rest_test
+
+import (
+	"fmt"
+	"net/http"
+	"sstest/model/coupon"
+	"sstest/rest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+//couponBody is the coupon JSON representation checked by tests
+type couponBody struct {
+	ID               string          `json:"id"`
+	Status           string          `json:"status"`
+	Stock            int64           `json:"stock"`
+	Kind             string          `json:"kind"`
+	Value            decimal.Decimal `json:"value"`
+	Currency         string          `json:"currency"`
+	StartDate        time.Time       `json:"startDate"`
+	EndDate          time.Time       `json:"endDate"`
+	MinSubtotal      decimal.Decimal `json:"minSubtotal"`
+	MinQuantity      int             `json:"minQuantity"`
+	MaxDiscount      decimal.Decimal `json:"maxDiscount"`
+	Products         []string        `json:"products"`
+	ExcludedProducts []string        `json:"excludedProducts"`
+	UserLimit        int             `json:"userLimit"`
+	RedemptionLimit  int             `json:"redemptionLimit"`
+	Stackable        bool            `json:"stackable"`
+	BuyQuantity      int             `json:"buyQuantity"`
+	FreeQuantity     int             `json:"freeQuantity"`
+	FreeProducts     []string        `json:"freeProducts"`
+	Tiers            []struct {
+		MinSpend   decimal.Decimal `json:"minSpend"`
+		Percentage decimal.Decimal `json:"percentage"`
+	} `json:"tiers"`
+	Automatic       bool `json:"automatic"`
+	Priority        int  `json:"priority"`
+	ExcludesCoupons bool `json:"excludesCoupons"`
+}
+
+//redemptionBody is the coupon redemption JSON representation checked by tests
+type redemptionBody struct {
+	CouponID     string          `json:"couponId"`
+	UserID       string          `json:"userId"`
+	OrderID      string          `json:"orderId"`
+	Amount       decimal.Decimal `json:"amount"`
+	Currency     string          `json:"currency"`
+	RedeemedDate time.Time       `json:"redeemedDate"`
+	ReversedDate time.Time       `json:"reversedDate"`
+}
+
+func TestCouponResource(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	startDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var created, switched, fetched, conditioned, limited couponBody
+	var listed []couponBody
+	createStatus := do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "NEWYEAR", "status": coupon.StatusActive, "stock": 5,
+		"kind": coupon.KindValue, "value": 150, "currency": "EUR", "startDate": startDate, "endDate": endDate}, &created)
+	invalidValueStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"kind": coupon.KindPercentage}, nil)
+	switchStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"kind": coupon.KindPercentage, "value": 20}, &switched)
+	conditionStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"minSubtotal": 50, "minQuantity": 2, "maxDiscount": 30,
+		"products": []string{"shirt"}, "excludedProducts": []string{"pants"}}, &conditioned)
+	limitStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"userLimit": 1, "redemptionLimit": 100}, &limited)
+	negativeLimitStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"userLimit": -1}, nil)
+	negativeConditionStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"minQuantity": -1}, nil)
+	invalidDatesStatus := do(server, http.MethodPut, "/coupons/NEWYEAR", map[string]interface{}{"startDate": endDate, "endDate": startDate}, nil)
+	fetchStatus := do(server, http.MethodGet, "/coupons/NEWYEAR", nil, &fetched)
+	listStatus := do(server, http.MethodGet, "/coupons", nil, &listed)
+
+	var couponResourceTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Coupon Value", "150", created.Value.String()},
+		{"Created Coupon Start Date", true, startDate.Equal(created.StartDate)},
+		{"Created Coupon Currency", "EUR", created.Currency},
+		{"Unknown Currency Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "BADCURRENCY", "currency": "XYZ"}, nil)},
+		{"Duplicate Status Code", http.StatusConflict, do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "save10"}, nil)},
+		{"Unknown Kind Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "BADKIND", "kind": "X"}, nil)},
+		{"Zero Value Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "ZERO", "value": 0}, nil)},
+		{"Percentage Over 100 Status Code", http.StatusBadRequest, invalidValueStatus},
+		{"Kind Switch Status Code", http.StatusOK, switchStatus},
+		{"Switched Coupon Kind", coupon.KindPercentage, switched.Kind},
+		{"Switched Coupon Value", "20", switched.Value.String()},
+		{"Switched Coupon Keeps Currency", "EUR", switched.Currency},
+		{"Created Coupon Without Products", 0, len(created.Products)},
+		{"Conditions Status Code", http.StatusOK, conditionStatus},
+		{"Conditioned Minimum Subtotal", "50", conditioned.MinSubtotal.String()},
+		{"Conditioned Minimum Quantity", 2, conditioned.MinQuantity},
+		{"Conditioned Maximum Discount", "30", conditioned.MaxDiscount.String()},
+		{"Conditioned Products", "shirt", conditioned.Products[0]},
+		{"Conditioned Excluded Products", "pants", conditioned.ExcludedProducts[0]},
+		{"Limits Status Code", http.StatusOK, limitStatus},
+		{"Limited User Limit", 1, limited.UserLimit},
+		{"Limited Redemption Limit", 100, limited.RedemptionLimit},
+		{"Limits Keep Conditions", 2, limited.MinQuantity},
+		{"Negative User Limit Status Code", http.StatusBadRequest, negativeLimitStatus},
+		{"Negative Minimum Quantity Status Code", http.StatusBadRequest, negativeConditionStatus},
+		{"Rejected Update Leaves Conditions", 2, fetched.MinQuantity},
+		{"Start After End Status Code", http.StatusBadRequest, invalidDatesStatus},
+		{"Rejected Update Leaves End Date", true, endDate.Equal(fetched.EndDate)},
+		{"Fetch Status Code", http.StatusOK, fetchStatus},
+		{"List Status Code", http.StatusOK, listStatus},
+		{"Listed Coupon Count", 2, len(listed)},
+		{"Delete Status Code", http.StatusNoContent, do(server, http.MethodDelete, "/coupons/NEWYEAR", nil, nil)},
+		{"Delete Missing Coupon Status Code", http.StatusNotFound, do(server, http.MethodDelete, "/coupons/NEWYEAR", nil, nil)},
+	}
+
+	for _, test := range couponResourceTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponRedemptions(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+	do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "ONCE", "status": coupon.StatusActive, "stock": 5, "kind": coupon.KindValue,
+		"value": 20, "redemptionLimit": 1}, nil)
+
+	for _, id := range []string{"order1", "order2"} {
+		do(server, http.MethodPost, "/orders", map[string]string{"id": id}, nil)
+		do(server, http.MethodPost, "/orders/"+id+"/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	}
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"couponCode": "ONCE"}, nil)
+	overLimitStatus := do(server, http.MethodPost, "/orders/order2/submit", map[string]string{"couponCode": "ONCE"}, nil)
+	cancelStatus := do(server, http.MethodPost, "/orders/order1/cancel", nil, nil)
+	afterCancelStatus := do(server, http.MethodPost, "/orders/order2/submit", map[string]string{"couponCode": "ONCE"}, nil)
+	var redemptions []redemptionBody
+	listStatus := do(server, http.MethodGet, "/coupons/ONCE/redemptions", nil, &redemptions)
+	if 2 != len(redemptions) {
+		t.Fatalf("expected 2 redemptions but got %d", len(redemptions))
+	}
+
+	var couponRedemptionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Over Redemption Limit Status Code", http.StatusConflict, overLimitStatus},
+		{"Cancel Status Code", http.StatusOK, cancelStatus},
+		{"Submit After Cancel Status Code", http.StatusOK, afterCancelStatus},
+		{"List Status Code", http.StatusOK, listStatus},
+		{"Reversed Redemption Order", "order1", redemptions[0].OrderID},
+		{"Reversed Redemption Is Reversed", false, redemptions[0].ReversedDate.IsZero()},
+		{"Redemption Order", "order2", redemptions[1].OrderID},
+		{"Redemption Coupon", "ONCE", redemptions[1].CouponID},
+		{"Redemption Amount", "20", redemptions[1].Amount.String()},
+		{"Redemption Currency", "USD", redemptions[1].Currency},
+		{"Redemption Is Not Reversed", true, redemptions[1].ReversedDate.IsZero()},
+		{"Missing Coupon Status Code", http.StatusNotFound, do(server, http.MethodGet, "/coupons/MISSING/redemptions", nil, nil)},
+		{"Unknown Coupon Path Status Code", http.StatusNotFound, do(server, http.MethodGet, "/coupons/ONCE/uses", nil, nil)},
+		{"Post Redemptions Status Code", http.StatusMethodNotAllowed, do(server, http.MethodPost, "/coupons/ONCE/redemptions", nil, nil)},
+	}
+
+	for _, test := range couponRedemptionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponStacking(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+	var created couponBody
+	createStatus := do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "PERCENT10", "status": coupon.StatusActive, "stock": 5,
+		"kind": coupon.KindPercentage, "value": 10, "stackable": true}, &created)
+	do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "VALUE10", "status": coupon.StatusActive, "stock": 5,
+		"kind": coupon.KindValue, "value": 10, "stackable": true}, nil)
+	var updated couponBody
+	updateStatus := do(server, http.MethodPut, "/coupons/VALUE10", map[string]interface{}{"stock": 4}, &updated)
+
+	for _, id := range []string{"order1", "order2"} {
+		do(server, http.MethodPost, "/orders", map[string]string{"id": id}, nil)
+		do(server, http.MethodPost, "/orders/"+id+"/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	}
+	var quoted struct {
+		Amount    decimal.Decimal `json:"amount"`
+		Breakdown breakdownBody   `json:"breakdown"`
+	}
+	quoteStatus := do(server, http.MethodGet, "/orders/order1/quote?couponCode=VALUE10&couponCode=PERCENT10", nil, &quoted)
+	exclusiveStatus := do(server, http.MethodGet, "/orders/order1/quote?couponCode=PERCENT10&couponCode=SAVE10", nil, nil)
+	exclusiveSubmitStatus := do(server, http.MethodPost, "/orders/order2/submit", map[string]interface{}{"couponCode": "SAVE10", "couponCodes": []string{"PERCENT10"}}, nil)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]interface{}{"couponCodes": []string{"VALUE10", "PERCENT10"}}, &submitted)
+	var value couponBody
+	do(server, http.MethodGet, "/coupons/VALUE10", nil, &value)
+	if 2 != len(quoted.Breakdown.Coupons) || 2 != len(submitted.Coupons) {
+		t.Fatalf("expected 2 applied coupons but got %d and %d", len(quoted.Breakdown.Coupons), len(submitted.Coupons))
+	}
+
+	var couponStackingTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Coupon Is Stackable", true, created.Stackable},
+		{"Update Status Code", http.StatusOK, updateStatus},
+		{"Updated Coupon Stays Stackable", true, updated.Stackable},
+		{"Quote Status Code", http.StatusOK, quoteStatus},
+		{"Quote Amount", "80", quoted.Amount.String()},
+		{"Quote Applies Percentage First", "PERCENT10", quoted.Breakdown.Coupons[0].CouponID},
+		{"Quote Applied Discount", "10", quoted.Breakdown.Coupons[1].Discount.String()},
+		{"Quote With Exclusive Coupon Status Code", http.StatusUnprocessableEntity, exclusiveStatus},
+		{"Submit With Exclusive Coupon Status Code", http.StatusUnprocessableEntity, exclusiveSubmitStatus},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Discount", "20", submitted.Breakdown.Discount.String()},
+		{"Submitted Coupon", "VALUE10", submitted.Coupons[1].CouponID},
+		{"Submitted Coupon Discount", "10", submitted.Coupons[0].Discount.String()},
+		{"Submitted Coupon Stock Is Saved", int64(3), value.Stock},
+	}
+
+	for _, test := range couponStackingTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponPromotions(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+	var created couponBody
+	createStatus := do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "BUY1GET1", "status": coupon.StatusActive, "stock": 5,
+		"kind": coupon.KindBuyXGetY, "products": []string{"availableProd"}, "buyQuantity": 1, "freeQuantity": 1}, &created)
+	var tiered couponBody
+	tieredStatus := do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "SPEND", "status": coupon.StatusActive, "stock": 5,
+		"kind": coupon.KindTiered, "tiers": []map[string]interface{}{{"minSpend": 500, "percentage": 10}, {"minSpend": 100, "percentage": 5}}}, &tiered)
+	invalidTierStatus := do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "INVALID", "kind": coupon.KindTiered,
+		"tiers": []map[string]interface{}{{"minSpend": 100, "percentage": 100}}}, nil)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 2}, nil)
+	var quoted struct {
+		Amount    decimal.Decimal `json:"amount"`
+		Breakdown breakdownBody   `json:"breakdown"`
+	}
+	quoteStatus := do(server, http.MethodGet, "/orders/order1/quote?couponCode=BUY1GET1", nil, &quoted)
+	var tieredQuoted struct {
+		Amount decimal.Decimal `json:"amount"`
+	}
+	do(server, http.MethodGet, "/orders/order1/quote?couponCode=SPEND", nil, &tieredQuoted)
+
+	var couponPromotionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Buy X Get Y Status Code", http.StatusCreated, createStatus},
+		{"Created Buy Quantity", 1, created.BuyQuantity},
+		{"Created Free Quantity", 1, created.FreeQuantity},
+		{"Created Free Products", 0, len(created.FreeProducts)},
+		{"Create Tiered Status Code", http.StatusCreated, tieredStatus},
+		{"Created Tiers Ordered By Minimum Spend", "100", tiered.Tiers[0].MinSpend.String()},
+		{"Created Tier Percentage", "10", tiered.Tiers[1].Percentage.String()},
+		{"Create Invalid Tier Status Code", http.StatusBadRequest, invalidTierStatus},
+		{"Quote Status Code", http.StatusOK, quoteStatus},
+		{"Quote Free Item Amount", "100", quoted.Amount.String()},
+		{"Quote Free Item Line Discount", "100", quoted.Breakdown.Lines[0].Discount.String()},
+		{"Quote Tiered Amount", "190", tieredQuoted.Amount.String()},
+	}
+
+	for _, test := range couponPromotionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestAutomaticPromotions(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+	var created couponBody
+	createStatus := do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "AUTO10", "status": coupon.StatusActive,
+		"kind": coupon.KindPercentage, "value": 10, "stackable": true, "automatic": true, "priority": 1}, &created)
+	do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "NOCOUPON", "status": coupon.StatusActive,
+		"kind": coupon.KindValue, "value": 20, "stackable": true, "automatic": true, "priority": 5, "excludesCoupons": true}, nil)
+
+	for _, id := range []string{"order1", "order2"} {
+		do(server, http.MethodPost, "/orders", map[string]string{"id": id}, nil)
+		do(server, http.MethodPost, "/orders/"+id+"/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	}
+	var quoted struct {
+		Amount    decimal.Decimal `json:"amount"`
+		Breakdown breakdownBody   `json:"breakdown"`
+	}
+	quoteStatus := do(server, http.MethodGet, "/orders/order1/quote", nil, &quoted)
+	var couponQuoted struct {
+		Amount    decimal.Decimal `json:"amount"`
+		Breakdown breakdownBody   `json:"breakdown"`
+	}
+	do(server, http.MethodGet, "/orders/order1/quote?couponCode=SAVE10", nil, &couponQuoted)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order2/submit", map[string]interface{}{}, &submitted)
+	var fetched orderBody
+	do(server, http.MethodGet, "/orders/order2", nil, &fetched)
+	if 2 != len(quoted.Breakdown.Promotions) || 1 != len(couponQuoted.Breakdown.Promotions) || 2 != len(fetched.Promotions) {
+		t.Fatalf("expected 2, 1 and 2 applied promotions but got %d, %d and %d", len(quoted.Breakdown.Promotions),
+			len(couponQuoted.Breakdown.Promotions), len(fetched.Promotions))
+	}
+
+	var automaticPromotionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Coupon Is Automatic", true, created.Automatic},
+		{"Created Priority", 1, created.Priority},
+		{"Created Coupon Doesn't Exclude Coupons", false, created.ExcludesCoupons},
+		{"Quote Status Code", http.StatusOK, quoteStatus},
+		{"Quote Amount", "72", quoted.Amount.String()},
+		{"Quote Applies Highest Priority First", "NOCOUPON", quoted.Breakdown.Promotions[0].CouponID},
+		{"Quote Promotion Discount", "8", quoted.Breakdown.Promotions[1].Discount.String()},
+		{"Quote Without Coupons", 0, len(quoted.Breakdown.Coupons)},
+		{"Quote With Coupon Amount", "80", couponQuoted.Amount.String()},
+		{"Quote With Coupon Skips Excluding Promotion", "AUTO10", couponQuoted.Breakdown.Promotions[0].CouponID},
+		{"Quote With Coupon Applied Coupon", "SAVE10", couponQuoted.Breakdown.Coupons[0].CouponID},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Amount", "72", submitted.Amount.String()},
+		{"Submitted Promotions Are Saved", "AUTO10", fetched.Promotions[1].CouponID},
+		{"Submitted Promotion Discount Is Saved", "20", fetched.Promotions[0].Discount.String()},
+	}
+
+	for _, test := range automaticPromotionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
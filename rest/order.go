@@ -0,0 +1,610 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+//orderResponse is the JSON representation of an order
+type orderResponse struct {
+	ID                 string             `json:"id"`
+	Status             string             `json:"status"`
+	CreatedDate        time.Time          `json:"createdDate"`
+	SubmittedDate      time.Time          `json:"submittedDate"`
+	ProcessedDate      time.Time          `json:"processedDate"`
+	Items              []itemResponse     `json:"items"`
+	Coupons            []appliedResponse  `json:"coupons"`    //in their application order
+	Promotions         []appliedResponse  `json:"promotions"` //the automatic promotions, in their application order
+	UserID             string             `json:"userId,omitempty"`
+	Amount             decimal.Decimal    `json:"amount"`
+	Currency           string             `json:"currency"`        //ISO 4217 code of every amount of the order
+	FormattedAmount    string             `json:"formattedAmount"` //amount formatted in the default locale
+	ShippingCost       decimal.Decimal    `json:"shippingCost"`    //included in amount
+	Tax                decimal.Decimal    `json:"tax"`             //included in amount, and in the items' prices when taxIncluded
+	TaxIncluded        bool               `json:"taxIncluded"`
+	Taxes              []taxResponse      `json:"taxes"`     //the items' taxes summed by name and rate
+	Breakdown          breakdownResponse  `json:"breakdown"` //the itemized amount (once submitted)
+	ShippingName       string             `json:"shippingName"`
+	ShippingAddress    string             `json:"shippingAddress"`
+	ShippingRegion     string             `json:"shippingRegion"`
+	ShippingStatus     string             `json:"shippingStatus"`
+	ShippingTrackingID string             `json:"shippingTrackingId"`
+	Shipments          []shipmentResponse `json:"shipments"`
+	Events             []string           `json:"events"`
+}
+
+//itemResponse is the JSON representation of an order item
+type itemResponse struct {
+	ID           string             `json:"id"`
+	ProductID    string             `json:"productId"`
+	Name         string             `json:"name"`   //captured on submission
+	Price        decimal.Decimal    `json:"price"`  //unit price captured on submission
+	Status       string             `json:"status"` //product status captured on submission
+	Quantity     int                `json:"quantity"`
+	Allocation   product.Allocation `json:"allocation,omitempty"` //quantity taken from every warehouse (once submitted)
+	Taxes        []taxResponse      `json:"taxes,omitempty"`      //in their rules' order (once submitted)
+	ExchangeRate decimal.Decimal    `json:"exchangeRate"`         //converting the product's price into the order's currency (captured on submission)
+	RateDate     time.Time          `json:"rateDate"`             //the time the exchange rate was quoted at
+}
+
+//taxResponse is the JSON representation of a tax of an order item or order
+type taxResponse struct {
+	Name   string          `json:"name"`
+	Rate   decimal.Decimal `json:"rate"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+//newTaxResponses creates the JSON representations of taxes
+func newTaxResponses(taxes []*order.Tax) []taxResponse {
+	responses := make([]taxResponse, 0, len(taxes))
+	for _, t := range taxes {
+		responses = append(responses, taxResponse{t.Name(), t.Rate(), t.Amount()})
+	}
+	return responses
+}
+
+//appliedResponse is the JSON representation of a coupon applied on an order and its discount
+type appliedResponse struct {
+	CouponID string          `json:"couponId"`
+	Discount decimal.Decimal `json:"discount"`
+}
+
+//newAppliedResponses creates the JSON representations of applied coupons
+func newAppliedResponses(applied []*order.AppliedCoupon) []appliedResponse {
+	responses := make([]appliedResponse, 0, len(applied))
+	for _, a := range applied {
+		responses = append(responses, appliedResponse{a.Coupon().ID(), a.Discount()})
+	}
+	return responses
+}
+
+//breakdownResponse is the JSON representation of an order's price breakdown, total is the order's amount
+type breakdownResponse struct {
+	Lines        []lineResponse    `json:"lines"` //ordered by product id
+	Subtotal     decimal.Decimal   `json:"subtotal"`
+	Discount     decimal.Decimal   `json:"discount"`   //the sum of the coupons' and promotions' discounts
+	Coupons      []appliedResponse `json:"coupons"`    //in their application order
+	Promotions   []appliedResponse `json:"promotions"` //the automatic promotions, applied before the coupons
+	ShippingCost decimal.Decimal   `json:"shippingCost"`
+	Tax          decimal.Decimal   `json:"tax"`
+	TaxIncluded  bool              `json:"taxIncluded"`
+	Total        decimal.Decimal   `json:"total"`
+}
+
+//lineResponse is the JSON representation of an order item's price breakdown
+type lineResponse struct {
+	ProductID string          `json:"productId"`
+	UnitPrice decimal.Decimal `json:"unitPrice"`
+	Quantity  int             `json:"quantity"`
+	Subtotal  decimal.Decimal `json:"subtotal"`
+	Discount  decimal.Decimal `json:"discount"` //the item's share of the coupons' discounts
+	Tax       decimal.Decimal `json:"tax"`
+}
+
+//newBreakdownResponse creates the JSON representation of a price breakdown
+func newBreakdownResponse(b *order.Breakdown) breakdownResponse {
+	lines := make([]lineResponse, 0, len(b.Lines()))
+	for _, l := range b.Lines() {
+		lines = append(lines, lineResponse{l.ProductID(), l.UnitPrice(), l.Quantity(), l.Subtotal(), l.Discount(), l.Tax()})
+	}
+	return breakdownResponse{lines, b.Subtotal(), b.Discount(), newAppliedResponses(b.AppliedCoupons()), newAppliedResponses(b.AppliedPromotions()),
+		b.ShippingCost(), b.Tax(), b.TaxIncluded(), b.Total()}
+}
+
+//shipmentResponse is the JSON representation of an order shipment
+type shipmentResponse struct {
+	ID            string                  `json:"id"`
+	Carrier       string                  `json:"carrier"`
+	TrackingID    string                  `json:"trackingId"`
+	Status        string                  `json:"status"`
+	Items         map[string]int          `json:"items"` //shipped quantities keyed by product id
+	ShippedDate   time.Time               `json:"shippedDate"`
+	DeliveredDate time.Time               `json:"deliveredDate"`
+	Events        []trackingEventResponse `json:"events"` //ordered by their date
+}
+
+//trackingEventResponse is the JSON representation of a shipment tracking event
+type trackingEventResponse struct {
+	Type        string    `json:"type"`
+	Date        time.Time `json:"date"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+//newShipmentResponse creates the JSON representation of an order shipment
+func newShipmentResponse(s *order.Shipment) shipmentResponse {
+	events := make([]trackingEventResponse, 0, len(s.Events()))
+	for _, e := range s.Events() {
+		events = append(events, trackingEventResponse{e.Kind(), e.Date(), e.Location(), e.Description()})
+	}
+	return shipmentResponse{s.ID(), s.Carrier(), s.TrackingID(), s.Status(), s.Items(), s.ShippedDate(), s.DeliveredDate(), events}
+}
+
+//newOrderResponse creates the JSON representation of an order (items ordered by product id, shipments in the order they were shipped)
+func newOrderResponse(o *order.Order) orderResponse {
+	items := make([]itemResponse, 0, len(o.Items()))
+	for _, item := range o.Items() {
+		items = append(items, itemResponse{item.ID(), item.Product().ID(), item.ProductName(), item.UnitPrice(), item.ProductStatus(), item.Quantity(),
+			item.Allocation(), newTaxResponses(item.Taxes()), item.ExchangeRate(), item.RateDate()})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ProductID < items[j].ProductID
+	})
+	shipments := make([]shipmentResponse, 0, len(o.Shipments()))
+	for _, s := range o.Shipments() {
+		shipments = append(shipments, newShipmentResponse(s))
+	}
+	var userID string
+	if o.User() != nil {
+		userID = o.User().ID()
+	}
+	return orderResponse{o.ID(), o.Status(), o.CreatedDate(), o.SubmittedDate(), o.ProcessedDate(), items, newAppliedResponses(o.AppliedCoupons()),
+		newAppliedResponses(o.AppliedPromotions()), userID, o.Amount(), o.Currency(), money.Format(o.Amount(), o.Currency()), o.ShippingCost(),
+		o.Tax(), o.TaxIncluded(), newTaxResponses(o.Taxes()),
+		newBreakdownResponse(o.Breakdown()), o.ShippingName(), o.ShippingAddress(), o.ShippingRegion(), o.ShippingStatus(), o.ShippingTrackingID(),
+		shipments, o.AllowedEvents()}
+}
+
+//quoteResponse is the JSON representation of a draft order's quote, amount includes shipping cost (and taxes)
+type quoteResponse struct {
+	Amount          decimal.Decimal   `json:"amount"`
+	Currency        string            `json:"currency"`
+	FormattedAmount string            `json:"formattedAmount"` //amount formatted in the default locale
+	ShippingCost    decimal.Decimal   `json:"shippingCost"`
+	Breakdown       breakdownResponse `json:"breakdown"`
+}
+
+//createOrderRequest is the JSON body of a draft order creation (a new id is generated when id is empty)
+type createOrderRequest struct {
+	ID       string `json:"id"`
+	UserID   string `json:"userId"`
+	Currency string `json:"currency"`
+}
+
+//itemRequest is the JSON body of an order item addition or edit
+type itemRequest struct {
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
+
+//submitRequest is the JSON body of an order submission
+type submitRequest struct {
+	ShippingName    string   `json:"shippingName"`
+	ShippingAddress string   `json:"shippingAddress"`
+	ShippingRegion  string   `json:"shippingRegion"`
+	CouponCode      string   `json:"couponCode"`
+	CouponCodes     []string `json:"couponCodes"`
+}
+
+//shippingRequest is the JSON body of an order shipment
+type shippingRequest struct {
+	Carrier    string         `json:"carrier"`
+	TrackingID string         `json:"trackingId"`
+	Items      map[string]int `json:"items"`
+}
+
+//trackingEventRequest is the JSON body of a shipment tracking event (the date defaults to now)
+type trackingEventRequest struct {
+	Type        string    `json:"type"`
+	Date        time.Time `json:"date"`
+	Location    string    `json:"location"`
+	Description string    `json:"description"`
+}
+
+//event creates the tracking event of a request
+func (req trackingEventRequest) event() *order.TrackingEvent {
+	if req.Date.IsZero() {
+		req.Date = time.Now()
+	}
+	return order.NewTrackingEvent(req.Type, req.Date, req.Location, req.Description)
+}
+
+//serveOrders dispatches an order request by its path segments (after "orders"):
+//
+//	GET    /orders?status={status}              list orders having a status
+//	POST   /orders                              create a draft order (optionally of a user)
+//	GET    /orders/{id}                         get an order
+//	GET    /orders/{id}/quote?couponCode={code}&shippingRegion={region}
+//	                                            quote a draft order's price breakdown (as submitted with the coupons and region,
+//	                                            couponCode can be repeated)
+//	POST   /orders/{id}/items                   add a product to a draft order
+//	PUT    /orders/{id}/items/{productId}       edit a product quantity in a draft order
+//	DELETE /orders/{id}/items/{productId}       delete a product from a draft order
+//	POST   /orders/{id}/submit                  submit a draft order (with optional coupon codes)
+//	POST   /orders/{id}/process                 process a submitted order
+//	POST   /orders/{id}/cancel                  cancel a submitted or processed order (returning its stock)
+//	POST   /orders/{id}/shipping                ship (part of) a processed order in a new shipment
+//	POST   /orders/{id}/shipments/{sid}/deliver mark a shipment as delivered (finishing the order once all are)
+//	POST   /orders/{id}/shipments/{sid}/events  record a tracking event of a shipment (see also POST /tracking)
+//	POST   /orders/{id}/finish                  finish a fully shipped order (delivering every shipment)
+//	POST   /orders/{id}/{event}                 fire another event of the order state machine (e.g. a custom status change)
+func (s *Server) serveOrders(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case 0 == len(segments) || "" == segments[0]:
+		switch r.Method {
+		case http.MethodGet:
+			s.listOrders(w, r)
+		case http.MethodPost:
+			s.createOrder(w, r)
+		default:
+			methodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+		}
+	case 1 == len(segments):
+		if http.MethodGet != r.Method {
+			methodNotAllowed(w, r, http.MethodGet)
+			return
+		}
+		s.getOrder(w, r, segments[0])
+	case 2 == len(segments) && "items" == segments[1]:
+		if http.MethodPost != r.Method {
+			methodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+		s.addProduct(w, r, segments[0])
+	case 3 == len(segments) && "items" == segments[1]:
+		switch r.Method {
+		case http.MethodPut:
+			s.editProduct(w, r, segments[0], segments[2])
+		case http.MethodDelete:
+			s.deleteProduct(w, r, segments[0], segments[2])
+		default:
+			methodNotAllowed(w, r, http.MethodPut, http.MethodDelete)
+		}
+	case 4 == len(segments) && "shipments" == segments[1] && "deliver" == segments[3]:
+		if http.MethodPost != r.Method {
+			methodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+		s.updateOrder(w, r, segments[0], http.StatusOK, func(o *order.Order) *errors.Error {
+			_, err := o.DeliverShipment(segments[2])
+			return err
+		})
+	case 4 == len(segments) && "shipments" == segments[1] && "events" == segments[3]:
+		if http.MethodPost != r.Method {
+			methodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+		var req trackingEventRequest
+		if err := readJSON(r, &req); err != nil {
+			writeError(w, err, http.StatusBadRequest)
+			return
+		}
+		s.updateOrder(w, r, segments[0], http.StatusOK, func(o *order.Order) *errors.Error {
+			_, err := o.TrackShipment(segments[2], req.event())
+			return err
+		})
+	case 2 == len(segments) && "quote" == segments[1]:
+		if http.MethodGet != r.Method {
+			methodNotAllowed(w, r, http.MethodGet)
+			return
+		}
+		s.quoteOrder(w, r, segments[0])
+	case 2 == len(segments):
+		if http.MethodPost != r.Method {
+			methodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+		s.serveOrderAction(w, r, segments[0], segments[1])
+	default:
+		writeError(w, unknownResource(r), http.StatusNotFound)
+	}
+}
+
+//serveOrderAction handles a status changing action on an order
+func (s *Server) serveOrderAction(w http.ResponseWriter, r *http.Request, id, action string) {
+	switch action {
+	case "submit":
+		s.submitOrder(w, r, id)
+	case "process":
+		s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+			_, err := o.Process()
+			return err
+		})
+	case "cancel":
+		s.cancelOrder(w, r, id)
+	case "shipping":
+		var req shippingRequest
+		if err := readJSON(r, &req); err != nil {
+			writeError(w, err, http.StatusBadRequest)
+			return
+		}
+		s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+			_, err := o.Ship(req.Carrier, req.TrackingID, req.Items)
+			return err
+		})
+	case "finish":
+		s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+			_, err := o.FinishOrder()
+			return err
+		})
+	default:
+		if false == order.DefaultStateMachine().HasEvent(action) {
+			writeError(w, unknownResource(r), http.StatusNotFound)
+			return
+		}
+		s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+			_, err := o.Fire(action)
+			return err
+		})
+	}
+}
+
+//serveTracking handles a carrier tracking webhook, recording a tracking event of the shipment having a carrier and tracking id:
+//
+//	POST   /tracking/{carrier}/{trackingId}     record a tracking event
+func (s *Server) serveTracking(w http.ResponseWriter, r *http.Request, segments []string) {
+	if 2 != len(segments) {
+		writeError(w, unknownResource(r), http.StatusNotFound)
+		return
+	}
+	if http.MethodPost != r.Method {
+		methodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	var req trackingEventRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	o, err := s.store.Orders.FindByTracking(segments[0], segments[1])
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	shipment, _ := o.TrackedShipment(segments[0], segments[1])
+	if _, err := o.TrackShipment(shipment.ID(), req.event()); err != nil {
+		writeError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := s.store.Orders.Save(o); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newShipmentResponse(shipment))
+}
+
+//listOrders handles listing the orders having the status given in the "status" query parameter
+func (s *Server) listOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := s.store.Orders.FindByStatus(r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	responses := make([]orderResponse, 0, len(orders))
+	for _, o := range orders {
+		responses = append(responses, newOrderResponse(o))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+//getOrder handles getting an order
+func (s *Server) getOrder(w http.ResponseWriter, r *http.Request, id string) {
+	o, err := s.store.Orders.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newOrderResponse(o))
+}
+
+//listUserOrders handles listing the orders of a user
+func (s *Server) listUserOrders(w http.ResponseWriter, r *http.Request, userID string) {
+	if _, err := s.store.Users.FindByID(userID); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	orders, err := s.store.Orders.FindByUser(userID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	responses := make([]orderResponse, 0, len(orders))
+	for _, o := range orders {
+		responses = append(responses, newOrderResponse(o))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+//createOrder handles creating a new draft order
+func (s *Server) createOrder(w http.ResponseWriter, r *http.Request) {
+	var req createOrderRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if "" == req.ID {
+		req.ID = uuid.New().String()
+	}
+	if _, err := s.store.Orders.FindByID(req.ID); nil == err {
+		writeError(w, repository.Duplicate("order", req.ID), http.StatusConflict)
+		return
+	} else if false == errors.Is(err, repository.ErrNotFound) {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	o := order.New(req.ID)
+	if "" != req.UserID {
+		u, err := s.store.Users.FindByID(req.UserID)
+		if err != nil {
+			writeError(w, err, http.StatusInternalServerError)
+			return
+		}
+		o.SetUser(u)
+	}
+	if "" != req.Currency {
+		if _, err := o.ChangeCurrency(req.Currency); err != nil {
+			writeError(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+	if err := s.store.Orders.Save(o); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newOrderResponse(o))
+}
+
+//addProduct handles adding a product to a draft order
+func (s *Server) addProduct(w http.ResponseWriter, r *http.Request, id string) {
+	var req itemRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	p, err := s.store.Products.FindByID(req.ProductID)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+		_, err := o.SetInventory(s.store.Products).AddProduct(p, req.Quantity)
+		return err
+	})
+}
+
+//editProduct handles editing a product quantity in a draft order
+func (s *Server) editProduct(w http.ResponseWriter, r *http.Request, id, productID string) {
+	var req itemRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+		item, ok := o.Items()[productID]
+		if false == ok {
+			return errors.WrapPrefix(order.ErrItemNotFound, fmt.Sprintf("Can't edit, order %v has no product with id: %v", o.ID(), productID), 0)
+		}
+		_, err := o.SetInventory(s.store.Products).EditProduct(item.Product(), req.Quantity)
+		return err
+	})
+}
+
+//deleteProduct handles deleting a product from a draft order
+func (s *Server) deleteProduct(w http.ResponseWriter, r *http.Request, id, productID string) {
+	s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+		item, ok := o.Items()[productID]
+		if false == ok {
+			return errors.WrapPrefix(order.ErrItemNotFound, fmt.Sprintf("Can't delete, order %v has no product with id: %v", o.ID(), productID), 0)
+		}
+		_, err := o.SetInventory(s.store.Products).DeleteProduct(item.Product())
+		return err
+	})
+}
+
+//submitOrder handles submitting a draft order, decrementing product stocks in the product repository
+func (s *Server) submitOrder(w http.ResponseWriter, r *http.Request, id string) {
+	var req submitRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	codes := req.CouponCodes
+	if "" != req.CouponCode {
+		codes = append([]string{req.CouponCode}, codes...)
+	}
+	coupons, err := s.findCoupons(codes)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+		if "" != req.ShippingRegion {
+			o.SetShippingRegion(req.ShippingRegion)
+		}
+		_, err := o.SetInventory(s.store.Products).SetLedger(s.store.Coupons).SetPromotions(s.store.Coupons).SetSubmitter(s.store.Orders).Submit(req.ShippingName, req.ShippingAddress, coupons...)
+		return err
+	})
+}
+
+//quoteOrder handles quoting a draft order with the coupon codes and shipping region given in its query parameters
+func (s *Server) quoteOrder(w http.ResponseWriter, r *http.Request, id string) {
+	o, err := s.store.Orders.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	coupons, err := s.findCoupons(r.URL.Query()["couponCode"])
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	b, err := o.SetPromotions(s.store.Coupons).Quote(r.URL.Query().Get("shippingRegion"), coupons...)
+	if err != nil {
+		writeError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, http.StatusOK, quoteResponse{b.Total(), o.Currency(), money.Format(b.Total(), o.Currency()), b.ShippingCost(), newBreakdownResponse(b)})
+}
+
+//cancelOrder handles canceling a submitted or processed order, returning its product stocks to the product repository and its coupons' uses
+func (s *Server) cancelOrder(w http.ResponseWriter, r *http.Request, id string) {
+	s.updateOrder(w, r, id, http.StatusOK, func(o *order.Order) *errors.Error {
+		_, err := o.SetCanceler(s.store.Orders).Cancel()
+		return err
+	})
+}
+
+//findCoupons finds the coupons having the given codes (in their given order, empty codes are skipped)
+func (s *Server) findCoupons(codes []string) ([]*coupon.Coupon, *errors.Error) {
+	coupons := make([]*coupon.Coupon, 0, len(codes))
+	for _, code := range codes {
+		if "" == code {
+			continue
+		}
+		c, err := s.store.Coupons.FindByCode(code)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, c)
+	}
+	return coupons, nil
+}
+
+//updateOrder loads an order, applies a change on it, saves it and writes it as response
+func (s *Server) updateOrder(w http.ResponseWriter, r *http.Request, id string, status int, change func(o *order.Order) *errors.Error) {
+	o, err := s.store.Orders.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if err := change(o); err != nil {
+		writeError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := s.store.Orders.Save(o); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, status, newOrderResponse(o))
+}
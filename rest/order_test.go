@@ -0,0 +1,712 @@
+//rest_test provides unit tests for the JSON over HTTP API
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/repository"
+	"sstest/rest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+//newTestStore creates an in-memory store with an available product, a limited product and an active coupon
+func newTestStore() *repository.Store {
+	store := repository.NewMemoryStore()
+
+	availableProd := product.New("availableProd", "Available Product")
+	availableProd.SetStatus(product.StatusAvailable)
+	availableProd.SetStock(100)
+	availableProd.SetPrice(decimal.New(100, 0))
+	store.Products.Save(availableProd)
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(5)
+	limitedProd.SetPrice(decimal.New(150, 0))
+	store.Products.Save(limitedProd)
+
+	activeCoupon := coupon.New("SAVE10")
+	activeCoupon.SetStatus(coupon.StatusActive)
+	activeCoupon.SetStock(10)
+	activeCoupon.SetKind(coupon.KindValue)
+	activeCoupon.SetValue(decimal.New(10, 0))
+	store.Coupons.Create(activeCoupon)
+
+	return store
+}
+
+//do sends a request with a JSON body (if not nil) to the handler and decodes the JSON response into out (if not nil)
+func do(handler http.Handler, method, path string, body interface{}, out interface{}) int {
+	var reqBody bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&reqBody).Encode(body)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, &reqBody))
+	if out != nil {
+		json.NewDecoder(rec.Body).Decode(out)
+	}
+	return rec.Code
+}
+
+//orderBody is the subset of the order JSON representation checked by tests
+type orderBody struct {
+	ID                 string          `json:"id"`
+	Status             string          `json:"status"`
+	Amount             decimal.Decimal `json:"amount"`
+	Currency           string          `json:"currency"`
+	FormattedAmount    string          `json:"formattedAmount"`
+	ShippingCost       decimal.Decimal `json:"shippingCost"`
+	Tax                decimal.Decimal `json:"tax"`
+	TaxIncluded        bool            `json:"taxIncluded"`
+	Taxes              []taxBody       `json:"taxes"`
+	Breakdown          breakdownBody   `json:"breakdown"`
+	Coupons            []appliedBody   `json:"coupons"`
+	Promotions         []appliedBody   `json:"promotions"`
+	UserID             string          `json:"userId"`
+	ShippingName       string          `json:"shippingName"`
+	ShippingAddress    string          `json:"shippingAddress"`
+	ShippingRegion     string          `json:"shippingRegion"`
+	ShippingStatus     string          `json:"shippingStatus"`
+	ShippingTrackingID string          `json:"shippingTrackingId"`
+	Events             []string        `json:"events"`
+	Items              []struct {
+		ProductID    string          `json:"productId"`
+		Name         string          `json:"name"`
+		Price        decimal.Decimal `json:"price"`
+		Status       string          `json:"status"`
+		Quantity     int             `json:"quantity"`
+		Taxes        []taxBody       `json:"taxes"`
+		ExchangeRate decimal.Decimal `json:"exchangeRate"`
+		RateDate     time.Time       `json:"rateDate"`
+	} `json:"items"`
+	Shipments []struct {
+		ID      string         `json:"id"`
+		Carrier string         `json:"carrier"`
+		Status  string         `json:"status"`
+		Items   map[string]int `json:"items"`
+	} `json:"shipments"`
+}
+
+//breakdownBody is the subset of the price breakdown JSON representation checked by tests
+type breakdownBody struct {
+	Subtotal   decimal.Decimal `json:"subtotal"`
+	Discount   decimal.Decimal `json:"discount"`
+	Coupons    []appliedBody   `json:"coupons"`
+	Promotions []appliedBody   `json:"promotions"`
+	Total      decimal.Decimal `json:"total"`
+	Lines      []struct {
+		ProductID string          `json:"productId"`
+		UnitPrice decimal.Decimal `json:"unitPrice"`
+		Subtotal  decimal.Decimal `json:"subtotal"`
+		Discount  decimal.Decimal `json:"discount"`
+	} `json:"lines"`
+}
+
+//appliedBody is the applied coupon JSON representation checked by tests
+type appliedBody struct {
+	CouponID string          `json:"couponId"`
+	Discount decimal.Decimal `json:"discount"`
+}
+
+//taxBody is the tax JSON representation checked by tests
+type taxBody struct {
+	Name   string          `json:"name"`
+	Rate   decimal.Decimal `json:"rate"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	var created, added, edited, deleted, submitted, processed, shipped, finished orderBody
+	createStatus := do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, &created)
+	addStatus := do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 2}, &added)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "limitedProd", "quantity": 1}, nil)
+	editStatus := do(server, http.MethodPut, "/orders/order1/items/availableProd", map[string]interface{}{"quantity": 1}, &edited)
+	deleteStatus := do(server, http.MethodDelete, "/orders/order1/items/limitedProd", nil, &deleted)
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"shippingName": "ship name", "shippingAddress": "ship address", "couponCode": "save10"}, &submitted)
+	processStatus := do(server, http.MethodPost, "/orders/order1/process", nil, &processed)
+	shippingStatus := do(server, http.MethodPost, "/orders/order1/shipping", map[string]string{"trackingId": "dummyTrackingNo"}, &shipped)
+	finishStatus := do(server, http.MethodPost, "/orders/order1/finish", nil, &finished)
+	var listed []orderBody
+	listStatus := do(server, http.MethodGet, "/orders?status="+order.StatusDelivered, nil, &listed)
+
+	availableProd, _ := store.Products.FindByID("availableProd")
+	usedCoupon, _ := store.Coupons.FindByCode("SAVE10")
+
+	var orderLifecycleTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Order Status", order.StatusDraft, created.Status},
+		{"Add Product Status Code", http.StatusOK, addStatus},
+		{"Added Item Quantity", 2, added.Items[0].Quantity},
+		{"Edit Product Status Code", http.StatusOK, editStatus},
+		{"Edited Item Quantity", 3, edited.Items[0].Quantity},
+		{"Delete Product Status Code", http.StatusOK, deleteStatus},
+		{"Item Count After Delete", 1, len(deleted.Items)},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Order Status", order.StatusSubmitted, submitted.Status},
+		{"Submitted Order Amount", "290", submitted.Amount.String()},
+		{"Submitted Order Coupon", "SAVE10", submitted.Coupons[0].CouponID},
+		{"Submitted Order Shipping Name", "ship name", submitted.ShippingName},
+		{"Submitted Order Events", "process,cancel", strings.Join(submitted.Events, ",")},
+		{"Submitted Product Stock", int64(97), availableProd.Stock()},
+		{"Submitted Coupon Stock", int64(9), usedCoupon.Stock()},
+		{"Process Status Code", http.StatusOK, processStatus},
+		{"Processed Order Status", order.StatusProcessed, processed.Status},
+		{"Shipping Status Code", http.StatusOK, shippingStatus},
+		{"Shipped Order Tracking ID", "dummyTrackingNo", shipped.ShippingTrackingID},
+		{"Finish Status Code", http.StatusOK, finishStatus},
+		{"Finished Order Status", order.StatusDelivered, finished.Status},
+		{"Finished Order Shipping Status", order.ShipStatusDelivered, finished.ShippingStatus},
+		{"List Status Code", http.StatusOK, listStatus},
+		{"Listed Order Count", 1, len(listed)},
+	}
+
+	for _, test := range orderLifecycleTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestQuoteOrder(t *testing.T) {
+	order.SetDefaultShippingRate(order.NewFreeOverThreshold(decimal.New(300, 0), "USD", order.NewFlatRate(decimal.New(15, 0), "USD")))
+	t.Cleanup(func() { order.SetDefaultShippingRate(nil) })
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 2}, nil)
+	emptyStatus := do(server, http.MethodPost, "/orders", map[string]string{"id": "emptyOrder"}, nil)
+	var quoted, couponQuoted, overQuoted struct {
+		Amount       decimal.Decimal `json:"amount"`
+		ShippingCost decimal.Decimal `json:"shippingCost"`
+		Breakdown    breakdownBody   `json:"breakdown"`
+	}
+	quoteStatus := do(server, http.MethodGet, "/orders/order1/quote", nil, &quoted)
+	couponQuoteStatus := do(server, http.MethodGet, "/orders/order1/quote?couponCode=SAVE10", nil, &couponQuoted)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"couponCode": "SAVE10"}, &submitted)
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order2"}, nil)
+	do(server, http.MethodPost, "/orders/order2/items", map[string]interface{}{"productId": "availableProd", "quantity": 3}, nil)
+	do(server, http.MethodGet, "/orders/order2/quote", nil, &overQuoted)
+
+	var quoteOrderTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Quote Status Code", http.StatusOK, quoteStatus},
+		{"Quoted Shipping Cost", "15", quoted.ShippingCost.String()},
+		{"Quoted Amount", "215", quoted.Amount.String()},
+		{"Coupon Quote Status Code", http.StatusOK, couponQuoteStatus},
+		{"Coupon Quoted Amount", "205", couponQuoted.Amount.String()},
+		{"Coupon Quoted Subtotal", "200", couponQuoted.Breakdown.Subtotal.String()},
+		{"Coupon Quoted Discount", "10", couponQuoted.Breakdown.Discount.String()},
+		{"Coupon Quoted Total Must Be Amount", couponQuoted.Amount.String(), couponQuoted.Breakdown.Total.String()},
+		{"Coupon Quoted Line Unit Price", "100", couponQuoted.Breakdown.Lines[0].UnitPrice.String()},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Amount Must Be Coupon Quoted Amount", couponQuoted.Amount.String(), submitted.Amount.String()},
+		{"Submitted Shipping Cost", "15", submitted.ShippingCost.String()},
+		{"Submitted Breakdown Total", "205", submitted.Breakdown.Total.String()},
+		{"Submitted Breakdown Line Subtotal", "200", submitted.Breakdown.Lines[0].Subtotal.String()},
+		{"Submitted Breakdown Line Discount", "10", submitted.Breakdown.Lines[0].Discount.String()},
+		{"Quote Submitted Order Status Code", http.StatusConflict, do(server, http.MethodGet, "/orders/order1/quote", nil, nil)},
+		{"Free Over Threshold Quoted Shipping Cost", "0", overQuoted.ShippingCost.String()},
+		{"Empty Order Create Status Code", http.StatusCreated, emptyStatus},
+		{"Quote Empty Order Status Code", http.StatusUnprocessableEntity, do(server, http.MethodGet, "/orders/emptyOrder/quote", nil, nil)},
+		{"Quote Missing Coupon Status Code", http.StatusNotFound, do(server, http.MethodGet, "/orders/order2/quote?couponCode=MISSING", nil, nil)},
+		{"Quote Missing Order Status Code", http.StatusNotFound, do(server, http.MethodGet, "/orders/missingOrder/quote", nil, nil)},
+		{"Quote Method Not Allowed", http.StatusMethodNotAllowed, do(server, http.MethodPost, "/orders/order2/quote", nil, nil)},
+	}
+
+	for _, test := range quoteOrderTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderTaxes(t *testing.T) {
+	order.SetDefaultTaxTable(order.NewTaxTable(
+		order.NewTaxRegion("CA-QC", false,
+			order.NewTaxRule("GST", "", decimal.New(5, -2), false),
+			order.NewTaxRule("QST", "", decimal.New(9975, -5), false))))
+	t.Cleanup(func() { order.SetDefaultTaxTable(nil) })
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	var quoted, untaxedQuoted struct {
+		Amount decimal.Decimal `json:"amount"`
+	}
+	quoteStatus := do(server, http.MethodGet, "/orders/order1/quote?shippingRegion=CA-QC", nil, &quoted)
+	do(server, http.MethodGet, "/orders/order1/quote", nil, &untaxedQuoted)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"shippingName": "ship name", "shippingAddress": "ship address", "shippingRegion": "CA-QC"}, &submitted)
+
+	var orderTaxTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Quote Status Code", http.StatusOK, quoteStatus},
+		{"Quoted Amount Must Add Taxes", "114.98", quoted.Amount.String()},
+		{"Quote Without Region Must Not Tax", "100", untaxedQuoted.Amount.String()},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Shipping Region", "CA-QC", submitted.ShippingRegion},
+		{"Submitted Amount Must Be Quoted Amount", quoted.Amount.String(), submitted.Amount.String()},
+		{"Submitted Tax", "14.98", submitted.Tax.String()},
+		{"Submitted Taxes Are Not Included", false, submitted.TaxIncluded},
+		{"Submitted Order Tax Count", 2, len(submitted.Taxes)},
+		{"Submitted First Order Tax", "GST=5", submitted.Taxes[0].Name + "=" + submitted.Taxes[0].Amount.String()},
+		{"Submitted Item Tax", "QST=9.98", submitted.Items[0].Taxes[1].Name + "=" + submitted.Items[0].Taxes[1].Amount.String()},
+	}
+
+	for _, test := range orderTaxTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestItemSnapshot(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 2}, nil)
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order2"}, nil)
+	do(server, http.MethodPost, "/orders/order2/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"shippingName": "ship name", "shippingAddress": "ship address"}, nil)
+	availableProd, _ := store.Products.FindByID("availableProd")
+	availableProd.SetName("Renamed Product")
+	availableProd.SetPrice(decimal.New(120, 0))
+	availableProd.SetStatus(product.StatusDiscontinued)
+	var submitted, draft orderBody
+	do(server, http.MethodGet, "/orders/order1", nil, &submitted)
+	do(server, http.MethodGet, "/orders/order2", nil, &draft)
+
+	var itemSnapshotTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Submitted Item Name", "Available Product", submitted.Items[0].Name},
+		{"Submitted Item Price", "100", submitted.Items[0].Price.String()},
+		{"Submitted Item Status", product.StatusAvailable, submitted.Items[0].Status},
+		{"Submitted Amount", "200", submitted.Amount.String()},
+		{"Submitted Breakdown Unit Price", "100", submitted.Breakdown.Lines[0].UnitPrice.String()},
+		{"Draft Item Name", "Renamed Product", draft.Items[0].Name},
+		{"Draft Item Price", "120", draft.Items[0].Price.String()},
+		{"Draft Item Status", product.StatusDiscontinued, draft.Items[0].Status},
+	}
+
+	for _, test := range itemSnapshotTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderCurrency(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+	do(server, http.MethodPost, "/products", map[string]interface{}{"id": "euroProd", "price": "1234.5", "stock": 10, "status": product.StatusAvailable, "currency": "EUR"}, nil)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	addStatus := do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "euroProd", "quantity": 1}, nil)
+	mixedStatus := do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	var quoted struct {
+		Currency        string `json:"currency"`
+		FormattedAmount string `json:"formattedAmount"`
+	}
+	quoteStatus := do(server, http.MethodGet, "/orders/order1/quote", nil, &quoted)
+	couponStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"couponCode": "SAVE10"}, nil)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, &submitted)
+
+	var orderCurrencyTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Add Status Code", http.StatusOK, addStatus},
+		{"Product In Other Currency Status Code", http.StatusUnprocessableEntity, mixedStatus},
+		{"Quote Status Code", http.StatusOK, quoteStatus},
+		{"Quoted Currency", "EUR", quoted.Currency},
+		{"Quoted Formatted Amount", "€1,234.50", quoted.FormattedAmount},
+		{"Value Coupon In Other Currency Status Code", http.StatusUnprocessableEntity, couponStatus},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Currency", "EUR", submitted.Currency},
+		{"Submitted Formatted Amount", "€1,234.50", submitted.FormattedAmount},
+	}
+
+	for _, test := range orderCurrencyTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderExchangeRates(t *testing.T) {
+	rateDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	order.SetDefaultExchangeRates(money.NewRateTable("USD", rateDate, map[string]decimal.Decimal{"EUR": decimal.New(8, -1)}))
+	t.Cleanup(func() { order.SetDefaultExchangeRates(nil) })
+	store := newTestStore()
+	server := rest.NewServer(store)
+	do(server, http.MethodPost, "/products", map[string]interface{}{"id": "euroProd", "price": "100", "stock": 10, "status": product.StatusAvailable, "currency": "EUR"}, nil)
+	do(server, http.MethodPost, "/products", map[string]interface{}{"id": "swissProd", "price": "100", "stock": 10, "status": product.StatusAvailable, "currency": "CHF"}, nil)
+
+	var created orderBody
+	createStatus := do(server, http.MethodPost, "/orders", map[string]string{"id": "order1", "currency": "USD"}, &created)
+	unknownStatus := do(server, http.MethodPost, "/orders", map[string]string{"id": "order2", "currency": "XYZ"}, nil)
+	euroStatus := do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "euroProd", "quantity": 1}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	swissStatus := do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "swissProd", "quantity": 1}, nil)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, &submitted)
+
+	var orderExchangeRateTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Currency", "USD", created.Currency},
+		{"Unknown Currency Status Code", http.StatusBadRequest, unknownStatus},
+		{"Product In Other Currency Status Code", http.StatusOK, euroStatus},
+		{"Product Without Rate Status Code", http.StatusUnprocessableEntity, swissStatus},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Submitted Currency", "USD", submitted.Currency},
+		{"Submitted Amount", "225", submitted.Amount.String()},
+		{"Converted Item Price", "125", submitted.Items[1].Price.String()},
+		{"Converted Item Exchange Rate", "1.25", submitted.Items[1].ExchangeRate.String()},
+		{"Converted Item Rate Date", rateDate, submitted.Items[1].RateDate},
+		{"Item In Order Currency Exchange Rate", "1", submitted.Items[0].ExchangeRate.String()},
+	}
+
+	for _, test := range orderExchangeRateTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponConditions(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+	do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "LIMITED20", "status": coupon.StatusActive, "stock": 5, "kind": coupon.KindPercentage,
+		"value": 20, "maxDiscount": 25, "products": []string{"limitedProd"}}, nil)
+	do(server, http.MethodPost, "/coupons", map[string]interface{}{"id": "BIGSPEND", "status": coupon.StatusActive, "stock": 5, "kind": coupon.KindPercentage,
+		"value": 20, "minSubtotal": 500}, nil)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 2}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "limitedProd", "quantity": 1}, nil)
+	ineligibleStatus := do(server, http.MethodGet, "/orders/order1/quote?couponCode=BIGSPEND", nil, nil)
+	ineligibleSubmitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"couponCode": "BIGSPEND"}, nil)
+	var submitted orderBody
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"couponCode": "LIMITED20"}, &submitted)
+
+	var couponConditionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Ineligible Quote Status Code", http.StatusUnprocessableEntity, ineligibleStatus},
+		{"Ineligible Submit Status Code", http.StatusUnprocessableEntity, ineligibleSubmitStatus},
+		{"Submit Status Code", http.StatusOK, submitStatus},
+		{"Capped Discount", "25", submitted.Breakdown.Discount.String()},
+		{"Submitted Amount", "325", submitted.Amount.String()},
+		{"Other Product Line Not Discounted", "0", submitted.Breakdown.Lines[0].Discount.String()},
+		{"Applicable Product Line Discount", "25", submitted.Breakdown.Lines[1].Discount.String()},
+	}
+
+	for _, test := range couponConditionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 3}, nil)
+	do(server, http.MethodPost, "/orders/order1/submit", map[string]string{"couponCode": "SAVE10"}, nil)
+	do(server, http.MethodPost, "/orders/order1/process", nil, nil)
+	var canceled orderBody
+	cancelStatus := do(server, http.MethodPost, "/orders/order1/cancel", nil, &canceled)
+
+	availableProd, _ := store.Products.FindByID("availableProd")
+	usedCoupon, _ := store.Coupons.FindByCode("SAVE10")
+
+	var cancelOrderTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Cancel Status Code", http.StatusOK, cancelStatus},
+		{"Canceled Order Status", order.StatusCanceled, canceled.Status},
+		{"Canceled Product Stock", int64(100), availableProd.Stock()},
+		{"Canceled Coupon Stock", int64(10), usedCoupon.Stock()},
+		{"Cancel Canceled Order Status Code", http.StatusConflict, do(server, http.MethodPost, "/orders/order1/cancel", nil, nil)},
+	}
+
+	for _, test := range cancelOrderTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestSplitShipments(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 3}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "limitedProd", "quantity": 1}, nil)
+	do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, nil)
+	do(server, http.MethodPost, "/orders/order1/process", nil, nil)
+
+	var partial, shipped, firstDelivered, delivered orderBody
+	partialStatus := do(server, http.MethodPost, "/orders/order1/shipping",
+		map[string]interface{}{"carrier": "firstCarrier", "trackingId": "firstTrackingNo", "items": map[string]int{"availableProd": 2}}, &partial)
+	overShipStatus := do(server, http.MethodPost, "/orders/order1/shipping", map[string]interface{}{"items": map[string]int{"limitedProd": 2}}, nil)
+	earlyFinishStatus := do(server, http.MethodPost, "/orders/order1/finish", nil, nil)
+	shippedStatus := do(server, http.MethodPost, "/orders/order1/shipping", map[string]interface{}{"carrier": "secondCarrier", "trackingId": "secondTrackingNo"}, &shipped)
+	missingDeliverStatus := do(server, http.MethodPost, "/orders/order1/shipments/missingShipment/deliver", nil, nil)
+	var firstID, secondID string
+	if 2 == len(shipped.Shipments) {
+		firstID, secondID = shipped.Shipments[0].ID, shipped.Shipments[1].ID
+	}
+	firstDeliverStatus := do(server, http.MethodPost, "/orders/order1/shipments/"+firstID+"/deliver", nil, &firstDelivered)
+	secondDeliverStatus := do(server, http.MethodPost, "/orders/order1/shipments/"+secondID+"/deliver", nil, &delivered)
+
+	var splitShipmentTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Partial Shipping Status Code", http.StatusOK, partialStatus},
+		{"Partially Shipped Status", order.ShipStatusPartiallyShipped, partial.ShippingStatus},
+		{"Partial Shipment Count", 1, len(partial.Shipments)},
+		{"Partial Shipment Carrier", "firstCarrier", partial.Shipments[0].Carrier},
+		{"Partial Shipment Quantity", 2, partial.Shipments[0].Items["availableProd"]},
+		{"Over Shipment Status Code", http.StatusUnprocessableEntity, overShipStatus},
+		{"Partially Shipped Finish Status Code", http.StatusConflict, earlyFinishStatus},
+		{"Remaining Shipping Status Code", http.StatusOK, shippedStatus},
+		{"Shipped Status", order.ShipStatusOnProcess, shipped.ShippingStatus},
+		{"Remaining Shipment Items", 2, len(shipped.Shipments[1].Items)},
+		{"Missing Shipment Delivery Status Code", http.StatusNotFound, missingDeliverStatus},
+		{"First Delivery Status Code", http.StatusOK, firstDeliverStatus},
+		{"First Delivered Shipment Status", order.ShipmentStatusDelivered, firstDelivered.Shipments[0].Status},
+		{"First Delivered Order Status", order.StatusProcessed, firstDelivered.Status},
+		{"Last Delivery Status Code", http.StatusOK, secondDeliverStatus},
+		{"Delivered Shipping Status", order.ShipStatusDelivered, delivered.ShippingStatus},
+		{"Delivered Order Status", order.StatusDelivered, delivered.Status},
+	}
+
+	for _, test := range splitShipmentTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestTrackingEvents(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, nil)
+	do(server, http.MethodPost, "/orders/order1/process", nil, nil)
+	var shipped, inTransit, outForDelivery orderBody
+	do(server, http.MethodPost, "/orders/order1/shipping", map[string]string{"carrier": "acme", "trackingId": "T1"}, &shipped)
+	var shipmentID string
+	if 1 == len(shipped.Shipments) {
+		shipmentID = shipped.Shipments[0].ID
+	}
+
+	var pickedUp, delivered struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Events []struct {
+			Type     string `json:"type"`
+			Location string `json:"location"`
+		} `json:"events"`
+	}
+	pickedUpStatus := do(server, http.MethodPost, "/tracking/acme/T1", map[string]string{"type": order.TrackingPickedUp, "location": "Warehouse"}, &pickedUp)
+	do(server, http.MethodGet, "/orders/order1", nil, &inTransit)
+	outForDeliveryStatus := do(server, http.MethodPost, "/orders/order1/shipments/"+shipmentID+"/events", map[string]string{"type": order.TrackingOutForDelivery}, &outForDelivery)
+	unknownTrackingStatus := do(server, http.MethodPost, "/tracking/acme/T2", map[string]string{"type": order.TrackingInTransit}, nil)
+	otherCarrierStatus := do(server, http.MethodPost, "/tracking/other/T1", map[string]string{"type": order.TrackingInTransit}, nil)
+	unknownTypeStatus := do(server, http.MethodPost, "/tracking/acme/T1", map[string]string{"type": "XX"}, nil)
+	deliveredStatus := do(server, http.MethodPost, "/tracking/acme/T1", map[string]string{"type": order.TrackingDelivered}, &delivered)
+	var finished orderBody
+	do(server, http.MethodGet, "/orders/order1", nil, &finished)
+	afterDeliveryStatus := do(server, http.MethodPost, "/tracking/acme/T1", map[string]string{"type": order.TrackingInTransit}, nil)
+
+	var trackingEventTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Picked Up Webhook Status Code", http.StatusOK, pickedUpStatus},
+		{"Webhook Responds Shipment", shipmentID, pickedUp.ID},
+		{"Picked Up Shipment Status", order.ShipmentStatusInTransit, pickedUp.Status},
+		{"Picked Up Event Location", "Warehouse", pickedUp.Events[0].Location},
+		{"Picked Up Shipping Status", order.ShipStatusInTransit, inTransit.ShippingStatus},
+		{"Order Shipment Event Status Code", http.StatusOK, outForDeliveryStatus},
+		{"Out For Delivery Shipping Status", order.ShipStatusOutForDelivery, outForDelivery.ShippingStatus},
+		{"Unknown Tracking ID Status Code", http.StatusNotFound, unknownTrackingStatus},
+		{"Other Carrier Status Code", http.StatusNotFound, otherCarrierStatus},
+		{"Unknown Event Type Status Code", http.StatusUnprocessableEntity, unknownTypeStatus},
+		{"Delivered Webhook Status Code", http.StatusOK, deliveredStatus},
+		{"Delivered Shipment Events", 3, len(delivered.Events)},
+		{"Delivered Shipping Status", order.ShipStatusDelivered, finished.ShippingStatus},
+		{"Delivered Event Must Finish Order", order.StatusDelivered, finished.Status},
+		{"Event After Delivery Status Code", http.StatusConflict, afterDeliveryStatus},
+		{"Webhook Get Status Code", http.StatusMethodNotAllowed, do(server, http.MethodGet, "/tracking/acme/T1", nil, nil)},
+	}
+
+	for _, test := range trackingEventTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCustomOrderEvent(t *testing.T) {
+	//the default state machine extended with an 'on hold' status for submitted orders
+	m := order.NewDefaultStateMachine().AddStatus("H", "On Hold")
+	m.AddTransition(order.Transition{From: order.StatusSubmitted, Event: "hold", To: "H"})
+	m.AddTransition(order.Transition{From: "H", Event: "resume", To: order.StatusSubmitted})
+	order.SetDefaultStateMachine(m)
+	t.Cleanup(func() { order.SetDefaultStateMachine(nil) })
+
+	store := newTestStore()
+	server := rest.NewServer(store)
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order1"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, nil)
+
+	var held, resumed orderBody
+	holdStatus := do(server, http.MethodPost, "/orders/order1/hold", nil, &held)
+	processHeldStatus := do(server, http.MethodPost, "/orders/order1/process", nil, nil)
+	resumeStatus := do(server, http.MethodPost, "/orders/order1/resume", nil, &resumed)
+
+	var customOrderEventTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Hold Status Code", http.StatusOK, holdStatus},
+		{"Held Order Status", "H", held.Status},
+		{"Held Order Events", "resume", strings.Join(held.Events, ",")},
+		{"Process Held Order Status Code", http.StatusConflict, processHeldStatus},
+		{"Resume Status Code", http.StatusOK, resumeStatus},
+		{"Resumed Order Status", order.StatusSubmitted, resumed.Status},
+		{"Resume Resumed Order Status Code", http.StatusConflict, do(server, http.MethodPost, "/orders/order1/resume", nil, nil)},
+	}
+
+	for _, test := range customOrderEventTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderErrorResponses(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "draftOrder"}, nil)
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "submittedOrder"}, nil)
+	do(server, http.MethodPost, "/orders/submittedOrder/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	do(server, http.MethodPost, "/orders/submittedOrder/submit", map[string]string{}, nil)
+
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	insufficientStockStatus := do(server, http.MethodPost, "/orders/draftOrder/items", map[string]interface{}{"productId": "limitedProd", "quantity": 10}, &errBody)
+
+	var errorResponseTests = []struct {
+		testCase       string
+		expectedStatus int
+		actualStatus   int
+	}{
+		{"Duplicate Order", http.StatusConflict, do(server, http.MethodPost, "/orders", map[string]string{"id": "draftOrder"}, nil)},
+		{"Missing Order", http.StatusNotFound, do(server, http.MethodGet, "/orders/missingOrder", nil, nil)},
+		{"Missing Product", http.StatusNotFound, do(server, http.MethodPost, "/orders/draftOrder/items", map[string]interface{}{"productId": "missingProd", "quantity": 1}, nil)},
+		{"Insufficient Stock", http.StatusConflict, insufficientStockStatus},
+		{"Invalid Quantity", http.StatusBadRequest, do(server, http.MethodPost, "/orders/draftOrder/items", map[string]interface{}{"productId": "limitedProd", "quantity": 0}, nil)},
+		{"Edit Missing Item", http.StatusNotFound, do(server, http.MethodPut, "/orders/draftOrder/items/limitedProd", map[string]interface{}{"quantity": 1}, nil)},
+		{"Submit Without Item", http.StatusUnprocessableEntity, do(server, http.MethodPost, "/orders/draftOrder/submit", map[string]string{}, nil)},
+		{"Submit With Missing Coupon", http.StatusNotFound, do(server, http.MethodPost, "/orders/draftOrder/submit", map[string]string{"couponCode": "missing"}, nil)},
+		{"Submit Submitted Order", http.StatusConflict, do(server, http.MethodPost, "/orders/submittedOrder/submit", map[string]string{}, nil)},
+		{"Finish Submitted Order", http.StatusConflict, do(server, http.MethodPost, "/orders/submittedOrder/finish", nil, nil)},
+		{"Malformed Body", http.StatusBadRequest, do(server, http.MethodPost, "/orders/draftOrder/items", "not an object", nil)},
+		{"Unknown Action", http.StatusNotFound, do(server, http.MethodPost, "/orders/draftOrder/unknown", nil, nil)},
+		{"Method Not Allowed", http.StatusMethodNotAllowed, do(server, http.MethodDelete, "/orders/draftOrder", nil, nil)},
+		{"Unknown Resource", http.StatusNotFound, do(server, http.MethodGet, "/unknown", nil, nil)},
+	}
+
+	for _, test := range errorResponseTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedStatus != test.actualStatus {
+				t.Errorf("want %v for status code, got %v", test.expectedStatus, test.actualStatus)
+			}
+		})
+	}
+	t.Run("Error Body Must Have Message", func(t *testing.T) {
+		if "" == errBody.Error {
+			t.Error("expected error message but got none\n")
+		}
+	})
+}
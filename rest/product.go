@@ -0,0 +1,246 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"sstest/model/money"
+	"sstest/model/product"
+	"sstest/repository"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+//productResponse is the JSON representation of a product
+type productResponse struct {
+	ID             string           `json:"id"`
+	Name           string           `json:"name"`
+	Status         string           `json:"status"`
+	Price          decimal.Decimal  `json:"price"`
+	Currency       string           `json:"currency"`       //ISO 4217 code of the price's currency
+	FormattedPrice string           `json:"formattedPrice"` //price formatted in the default locale
+	Stock          int64            `json:"stock"`
+	Stocks         map[string]int64 `json:"stocks"`    //stock in every warehouse having stock, keyed by warehouse id
+	Available      int64            `json:"available"` //available-to-sell stock (not held by draft orders)
+	Weight         decimal.Decimal  `json:"weight"`    //shipping weight in kilograms
+	Length         decimal.Decimal  `json:"length"`    //shipping dimensions in centimeters
+	Width          decimal.Decimal  `json:"width"`
+	Height         decimal.Decimal  `json:"height"`
+	TaxCategory    string           `json:"taxCategory"` //product tax category taxing the product in an order's shipping region
+}
+
+//newProductResponse creates the JSON representation of a product with its available-to-sell stock
+func newProductResponse(p *product.Product, available int64) productResponse {
+	length, width, height := p.Dimensions()
+	return productResponse{p.ID(), p.Name(), p.Status(), p.Price(), p.Currency(), money.Format(p.Price(), p.Currency()), p.Stock(), p.Stocks(), available,
+		p.Weight(), length, width, height, p.TaxCategory()}
+}
+
+//productResponses creates the JSON representations of products, looking up their available-to-sell stock in the product repository
+func (s *Server) productResponses(products ...*product.Product) ([]productResponse, *errors.Error) {
+	responses := make([]productResponse, 0, len(products))
+	for _, p := range products {
+		available, err := s.store.Products.Available(p.ID())
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, newProductResponse(p, available))
+	}
+	return responses, nil
+}
+
+//productRequest is the JSON body of a product creation or update (omitted fields are left unchanged)
+type productRequest struct {
+	ID          string            `json:"id"`
+	Name        *string           `json:"name"`
+	Status      *string           `json:"status"`
+	Price       *decimal.Decimal  `json:"price"`
+	Currency    *string           `json:"currency"`
+	Stock       *int64            `json:"stock"`
+	Stocks      *map[string]int64 `json:"stocks"`
+	Weight      *decimal.Decimal  `json:"weight"`
+	Length      *decimal.Decimal  `json:"length"`
+	Width       *decimal.Decimal  `json:"width"`
+	Height      *decimal.Decimal  `json:"height"`
+	TaxCategory *string           `json:"taxCategory"`
+}
+
+//build creates the product resulting from applying the request on a current product (nil on creation)
+func (req productRequest) build(id string, current *product.Product) (*product.Product, *errors.Error) {
+	p := product.New(id, "")
+	if current != nil {
+		p.SetName(current.Name()).SetStocks(current.Stocks())
+		p.SetPrice(current.Price())
+		p.SetStatus(current.Status())
+		p.SetWeight(current.Weight())
+		p.SetDimensions(current.Dimensions())
+		p.SetTaxCategory(current.TaxCategory())
+		p.SetCurrency(current.Currency())
+	}
+	if req.Name != nil {
+		p.SetName(*req.Name)
+	}
+	if req.Stock != nil {
+		if *req.Stock < 0 {
+			return nil, errors.Wrap(fmt.Errorf("Can't set negative value %d for stock", *req.Stock), 0)
+		}
+		p.SetStock(*req.Stock)
+	}
+	if req.Stocks != nil {
+		for warehouse, stock := range *req.Stocks {
+			if stock < 0 {
+				return nil, errors.Wrap(fmt.Errorf("Can't set negative value %d for stock in warehouse %v", stock, warehouse), 0)
+			}
+		}
+		p.SetStocks(*req.Stocks)
+	}
+	if req.Price != nil {
+		if _, err := p.SetPrice(*req.Price); err != nil {
+			return nil, err
+		}
+	}
+	if req.Currency != nil {
+		if _, err := p.SetCurrency(*req.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != nil {
+		if _, err := p.SetStatus(*req.Status); err != nil {
+			return nil, err
+		}
+	}
+	if req.Weight != nil {
+		if _, err := p.SetWeight(*req.Weight); err != nil {
+			return nil, err
+		}
+	}
+	if req.Length != nil || req.Width != nil || req.Height != nil {
+		length, width, height := p.Dimensions()
+		if req.Length != nil {
+			length = *req.Length
+		}
+		if req.Width != nil {
+			width = *req.Width
+		}
+		if req.Height != nil {
+			height = *req.Height
+		}
+		if _, err := p.SetDimensions(length, width, height); err != nil {
+			return nil, err
+		}
+	}
+	if req.TaxCategory != nil {
+		if _, err := p.SetTaxCategory(*req.TaxCategory); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+//productResource returns the CRUD handlers of products
+func (s *Server) productResource() resource {
+	return resource{s.listProducts, s.createProduct, s.getProduct, s.updateProduct, s.removeProduct}
+}
+
+//listProducts handles listing all products
+func (s *Server) listProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := s.store.Products.FindAll()
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	responses, err := s.productResponses(products...)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+//createProduct handles creating a new product
+func (s *Server) createProduct(w http.ResponseWriter, r *http.Request) {
+	var req productRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if "" == req.ID {
+		writeError(w, errors.Wrap(fmt.Errorf("Can't create product without id"), 0), http.StatusBadRequest)
+		return
+	}
+	if _, err := s.store.Products.FindByID(req.ID); nil == err {
+		writeError(w, repository.Duplicate("product", req.ID), http.StatusConflict)
+		return
+	} else if false == errors.Is(err, repository.ErrNotFound) {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	p, err := req.build(req.ID, nil)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := s.store.Products.Save(p); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.writeProduct(w, http.StatusCreated, p)
+}
+
+//getProduct handles getting a product
+func (s *Server) getProduct(w http.ResponseWriter, r *http.Request, id string) {
+	p, err := s.store.Products.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.writeProduct(w, http.StatusOK, p)
+}
+
+//updateProduct handles updating a product's name, status, price or stock (the stored stock is kept when the request has none)
+func (s *Server) updateProduct(w http.ResponseWriter, r *http.Request, id string) {
+	var req productRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	current, err := s.store.Products.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	p, err := req.build(id, current)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	//the stored stocks are left unchanged (orders may have been submitted since the product was loaded) unless the request replaces them
+	save := s.store.Products.Update
+	if req.Stock != nil || req.Stocks != nil {
+		save = s.store.Products.Save
+	}
+	if err := save(p); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.writeProduct(w, http.StatusOK, p)
+}
+
+//writeProduct writes a product (with its available-to-sell stock) as response
+func (s *Server) writeProduct(w http.ResponseWriter, status int, p *product.Product) {
+	responses, err := s.productResponses(p)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, status, responses[0])
+}
+
+//removeProduct handles deleting a product
+func (s *Server) removeProduct(w http.ResponseWriter, r *http.Request, id string) {
+	if err := s.store.Products.Delete(id); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,111 @@
+package rest_test
+
+import (
+	"fmt"
+	"net/http"
+	"sstest/model/product"
+	"sstest/rest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+//productBody is the product JSON representation checked by tests
+type productBody struct {
+	ID             string           `json:"id"`
+	Name           string           `json:"name"`
+	Status         string           `json:"status"`
+	Price          decimal.Decimal  `json:"price"`
+	Currency       string           `json:"currency"`
+	FormattedPrice string           `json:"formattedPrice"`
+	Stock          int64            `json:"stock"`
+	Stocks         map[string]int64 `json:"stocks"`
+	Available      int64            `json:"available"`
+	Weight         decimal.Decimal  `json:"weight"`
+	Length         decimal.Decimal  `json:"length"`
+	Height         decimal.Decimal  `json:"height"`
+	TaxCategory    string           `json:"taxCategory"`
+}
+
+func TestProductResource(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	var created, updated, fetched, warehoused productBody
+	var listed []productBody
+	createStatus := do(server, http.MethodPost, "/products", map[string]interface{}{"id": "newProd", "name": "New Product", "price": "25.5", "stock": 7, "status": product.StatusAvailable,
+		"currency": "EUR", "weight": "1.5", "length": 30, "width": 20, "height": 10}, &created)
+	updateStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"price": 30, "stock": 3, "height": 15}, &updated)
+	taxStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"taxCategory": "reduced"}, nil)
+	emptyTaxStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"taxCategory": ""}, nil)
+	invalidWeightStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"weight": -1}, nil)
+	invalidPriceStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"price": -1, "stock": 100}, nil)
+	invalidCurrencyStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"currency": "XYZ"}, nil)
+	invalidStatusStatus := do(server, http.MethodPut, "/products/newProd", map[string]interface{}{"status": "unknown"}, nil)
+	do(server, http.MethodPost, "/orders", map[string]interface{}{"id": "holdingOrder"}, nil)
+	holdStatus := do(server, http.MethodPost, "/orders/holdingOrder/items", map[string]interface{}{"productId": "newProd", "quantity": 2}, nil)
+	overHoldStatus := do(server, http.MethodPost, "/orders/holdingOrder/items", map[string]interface{}{"productId": "newProd", "quantity": 2}, nil)
+	fetchStatus := do(server, http.MethodGet, "/products/newProd", nil, &fetched)
+	listStatus := do(server, http.MethodGet, "/products", nil, &listed)
+	warehouseStatus := do(server, http.MethodPost, "/products", map[string]interface{}{"id": "warehouseProd", "stocks": map[string]int64{"east": 2, "west": 5}}, &warehoused)
+	negativeWarehouseStatus := do(server, http.MethodPut, "/products/warehouseProd", map[string]interface{}{"stocks": map[string]int64{"east": -1}}, nil)
+	deleteStatus := do(server, http.MethodDelete, "/products/newProd", nil, nil)
+
+	var productResourceTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Product Price", "25.5", created.Price.String()},
+		{"Created Product Status", product.StatusAvailable, created.Status},
+		{"Created Product Weight", "1.5", created.Weight.String()},
+		{"Created Product Height", "10", created.Height.String()},
+		{"Created Product Tax Category", product.TaxCategoryStandard, created.TaxCategory},
+		{"Created Product Currency", "EUR", created.Currency},
+		{"Created Product Formatted Price", "€25.50", created.FormattedPrice},
+		{"Duplicate Status Code", http.StatusConflict, do(server, http.MethodPost, "/products", map[string]interface{}{"id": "availableProd"}, nil)},
+		{"Missing Id Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/products", map[string]interface{}{"name": "No Id"}, nil)},
+		{"Negative Stock Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/products", map[string]interface{}{"id": "negativeProd", "stock": -1}, nil)},
+		{"Update Status Code", http.StatusOK, updateStatus},
+		{"Updated Product Name", "New Product", updated.Name},
+		{"Updated Product Price", "30", updated.Price.String()},
+		{"Updated Product Stock", int64(3), updated.Stock},
+		{"Updated Product Height", "15", updated.Height.String()},
+		{"Updated Product Keeps Length", "30", updated.Length.String()},
+		{"Updated Product Keeps Weight", "1.5", updated.Weight.String()},
+		{"Updated Product Keeps Tax Category", product.TaxCategoryStandard, updated.TaxCategory},
+		{"Updated Product Keeps Currency", "EUR", updated.Currency},
+		{"Tax Category Status Code", http.StatusOK, taxStatus},
+		{"Empty Tax Category Status Code", http.StatusBadRequest, emptyTaxStatus},
+		{"Fetched Product Tax Category", "reduced", fetched.TaxCategory},
+		{"Invalid Weight Status Code", http.StatusBadRequest, invalidWeightStatus},
+		{"Invalid Price Status Code", http.StatusBadRequest, invalidPriceStatus},
+		{"Invalid Status Status Code", http.StatusBadRequest, invalidStatusStatus},
+		{"Invalid Currency Status Code", http.StatusBadRequest, invalidCurrencyStatus},
+		{"Rejected Update Leaves Stock", int64(3), fetched.Stock},
+		{"Hold Status Code", http.StatusOK, holdStatus},
+		{"Over Hold Status Code", http.StatusConflict, overHoldStatus},
+		{"Held Product Available Stock", int64(1), fetched.Available},
+		{"Updated Product Available Stock", int64(3), updated.Available},
+		{"Updated Product Stocks", int64(3), updated.Stocks[product.DefaultWarehouse]},
+		{"Fetch Status Code", http.StatusOK, fetchStatus},
+		{"Warehouse Stocks Status Code", http.StatusCreated, warehouseStatus},
+		{"Warehouse Stocks Total", int64(7), warehoused.Stock},
+		{"Warehouse Stocks East", int64(2), warehoused.Stocks["east"]},
+		{"Negative Warehouse Stock Status Code", http.StatusBadRequest, negativeWarehouseStatus},
+		{"List Status Code", http.StatusOK, listStatus},
+		{"Listed Product Count", 3, len(listed)},
+		{"Delete Status Code", http.StatusNoContent, deleteStatus},
+		{"Deleted Product Status Code", http.StatusNotFound, do(server, http.MethodGet, "/products/newProd", nil, nil)},
+		{"Update Missing Product Status Code", http.StatusNotFound, do(server, http.MethodPut, "/products/missingProd", map[string]interface{}{}, nil)},
+	}
+
+	for _, test := range productResourceTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
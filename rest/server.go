@@ -0,0 +1,163 @@
+//Package rest provides the JSON over HTTP API of the business domain models
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+//Server is the HTTP handler of the API, loading and saving models through a repository store
+type Server struct {
+	store *repository.Store
+}
+
+//NewServer creates a new API server on the given repository store and returns a reference to it
+func NewServer(store *repository.Store) *Server {
+	return &Server{store}
+}
+
+//ServeHTTP dispatches a request to its resource handler by the first path segment
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch segments[0] {
+	case "orders":
+		s.serveOrders(w, r, segments[1:])
+	case "products":
+		s.productResource().serve(w, r, segments[1:])
+	case "coupons":
+		s.serveCoupons(w, r, segments[1:])
+	case "users":
+		s.serveUsers(w, r, segments[1:])
+	case "tracking":
+		s.serveTracking(w, r, segments[1:])
+	default:
+		writeError(w, unknownResource(r), http.StatusNotFound)
+	}
+}
+
+//unknownResource returns the not found error of a request on an unknown path
+func unknownResource(r *http.Request) *errors.Error {
+	return errors.WrapPrefix(repository.ErrNotFound, fmt.Sprintf("Unknown resource %v", r.URL.Path), 1)
+}
+
+//resource is the set of handlers of a CRUD resource:
+//
+//	GET    /{resource}         list
+//	POST   /{resource}         create
+//	GET    /{resource}/{id}    get
+//	PUT    /{resource}/{id}    update
+//	DELETE /{resource}/{id}    remove
+type resource struct {
+	list   func(w http.ResponseWriter, r *http.Request)
+	create func(w http.ResponseWriter, r *http.Request)
+	get    func(w http.ResponseWriter, r *http.Request, id string)
+	update func(w http.ResponseWriter, r *http.Request, id string)
+	remove func(w http.ResponseWriter, r *http.Request, id string)
+}
+
+//serve dispatches a request on a CRUD resource by its path segments (after the resource name)
+func (res resource) serve(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case 0 == len(segments) || "" == segments[0]:
+		switch r.Method {
+		case http.MethodGet:
+			res.list(w, r)
+		case http.MethodPost:
+			res.create(w, r)
+		default:
+			methodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+		}
+	case 1 == len(segments):
+		switch r.Method {
+		case http.MethodGet:
+			res.get(w, r, segments[0])
+		case http.MethodPut:
+			res.update(w, r, segments[0])
+		case http.MethodDelete:
+			res.remove(w, r, segments[0])
+		default:
+			methodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+		}
+	default:
+		writeError(w, unknownResource(r), http.StatusNotFound)
+	}
+}
+
+//errorResponse is the JSON body of a failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+//errorStatuses maps the known failure reasons of the models and repositories to HTTP status codes
+var errorStatuses = []struct {
+	reason error
+	status int
+}{
+	{repository.ErrNotFound, http.StatusNotFound},
+	{repository.ErrDuplicate, http.StatusConflict},
+	{order.ErrInvalidStatus, http.StatusConflict},
+	{order.ErrItemNotFound, http.StatusNotFound},
+	{order.ErrShipmentNotFound, http.StatusNotFound},
+	{order.ErrInvalidShipment, http.StatusUnprocessableEntity},
+	{order.ErrNoItem, http.StatusUnprocessableEntity},
+	{order.ErrInvalidAmount, http.StatusUnprocessableEntity},
+	{order.ErrCurrencyMismatch, http.StatusUnprocessableEntity},
+	{money.ErrNoRate, http.StatusUnprocessableEntity},
+	{product.ErrInsufficientStock, http.StatusConflict},
+	{product.ErrNotAvailable, http.StatusUnprocessableEntity},
+	{product.ErrInvalidQuantity, http.StatusBadRequest},
+	{coupon.ErrNotApplicable, http.StatusUnprocessableEntity},
+	{coupon.ErrNotEligible, http.StatusUnprocessableEntity},
+	{coupon.ErrNoStock, http.StatusConflict},
+	{coupon.ErrRedemptionLimit, http.StatusConflict},
+	{coupon.ErrNotCombinable, http.StatusUnprocessableEntity},
+	{user.ErrNotActive, http.StatusForbidden},
+}
+
+//statusOf returns the HTTP status code of an error's failure reason, or the given fallback status for an unknown reason
+func statusOf(err *errors.Error, fallback int) int {
+	for _, known := range errorStatuses {
+		if errors.Is(err, known.reason) {
+			return known.status
+		}
+	}
+	return fallback
+}
+
+//writeError writes an error as JSON response with the status code of its failure reason
+func writeError(w http.ResponseWriter, err *errors.Error, fallback int) {
+	writeJSON(w, statusOf(err, fallback), errorResponse{err.Error()})
+}
+
+//writeJSON writes a value as JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+//readJSON decodes a request's JSON body into a value
+func readJSON(r *http.Request, v interface{}) *errors.Error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errors.Wrap(fmt.Errorf("Can't read request body: %v", err), 0)
+	}
+	return nil
+}
+
+//methodNotAllowed writes the error response of a request with a method not allowed on its resource
+func methodNotAllowed(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{fmt.Sprintf("Method %v is not allowed on %v", r.Method, r.URL.Path)})
+}
@@ -0,0 +1,197 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"sstest/model/user"
+
+	"github.com/go-errors/errors"
+)
+
+//userResponse is the JSON representation of a user (without its password hash)
+type userResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Status  string `json:"status"`
+}
+
+//newUserResponse creates the JSON representation of a user
+func newUserResponse(u *user.User) userResponse {
+	return userResponse{u.ID(), u.Name(), u.Address(), u.Status()}
+}
+
+//userRequest is the JSON body of a user creation or update (omitted fields are left unchanged)
+type userRequest struct {
+	ID       string  `json:"id"`
+	Name     *string `json:"name"`
+	Address  *string `json:"address"`
+	Password *string `json:"password"`
+}
+
+//build creates the user resulting from applying the request on a current user (nil on creation)
+func (req userRequest) build(id string, current *user.User) (*user.User, *errors.Error) {
+	var u *user.User
+	var err *errors.Error
+	if nil == current {
+		u, err = user.New(id, "", "")
+	} else {
+		if u, err = user.NewFromHash(id, current.Name(), current.Address(), current.Password()); nil == err {
+			_, err = u.SetStatus(current.Status())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		u.SetName(*req.Name)
+	}
+	if req.Address != nil {
+		u.SetAddress(*req.Address)
+	}
+	if req.Password != nil {
+		if _, err := u.SetPassword(*req.Password); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+//serveUsers dispatches a user request by its path segments (after "users"):
+//
+//	GET    /users                   list users
+//	POST   /users                   create an inactive user (with its password)
+//	GET    /users/{id}              get a user
+//	PUT    /users/{id}              update a user's name, address or password
+//	DELETE /users/{id}              delete a user
+//	GET    /users/{id}/orders       list a user's orders
+//	POST   /users/{id}/activate     activate a user
+//	POST   /users/{id}/deactivate   deactivate a user
+//	POST   /users/{id}/suspend      suspend a user
+func (s *Server) serveUsers(w http.ResponseWriter, r *http.Request, segments []string) {
+	if 2 != len(segments) {
+		resource{s.listUsers, s.createUser, s.getUser, s.updateUser, s.removeUser}.serve(w, r, segments)
+		return
+	}
+	if "orders" == segments[1] {
+		if http.MethodGet != r.Method {
+			methodNotAllowed(w, r, http.MethodGet)
+			return
+		}
+		s.listUserOrders(w, r, segments[0])
+		return
+	}
+	if http.MethodPost != r.Method {
+		methodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	switch segments[1] {
+	case "activate":
+		s.changeUserStatus(w, r, segments[0], (*user.User).Activate)
+	case "deactivate":
+		s.changeUserStatus(w, r, segments[0], (*user.User).Deactivate)
+	case "suspend":
+		s.changeUserStatus(w, r, segments[0], (*user.User).Suspend)
+	default:
+		writeError(w, unknownResource(r), http.StatusNotFound)
+	}
+}
+
+//listUsers handles listing all users
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.store.Users.FindAll()
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	responses := make([]userResponse, 0, len(users))
+	for _, u := range users {
+		responses = append(responses, newUserResponse(u))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+//createUser handles creating a new inactive user (its password is required)
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req userRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if "" == req.ID {
+		writeError(w, errors.Wrap(fmt.Errorf("Can't create user without id"), 0), http.StatusBadRequest)
+		return
+	}
+	if nil == req.Password || "" == *req.Password {
+		writeError(w, errors.Wrap(fmt.Errorf("Can't create user %v without password", req.ID), 0), http.StatusBadRequest)
+		return
+	}
+	u, err := req.build(req.ID, nil)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := s.store.Users.Create(u); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newUserResponse(u))
+}
+
+//getUser handles getting a user
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, id string) {
+	u, err := s.store.Users.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newUserResponse(u))
+}
+
+//updateUser handles updating a user's name, address or password
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, id string) {
+	var req userRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	current, err := s.store.Users.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	u, err := req.build(id, current)
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := s.store.Users.Save(u); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newUserResponse(u))
+}
+
+//changeUserStatus handles changing a user's status through one of the user's status methods
+func (s *Server) changeUserStatus(w http.ResponseWriter, r *http.Request, id string, change func(u *user.User)) {
+	u, err := s.store.Users.FindByID(id)
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	change(u)
+	if err := s.store.Users.Save(u); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newUserResponse(u))
+}
+
+//removeUser handles deleting a user
+func (s *Server) removeUser(w http.ResponseWriter, r *http.Request, id string) {
+	if err := s.store.Users.Delete(id); err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
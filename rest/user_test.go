@@ -0,0 +1,110 @@
+package rest_test
+
+import (
+	"fmt"
+	"net/http"
+	"sstest/model/user"
+	"sstest/rest"
+	"testing"
+)
+
+//userBody is the user JSON representation checked by tests
+type userBody struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Status  string `json:"status"`
+}
+
+func TestUserResource(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	var created, updated, activated, suspended, deactivated userBody
+	createStatus := do(server, http.MethodPost, "/users", map[string]string{"id": "user1", "name": "User One", "address": "Address One", "password": "secret"}, &created)
+	updateStatus := do(server, http.MethodPut, "/users/user1", map[string]string{"address": "New Address"}, &updated)
+	activateStatus := do(server, http.MethodPost, "/users/user1/activate", nil, &activated)
+	suspendStatus := do(server, http.MethodPost, "/users/user1/suspend", nil, &suspended)
+	deactivateStatus := do(server, http.MethodPost, "/users/user1/deactivate", nil, &deactivated)
+	storedUser, _ := store.Users.FindByID("user1")
+	passwordOk, _ := storedUser.ValidatePassword("secret")
+
+	var userResourceTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created User Status", user.StatusInactive, created.Status},
+		{"Duplicate Status Code", http.StatusConflict, do(server, http.MethodPost, "/users", map[string]string{"id": "user1", "password": "secret"}, nil)},
+		{"Missing Password Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/users", map[string]string{"id": "user2"}, nil)},
+		{"Empty Password Status Code", http.StatusBadRequest, do(server, http.MethodPost, "/users", map[string]string{"id": "user2", "password": ""}, nil)},
+		{"User Without Password Must Not Be Created", http.StatusNotFound, do(server, http.MethodGet, "/users/user2", nil, nil)},
+		{"Update Status Code", http.StatusOK, updateStatus},
+		{"Updated User Name", "User One", updated.Name},
+		{"Updated User Address", "New Address", updated.Address},
+		{"Password Kept On Update", true, passwordOk},
+		{"Activate Status Code", http.StatusOK, activateStatus},
+		{"Activated User Status", user.StatusActive, activated.Status},
+		{"Suspend Status Code", http.StatusOK, suspendStatus},
+		{"Suspended User Status", user.StatusSuspended, suspended.Status},
+		{"Deactivate Status Code", http.StatusOK, deactivateStatus},
+		{"Deactivated User Status", user.StatusInactive, deactivated.Status},
+		{"Unknown Action Status Code", http.StatusNotFound, do(server, http.MethodPost, "/users/user1/promote", nil, nil)},
+		{"Action Method Not Allowed", http.StatusMethodNotAllowed, do(server, http.MethodGet, "/users/user1/activate", nil, nil)},
+		{"Activate Missing User Status Code", http.StatusNotFound, do(server, http.MethodPost, "/users/missingUser/activate", nil, nil)},
+		{"Delete Status Code", http.StatusNoContent, do(server, http.MethodDelete, "/users/user1", nil, nil)},
+		{"Deleted User Status Code", http.StatusNotFound, do(server, http.MethodGet, "/users/user1", nil, nil)},
+	}
+
+	for _, test := range userResourceTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestUserOrders(t *testing.T) {
+	store := newTestStore()
+	server := rest.NewServer(store)
+
+	do(server, http.MethodPost, "/users", map[string]string{"id": "user1", "name": "User One", "address": "Address One", "password": "secret"}, nil)
+	var created, refused, submitted orderBody
+	createStatus := do(server, http.MethodPost, "/orders", map[string]string{"id": "order1", "userId": "user1"}, &created)
+	do(server, http.MethodPost, "/orders", map[string]string{"id": "order2"}, nil)
+	do(server, http.MethodPost, "/orders/order1/items", map[string]interface{}{"productId": "availableProd", "quantity": 1}, nil)
+	refusedStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, &refused)
+	do(server, http.MethodPost, "/users/user1/activate", nil, nil)
+	submitStatus := do(server, http.MethodPost, "/orders/order1/submit", map[string]string{}, &submitted)
+	var listed []orderBody
+	listStatus := do(server, http.MethodGet, "/users/user1/orders", nil, &listed)
+
+	var userOrdersTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Create Status Code", http.StatusCreated, createStatus},
+		{"Created Order User", "user1", created.UserID},
+		{"Create With Missing User Status Code", http.StatusNotFound, do(server, http.MethodPost, "/orders", map[string]string{"id": "order3", "userId": "missingUser"}, nil)},
+		{"Submit With Inactive User Status Code", http.StatusForbidden, refusedStatus},
+		{"Submit With Active User Status Code", http.StatusOK, submitStatus},
+		{"Submitted Order Default Shipping Name", "User One", submitted.ShippingName},
+		{"Submitted Order Default Shipping Address", "Address One", submitted.ShippingAddress},
+		{"List Status Code", http.StatusOK, listStatus},
+		{"Listed Order Count", 1, len(listed)},
+		{"Listed Order ID", "order1", listed[0].ID},
+		{"List Missing User Status Code", http.StatusNotFound, do(server, http.MethodGet, "/users/missingUser/orders", nil, nil)},
+		{"List Method Not Allowed", http.StatusMethodNotAllowed, do(server, http.MethodPost, "/users/user1/orders", nil, nil)},
+	}
+
+	for _, test := range userOrdersTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
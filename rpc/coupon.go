@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sstest/model/coupon"
+	"sstest/rpc/pb"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//newCoupon creates the protobuf message of a coupon
+func newCoupon(c *coupon.Coupon) *pb.Coupon {
+	return &pb.Coupon{
+		Id:               c.ID(),
+		Status:           c.Status(),
+		Stock:            c.Stock(),
+		Kind:             c.Kind(),
+		Value:            c.Value().String(),
+		StartDate:        timestampOf(c.StartDate()),
+		EndDate:          timestampOf(c.EndDate()),
+		Currency:         c.Currency(),
+		MinSubtotal:      c.MinSubtotal().String(),
+		MinQuantity:      int32(c.MinQuantity()),
+		MaxDiscount:      c.MaxDiscount().String(),
+		Products:         c.Products(),
+		ExcludedProducts: c.ExcludedProducts(),
+		UserLimit:        int32(c.UserLimit()),
+		RedemptionLimit:  int32(c.RedemptionLimit()),
+		Stackable:        c.Stackable(),
+		BuyQuantity:      int32(c.BuyQuantity()),
+		FreeQuantity:     int32(c.FreeQuantity()),
+		FreeProducts:     c.FreeProducts(),
+		Tiers:            newCouponTiers(c.Tiers()),
+		Automatic:        c.Automatic(),
+		Priority:         int32(c.Priority()),
+		ExcludesCoupons:  c.ExcludesCoupons(),
+	}
+}
+
+//newCouponTiers creates the protobuf messages of a tiered coupon's spend tiers
+func newCouponTiers(tiers []*coupon.Tier) []*pb.CouponTier {
+	msgs := make([]*pb.CouponTier, 0, len(tiers))
+	for _, tier := range tiers {
+		msgs = append(msgs, &pb.CouponTier{MinSpend: tier.MinSpend().String(), Percentage: tier.Percentage().String()})
+	}
+	return msgs
+}
+
+//newRedemption creates the protobuf message of a coupon redemption
+func newRedemption(r *coupon.Redemption) *pb.Redemption {
+	return &pb.Redemption{
+		CouponId:     r.CouponID(),
+		UserId:       r.UserID(),
+		OrderId:      r.OrderID(),
+		Amount:       r.Amount().String(),
+		Currency:     r.Currency(),
+		RedeemedDate: timestampOf(r.RedeemedDate()),
+		ReversedDate: timestampOf(r.ReversedDate()),
+	}
+}
+
+//GetCoupon returns a coupon by its code
+func (s *Server) GetCoupon(ctx context.Context, req *pb.GetCouponRequest) (*pb.Coupon, error) {
+	c, err := s.store.Coupons.FindByCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newCoupon(c), nil
+}
+
+//ListCoupons returns all coupons
+func (s *Server) ListCoupons(ctx context.Context, req *pb.ListCouponsRequest) (*pb.ListCouponsResponse, error) {
+	coupons, err := s.store.Coupons.FindAll()
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	resp := &pb.ListCouponsResponse{Coupons: make([]*pb.Coupon, 0, len(coupons))}
+	for _, c := range coupons {
+		resp.Coupons = append(resp.Coupons, newCoupon(c))
+	}
+	return resp, nil
+}
+
+//CanBeApplied checks whether a coupon can be applied
+func (s *Server) CanBeApplied(ctx context.Context, req *pb.CanBeAppliedRequest) (*pb.CheckResponse, error) {
+	c, err := s.store.Coupons.FindByCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	ok, checkErr := c.CanBeApplied()
+	if checkErr != nil {
+		return checkResponse(ok, checkErr), nil
+	}
+	return checkResponse(ok, nil), nil
+}
+
+//GetDiscountAmount returns a coupon's discount on an amount (a promotion's discounts are only quoted on an order)
+func (s *Server) GetDiscountAmount(ctx context.Context, req *pb.GetDiscountAmountRequest) (*pb.GetDiscountAmountResponse, error) {
+	amount, parseErr := decimal.NewFromString(req.GetAmount())
+	if parseErr != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Can't read amount %v: %v", req.GetAmount(), parseErr))
+	}
+	c, err := s.store.Coupons.FindByCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	if c.IsPromotion() {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("Can't discount an amount with coupon %v, its promotion discounts an order's lines", c.ID()))
+	}
+	return &pb.GetDiscountAmountResponse{Discount: c.GetDiscountAmount(amount).String()}, nil
+}
+
+//ListRedemptions returns a coupon's redemptions, the reversed ones included
+func (s *Server) ListRedemptions(ctx context.Context, req *pb.ListRedemptionsRequest) (*pb.ListRedemptionsResponse, error) {
+	c, err := s.store.Coupons.FindByCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	redemptions, err := s.store.Coupons.FindRedemptions(c.ID())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	resp := &pb.ListRedemptionsResponse{Redemptions: make([]*pb.Redemption, 0, len(redemptions))}
+	for _, r := range redemptions {
+		resp.Redemptions = append(resp.Redemptions, newRedemption(r))
+	}
+	return resp, nil
+}
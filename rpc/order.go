@@ -0,0 +1,373 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/repository"
+	"sstest/rpc/pb"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+//newOrder creates the protobuf message of an order (items ordered by product id, shipments in the order they were shipped)
+func newOrder(o *order.Order) *pb.Order {
+	items := make([]*pb.Item, 0, len(o.Items()))
+	for _, item := range o.Items() {
+		var allocation map[string]int32
+		if 0 != len(item.Allocation()) {
+			allocation = make(map[string]int32, len(item.Allocation()))
+			for warehouse, quantity := range item.Allocation() {
+				allocation[warehouse] = int32(quantity)
+			}
+		}
+		items = append(items, &pb.Item{Id: item.ID(), Product: newProduct(item.Product()), Quantity: int32(item.Quantity()), Allocation: allocation,
+			Taxes: newTaxes(item.Taxes()), UnitPrice: item.UnitPrice().String(), ProductName: item.ProductName(),
+			ProductStatus: item.ProductStatus(), ExchangeRate: item.ExchangeRate().String(), RateDate: timestampOf(item.RateDate())})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Product.Id < items[j].Product.Id
+	})
+	shipments := make([]*pb.Shipment, 0, len(o.Shipments()))
+	for _, shipment := range o.Shipments() {
+		shipments = append(shipments, newShipment(shipment))
+	}
+	var userID string
+	if o.User() != nil {
+		userID = o.User().ID()
+	}
+	return &pb.Order{
+		Id:                 o.ID(),
+		Status:             o.Status(),
+		CreatedDate:        timestampOf(o.CreatedDate()),
+		SubmittedDate:      timestampOf(o.SubmittedDate()),
+		ProcessedDate:      timestampOf(o.ProcessedDate()),
+		Items:              items,
+		Amount:             o.Amount().String(),
+		ShippingName:       o.ShippingName(),
+		ShippingAddress:    o.ShippingAddress(),
+		ShippingStatus:     o.ShippingStatus(),
+		ShippingTrackingId: o.ShippingTrackingID(),
+		UserId:             userID,
+		Events:             o.AllowedEvents(),
+		Shipments:          shipments,
+		ShippingCost:       o.ShippingCost().String(),
+		ShippingRegion:     o.ShippingRegion(),
+		Tax:                o.Tax().String(),
+		TaxIncluded:        o.TaxIncluded(),
+		Taxes:              newTaxes(o.Taxes()),
+		Breakdown:          newBreakdown(o.Breakdown()),
+		Currency:           o.Currency(),
+		FormattedAmount:    money.Format(o.Amount(), o.Currency()),
+		Coupons:            newAppliedCoupons(o.AppliedCoupons()),
+		Promotions:         newAppliedCoupons(o.AppliedPromotions()),
+	}
+}
+
+//newBreakdown creates the protobuf message of a price breakdown
+func newBreakdown(b *order.Breakdown) *pb.Breakdown {
+	lines := make([]*pb.BreakdownLine, 0, len(b.Lines()))
+	for _, l := range b.Lines() {
+		lines = append(lines, &pb.BreakdownLine{ProductId: l.ProductID(), UnitPrice: l.UnitPrice().String(), Quantity: int32(l.Quantity()),
+			Subtotal: l.Subtotal().String(), Discount: l.Discount().String(), Tax: l.Tax().String()})
+	}
+	return &pb.Breakdown{Lines: lines, Subtotal: b.Subtotal().String(), Discount: b.Discount().String(), ShippingCost: b.ShippingCost().String(),
+		Tax: b.Tax().String(), TaxIncluded: b.TaxIncluded(), Total: b.Total().String(), Coupons: newAppliedCoupons(b.AppliedCoupons()),
+		Promotions: newAppliedCoupons(b.AppliedPromotions())}
+}
+
+//newAppliedCoupons creates the protobuf messages of the coupons applied on an order
+func newAppliedCoupons(applied []*order.AppliedCoupon) []*pb.AppliedCoupon {
+	msgs := make([]*pb.AppliedCoupon, 0, len(applied))
+	for _, a := range applied {
+		msgs = append(msgs, &pb.AppliedCoupon{CouponId: a.Coupon().ID(), Discount: a.Discount().String()})
+	}
+	return msgs
+}
+
+//newTaxes creates the protobuf messages of item or order taxes
+func newTaxes(taxes []*order.Tax) []*pb.Tax {
+	msgs := make([]*pb.Tax, 0, len(taxes))
+	for _, t := range taxes {
+		msgs = append(msgs, &pb.Tax{Name: t.Name(), Rate: t.Rate().String(), Amount: t.Amount().String()})
+	}
+	return msgs
+}
+
+//newShipment creates the protobuf message of an order shipment (tracking events ordered by their date)
+func newShipment(shipment *order.Shipment) *pb.Shipment {
+	items := make(map[string]int32, len(shipment.Items()))
+	for productID, quantity := range shipment.Items() {
+		items[productID] = int32(quantity)
+	}
+	events := make([]*pb.TrackingEvent, 0, len(shipment.Events()))
+	for _, e := range shipment.Events() {
+		events = append(events, &pb.TrackingEvent{Type: e.Kind(), Date: timestampOf(e.Date()), Location: e.Location(), Description: e.Description()})
+	}
+	return &pb.Shipment{
+		Id:            shipment.ID(),
+		Carrier:       shipment.Carrier(),
+		TrackingId:    shipment.TrackingID(),
+		Status:        shipment.Status(),
+		Items:         items,
+		ShippedDate:   timestampOf(shipment.ShippedDate()),
+		DeliveredDate: timestampOf(shipment.DeliveredDate()),
+		Events:        events,
+	}
+}
+
+//CreateOrder creates a new draft order (a new id is generated when id is empty), optionally of a user and in a currency
+func (s *Server) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.Order, error) {
+	id := req.GetId()
+	if "" == id {
+		id = uuid.New().String()
+	}
+	if _, err := s.store.Orders.FindByID(id); nil == err {
+		return nil, statusError(repository.Duplicate("order", id), codes.AlreadyExists)
+	} else if false == errors.Is(err, repository.ErrNotFound) {
+		return nil, statusError(err, codes.Internal)
+	}
+	o := order.New(id)
+	if "" != req.GetUserId() {
+		u, err := s.store.Users.FindByID(req.GetUserId())
+		if err != nil {
+			return nil, statusError(err, codes.Internal)
+		}
+		o.SetUser(u)
+	}
+	if "" != req.GetCurrency() {
+		if _, err := o.ChangeCurrency(req.GetCurrency()); err != nil {
+			return nil, statusError(err, codes.InvalidArgument)
+		}
+	}
+	if err := s.store.Orders.Save(o); err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newOrder(o), nil
+}
+
+//GetOrder returns an order
+func (s *Server) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
+	o, err := s.store.Orders.FindByID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newOrder(o), nil
+}
+
+//ListOrders returns the orders having a status
+func (s *Server) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	orders, err := s.store.Orders.FindByStatus(req.GetStatus())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newListOrdersResponse(orders), nil
+}
+
+//ListUserOrders returns the orders of a user
+func (s *Server) ListUserOrders(ctx context.Context, req *pb.ListUserOrdersRequest) (*pb.ListOrdersResponse, error) {
+	if _, err := s.store.Users.FindByID(req.GetUserId()); err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	orders, err := s.store.Orders.FindByUser(req.GetUserId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newListOrdersResponse(orders), nil
+}
+
+//newListOrdersResponse creates the protobuf message of a list of orders
+func newListOrdersResponse(orders []*order.Order) *pb.ListOrdersResponse {
+	resp := &pb.ListOrdersResponse{Orders: make([]*pb.Order, 0, len(orders))}
+	for _, o := range orders {
+		resp.Orders = append(resp.Orders, newOrder(o))
+	}
+	return resp
+}
+
+//AddProduct adds a product to a draft order
+func (s *Server) AddProduct(ctx context.Context, req *pb.AddProductRequest) (*pb.Order, error) {
+	p, err := s.store.Products.FindByID(req.GetProductId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.SetInventory(s.store.Products).AddProduct(p, int(req.GetQuantity()))
+		return err
+	})
+}
+
+//EditProduct adds a quantity to a product of a draft order
+func (s *Server) EditProduct(ctx context.Context, req *pb.EditProductRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		item, ok := o.Items()[req.GetProductId()]
+		if false == ok {
+			return errors.WrapPrefix(order.ErrItemNotFound, fmt.Sprintf("Can't edit, order %v has no product with id: %v", o.ID(), req.GetProductId()), 0)
+		}
+		_, err := o.SetInventory(s.store.Products).EditProduct(item.Product(), int(req.GetQuantity()))
+		return err
+	})
+}
+
+//DeleteProduct deletes a product from a draft order
+func (s *Server) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		item, ok := o.Items()[req.GetProductId()]
+		if false == ok {
+			return errors.WrapPrefix(order.ErrItemNotFound, fmt.Sprintf("Can't delete, order %v has no product with id: %v", o.ID(), req.GetProductId()), 0)
+		}
+		_, err := o.SetInventory(s.store.Products).DeleteProduct(item.Product())
+		return err
+	})
+}
+
+//SubmitOrder submits a draft order, decrementing product stocks in the product repository
+func (s *Server) SubmitOrder(ctx context.Context, req *pb.SubmitOrderRequest) (*pb.Order, error) {
+	coupons, err := s.findCoupons(append([]string{req.GetCouponCode()}, req.GetCouponCodes()...))
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		if "" != req.GetShippingRegion() {
+			o.SetShippingRegion(req.GetShippingRegion())
+		}
+		_, err := o.SetInventory(s.store.Products).SetLedger(s.store.Coupons).SetPromotions(s.store.Coupons).SetSubmitter(s.store.Orders).Submit(req.GetShippingName(), req.GetShippingAddress(), coupons...)
+		return err
+	})
+}
+
+//QuoteOrder returns a draft order's amount, shipping cost and price breakdown as submitted with optional coupon codes and shipping region
+func (s *Server) QuoteOrder(ctx context.Context, req *pb.QuoteOrderRequest) (*pb.QuoteOrderResponse, error) {
+	o, err := s.store.Orders.FindByID(req.GetOrderId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	coupons, err := s.findCoupons(append([]string{req.GetCouponCode()}, req.GetCouponCodes()...))
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	b, err := o.SetPromotions(s.store.Coupons).Quote(req.GetShippingRegion(), coupons...)
+	if err != nil {
+		return nil, statusError(err, codes.FailedPrecondition)
+	}
+	return &pb.QuoteOrderResponse{Amount: b.Total().String(), ShippingCost: b.ShippingCost().String(), Breakdown: newBreakdown(b),
+		Currency: o.Currency(), FormattedAmount: money.Format(b.Total(), o.Currency())}, nil
+}
+
+//ProcessOrder processes a submitted order
+func (s *Server) ProcessOrder(ctx context.Context, req *pb.ProcessOrderRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.Process()
+		return err
+	})
+}
+
+//CancelOrder cancels a submitted or processed order, returning its product stocks to the product repository and its coupons' uses
+func (s *Server) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.SetCanceler(s.store.Orders).Cancel()
+		return err
+	})
+}
+
+//ProcessShipping ships every unshipped quantity of a processed order in a single shipment
+func (s *Server) ProcessShipping(ctx context.Context, req *pb.ProcessShippingRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.ProcessShipping(req.GetTrackingId())
+		return err
+	})
+}
+
+//ShipOrder ships quantities of a processed order in a new shipment (every unshipped quantity when no item is given)
+func (s *Server) ShipOrder(ctx context.Context, req *pb.ShipOrderRequest) (*pb.Order, error) {
+	quantities := make(map[string]int, len(req.GetItems()))
+	for productID, quantity := range req.GetItems() {
+		quantities[productID] = int(quantity)
+	}
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.Ship(req.GetCarrier(), req.GetTrackingId(), quantities)
+		return err
+	})
+}
+
+//DeliverShipment marks a shipment of an order as delivered (finishing the order once every shipment is delivered)
+func (s *Server) DeliverShipment(ctx context.Context, req *pb.DeliverShipmentRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.DeliverShipment(req.GetShipmentId())
+		return err
+	})
+}
+
+//TrackShipment records a tracking event of the shipment having a carrier and tracking id (an identical event is recorded once)
+func (s *Server) TrackShipment(ctx context.Context, req *pb.TrackShipmentRequest) (*pb.Shipment, error) {
+	o, err := s.store.Orders.FindByTracking(req.GetCarrier(), req.GetTrackingId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	date := time.Now()
+	if req.GetEvent().GetDate() != nil {
+		date = req.GetEvent().GetDate().AsTime()
+	}
+	shipment, _ := o.TrackedShipment(req.GetCarrier(), req.GetTrackingId())
+	e := order.NewTrackingEvent(req.GetEvent().GetType(), date, req.GetEvent().GetLocation(), req.GetEvent().GetDescription())
+	if _, err := o.TrackShipment(shipment.ID(), e); err != nil {
+		return nil, statusError(err, codes.InvalidArgument)
+	}
+	if err := s.store.Orders.Save(o); err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newShipment(shipment), nil
+}
+
+//FinishOrder finishes a fully shipped order (delivering every shipment)
+func (s *Server) FinishOrder(ctx context.Context, req *pb.FinishOrderRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.FinishOrder()
+		return err
+	})
+}
+
+//FireEvent fires another event of the order state machine (e.g. a custom status change)
+func (s *Server) FireEvent(ctx context.Context, req *pb.FireEventRequest) (*pb.Order, error) {
+	return s.updateOrder(req.GetOrderId(), func(o *order.Order) *errors.Error {
+		_, err := o.Fire(req.GetEvent())
+		return err
+	})
+}
+
+//findCoupons finds the coupons having the given codes (in their given order, empty codes are skipped)
+func (s *Server) findCoupons(codes []string) ([]*coupon.Coupon, *errors.Error) {
+	coupons := make([]*coupon.Coupon, 0, len(codes))
+	for _, code := range codes {
+		if "" == code {
+			continue
+		}
+		c, err := s.store.Coupons.FindByCode(code)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, c)
+	}
+	return coupons, nil
+}
+
+//updateOrder loads an order, applies a change on it, saves it and returns it
+func (s *Server) updateOrder(id string, change func(o *order.Order) *errors.Error) (*pb.Order, error) {
+	o, err := s.store.Orders.FindByID(id)
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	if err := change(o); err != nil {
+		return nil, statusError(err, codes.FailedPrecondition)
+	}
+	if err := s.store.Orders.Save(o); err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newOrder(o), nil
+}
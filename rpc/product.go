@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"sstest/model/money"
+	"sstest/model/product"
+	"sstest/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+)
+
+//newProduct creates the protobuf message of a product
+func newProduct(p *product.Product) *pb.Product {
+	length, width, height := p.Dimensions()
+	return &pb.Product{Id: p.ID(), Name: p.Name(), Status: p.Status(), Price: p.Price().String(), Stock: p.Stock(), Stocks: p.Stocks(),
+		Weight: p.Weight().String(), Length: length.String(), Width: width.String(), Height: height.String(), TaxCategory: p.TaxCategory(),
+		Currency: p.Currency(), FormattedPrice: money.Format(p.Price(), p.Currency())}
+}
+
+//GetProduct returns a product
+func (s *Server) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	p, err := s.store.Products.FindByID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return s.newAvailableProduct(p)
+}
+
+//ListProducts returns all products
+func (s *Server) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	products, err := s.store.Products.FindAll()
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	resp := &pb.ListProductsResponse{Products: make([]*pb.Product, 0, len(products))}
+	for _, p := range products {
+		msg, err := s.newAvailableProduct(p)
+		if err != nil {
+			return nil, err
+		}
+		resp.Products = append(resp.Products, msg)
+	}
+	return resp, nil
+}
+
+//newAvailableProduct creates the protobuf message of a product with its available-to-sell stock looked up in the product repository
+func (s *Server) newAvailableProduct(p *product.Product) (*pb.Product, error) {
+	available, err := s.store.Products.Available(p.ID())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	msg := newProduct(p)
+	msg.Available = available
+	return msg, nil
+}
+
+//CanBeOrdered checks whether a quantity of a product can be ordered
+func (s *Server) CanBeOrdered(ctx context.Context, req *pb.CanBeOrderedRequest) (*pb.CheckResponse, error) {
+	p, err := s.store.Products.FindByID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	ok, checkErr := p.CanBeOrdered(int(req.GetQuantity()))
+	if checkErr != nil {
+		return checkResponse(ok, checkErr), nil
+	}
+	return checkResponse(ok, nil), nil
+}
@@ -0,0 +1,99 @@
+//Package rpc provides the gRPC API of the business domain models
+package rpc
+
+import (
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"sstest/rpc/pb"
+	"time"
+
+	"github.com/go-errors/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//Server is the implementation of the gRPC services, loading and saving models through a repository store
+type Server struct {
+	pb.UnimplementedOrderServiceServer
+	pb.UnimplementedProductServiceServer
+	pb.UnimplementedCouponServiceServer
+	pb.UnimplementedUserServiceServer
+	store *repository.Store
+}
+
+//NewServer creates a new gRPC server implementation on the given repository store and returns a reference to it
+func NewServer(store *repository.Store) *Server {
+	return &Server{
+		pb.UnimplementedOrderServiceServer{},
+		pb.UnimplementedProductServiceServer{},
+		pb.UnimplementedCouponServiceServer{},
+		pb.UnimplementedUserServiceServer{},
+		store,
+	}
+}
+
+//Register registers all the services of the server on a gRPC service registrar (e.g. a grpc.Server)
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	pb.RegisterOrderServiceServer(registrar, s)
+	pb.RegisterProductServiceServer(registrar, s)
+	pb.RegisterCouponServiceServer(registrar, s)
+	pb.RegisterUserServiceServer(registrar, s)
+}
+
+//errorCodes maps the known failure reasons of the models and repositories to gRPC status codes
+var errorCodes = []struct {
+	reason error
+	code   codes.Code
+}{
+	{repository.ErrNotFound, codes.NotFound},
+	{repository.ErrDuplicate, codes.AlreadyExists},
+	{order.ErrInvalidStatus, codes.FailedPrecondition},
+	{order.ErrItemNotFound, codes.NotFound},
+	{order.ErrShipmentNotFound, codes.NotFound},
+	{order.ErrInvalidShipment, codes.FailedPrecondition},
+	{order.ErrNoItem, codes.FailedPrecondition},
+	{order.ErrInvalidAmount, codes.FailedPrecondition},
+	{order.ErrCurrencyMismatch, codes.FailedPrecondition},
+	{money.ErrNoRate, codes.FailedPrecondition},
+	{product.ErrInsufficientStock, codes.ResourceExhausted},
+	{product.ErrNotAvailable, codes.FailedPrecondition},
+	{product.ErrInvalidQuantity, codes.InvalidArgument},
+	{coupon.ErrNotApplicable, codes.FailedPrecondition},
+	{coupon.ErrNotEligible, codes.FailedPrecondition},
+	{coupon.ErrNoStock, codes.ResourceExhausted},
+	{coupon.ErrRedemptionLimit, codes.ResourceExhausted},
+	{coupon.ErrNotCombinable, codes.FailedPrecondition},
+	{user.ErrNotActive, codes.PermissionDenied},
+}
+
+//statusError returns an error as gRPC status error with the code of its failure reason
+func statusError(err *errors.Error, fallback codes.Code) error {
+	for _, known := range errorCodes {
+		if errors.Is(err, known.reason) {
+			return status.Error(known.code, err.Error())
+		}
+	}
+	return status.Error(fallback, err.Error())
+}
+
+//timestampOf returns the protobuf timestamp of a time, nil for the zero time or the Unix epoch
+func timestampOf(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() || 0 == t.UnixNano() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+//checkResponse returns the response of a model's check from its result
+func checkResponse(ok bool, err error) *pb.CheckResponse {
+	if err != nil {
+		return &pb.CheckResponse{Ok: ok, Reason: err.Error()}
+	}
+	return &pb.CheckResponse{Ok: ok}
+}
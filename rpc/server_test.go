@@ -0,0 +1,720 @@
+//rpc_test provides unit tests for the gRPC API
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sstest/model/coupon"
+	"sstest/model/money"
+	"sstest/model/order"
+	"sstest/model/product"
+	"sstest/model/user"
+	"sstest/repository"
+	"sstest/rpc"
+	"sstest/rpc/pb"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+//newTestStore creates an in-memory store with an available product, a limited product, an active coupon and an inactive user
+func newTestStore() *repository.Store {
+	store := repository.NewMemoryStore()
+
+	availableProd := product.New("availableProd", "Available Product")
+	availableProd.SetStatus(product.StatusAvailable)
+	availableProd.SetStock(100)
+	availableProd.SetPrice(decimal.New(100, 0))
+	store.Products.Save(availableProd)
+
+	limitedProd := product.New("limitedProd", "Limited Product")
+	limitedProd.SetStatus(product.StatusAvailable)
+	limitedProd.SetStock(5)
+	limitedProd.SetPrice(decimal.New(150, 0))
+	store.Products.Save(limitedProd)
+
+	activeCoupon := coupon.New("SAVE10")
+	activeCoupon.SetStatus(coupon.StatusActive)
+	activeCoupon.SetStock(10)
+	activeCoupon.SetKind(coupon.KindValue)
+	activeCoupon.SetValue(decimal.New(10, 0))
+	store.Coupons.Create(activeCoupon)
+
+	inactiveUser, _ := user.New("inactiveUser", "Inactive User", "Inactive Address")
+	store.Users.Create(inactiveUser)
+
+	return store
+}
+
+//dial serves the gRPC API of a store on an in-memory listener and returns a client connection to it
+func dial(t *testing.T, store *repository.Store) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	rpc.NewServer(store).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("can't dial test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestOrderService(t *testing.T) {
+	store := newTestStore()
+	ctx := context.Background()
+	orders := pb.NewOrderServiceClient(dial(t, store))
+
+	created, _ := orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	added, _ := orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 2})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "limitedProd", Quantity: 1})
+	edited, _ := orders.EditProduct(ctx, &pb.EditProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	deleted, _ := orders.DeleteProduct(ctx, &pb.DeleteProductRequest{OrderId: "order1", ProductId: "limitedProd"})
+	quoted, _ := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1", CouponCode: "save10"})
+	submitted, _ := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", ShippingName: "ship name", ShippingAddress: "ship address", CouponCode: "save10"})
+	processed, _ := orders.ProcessOrder(ctx, &pb.ProcessOrderRequest{OrderId: "order1"})
+	shipped, _ := orders.ProcessShipping(ctx, &pb.ProcessShippingRequest{OrderId: "order1", TrackingId: "dummyTrackingNo"})
+	finished, _ := orders.FinishOrder(ctx, &pb.FinishOrderRequest{OrderId: "order1"})
+	listed, _ := orders.ListOrders(ctx, &pb.ListOrdersRequest{Status: order.StatusDelivered})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order2"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order2", ProductId: "limitedProd", Quantity: 1})
+	orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order2"})
+	fired, _ := orders.FireEvent(ctx, &pb.FireEventRequest{OrderId: "order2", Event: order.EventProcess})
+	_, errOverShip := orders.ShipOrder(ctx, &pb.ShipOrderRequest{OrderId: "order2", Items: map[string]int32{"limitedProd": 2}})
+	splitShipped, _ := orders.ShipOrder(ctx, &pb.ShipOrderRequest{OrderId: "order2", Carrier: "carrier", TrackingId: "splitTrackingNo", Items: map[string]int32{"limitedProd": 1}})
+	var shipmentID string
+	if 1 == len(splitShipped.GetShipments()) {
+		shipmentID = splitShipped.GetShipments()[0].GetId()
+	}
+	tracked, _ := orders.TrackShipment(ctx, &pb.TrackShipmentRequest{Carrier: "carrier", TrackingId: "splitTrackingNo",
+		Event: &pb.TrackingEvent{Type: order.TrackingPickedUp, Location: "Warehouse"}})
+	_, errTrackMissing := orders.TrackShipment(ctx, &pb.TrackShipmentRequest{Carrier: "carrier", TrackingId: "missingTrackingNo",
+		Event: &pb.TrackingEvent{Type: order.TrackingPickedUp}})
+	_, errTrackUnknown := orders.TrackShipment(ctx, &pb.TrackShipmentRequest{Carrier: "carrier", TrackingId: "splitTrackingNo",
+		Event: &pb.TrackingEvent{Type: "XX"}})
+	_, errDeliverMissing := orders.DeliverShipment(ctx, &pb.DeliverShipmentRequest{OrderId: "order2", ShipmentId: "missingShipment"})
+	delivered, _ := orders.DeliverShipment(ctx, &pb.DeliverShipmentRequest{OrderId: "order2", ShipmentId: shipmentID})
+
+	availableProd, _ := store.Products.FindByID("availableProd")
+
+	var orderServiceTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Created Order Status", order.StatusDraft, created.GetStatus()},
+		{"Created Order Has No Submitted Date", true, nil == created.GetSubmittedDate()},
+		{"Added Item Quantity", int32(2), added.GetItems()[0].GetQuantity()},
+		{"Edited Item Quantity", int32(3), edited.GetItems()[0].GetQuantity()},
+		{"Item Count After Delete", 1, len(deleted.GetItems())},
+		{"Submitted Order Status", order.StatusSubmitted, submitted.GetStatus()},
+		{"Quoted Amount", "290", quoted.GetAmount()},
+		{"Quoted Shipping Cost", "0", quoted.GetShippingCost()},
+		{"Quoted Breakdown Discount", "10", quoted.GetBreakdown().GetDiscount()},
+		{"Quoted Breakdown Line Subtotal", "300", quoted.GetBreakdown().GetLines()[0].GetSubtotal()},
+		{"Submitted Order Amount", "290", submitted.GetAmount()},
+		{"Submitted Order Shipping Cost", "0", submitted.GetShippingCost()},
+		{"Submitted Order Breakdown Subtotal", "300", submitted.GetBreakdown().GetSubtotal()},
+		{"Submitted Order Breakdown Total", "290", submitted.GetBreakdown().GetTotal()},
+		{"Submitted Order Breakdown Line Discount", "10", submitted.GetBreakdown().GetLines()[0].GetDiscount()},
+		{"Submitted Order Coupon", "SAVE10", submitted.GetCoupons()[0].GetCouponId()},
+		{"Submitted Order Has Submitted Date", true, submitted.GetSubmittedDate() != nil},
+		{"Submitted Order Events", "process,cancel", strings.Join(submitted.GetEvents(), ",")},
+		{"Submitted Product Stock", int64(97), availableProd.Stock()},
+		{"Processed Order Status", order.StatusProcessed, processed.GetStatus()},
+		{"Shipped Order Tracking ID", "dummyTrackingNo", shipped.GetShippingTrackingId()},
+		{"Finished Order Status", order.StatusDelivered, finished.GetStatus()},
+		{"Listed Order Count", 1, len(listed.GetOrders())},
+		{"Fired Event Order Status", order.StatusProcessed, fired.GetStatus()},
+		{"Over Shipment Code", codes.FailedPrecondition, status.Code(errOverShip)},
+		{"Shipped Order Shipping Status", order.ShipStatusOnProcess, splitShipped.GetShippingStatus()},
+		{"Shipment Carrier", "carrier", splitShipped.GetShipments()[0].GetCarrier()},
+		{"Shipment Quantity", int32(1), splitShipped.GetShipments()[0].GetItems()["limitedProd"]},
+		{"Tracked Shipment Status", order.ShipmentStatusInTransit, tracked.GetStatus()},
+		{"Tracked Shipment Event Location", "Warehouse", tracked.GetEvents()[0].GetLocation()},
+		{"Tracked Shipment Event Has Date", true, tracked.GetEvents()[0].GetDate() != nil},
+		{"Track Missing Shipment Code", codes.NotFound, status.Code(errTrackMissing)},
+		{"Track Unknown Event Code", codes.InvalidArgument, status.Code(errTrackUnknown)},
+		{"Deliver Missing Shipment Code", codes.NotFound, status.Code(errDeliverMissing)},
+		{"Delivered Shipment Status", order.ShipmentStatusDelivered, delivered.GetShipments()[0].GetStatus()},
+		{"Delivered Shipment Has Delivered Date", true, delivered.GetShipments()[0].GetDeliveredDate() != nil},
+		{"Delivered Shipment Events", 2, len(delivered.GetShipments()[0].GetEvents())},
+		{"Delivered Order Status", order.StatusDelivered, delivered.GetStatus()},
+	}
+
+	for _, test := range orderServiceTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderTaxes(t *testing.T) {
+	order.SetDefaultTaxTable(order.NewTaxTable(
+		order.NewTaxRegion("FR", true,
+			order.NewTaxRule("VAT", product.TaxCategoryStandard, decimal.New(2, -1), false))))
+	t.Cleanup(func() { order.SetDefaultTaxTable(nil) })
+	store := newTestStore()
+	ctx := context.Background()
+	orders := pb.NewOrderServiceClient(dial(t, store))
+
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	quoted, _ := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1", ShippingRegion: "FR"})
+	submitted, _ := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", ShippingRegion: "FR"})
+
+	var orderTaxTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Quoted Amount Must Include Taxes", "100", quoted.GetAmount()},
+		{"Submitted Order Shipping Region", "FR", submitted.GetShippingRegion()},
+		{"Submitted Order Amount", "100", submitted.GetAmount()},
+		{"Submitted Order Tax", "16.67", submitted.GetTax()},
+		{"Submitted Order Tax Included", true, submitted.GetTaxIncluded()},
+		{"Submitted Order Tax Rate", "0.2", submitted.GetTaxes()[0].GetRate()},
+		{"Submitted Item Tax", "16.67", submitted.GetItems()[0].GetTaxes()[0].GetAmount()},
+		{"Item Product Tax Category", product.TaxCategoryStandard, submitted.GetItems()[0].GetProduct().GetTaxCategory()},
+	}
+
+	for _, test := range orderTaxTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestItemSnapshot(t *testing.T) {
+	store := newTestStore()
+	ctx := context.Background()
+	orders := pb.NewOrderServiceClient(dial(t, store))
+
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 2})
+	orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1"})
+	availableProd, _ := store.Products.FindByID("availableProd")
+	availableProd.SetName("Renamed Product")
+	availableProd.SetPrice(decimal.New(120, 0))
+	availableProd.SetStatus(product.StatusDiscontinued)
+	submitted, _ := orders.GetOrder(ctx, &pb.GetOrderRequest{Id: "order1"})
+
+	var itemSnapshotTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Captured Unit Price", "100", submitted.GetItems()[0].GetUnitPrice()},
+		{"Captured Product Name", "Available Product", submitted.GetItems()[0].GetProductName()},
+		{"Captured Product Status", product.StatusAvailable, submitted.GetItems()[0].GetProductStatus()},
+		{"Current Product Price", "120", submitted.GetItems()[0].GetProduct().GetPrice()},
+		{"Amount", "200", submitted.GetAmount()},
+	}
+
+	for _, test := range itemSnapshotTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderCurrency(t *testing.T) {
+	store := newTestStore()
+	euroProd := product.New("euroProd", "Euro Product")
+	euroProd.SetStatus(product.StatusAvailable)
+	euroProd.SetStock(10)
+	euroProd.SetPrice(decimal.New(12345, -1))
+	euroProd.SetCurrency("EUR")
+	store.Products.Save(euroProd)
+	ctx := context.Background()
+	conn := dial(t, store)
+	orders, products := pb.NewOrderServiceClient(conn), pb.NewProductServiceClient(conn)
+
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "euroProd", Quantity: 1})
+	_, errMixed := orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	quoted, _ := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1"})
+	_, errCoupon := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", CouponCode: "SAVE10"})
+	submitted, _ := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1"})
+	fetchedProd, _ := products.GetProduct(ctx, &pb.GetProductRequest{Id: "euroProd"})
+
+	var orderCurrencyTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Product In Other Currency", codes.FailedPrecondition, status.Code(errMixed)},
+		{"Quoted Currency", "EUR", quoted.GetCurrency()},
+		{"Quoted Formatted Amount", "€1,234.50", quoted.GetFormattedAmount()},
+		{"Value Coupon In Other Currency", codes.FailedPrecondition, status.Code(errCoupon)},
+		{"Submitted Currency", "EUR", submitted.GetCurrency()},
+		{"Submitted Formatted Amount", "€1,234.50", submitted.GetFormattedAmount()},
+		{"Product Currency", "EUR", fetchedProd.GetCurrency()},
+		{"Product Formatted Price", "€1,234.50", fetchedProd.GetFormattedPrice()},
+	}
+
+	for _, test := range orderCurrencyTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestOrderExchangeRates(t *testing.T) {
+	rateDate := time.Date(2017, 9, 15, 0, 0, 0, 0, time.UTC)
+	order.SetDefaultExchangeRates(money.NewRateTable("USD", rateDate, map[string]decimal.Decimal{"EUR": decimal.New(8, -1)}))
+	t.Cleanup(func() { order.SetDefaultExchangeRates(nil) })
+	store := newTestStore()
+	euroProd := product.New("euroProd", "Euro Product")
+	euroProd.SetStatus(product.StatusAvailable)
+	euroProd.SetStock(10)
+	euroProd.SetPrice(decimal.New(100, 0))
+	euroProd.SetCurrency("EUR")
+	store.Products.Save(euroProd)
+	ctx := context.Background()
+	orders := pb.NewOrderServiceClient(dial(t, store))
+
+	created, _ := orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1", Currency: "USD"})
+	_, errUnknown := orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order2", Currency: "XYZ"})
+	_, errEuro := orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "euroProd", Quantity: 2})
+	submitted, _ := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1"})
+
+	var orderExchangeRateTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Created Currency", "USD", created.GetCurrency()},
+		{"Unknown Currency", codes.InvalidArgument, status.Code(errUnknown)},
+		{"Product In Other Currency", codes.OK, status.Code(errEuro)},
+		{"Submitted Amount", "250", submitted.GetAmount()},
+		{"Converted Item Price", "125", submitted.GetItems()[0].GetUnitPrice()},
+		{"Converted Item Exchange Rate", "1.25", submitted.GetItems()[0].GetExchangeRate()},
+		{"Converted Item Rate Date", rateDate, submitted.GetItems()[0].GetRateDate().AsTime()},
+	}
+
+	for _, test := range orderExchangeRateTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponConditions(t *testing.T) {
+	store := newTestStore()
+	limitedCoupon := coupon.New("LIMITED20")
+	limitedCoupon.SetStatus(coupon.StatusActive)
+	limitedCoupon.SetStock(5)
+	limitedCoupon.SetValue(decimal.New(20, 0))
+	limitedCoupon.SetMaxDiscount(decimal.New(25, 0))
+	limitedCoupon.SetMinQuantity(3)
+	limitedCoupon.SetProducts("limitedProd")
+	store.Coupons.Create(limitedCoupon)
+	ctx := context.Background()
+	conn := dial(t, store)
+	orders := pb.NewOrderServiceClient(conn)
+	coupons := pb.NewCouponServiceClient(conn)
+
+	fetched, _ := coupons.GetCoupon(ctx, &pb.GetCouponRequest{Code: "LIMITED20"})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "limitedProd", Quantity: 1})
+	_, errIneligible := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", CouponCode: "LIMITED20"})
+	orders.EditProduct(ctx, &pb.EditProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	submitted, errSubmit := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", CouponCode: "LIMITED20"})
+
+	var couponConditionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon Minimum Quantity", int32(3), fetched.GetMinQuantity()},
+		{"Coupon Maximum Discount", "25", fetched.GetMaxDiscount()},
+		{"Coupon Products", "limitedProd", fetched.GetProducts()[0]},
+		{"Ineligible Order", codes.FailedPrecondition, status.Code(errIneligible)},
+		{"Eligible Order", codes.OK, status.Code(errSubmit)},
+		{"Capped Discount", "25", submitted.GetBreakdown().GetDiscount()},
+		{"Submitted Amount", "325", submitted.GetAmount()},
+	}
+
+	for _, test := range couponConditionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponRedemptions(t *testing.T) {
+	store := newTestStore()
+	onceCoupon := coupon.New("ONCE")
+	onceCoupon.SetStatus(coupon.StatusActive)
+	onceCoupon.SetStock(5)
+	onceCoupon.SetValue(decimal.New(20, 0))
+	onceCoupon.SetUserLimit(1)
+	onceCoupon.SetRedemptionLimit(10)
+	store.Coupons.Create(onceCoupon)
+	activeUser, _ := user.New("activeUser", "Active User", "Active Address")
+	activeUser.Activate()
+	store.Users.Create(activeUser)
+	ctx := context.Background()
+	conn := dial(t, store)
+	orders := pb.NewOrderServiceClient(conn)
+	coupons := pb.NewCouponServiceClient(conn)
+
+	fetched, _ := coupons.GetCoupon(ctx, &pb.GetCouponRequest{Code: "ONCE"})
+	for _, id := range []string{"order1", "order2"} {
+		orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: id, UserId: "activeUser"})
+		orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: id, ProductId: "availableProd", Quantity: 1})
+	}
+	_, errSubmit := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", CouponCode: "ONCE"})
+	_, errOverLimit := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order2", CouponCode: "ONCE"})
+	_, errCancel := orders.CancelOrder(ctx, &pb.CancelOrderRequest{OrderId: "order1"})
+	_, errAfterCancel := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order2", CouponCode: "ONCE"})
+	listed, errList := coupons.ListRedemptions(ctx, &pb.ListRedemptionsRequest{Code: "ONCE"})
+	_, errMissing := coupons.ListRedemptions(ctx, &pb.ListRedemptionsRequest{Code: "MISSING"})
+	if 2 != len(listed.GetRedemptions()) {
+		t.Fatalf("expected 2 redemptions but got %d", len(listed.GetRedemptions()))
+	}
+
+	var couponRedemptionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon User Limit", int32(1), fetched.GetUserLimit()},
+		{"Coupon Redemption Limit", int32(10), fetched.GetRedemptionLimit()},
+		{"Submit", codes.OK, status.Code(errSubmit)},
+		{"Submit Over User Limit", codes.ResourceExhausted, status.Code(errOverLimit)},
+		{"Cancel", codes.OK, status.Code(errCancel)},
+		{"Submit After Cancel", codes.OK, status.Code(errAfterCancel)},
+		{"List Redemptions", codes.OK, status.Code(errList)},
+		{"List Redemptions Of Missing Coupon", codes.NotFound, status.Code(errMissing)},
+		{"Reversed Redemption Order", "order1", listed.GetRedemptions()[0].GetOrderId()},
+		{"Reversed Redemption Is Reversed", true, nil != listed.GetRedemptions()[0].GetReversedDate()},
+		{"Redemption User", "activeUser", listed.GetRedemptions()[1].GetUserId()},
+		{"Redemption Amount", "20", listed.GetRedemptions()[1].GetAmount()},
+		{"Redemption Is Not Reversed", true, nil == listed.GetRedemptions()[1].GetReversedDate()},
+	}
+
+	for _, test := range couponRedemptionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponStacking(t *testing.T) {
+	store := newTestStore()
+	for _, code := range []string{"PERCENT10", "VALUE10"} {
+		c := coupon.New(code).SetStackable(true)
+		c.SetStatus(coupon.StatusActive)
+		c.SetStock(5)
+		if "VALUE10" == code {
+			c.SetKind(coupon.KindValue)
+		}
+		store.Coupons.Create(c)
+	}
+	ctx := context.Background()
+	conn := dial(t, store)
+	orders := pb.NewOrderServiceClient(conn)
+	coupons := pb.NewCouponServiceClient(conn)
+
+	fetched, _ := coupons.GetCoupon(ctx, &pb.GetCouponRequest{Code: "PERCENT10"})
+	for _, id := range []string{"order1", "order2"} {
+		orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: id})
+		orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: id, ProductId: "availableProd", Quantity: 1})
+	}
+	quoted, errQuote := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1", CouponCodes: []string{"VALUE10", "PERCENT10"}})
+	_, errExclusive := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order2", CouponCode: "SAVE10", CouponCodes: []string{"PERCENT10"}})
+	submitted, errSubmit := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1", CouponCode: "VALUE10", CouponCodes: []string{"PERCENT10"}})
+	if 2 != len(quoted.GetBreakdown().GetCoupons()) || 2 != len(submitted.GetCoupons()) {
+		t.Fatalf("expected 2 applied coupons but got %d and %d", len(quoted.GetBreakdown().GetCoupons()), len(submitted.GetCoupons()))
+	}
+
+	var couponStackingTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon Is Stackable", true, fetched.GetStackable()},
+		{"Quote", codes.OK, status.Code(errQuote)},
+		{"Quote Amount", "80", quoted.GetAmount()},
+		{"Quote Applies Percentage First", "PERCENT10", quoted.GetBreakdown().GetCoupons()[0].GetCouponId()},
+		{"Submit With Exclusive Coupon", codes.FailedPrecondition, status.Code(errExclusive)},
+		{"Submit", codes.OK, status.Code(errSubmit)},
+		{"Submitted Discount", "20", submitted.GetBreakdown().GetDiscount()},
+		{"Submitted Coupon", "VALUE10", submitted.GetCoupons()[1].GetCouponId()},
+		{"Submitted Coupon Discount", "10", submitted.GetCoupons()[1].GetDiscount()},
+	}
+
+	for _, test := range couponStackingTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestCouponPromotions(t *testing.T) {
+	store := newTestStore()
+	buyXGetY := coupon.New("BUY1GET1").SetProducts("availableProd")
+	buyXGetY.SetStatus(coupon.StatusActive)
+	buyXGetY.SetStock(5)
+	buyXGetY.SetKind(coupon.KindBuyXGetY)
+	buyXGetY.SetBuyQuantity(1)
+	buyXGetY.SetFreeQuantity(1)
+	store.Coupons.Create(buyXGetY)
+	tiered := coupon.New("SPEND")
+	tiered.SetKind(coupon.KindTiered)
+	tiered.SetTiers(coupon.NewTier(decimal.New(100, 0), decimal.New(5, 0)))
+	store.Coupons.Create(tiered)
+	ctx := context.Background()
+	conn := dial(t, store)
+	orders := pb.NewOrderServiceClient(conn)
+	coupons := pb.NewCouponServiceClient(conn)
+
+	fetched, _ := coupons.GetCoupon(ctx, &pb.GetCouponRequest{Code: "BUY1GET1"})
+	fetchedTiered, _ := coupons.GetCoupon(ctx, &pb.GetCouponRequest{Code: "SPEND"})
+	_, errPromotionDiscount := coupons.GetDiscountAmount(ctx, &pb.GetDiscountAmountRequest{Code: "BUY1GET1", Amount: "100"})
+	tieredDiscount, _ := coupons.GetDiscountAmount(ctx, &pb.GetDiscountAmountRequest{Code: "SPEND", Amount: "200"})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 2})
+	quoted, errQuote := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1", CouponCodes: []string{"BUY1GET1"}})
+
+	var couponPromotionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon Buy Quantity", int32(1), fetched.GetBuyQuantity()},
+		{"Coupon Free Quantity", int32(1), fetched.GetFreeQuantity()},
+		{"Coupon Tiers", 1, len(fetchedTiered.GetTiers())},
+		{"Coupon Tier Percentage", "5", fetchedTiered.GetTiers()[0].GetPercentage()},
+		{"Promotion Discount Amount", codes.FailedPrecondition, status.Code(errPromotionDiscount)},
+		{"Tiered Discount Amount", "10", tieredDiscount.GetDiscount()},
+		{"Quote", codes.OK, status.Code(errQuote)},
+		{"Quote Free Item Amount", "100", quoted.GetAmount()},
+	}
+
+	for _, test := range couponPromotionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestAutomaticPromotions(t *testing.T) {
+	store := newTestStore()
+	promotion := coupon.New("AUTO10").SetAutomatic(true).SetPriority(1)
+	promotion.SetStatus(coupon.StatusActive)
+	promotion.SetStackable(true)
+	store.Coupons.Create(promotion)
+	excluding := coupon.New("NOCOUPON").SetAutomatic(true).SetPriority(5).SetExcludesCoupons(true)
+	excluding.SetStatus(coupon.StatusActive)
+	excluding.SetKind(coupon.KindValue)
+	excluding.SetValue(decimal.New(20, 0))
+	excluding.SetStackable(true)
+	store.Coupons.Create(excluding)
+	ctx := context.Background()
+	conn := dial(t, store)
+	orders := pb.NewOrderServiceClient(conn)
+	coupons := pb.NewCouponServiceClient(conn)
+
+	fetched, _ := coupons.GetCoupon(ctx, &pb.GetCouponRequest{Code: "NOCOUPON"})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	quoted, errQuote := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1"})
+	couponQuoted, _ := orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "order1", CouponCode: "SAVE10"})
+	submitted, errSubmit := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1"})
+	if 2 != len(quoted.GetBreakdown().GetPromotions()) || 1 != len(couponQuoted.GetBreakdown().GetPromotions()) || 2 != len(submitted.GetPromotions()) {
+		t.Fatalf("expected 2, 1 and 2 applied promotions but got %d, %d and %d", len(quoted.GetBreakdown().GetPromotions()),
+			len(couponQuoted.GetBreakdown().GetPromotions()), len(submitted.GetPromotions()))
+	}
+
+	var automaticPromotionTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Coupon Is Automatic", true, fetched.GetAutomatic()},
+		{"Coupon Priority", int32(5), fetched.GetPriority()},
+		{"Coupon Excludes Coupons", true, fetched.GetExcludesCoupons()},
+		{"Quote", codes.OK, status.Code(errQuote)},
+		{"Quote Amount", "72", quoted.GetAmount()},
+		{"Quote Applies Highest Priority First", "NOCOUPON", quoted.GetBreakdown().GetPromotions()[0].GetCouponId()},
+		{"Quote With Coupon Amount", "80", couponQuoted.GetAmount()},
+		{"Quote With Coupon Skips Excluding Promotion", "AUTO10", couponQuoted.GetBreakdown().GetPromotions()[0].GetCouponId()},
+		{"Submit", codes.OK, status.Code(errSubmit)},
+		{"Submitted Amount", "72", submitted.GetAmount()},
+		{"Submitted Promotion Discount", "8", submitted.GetPromotions()[1].GetDiscount()},
+	}
+
+	for _, test := range automaticPromotionTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestUserOrders(t *testing.T) {
+	store := newTestStore()
+	ctx := context.Background()
+	orders := pb.NewOrderServiceClient(dial(t, store))
+
+	activeUser, _ := user.New("activeUser", "Active User", "Active Address")
+	activeUser.Activate()
+	store.Users.Create(activeUser)
+
+	created, _ := orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order1", UserId: "activeUser"})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "order2"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "order1", ProductId: "availableProd", Quantity: 1})
+	submitted, _ := orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "order1"})
+	listed, _ := orders.ListUserOrders(ctx, &pb.ListUserOrdersRequest{UserId: "activeUser"})
+
+	var userOrdersTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Created Order User", "activeUser", created.GetUserId()},
+		{"Submitted Order Status", order.StatusSubmitted, submitted.GetStatus()},
+		{"Submitted Order Default Shipping Name", "Active User", submitted.GetShippingName()},
+		{"Submitted Order Default Shipping Address", "Active Address", submitted.GetShippingAddress()},
+		{"Listed Order Count", 1, len(listed.GetOrders())},
+		{"Listed Order ID", "order1", listed.GetOrders()[0].GetId()},
+	}
+
+	for _, test := range userOrdersTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	store := newTestStore()
+	ctx := context.Background()
+	orders := pb.NewOrderServiceClient(dial(t, store))
+
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "draftOrder"})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "submittedOrder"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "submittedOrder", ProductId: "availableProd", Quantity: 1})
+	orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "submittedOrder"})
+	orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "inactiveUserOrder", UserId: "inactiveUser"})
+	orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "inactiveUserOrder", ProductId: "availableProd", Quantity: 1})
+
+	codeOf := func(_ interface{}, err error) codes.Code {
+		return status.Code(err)
+	}
+
+	var statusCodeTests = []struct {
+		testCase     string
+		expectedCode codes.Code
+		actualCode   codes.Code
+	}{
+		{"Duplicate Order", codes.AlreadyExists, codeOf(orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "draftOrder"}))},
+		{"Missing Order", codes.NotFound, codeOf(orders.GetOrder(ctx, &pb.GetOrderRequest{Id: "missingOrder"}))},
+		{"Missing Product", codes.NotFound, codeOf(orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "draftOrder", ProductId: "missingProd", Quantity: 1}))},
+		{"Out Of Stock", codes.ResourceExhausted, codeOf(orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "draftOrder", ProductId: "limitedProd", Quantity: 10}))},
+		{"Invalid Quantity", codes.InvalidArgument, codeOf(orders.AddProduct(ctx, &pb.AddProductRequest{OrderId: "draftOrder", ProductId: "limitedProd", Quantity: 0}))},
+		{"Edit Missing Item", codes.NotFound, codeOf(orders.EditProduct(ctx, &pb.EditProductRequest{OrderId: "draftOrder", ProductId: "limitedProd", Quantity: 1}))},
+		{"Submit Without Item", codes.FailedPrecondition, codeOf(orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "draftOrder"}))},
+		{"Submit Submitted Order", codes.FailedPrecondition, codeOf(orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "submittedOrder"}))},
+		{"Quote Submitted Order", codes.FailedPrecondition, codeOf(orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "submittedOrder"}))},
+		{"Quote Missing Order", codes.NotFound, codeOf(orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "missingOrder"}))},
+		{"Quote Missing Coupon", codes.NotFound, codeOf(orders.QuoteOrder(ctx, &pb.QuoteOrderRequest{OrderId: "draftOrder", CouponCode: "missing"}))},
+		{"Finish Submitted Order", codes.FailedPrecondition, codeOf(orders.FinishOrder(ctx, &pb.FinishOrderRequest{OrderId: "submittedOrder"}))},
+		{"Create With Missing User", codes.NotFound, codeOf(orders.CreateOrder(ctx, &pb.CreateOrderRequest{Id: "missingUserOrder", UserId: "missingUser"}))},
+		{"Submit With Inactive User", codes.PermissionDenied, codeOf(orders.SubmitOrder(ctx, &pb.SubmitOrderRequest{OrderId: "inactiveUserOrder"}))},
+		{"Fire Unknown Event", codes.FailedPrecondition, codeOf(orders.FireEvent(ctx, &pb.FireEventRequest{OrderId: "submittedOrder", Event: "unknown"}))},
+		{"Fire Submit Event", codes.FailedPrecondition, codeOf(orders.FireEvent(ctx, &pb.FireEventRequest{OrderId: "draftOrder", Event: order.EventSubmit}))},
+		{"List Missing User Orders", codes.NotFound, codeOf(orders.ListUserOrders(ctx, &pb.ListUserOrdersRequest{UserId: "missingUser"}))},
+	}
+
+	for _, test := range statusCodeTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedCode != test.actualCode {
+				t.Errorf("want %v for status code, got %v", test.expectedCode, test.actualCode)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	store := newTestStore()
+	ctx := context.Background()
+	conn := dial(t, store)
+	products := pb.NewProductServiceClient(conn)
+	coupons := pb.NewCouponServiceClient(conn)
+	users := pb.NewUserServiceClient(conn)
+
+	orderable, _ := products.CanBeOrdered(ctx, &pb.CanBeOrderedRequest{Id: "limitedProd", Quantity: 5})
+	notOrderable, _ := products.CanBeOrdered(ctx, &pb.CanBeOrderedRequest{Id: "limitedProd", Quantity: 6})
+	applicable, _ := coupons.CanBeApplied(ctx, &pb.CanBeAppliedRequest{Code: "save10"})
+	discount, _ := coupons.GetDiscountAmount(ctx, &pb.GetDiscountAmountRequest{Code: "SAVE10", Amount: "250"})
+	canOrder, _ := users.CanOrder(ctx, &pb.CanOrderRequest{Id: "inactiveUser"})
+	validPassword, _ := users.ValidatePassword(ctx, &pb.ValidatePasswordRequest{Id: "inactiveUser", Password: "changeme"})
+	invalidPassword, _ := users.ValidatePassword(ctx, &pb.ValidatePasswordRequest{Id: "inactiveUser", Password: "wrong"})
+	_, missingUserErr := users.CanOrder(ctx, &pb.CanOrderRequest{Id: "missingUser"})
+	_, invalidAmountErr := coupons.GetDiscountAmount(ctx, &pb.GetDiscountAmountRequest{Code: "SAVE10", Amount: "abc"})
+
+	var checkTests = []struct {
+		testCase      string
+		expectedValue interface{}
+		actualValue   interface{}
+	}{
+		{"Product Can Be Ordered", true, orderable.GetOk()},
+		{"Product Can't Be Ordered", false, notOrderable.GetOk()},
+		{"Product Can't Be Ordered Reason", true, "" != notOrderable.GetReason()},
+		{"Coupon Can Be Applied", true, applicable.GetOk()},
+		{"Coupon Discount Amount", "10", discount.GetDiscount()},
+		{"Inactive User Can't Order", false, canOrder.GetOk()},
+		{"Valid Password", true, validPassword.GetOk()},
+		{"Invalid Password", false, invalidPassword.GetOk()},
+		{"Missing User Status Code", codes.NotFound, status.Code(missingUserErr)},
+		{"Invalid Amount Status Code", codes.InvalidArgument, status.Code(invalidAmountErr)},
+	}
+
+	for _, test := range checkTests {
+		t.Run(fmt.Sprintf("%s", test.testCase), func(t *testing.T) {
+			if test.expectedValue != test.actualValue {
+				t.Errorf("want %v, got %v", test.expectedValue, test.actualValue)
+			}
+		})
+	}
+}
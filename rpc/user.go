@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"context"
+	"sstest/model/user"
+	"sstest/rpc/pb"
+
+	"google.golang.org/grpc/codes"
+)
+
+//newUser creates the protobuf message of a user (without its password hash)
+func newUser(u *user.User) *pb.User {
+	return &pb.User{Id: u.ID(), Name: u.Name(), Address: u.Address(), Status: u.Status()}
+}
+
+//GetUser returns a user
+func (s *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	u, err := s.store.Users.FindByID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newUser(u), nil
+}
+
+//ListUsers returns all users
+func (s *Server) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	users, err := s.store.Users.FindAll()
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	resp := &pb.ListUsersResponse{Users: make([]*pb.User, 0, len(users))}
+	for _, u := range users {
+		resp.Users = append(resp.Users, newUser(u))
+	}
+	return resp, nil
+}
+
+//CanOrder checks whether a user can place orders
+func (s *Server) CanOrder(ctx context.Context, req *pb.CanOrderRequest) (*pb.CheckResponse, error) {
+	u, err := s.store.Users.FindByID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return checkResponse(u.CanOrder()), nil
+}
+
+//ValidatePassword checks a user's password
+func (s *Server) ValidatePassword(ctx context.Context, req *pb.ValidatePasswordRequest) (*pb.CheckResponse, error) {
+	u, err := s.store.Users.FindByID(req.GetId())
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	ok, checkErr := u.ValidatePassword(req.GetPassword())
+	if checkErr != nil {
+		return checkResponse(ok, checkErr), nil
+	}
+	return checkResponse(ok, nil), nil
+}